@@ -16,6 +16,12 @@ type Config struct {
 	URL      string `json:"url"`
 	APIToken string `json:"apiToken"`
 
+	// Platform authentication, used instead of the APIToken if set.
+	PlatformToken     string `json:"platformToken,omitempty"`
+	OAuthClientID     string `json:"oAuthClientId,omitempty"`
+	OAuthClientSecret string `json:"oAuthClientSecret,omitempty"`
+	OAuthTokenURL     string `json:"oAuthTokenUrl,omitempty"`
+
 	Proxy       string `json:"proxy"`
 	NoProxy     string `json:"noProxy"`
 	NetworkZone string `json:"networkZone"`
@@ -43,6 +49,13 @@ func (c Config) toDTClientOptions() []dtclient.Option {
 		options = append(options, dtclient.SkipCertificateValidation(c.SkipCertCheck))
 	}
 
+	switch {
+	case c.PlatformToken != "":
+		options = append(options, dtclient.PlatformTokenAuth(c.PlatformToken, c.OAuthTokenURL))
+	case c.OAuthClientID != "" && c.OAuthClientSecret != "":
+		options = append(options, dtclient.OAuthClientAuth(c.OAuthClientID, c.OAuthClientSecret, c.OAuthTokenURL))
+	}
+
 	return options
 }
 
@@ -43,6 +43,21 @@ func TestToDTClientOptions(t *testing.T) {
 			in:    Config{SkipCertCheck: true},
 			out:   []dtclient.Option{dtclient.SkipCertificateValidation(true)},
 		},
+		{
+			title: "platform token propagated",
+			in:    Config{PlatformToken: "platform-token", OAuthClientID: "client-id", OAuthClientSecret: "client-secret"},
+			out:   []dtclient.Option{dtclient.PlatformTokenAuth("platform-token", "")},
+		},
+		{
+			title: "oauth client propagated",
+			in:    Config{OAuthClientID: "client-id", OAuthClientSecret: "client-secret", OAuthTokenURL: "https://sso.example.com/token"},
+			out:   []dtclient.Option{dtclient.OAuthClientAuth("client-id", "client-secret", "https://sso.example.com/token")},
+		},
+		{
+			title: "oauth client without secret not propagated",
+			in:    Config{OAuthClientID: "client-id"},
+			out:   []dtclient.Option{},
+		},
 		{
 			title: "everything propagated",
 			in: Config{
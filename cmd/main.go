@@ -25,6 +25,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/cmd/csi/registrar"
 	csiServer "github.com/Dynatrace/dynatrace-operator/cmd/csi/server"
 	"github.com/Dynatrace/dynatrace-operator/cmd/operator"
+	"github.com/Dynatrace/dynatrace-operator/cmd/plan"
 	startupProbe "github.com/Dynatrace/dynatrace-operator/cmd/startupprobe"
 	supportArchive "github.com/Dynatrace/dynatrace-operator/cmd/supportarchive"
 	"github.com/Dynatrace/dynatrace-operator/cmd/troubleshoot"
@@ -63,6 +64,7 @@ func main() {
 		operator.New(),
 		troubleshoot.New(),
 		supportArchive.New(),
+		plan.New(),
 		startupProbe.New(),
 		csiInit.New(),
 		csiProvisioner.New(),
@@ -74,7 +76,11 @@ func main() {
 
 	err := cmd.Execute()
 	if err != nil {
-		log.Info(err.Error())
+		// the failures of a structured troubleshoot report are written to stdout already, logging them would break the report
+		if !errors.Is(err, troubleshoot.ErrReportFailed) {
+			log.Info(err.Error())
+		}
+
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,83 @@
+package plan
+
+import (
+	"context"
+	"io"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const (
+	use                    = "plan"
+	fileFlagName           = "file"
+	fileFlagShorthand      = "f"
+	namespaceFlagName      = "namespace"
+	namespaceFlagShorthand = "n"
+)
+
+var (
+	fileFlagValue      string
+	namespaceFlagValue string
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: "Show the changes the operator would apply for a DynaKube manifest",
+		Long: "Builds the Kubernetes objects the operator would apply for the given DynaKube manifest and compares them with the objects in the cluster. " +
+			"Nothing is written to the cluster, all changes are validated via server-side dry-run requests.",
+		RunE: run(),
+	}
+
+	addFlags(cmd)
+
+	return cmd
+}
+
+func addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&fileFlagValue, fileFlagName, fileFlagShorthand, "", "Path to the DynaKube manifest, use '-' to read from stdin.")
+	cmd.PersistentFlags().StringVarP(&namespaceFlagValue, namespaceFlagName, namespaceFlagShorthand, env.DefaultNamespace(), "Namespace of the DynaKube, if it is not set in the manifest.")
+	_ = cmd.MarkPersistentFlagRequired(fileFlagName)
+}
+
+func run() func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		kubeConfig, err := config.GetConfig()
+		if err != nil {
+			return err
+		}
+
+		manifest, err := readManifest(cmd.InOrStdin(), fileFlagValue)
+		if err != nil {
+			return err
+		}
+
+		return runPlan(cmd.Context(), cmd.OutOrStdout(), kubeConfig, manifest, namespaceFlagValue)
+	}
+}
+
+func runPlan(ctx context.Context, out io.Writer, kubeConfig *rest.Config, manifest []byte, namespace string) error {
+	dk, err := decodeDynakube(manifest, namespace)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := client.New(kubeConfig, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// every write request is sent with dryRun=All, so the cluster state is never changed
+	p, err := newPlanner(ctx, client.NewDryRunClient(kubeClient), kubeClient, dk)
+	if err != nil {
+		return err
+	}
+
+	return printChanges(out, p.plan(ctx))
+}
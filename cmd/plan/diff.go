@@ -0,0 +1,116 @@
+package plan
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+const diffContextLines = 3
+
+// metadata fields that are managed by the API server and would only add noise to the diff
+var serverSideMetadataFields = []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink"}
+
+func printChanges(out io.Writer, changes []change) error {
+	counts := map[action]int{}
+
+	var failed int
+
+	for _, c := range changes {
+		if c.action == actionNone {
+			continue
+		}
+
+		if c.err != nil {
+			failed++
+
+			fmt.Fprintf(out, "%s %s: failed: %v\n\n", c.kind, c.key, c.err)
+
+			continue
+		}
+
+		counts[c.action]++
+
+		fmt.Fprintf(out, "%s %s: %s\n", c.kind, c.key, c.action)
+
+		if c.action == actionUnchanged {
+			fmt.Fprintln(out)
+
+			continue
+		}
+
+		diff, err := diffObjects(c)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(out, diff)
+	}
+
+	fmt.Fprintf(out, "Plan: %d to create, %d to update, %d to recreate, %d to delete, %d unchanged.\n",
+		counts[actionCreate], counts[actionUpdate], counts[actionRecreate], counts[actionDelete], counts[actionUnchanged])
+
+	if failed > 0 {
+		return errors.Errorf("failed to plan %d object(s)", failed)
+	}
+
+	return nil
+}
+
+func diffObjects(c change) (string, error) {
+	current, err := toYAML(c.current)
+	if err != nil {
+		return "", err
+	}
+
+	planned, err := toYAML(c.planned)
+	if err != nil {
+		return "", err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(current),
+		B:        difflib.SplitLines(planned),
+		FromFile: "current/" + c.key.String(),
+		ToFile:   "planned/" + c.key.String(),
+		Context:  diffContextLines,
+	})
+
+	return diff, errors.WithStack(err)
+}
+
+func toYAML(obj client.Object) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, scheme.Scheme)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	content["apiVersion"], content["kind"] = gvk.ToAPIVersionAndKind()
+	delete(content, "status")
+
+	if metadata, ok := content["metadata"].(map[string]any); ok {
+		for _, field := range serverSideMetadataFields {
+			delete(metadata, field)
+		}
+	}
+
+	data, err := yaml.Marshal(content)
+
+	return string(data), errors.WithStack(err)
+}
@@ -0,0 +1,56 @@
+package plan
+
+import (
+	"io"
+	"os"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+const stdinFileName = "-"
+
+func readManifest(stdin io.Reader, path string) ([]byte, error) {
+	if path == stdinFileName {
+		data, err := io.ReadAll(stdin)
+
+		return data, errors.WithMessage(err, "failed to read DynaKube manifest from stdin")
+	}
+
+	data, err := os.ReadFile(path)
+
+	return data, errors.WithMessagef(err, "failed to read DynaKube manifest '%s'", path)
+}
+
+// decodeDynakube decodes a DynaKube manifest of any served API version and converts it to the latest version, which is used by the reconcilers.
+func decodeDynakube(manifest []byte, namespace string) (*dynakube.DynaKube, error) {
+	obj, gvk, err := serializer.NewCodecFactory(scheme.Scheme).UniversalDeserializer().Decode(manifest, nil, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to decode DynaKube manifest")
+	}
+
+	var dk *dynakube.DynaKube
+
+	switch typed := obj.(type) {
+	case *dynakube.DynaKube:
+		dk = typed
+	case conversion.Convertible:
+		dk = &dynakube.DynaKube{}
+
+		err = typed.ConvertTo(dk)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to convert DynaKube from %s", gvk.Version)
+		}
+	default:
+		return nil, errors.Errorf("manifest contains a %s, but a DynaKube is expected", gvk.Kind)
+	}
+
+	if dk.Namespace == "" {
+		dk.Namespace = namespace
+	}
+
+	return dk, nil
+}
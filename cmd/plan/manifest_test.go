@@ -0,0 +1,91 @@
+package plan
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNamespace = "dynatrace"
+	testName      = "dynakube"
+	testAPIURL    = "https://test.dev.dynatracelabs.com/api"
+)
+
+func TestReadManifest(t *testing.T) {
+	t.Run("from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dynakube.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("content"), 0600))
+
+		data, err := readManifest(nil, path)
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(data))
+	})
+	t.Run("from stdin", func(t *testing.T) {
+		data, err := readManifest(bytes.NewBufferString("content"), stdinFileName)
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(data))
+	})
+	t.Run("missing file", func(t *testing.T) {
+		_, err := readManifest(nil, filepath.Join(t.TempDir(), "missing.yaml"))
+		require.Error(t, err)
+	})
+}
+
+func TestDecodeDynakube(t *testing.T) {
+	t.Run("latest version", func(t *testing.T) {
+		manifest := `
+apiVersion: dynatrace.com/v1beta6
+kind: DynaKube
+metadata:
+  name: dynakube
+  namespace: other
+spec:
+  apiUrl: ` + testAPIURL + `
+  oneAgent:
+    hostMonitoring: {}
+`
+
+		dk, err := decodeDynakube([]byte(manifest), testNamespace)
+		require.NoError(t, err)
+		assert.Equal(t, "other", dk.Namespace)
+		assert.Equal(t, testAPIURL, dk.APIURL())
+		assert.True(t, dk.OneAgent().IsHostMonitoringMode())
+	})
+	t.Run("older version is converted", func(t *testing.T) {
+		manifest := `
+apiVersion: dynatrace.com/v1beta5
+kind: DynaKube
+metadata:
+  name: dynakube
+spec:
+  apiUrl: ` + testAPIURL + `
+  oneAgent:
+    cloudNativeFullStack: {}
+`
+
+		dk, err := decodeDynakube([]byte(manifest), testNamespace)
+		require.NoError(t, err)
+		assert.Equal(t, testNamespace, dk.Namespace)
+		assert.True(t, dk.OneAgent().IsCloudNativeFullstackMode())
+	})
+	t.Run("other kind", func(t *testing.T) {
+		manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dynakube
+`
+
+		_, err := decodeDynakube([]byte(manifest), testNamespace)
+		require.Error(t, err)
+	})
+	t.Run("invalid manifest", func(t *testing.T) {
+		_, err := decodeDynakube([]byte("not a manifest"), testNamespace)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,227 @@
+package plan
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
+	kspmdaemonset "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/kspm/daemonset"
+	logmondaemonset "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent"
+	otelcstatefulset "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/statefulset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/statefulset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubesystem"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the queries log every create/update, which would only clutter the printed plan
+var queryLog = logd.Logger{Logger: logr.Discard()}
+
+type action string
+
+const (
+	actionCreate    action = "create"
+	actionUpdate    action = "update"
+	actionRecreate  action = "recreate"
+	actionDelete    action = "delete"
+	actionUnchanged action = "unchanged"
+	// actionNone is used for objects of disabled components that don't exist, they are left out of the plan
+	actionNone action = "none"
+)
+
+type change struct {
+	err     error
+	action  action
+	kind    string
+	key     types.NamespacedName
+	current client.Object
+	planned client.Object
+}
+
+type query[T client.Object] interface {
+	Get(ctx context.Context, objectKey client.ObjectKey) (T, error)
+	Create(ctx context.Context, object T) error
+	Update(ctx context.Context, object T) error
+}
+
+type planner struct {
+	dryRunClient client.Client
+	apiReader    client.Reader
+	dk           *dynakube.DynaKube
+	clusterID    string
+	hasOwner     bool
+}
+
+// newPlanner prepares the DynaKube for building its objects, as the reconcilers expect a status that is filled by previous reconciles.
+// If the DynaKube already exists in the cluster, its status is used, otherwise only the values known without calling the Dynatrace API are set.
+func newPlanner(ctx context.Context, dryRunClient client.Client, apiReader client.Reader, dk *dynakube.DynaKube) (*planner, error) {
+	clusterID, err := kubesystem.GetUID(ctx, apiReader)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get cluster ID")
+	}
+
+	var currentDk dynakube.DynaKube
+
+	err = apiReader.Get(ctx, client.ObjectKeyFromObject(dk), &currentDk)
+
+	switch {
+	case k8serrors.IsNotFound(err):
+	case err != nil:
+		return nil, errors.WithMessagef(err, "failed to get DynaKube '%s:%s'", dk.Namespace, dk.Name)
+	default:
+		dk.UID = currentDk.UID
+		dk.Status = currentDk.Status
+	}
+
+	if dk.Status.KubeSystemUUID == "" {
+		dk.Status.KubeSystemUUID = string(clusterID)
+	}
+
+	return &planner{
+		dryRunClient: dryRunClient,
+		apiReader:    apiReader,
+		dk:           dk,
+		clusterID:    string(clusterID),
+		hasOwner:     dk.UID != "",
+	}, nil
+}
+
+func (p *planner) plan(ctx context.Context) []change {
+	return []change{
+		p.planDaemonSet(ctx, p.dk.OneAgent().GetDaemonsetName(), p.dk.OneAgent().IsDaemonsetRequired(), func() (*appsv1.DaemonSet, error) {
+			return oneagent.BuildDesiredDaemonSet(p.dk, p.clusterID)
+		}),
+		p.planStatefulSet(ctx, capability.CalculateStatefulSetName(p.dk.Name), capability.NewMultiCapability(p.dk).Enabled(), func() (*appsv1.StatefulSet, error) {
+			return activegate.BuildDesiredStatefulSet(ctx, p.apiReader, p.dk)
+		}),
+		p.planStatefulSet(ctx, p.dk.OtelCollectorStatefulsetName(), p.dk.Extensions().IsPrometheusEnabled() || p.dk.TelemetryIngest().IsEnabled(), func() (*appsv1.StatefulSet, error) {
+			return otelcstatefulset.NewReconciler(p.dryRunClient, p.apiReader, p.dk).BuildDesiredStatefulSet(ctx)
+		}),
+		p.planDaemonSet(ctx, p.dk.LogMonitoring().GetDaemonSetName(), p.dk.LogMonitoring().IsStandalone(), func() (*appsv1.DaemonSet, error) {
+			return logmondaemonset.NewReconciler(p.dryRunClient, p.apiReader, p.dk).BuildDesiredDaemonSet()
+		}),
+		p.planDaemonSet(ctx, p.dk.KSPM().GetDaemonSetName(), p.dk.KSPM().IsEnabled(), func() (*appsv1.DaemonSet, error) {
+			return kspmdaemonset.NewReconciler(p.dryRunClient, p.apiReader, p.dk).BuildDesiredDaemonSet()
+		}),
+	}
+}
+
+func (p *planner) planDaemonSet(ctx context.Context, name string, enabled bool, build func() (*appsv1.DaemonSet, error)) change {
+	q := daemonset.Query(p.dryRunClient, p.apiReader, queryLog)
+	if p.hasOwner {
+		q = q.WithOwner(p.dk)
+	}
+
+	desired := &appsv1.DaemonSet{}
+	if enabled {
+		var err error
+
+		desired, err = build()
+		if err != nil {
+			return p.failed("DaemonSet", name, err)
+		}
+	}
+
+	return planObject(ctx, p, "DaemonSet", name, enabled, q, q.IsEqual, q.MustRecreate, desired)
+}
+
+func (p *planner) planStatefulSet(ctx context.Context, name string, enabled bool, build func() (*appsv1.StatefulSet, error)) change {
+	q := statefulset.Query(p.dryRunClient, p.apiReader, queryLog)
+	if p.hasOwner {
+		q = q.WithOwner(p.dk)
+	}
+
+	desired := &appsv1.StatefulSet{}
+	if enabled {
+		var err error
+
+		desired, err = build()
+		if err != nil {
+			return p.failed("StatefulSet", name, err)
+		}
+	}
+
+	return planObject(ctx, p, "StatefulSet", name, enabled, q, q.IsEqual, q.MustRecreate, desired)
+}
+
+func (p *planner) failed(kind, name string, err error) change {
+	return change{
+		kind: kind,
+		key:  types.NamespacedName{Namespace: p.dk.Namespace, Name: name},
+		err:  errors.WithMessage(err, "failed to build desired object"),
+	}
+}
+
+// planObject determines the change of a single object in the same way as the CreateOrUpdate of the query does.
+// The create and update requests are sent as dry-run, so the planned object contains the defaults set by the API server.
+func planObject[T client.Object](ctx context.Context, p *planner, kind, name string, enabled bool, q query[T], isEqual, mustRecreate func(T, T) bool, desired T) change {
+	result := change{
+		kind: kind,
+		key:  types.NamespacedName{Namespace: p.dk.Namespace, Name: name},
+	}
+
+	current, err := q.Get(ctx, result.key)
+
+	exists := true
+	if k8serrors.IsNotFound(err) {
+		exists = false
+	} else if err != nil {
+		result.err = err
+
+		return result
+	}
+
+	switch {
+	case !enabled && !exists:
+		result.action = actionNone
+
+		return result
+	case !enabled:
+		result.action = actionDelete
+		result.current = current
+
+		return result
+	case !exists:
+		result.action = actionCreate
+		result.err = q.Create(ctx, desired)
+		result.planned = desired
+
+		return result
+	}
+
+	result.current = current
+
+	err = hasher.AddAnnotation(desired)
+	if err != nil {
+		result.err = err
+
+		return result
+	}
+
+	switch {
+	case isEqual(current, desired):
+		result.action = actionUnchanged
+	case mustRecreate(current, desired):
+		// a dry-run create would fail, because the current object still exists
+		result.action = actionRecreate
+		result.planned = desired
+	default:
+		desired.SetUID(current.GetUID())
+		desired.SetResourceVersion(current.GetResourceVersion())
+
+		result.action = actionUpdate
+		result.err = q.Update(ctx, desired)
+		result.planned = desired
+	}
+
+	return result
+}
@@ -0,0 +1,210 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
+	oneagentcontroller "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const testClusterID = "cluster-id"
+
+func newTestDynakube() *dynakube.DynaKube {
+	return &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: dynakube.DynaKubeSpec{
+			APIURL: testAPIURL,
+			OneAgent: oneagent.Spec{
+				HostMonitoring: &oneagent.HostInjectSpec{},
+			},
+		},
+	}
+}
+
+func newKubeSystemNamespace() *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: kubesystem.Namespace,
+			UID:  testClusterID,
+		},
+	}
+}
+
+func newTestPlanner(t *testing.T, dk *dynakube.DynaKube, objs ...client.Object) (*planner, client.Client) {
+	clt := fake.NewClient(append(objs, newKubeSystemNamespace())...)
+
+	p, err := newPlanner(context.Background(), client.NewDryRunClient(clt), clt, dk)
+	require.NoError(t, err)
+
+	return p, clt
+}
+
+func findChange(t *testing.T, changes []change, name string) change {
+	for _, c := range changes {
+		if c.key.Name == name {
+			return c
+		}
+	}
+
+	require.Failf(t, "change not found", "no change for %s", name)
+
+	return change{}
+}
+
+func TestNewPlanner(t *testing.T) {
+	t.Run("new dynakube => cluster ID is set, no owner", func(t *testing.T) {
+		p, _ := newTestPlanner(t, newTestDynakube())
+
+		assert.Equal(t, testClusterID, p.clusterID)
+		assert.Equal(t, testClusterID, p.dk.Status.KubeSystemUUID)
+		assert.False(t, p.hasOwner)
+	})
+	t.Run("existing dynakube => status is taken over", func(t *testing.T) {
+		current := newTestDynakube()
+		current.UID = "dk-uid"
+		current.Status.OneAgent.ConnectionInfoStatus.TenantUUID = "tenant"
+
+		p, _ := newTestPlanner(t, newTestDynakube(), current)
+
+		assert.True(t, p.hasOwner)
+		tenantUUID, err := p.dk.TenantUUID()
+		require.NoError(t, err)
+		assert.Equal(t, "tenant", tenantUUID)
+	})
+	t.Run("missing kube-system namespace => error", func(t *testing.T) {
+		clt := fake.NewClient()
+
+		_, err := newPlanner(context.Background(), clt, clt, newTestDynakube())
+		require.Error(t, err)
+	})
+}
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing daemonset => create, nothing written", func(t *testing.T) {
+		dk := newTestDynakube()
+		p, clt := newTestPlanner(t, dk)
+
+		changes := p.plan(ctx)
+
+		c := findChange(t, changes, dk.OneAgent().GetDaemonsetName())
+		require.NoError(t, c.err)
+		assert.Equal(t, actionCreate, c.action)
+		assert.NotNil(t, c.planned)
+
+		err := clt.Get(ctx, c.key, &appsv1.DaemonSet{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		agChange := findChange(t, changes, capability.CalculateStatefulSetName(dk.Name))
+		assert.Equal(t, actionNone, agChange.action)
+	})
+	t.Run("daemonset without changes => unchanged", func(t *testing.T) {
+		dk := newTestDynakube()
+		p, _ := newTestPlanner(t, dk)
+
+		current, err := oneagentcontroller.BuildDesiredDaemonSet(p.dk, p.clusterID)
+		require.NoError(t, err)
+
+		p, _ = newTestPlanner(t, newTestDynakube(), current)
+
+		c := findChange(t, p.plan(ctx), dk.OneAgent().GetDaemonsetName())
+		require.NoError(t, c.err)
+		assert.Equal(t, actionUnchanged, c.action)
+	})
+	t.Run("changed daemonset => update", func(t *testing.T) {
+		dk := newTestDynakube()
+		p, _ := newTestPlanner(t, dk)
+
+		current, err := oneagentcontroller.BuildDesiredDaemonSet(p.dk, p.clusterID)
+		require.NoError(t, err)
+
+		changedDk := newTestDynakube()
+		changedDk.Spec.OneAgent.HostMonitoring.PriorityClassName = "high"
+
+		p, clt := newTestPlanner(t, changedDk, current)
+
+		c := findChange(t, p.plan(ctx), dk.OneAgent().GetDaemonsetName())
+		require.NoError(t, c.err)
+		assert.Equal(t, actionUpdate, c.action)
+
+		var persisted appsv1.DaemonSet
+		require.NoError(t, clt.Get(ctx, c.key, &persisted))
+		assert.Empty(t, persisted.Spec.Template.Spec.PriorityClassName)
+	})
+	t.Run("disabled component with existing object => delete", func(t *testing.T) {
+		dk := newTestDynakube()
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      capability.CalculateStatefulSetName(dk.Name),
+				Namespace: testNamespace,
+			},
+		}
+
+		p, _ := newTestPlanner(t, dk, sts)
+
+		c := findChange(t, p.plan(ctx), sts.Name)
+		require.NoError(t, c.err)
+		assert.Equal(t, actionDelete, c.action)
+		assert.NotNil(t, c.current)
+		assert.Nil(t, c.planned)
+	})
+}
+
+func TestPrintChanges(t *testing.T) {
+	current := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            testName,
+			Namespace:       testNamespace,
+			ResourceVersion: "42",
+			Labels:          map[string]string{"version": "1"},
+		},
+	}
+	planned := current.DeepCopy()
+	planned.Labels["version"] = "2"
+
+	t.Run("diff of changed objects and summary", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := printChanges(out, []change{
+			{action: actionUpdate, kind: "DaemonSet", key: client.ObjectKeyFromObject(current), current: current, planned: planned},
+			{action: actionUnchanged, kind: "StatefulSet", key: client.ObjectKeyFromObject(current)},
+			{action: actionNone, kind: "StatefulSet", key: client.ObjectKeyFromObject(current)},
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, out.String(), "DaemonSet dynatrace/dynakube: update")
+		assert.Contains(t, out.String(), "--- current/dynatrace/dynakube")
+		assert.Contains(t, out.String(), "+++ planned/dynatrace/dynakube")
+		assert.Contains(t, out.String(), "-    version: \"1\"")
+		assert.Contains(t, out.String(), "+    version: \"2\"")
+		assert.Contains(t, out.String(), "kind: DaemonSet")
+		assert.NotContains(t, out.String(), "resourceVersion")
+		assert.Contains(t, out.String(), "Plan: 0 to create, 1 to update, 0 to recreate, 0 to delete, 1 unchanged.")
+	})
+	t.Run("failed changes are reported", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := printChanges(out, []change{
+			{err: assert.AnError, kind: "DaemonSet", key: client.ObjectKeyFromObject(current)},
+		})
+		require.Error(t, err)
+		assert.Contains(t, out.String(), "DaemonSet dynatrace/dynakube: failed")
+	})
+}
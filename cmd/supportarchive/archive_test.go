@@ -1,9 +1,12 @@
 package supportarchive
 
 import (
+	"archive/tar"
 	"archive/zip"
 	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"testing"
@@ -47,6 +50,38 @@ func TestAddFile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTarGzArchive(t *testing.T) {
+	buffer := bytes.Buffer{}
+
+	archive, err := newArchive(tarGzFormat, &buffer)
+	require.NoError(t, err)
+
+	testString := []byte(`Lorem ipsum dolor sit amet`)
+	require.NoError(t, archive.addFile("logs/lorem-ipsum.txt", bytes.NewReader(testString)))
+	require.NoError(t, archive.Close())
+
+	gzipReader, err := gzip.NewReader(&buffer)
+	require.NoError(t, err)
+
+	tarReader := tar.NewReader(gzipReader)
+
+	header, err := tarReader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "logs/lorem-ipsum.txt", header.Name)
+
+	content, err := io.ReadAll(tarReader)
+	require.NoError(t, err)
+	assert.Equal(t, testString, content)
+
+	_, err = tarReader.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestNewArchive(t *testing.T) {
+	_, err := newArchive("rar", &bytes.Buffer{})
+	require.Error(t, err)
+}
+
 func createZipArchiveFile(targetDir string) (*os.File, error) {
 	archiveFilePath := fmt.Sprintf(zipArchiveFileName, targetDir, time.Now().Format(time.RFC3339))
 	archiveFilePath = strings.ReplaceAll(archiveFilePath, ":", "_")
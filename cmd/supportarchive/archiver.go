@@ -1,13 +1,25 @@
 package supportarchive
 
 import (
+	"archive/tar"
+	"bytes"
 	"io"
+	"time"
 
+	"github.com/klauspost/compress/gzip"
 	"github.com/klauspost/compress/zip"
 	"github.com/pkg/errors"
 )
 
-const zipArchiveFileName = "%s/operator-support-archive-%s.zip"
+const (
+	zipArchiveFileName   = "%s/operator-support-archive-%s.zip"
+	tarGzArchiveFileName = "%s/operator-support-archive-%s.tar.gz"
+
+	zipFormat   = "zip"
+	tarGzFormat = "tar.gz"
+
+	tarFileMode = 0o644
+)
 
 type archiver interface {
 	addFile(fileName string, reader io.Reader) error
@@ -57,3 +69,61 @@ func (z zipArchive) Close() error {
 
 	return nil
 }
+
+func newTarGzArchive(target io.Writer) archiveCloser {
+	gzipWriter := gzip.NewWriter(target)
+
+	return tarGzArchive{gzipWriter: gzipWriter, writer: tar.NewWriter(gzipWriter)}
+}
+
+type tarGzArchive struct {
+	gzipWriter *gzip.Writer
+	writer     *tar.Writer
+}
+
+// addFile buffers the file, as the size has to be known before its header is written.
+func (t tarGzArchive) addFile(fileName string, reader io.Reader) error {
+	var content bytes.Buffer
+
+	_, err := io.Copy(&content, reader)
+	if err != nil {
+		return errors.WithMessagef(err, "could not read the file '%s' data", fileName)
+	}
+
+	err = t.writer.WriteHeader(&tar.Header{
+		Name:    fileName,
+		Mode:    tarFileMode,
+		Size:    int64(content.Len()),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return errors.WithMessagef(err, "could not create file '%s' in tar archive", fileName)
+	}
+
+	_, err = io.Copy(t.writer, &content)
+	if err != nil {
+		return errors.WithMessagef(err, "could not copy the file '%s' data to the tar archive", fileName)
+	}
+
+	return nil
+}
+
+func (t tarGzArchive) Close() error {
+	err := t.writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return t.gzipWriter.Close()
+}
+
+func newArchive(format string, target io.Writer) (archiveCloser, error) {
+	switch format {
+	case zipFormat:
+		return newZipArchive(target), nil
+	case tarGzFormat:
+		return newTarGzArchive(target), nil
+	default:
+		return nil, errors.Errorf("unknown archive format '%s', supported formats are '%s' and '%s'", format, zipFormat, tarGzFormat)
+	}
+}
@@ -37,7 +37,15 @@ const (
 	loadsimFileSizeFlagName        = "loadsim-file-size"
 	loadsimFilesFlagName           = "loadsim-files"
 	collectManagedLogsFlagName     = "managed-logs"
+	collectNodeDiagnosticsFlagName = "node-diagnostics"
 	numEventsFlagName              = "num-events"
+	outputFileFlagName             = "output-file"
+	formatFlagName                 = "format"
+	dynakubeFlagName               = "dynakube"
+	componentFlagName              = "component"
+	sinceFlagName                  = "since"
+	redactFlagName                 = "redact"
+	redactPatternFlagName          = "redact-pattern"
 	defaultSimFileSize             = 10
 	DefaultNumEvents               = 300
 )
@@ -49,13 +57,21 @@ const (
 )
 
 var (
-	namespaceFlagValue          string
-	archiveToStdoutFlagValue    bool
-	loadsimFilesFlagValue       int
-	loadsimFileSizeFlagValue    int
-	collectManagedLogsFlagValue bool
-	delayFlagValue              int
-	NumEventsFlagValue          int
+	namespaceFlagValue              string
+	archiveToStdoutFlagValue        bool
+	loadsimFilesFlagValue           int
+	loadsimFileSizeFlagValue        int
+	collectManagedLogsFlagValue     bool
+	collectNodeDiagnosticsFlagValue bool
+	delayFlagValue                  int
+	NumEventsFlagValue              int
+	outputFileFlagValue             string
+	formatFlagValue                 string
+	dynakubeFlagValue               []string
+	componentFlagValue              []string
+	sinceFlagValue                  time.Duration
+	redactFlagValue                 bool
+	redactPatternFlagValue          []string
 )
 
 func New() *cobra.Command {
@@ -64,19 +80,28 @@ func New() *cobra.Command {
 		Long: "Pack logs and manifests useful for troubleshooting into single tarball",
 		RunE: run(),
 		Args: func(cmd *cobra.Command, args []string) error {
-			if archiveToStdoutFlagValue {
+			if formatFlagValue != zipFormat && formatFlagValue != tarGzFormat {
+				return errors.Errorf("unknown archive format '%s', supported formats are '%s' and '%s'", formatFlagValue, zipFormat, tarGzFormat)
+			}
+
+			if archiveToStdoutFlagValue && outputFileFlagValue != "" {
+				return errors.Errorf("'--%s' and '--%s' can't be used together", archiveToStdoutFlagName, outputFileFlagName)
+			}
+
+			if archiveToStdoutFlagValue || outputFileFlagValue != "" {
 				return nil
 			}
 
 			sb := strings.Builder{}
-			sb.WriteString("The only option to retrieve the support archive is by using '--stdout=true'. ")
-			sb.WriteString("Please provide this parameter and make sure that you pipe the command output to a file. ")
+			sb.WriteString("The support archive is either retrieved by using '--stdout=true' or '--output-file <path>'. ")
+			sb.WriteString("When using '--stdout=true', make sure that you pipe the command output to a file. ")
 			sb.WriteString("Otherwise, your terminal will be flooded with binary data.")
 
 			return errors.New(sb.String())
 		},
 	}
 	addFlags(cmd)
+	cmd.AddCommand(newCSITreeCommand(), newCSIDiskUsageCommand())
 
 	return cmd
 }
@@ -87,8 +112,18 @@ func addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().IntVar(&loadsimFileSizeFlagValue, loadsimFileSizeFlagName, defaultSimFileSize, "Simulated log files, size in MiB (default 10)")
 	cmd.PersistentFlags().IntVar(&loadsimFilesFlagValue, loadsimFilesFlagName, 0, "Number of simulated log files (default 0)")
 	cmd.PersistentFlags().BoolVar(&collectManagedLogsFlagValue, collectManagedLogsFlagName, true, "Add logs from rolled out pods to the support archive.")
+	cmd.PersistentFlags().BoolVar(&collectNodeDiagnosticsFlagValue, collectNodeDiagnosticsFlagName, false,
+		fmt.Sprintf("Add the CSI driver data directory listing, oneagentctl output and the last %d OneAgent host logs (up to %d MiB each) of every node to the support archive.", maxHostLogFiles, maxHostLogFileSize/Mebi))
 	cmd.PersistentFlags().IntVar(&delayFlagValue, delayFlagName, 0, "Delay start of support-archive collection. Useful for standalone execution with 'kubectl run'")
 	cmd.PersistentFlags().IntVar(&NumEventsFlagValue, numEventsFlagName, DefaultNumEvents, fmt.Sprintf("Number of events to be fetched (default %d)", DefaultNumEvents))
+	cmd.PersistentFlags().StringVar(&outputFileFlagValue, outputFileFlagName, "", "Write the archive to this file. If it's a directory, a file with a generated name is created in it.")
+	cmd.PersistentFlags().StringVar(&formatFlagValue, formatFlagName, zipFormat, fmt.Sprintf("Format of the archive, one of '%s' or '%s'.", zipFormat, tarGzFormat))
+	cmd.PersistentFlags().StringSliceVar(&dynakubeFlagValue, dynakubeFlagName, nil, "Only collect the resources and logs of these DynaKubes.")
+	cmd.PersistentFlags().StringSliceVar(&componentFlagValue, componentFlagName, nil,
+		fmt.Sprintf("Only collect the resources and logs of these components, e.g. '%s', '%s' or '%s'.", labels.OneAgentComponentLabel, labels.ActiveGateComponentLabel, labels.WebhookComponentLabel))
+	cmd.PersistentFlags().DurationVar(&sinceFlagValue, sinceFlagName, 0, "Only collect the logs written within this duration, e.g. '2h'. All logs are collected by default.")
+	cmd.PersistentFlags().BoolVar(&redactFlagValue, redactFlagName, true, "Redact secrets, tokens and proxy credentials from the archive.")
+	cmd.PersistentFlags().StringArrayVar(&redactPatternFlagValue, redactPatternFlagName, nil, "Additional regular expression to redact from the archive, e.g. customer hostnames. Can be repeated.")
 }
 
 func run() func(*cobra.Command, []string) error {
@@ -100,13 +135,33 @@ func run() func(*cobra.Command, []string) error {
 		installconfig.ReadModulesToLogger(log)
 		version.LogVersionToLogger(log)
 
-		archiveTargetFile := os.Stdout
-		supportArchive := newZipArchive(archiveTargetFile)
+		archiveTargetFile, archiveTargetName, err := createArchiveTarget(outputFileFlagValue, formatFlagValue)
+		if err != nil {
+			return err
+		}
+
+		archive, err := newArchive(formatFlagValue, archiveTargetFile)
+		if err != nil {
+			return err
+		}
 
 		defer archiveTargetFile.Close()
-		defer supportArchive.Close()
+		defer archive.Close()
+
+		supportArchive := archiver(archive)
+
+		if redactFlagValue {
+			redactor, err := newRedactor(redactPatternFlagValue)
+			if err != nil {
+				return err
+			}
+
+			supportArchive = newRedactingArchive(archive, redactor)
+		}
+
+		logInfof(log, "writing support archive to %s", archiveTargetName)
 
-		err := runCollectors(log, supportArchive)
+		err = runCollectors(log, supportArchive)
 		if err != nil {
 			return err
 		}
@@ -118,6 +173,30 @@ func run() func(*cobra.Command, []string) error {
 	}
 }
 
+// createArchiveTarget opens the file the archive is written to, stdout is used if no output file is given.
+func createArchiveTarget(outputFile string, format string) (*os.File, string, error) {
+	if outputFile == "" {
+		return os.Stdout, "stdout", nil
+	}
+
+	fileInfo, err := os.Stat(outputFile)
+	if err == nil && fileInfo.IsDir() {
+		fileNamePattern := zipArchiveFileName
+		if format == tarGzFormat {
+			fileNamePattern = tarGzArchiveFileName
+		}
+
+		outputFile = fmt.Sprintf(fileNamePattern, outputFile, strings.ReplaceAll(time.Now().Format(time.RFC3339), ":", "_"))
+	}
+
+	targetFile, err := os.Create(outputFile)
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	return targetFile, outputFile, nil
+}
+
 func getAppNameLabel(ctx context.Context, pods clientgocorev1.PodInterface) string {
 	podName := os.Getenv(env.PodName)
 	if podName != "" {
@@ -167,6 +246,10 @@ func runCollectors(log logd.Logger, supportArchive archiver) error {
 		newLoadSimCollector(ctx, log, supportArchive, fileSize, loadsimFilesFlagValue, clientSet.CoreV1().Pods(namespaceFlagValue)),
 	}
 
+	if collectNodeDiagnosticsFlagValue {
+		collectors = append(collectors, newNodeDiagnosticsCollector(ctx, kubeConfig, &remotecommand.DefaultExecutor{}, log, supportArchive, pods, appName))
+	}
+
 	for _, c := range collectors {
 		if err := c.Do(); err != nil {
 			logErrorf(log, err, "%s failed", c.Name())
@@ -2,6 +2,8 @@ package supportarchive
 
 import (
 	"context"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
@@ -49,3 +51,35 @@ func TestStdoutArgMissing(t *testing.T) {
 	err := cmd.Execute()
 	require.Error(t, err)
 }
+
+func TestStdoutAndOutputFileExclusive(t *testing.T) {
+	cmd := New()
+	cmd.SetArgs([]string{"--stdout", "--output-file", t.TempDir()})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+}
+
+func TestCreateArchiveTarget(t *testing.T) {
+	t.Run("directory => generated file name", func(t *testing.T) {
+		dir := t.TempDir()
+
+		targetFile, name, err := createArchiveTarget(dir, tarGzFormat)
+		require.NoError(t, err)
+
+		defer targetFile.Close()
+
+		assert.True(t, strings.HasPrefix(name, dir+"/operator-support-archive-"))
+		assert.True(t, strings.HasSuffix(name, ".tar.gz"))
+	})
+	t.Run("file path is used as is", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "archive.zip")
+
+		targetFile, name, err := createArchiveTarget(path, zipFormat)
+		require.NoError(t, err)
+
+		defer targetFile.Close()
+
+		assert.Equal(t, path, name)
+	})
+}
@@ -6,6 +6,7 @@ const SupportArchiveOutputFileName = "supportarchive_console.log"
 const LogsDirectoryName = "logs"
 const ManifestsDirectoryName = "manifests"
 const InjectedNamespacesManifestsDirectoryName = "injected_namespaces"
+const NodesDirectoryName = "nodes"
 const CRDDirectoryName = "crds"
 const WebhookConfigurationsDirectoryName = "webhook_configurations"
 const ManifestsFileExtension = ".yaml"
@@ -0,0 +1,187 @@
+package supportarchive
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/api/resource"
+	mount "k8s.io/mount-utils"
+)
+
+const (
+	csiTreeUse      = "csi-tree"
+	csiDiskUsageUse = "csi-disk-usage"
+
+	// operatorBinary is used to run the CSI diagnostics in the CSI driver containers, their image has no find, grep or du
+	operatorBinary = "/usr/local/bin/dynatrace-operator"
+)
+
+// newCSITreeCommand is run in the CSI driver server container by the nodeDiagnosticsCollector and writes the listing of the data directory to stdout.
+func newCSITreeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    csiTreeUse,
+		Hidden: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			mounts, err := mount.New("").List()
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			return writeCSITree(os.Stdout, csiDataDirs(), mounts)
+		},
+	}
+}
+
+// newCSIDiskUsageCommand is run in the CSI driver server container by the nodeDiagnosticsCollector and writes the disk usage of the data directory to stdout.
+func newCSIDiskUsageCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    csiDiskUsageUse,
+		Hidden: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			mounts, err := mount.New("").List()
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			return writeCSIDiskUsage(os.Stdout, csiDataDirs(), mounts)
+		},
+	}
+}
+
+func csiDataDirs() []string {
+	pathResolver := metadata.PathResolver{RootDir: dtcsi.DataPath}
+
+	return []string{
+		pathResolver.AgentSharedBinaryDirBase(),
+		pathResolver.AppMountsBaseDir(),
+		pathResolver.DynaKubesBaseDir(),
+	}
+}
+
+// writeCSITree lists the directories up to csiTreeMaxDepth without descending into mounted volumes, followed by the mounts of the data directory.
+func writeCSITree(out io.Writer, dirs []string, mounts []mount.MountPoint) error {
+	mountPoints := collectMountPoints(mounts)
+
+	for _, dir := range dirs {
+		fmt.Fprintf(out, "== %s\n", dir)
+
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) && path == dir {
+				fmt.Fprintln(out, fileNotFoundMarker)
+
+				return nil
+			} else if err != nil {
+				fmt.Fprintf(out, "%s: %s\n", path, err)
+
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil //nolint:nilerr // files can be removed while walking
+			}
+
+			fmt.Fprintf(out, "%s %12d %s %s\n", info.Mode(), info.Size(), info.ModTime().Format("2006-01-02T15:04:05Z07:00"), path)
+
+			if entry.IsDir() && path != dir && (mountPoints[path] || strings.Count(strings.TrimPrefix(path, dir), string(filepath.Separator)) >= csiTreeMaxDepth) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	fmt.Fprintln(out, "== mounts")
+
+	for _, mountPoint := range mounts {
+		if strings.HasPrefix(mountPoint.Path, dtcsi.DataPath) || strings.Contains(strings.Join(mountPoint.Opts, ","), dtcsi.DataPath) {
+			fmt.Fprintf(out, "%s %s %s %s\n", mountPoint.Device, mountPoint.Path, mountPoint.Type, strings.Join(mountPoint.Opts, ","))
+		}
+	}
+
+	return nil
+}
+
+// writeCSIDiskUsage writes the capacity of the data directory and the size of every entry of the directories, mounted volumes are not counted.
+func writeCSIDiskUsage(out io.Writer, dirs []string, mounts []mount.MountPoint) error {
+	var stat unix.Statfs_t
+
+	if err := unix.Statfs(dtcsi.DataPath, &stat); err == nil {
+		fmt.Fprintf(out, "%s: total %s, available %s\n", dtcsi.DataPath,
+			formatBytes(int64(stat.Blocks)*int64(stat.Bsize)), //nolint:gosec
+			formatBytes(int64(stat.Bavail)*int64(stat.Bsize))) //nolint:gosec
+	} else {
+		fmt.Fprintf(out, "%s: %s\n", dtcsi.DataPath, err)
+	}
+
+	mountPoints := collectMountPoints(mounts)
+
+	for _, dir := range dirs {
+		fmt.Fprintf(out, "== %s\n", dir)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			fmt.Fprintln(out, fileNotFoundMarker)
+
+			continue
+		}
+
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			fmt.Fprintf(out, "%s %s\n", formatBytes(dirSizeWithoutMounts(path, mountPoints)), path)
+		}
+	}
+
+	return nil
+}
+
+func collectMountPoints(mounts []mount.MountPoint) map[string]bool {
+	mountPoints := make(map[string]bool, len(mounts))
+
+	for _, mountPoint := range mounts {
+		mountPoints[mountPoint.Path] = true
+	}
+
+	return mountPoints
+}
+
+// dirSizeWithoutMounts sums up the size of all files in the directory, like du -x it doesn't descend into mounted volumes.
+func dirSizeWithoutMounts(dir string, mountPoints map[string]bool) int64 {
+	var size int64
+
+	_ = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // files can be removed while walking
+		}
+
+		if entry.IsDir() && mountPoints[path] {
+			return filepath.SkipDir
+		}
+
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+
+		return nil
+	})
+
+	return size
+}
+
+func formatBytes(bytes int64) string {
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
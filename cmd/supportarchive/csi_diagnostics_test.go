@@ -0,0 +1,59 @@
+package supportarchive
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mount "k8s.io/mount-utils"
+)
+
+func createTestFile(t *testing.T, path string, size int) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0600))
+}
+
+func TestWriteCSITree(t *testing.T) {
+	dataDir := t.TempDir()
+	codeModulesDir := filepath.Join(dataDir, "codemodules")
+	appMountsDir := filepath.Join(dataDir, "appmounts")
+	mappedDir := filepath.Join(appMountsDir, "volume-1", "mapped")
+
+	createTestFile(t, filepath.Join(codeModulesDir, "1.2.3", "agent", "lib", "deep", "agent.so"), 10)
+	createTestFile(t, filepath.Join(mappedDir, "agent.so"), 10)
+
+	mounts := []mount.MountPoint{{Device: "overlay", Path: mappedDir, Type: "overlay", Opts: []string{"lowerdir=/data/codemodules/1.2.3"}}}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, writeCSITree(out, []string{codeModulesDir, appMountsDir, filepath.Join(dataDir, "missing")}, mounts))
+
+	assert.Contains(t, out.String(), "== "+codeModulesDir)
+	assert.Contains(t, out.String(), filepath.Join(codeModulesDir, "1.2.3", "agent", "lib"))
+	assert.NotContains(t, out.String(), "deep", "max depth is exceeded")
+	assert.Contains(t, out.String(), mappedDir)
+	assert.NotContains(t, out.String(), filepath.Join(mappedDir, "agent.so"), "mounted volumes are not listed")
+	assert.Contains(t, out.String(), "== "+filepath.Join(dataDir, "missing")+"\n"+fileNotFoundMarker)
+	assert.Contains(t, out.String(), "== mounts\noverlay "+mappedDir)
+}
+
+func TestWriteCSIDiskUsage(t *testing.T) {
+	dataDir := t.TempDir()
+	codeModulesDir := filepath.Join(dataDir, "codemodules")
+	appMountsDir := filepath.Join(dataDir, "appmounts")
+	mappedDir := filepath.Join(appMountsDir, "volume-1", "mapped")
+
+	createTestFile(t, filepath.Join(codeModulesDir, "1.2.3", "agent.so"), 2048)
+	createTestFile(t, filepath.Join(appMountsDir, "volume-1", "var", "log"), 1024)
+	createTestFile(t, filepath.Join(mappedDir, "agent.so"), 2048)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, writeCSIDiskUsage(out, []string{codeModulesDir, appMountsDir}, []mount.MountPoint{{Path: mappedDir}}))
+
+	assert.Contains(t, out.String(), "2Ki "+filepath.Join(codeModulesDir, "1.2.3"))
+	assert.Contains(t, out.String(), "1Ki "+filepath.Join(appMountsDir, "volume-1"), "mounted volumes are not counted")
+}
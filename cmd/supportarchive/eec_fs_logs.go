@@ -111,7 +111,13 @@ func (flc fsLogCollector) Do() error {
 		return err
 	}
 
+	filter := getArchiveFilter()
+
 	for _, eecPod := range eecPodList.Items {
+		if !filter.includesPod(eecPod) {
+			continue
+		}
+
 		logFiles, err := flc.findLogFilesRecursively(eecPod.Name, eecPod.Namespace, eecExtensionsPath)
 		if err != nil {
 			logErrorf(flc.log, err, "log files lookup failed, podName: %s", eecPod.Name)
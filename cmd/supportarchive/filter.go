@@ -0,0 +1,82 @@
+package supportarchive
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	webhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const dynakubeKind = "DynaKube"
+
+// archiveFilter limits the support archive to the selected DynaKubes and components, an empty selection includes everything.
+type archiveFilter struct {
+	now        func() time.Time
+	dynakubes  []string
+	components []string
+	since      time.Duration
+}
+
+func getArchiveFilter() archiveFilter {
+	return archiveFilter{
+		dynakubes:  dynakubeFlagValue,
+		components: componentFlagValue,
+		since:      sinceFlagValue,
+		now:        time.Now,
+	}
+}
+
+func (filter archiveFilter) matchesDynakube(name string) bool {
+	return len(filter.dynakubes) == 0 || slices.Contains(filter.dynakubes, name)
+}
+
+func (filter archiveFilter) matchesComponent(name string) bool {
+	return len(filter.components) == 0 || slices.Contains(filter.components, name)
+}
+
+// includesObject checks the DynaKube and component an object belongs to, objects which belong to neither are always included.
+func (filter archiveFilter) includesObject(resource unstructured.Unstructured) bool {
+	if resource.GetKind() == dynakubeKind {
+		return filter.matchesDynakube(resource.GetName())
+	}
+
+	return filter.includesLabels(resource.GetLabels())
+}
+
+func (filter archiveFilter) includesPod(pod corev1.Pod) bool {
+	return filter.includesLabels(pod.Labels)
+}
+
+func (filter archiveFilter) includesLabels(objectLabels map[string]string) bool {
+	if dkName, ok := objectLabels[webhook.InjectionInstanceLabel]; ok && !filter.matchesDynakube(dkName) {
+		return false
+	}
+
+	// only the components managed by the operator are created by a DynaKube, the operator components are created by helm
+	if _, managed := objectLabels[labels.AppManagedByLabel]; managed {
+		if dkName, ok := objectLabels[labels.AppCreatedByLabel]; ok && !filter.matchesDynakube(dkName) {
+			return false
+		}
+	}
+
+	if component, ok := objectLabels[labels.AppComponentLabel]; ok && !filter.matchesComponent(component) {
+		return false
+	}
+
+	return true
+}
+
+// sinceTime is passed to the log requests, so only the logs of the selected time window are collected.
+func (filter archiveFilter) sinceTime() *metav1.Time {
+	if filter.since <= 0 {
+		return nil
+	}
+
+	sinceTime := metav1.NewTime(filter.now().Add(-filter.since))
+
+	return &sinceTime
+}
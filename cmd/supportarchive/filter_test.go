@@ -0,0 +1,66 @@
+package supportarchive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	webhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestUnstructured(kind, name string, objectLabels map[string]string) unstructured.Unstructured {
+	resource := unstructured.Unstructured{}
+	resource.SetKind(kind)
+	resource.SetName(name)
+	resource.SetLabels(objectLabels)
+
+	return resource
+}
+
+func TestArchiveFilter(t *testing.T) {
+	filter := archiveFilter{dynakubes: []string{"dynakube"}, components: []string{labels.ActiveGateComponentLabel}}
+
+	t.Run("empty filter includes everything", func(t *testing.T) {
+		assert.True(t, archiveFilter{}.includesObject(newTestUnstructured(dynakubeKind, "other", nil)))
+		assert.True(t, archiveFilter{}.includesPod(corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{labels.AppComponentLabel: "oneagent"}}}))
+	})
+	t.Run("dynakubes are filtered by name", func(t *testing.T) {
+		assert.True(t, filter.includesObject(newTestUnstructured(dynakubeKind, "dynakube", nil)))
+		assert.False(t, filter.includesObject(newTestUnstructured(dynakubeKind, "other", nil)))
+	})
+	t.Run("injected namespaces are filtered by dynakube", func(t *testing.T) {
+		assert.True(t, filter.includesObject(newTestUnstructured("Namespace", "app", map[string]string{webhook.InjectionInstanceLabel: "dynakube"})))
+		assert.False(t, filter.includesObject(newTestUnstructured("Namespace", "app", map[string]string{webhook.InjectionInstanceLabel: "other"})))
+	})
+	t.Run("managed components are filtered by dynakube and component", func(t *testing.T) {
+		managedLabels := func(dk, component string) map[string]string {
+			return map[string]string{
+				labels.AppManagedByLabel: "dynatrace-operator",
+				labels.AppCreatedByLabel: dk,
+				labels.AppComponentLabel: component,
+			}
+		}
+
+		assert.True(t, filter.includesObject(newTestUnstructured("StatefulSet", "ag", managedLabels("dynakube", labels.ActiveGateComponentLabel))))
+		assert.False(t, filter.includesObject(newTestUnstructured("StatefulSet", "ag", managedLabels("other", labels.ActiveGateComponentLabel))))
+		assert.False(t, filter.includesObject(newTestUnstructured("DaemonSet", "oa", managedLabels("dynakube", labels.OneAgentComponentLabel))))
+	})
+	t.Run("objects without labels are included", func(t *testing.T) {
+		assert.True(t, filter.includesObject(newTestUnstructured("ConfigMap", "config", nil)))
+	})
+}
+
+func TestArchiveFilterSinceTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, archiveFilter{}.sinceTime())
+
+	sinceTime := archiveFilter{since: 2 * time.Hour, now: func() time.Time { return now }}.sinceTime()
+	require.NotNil(t, sinceTime)
+	assert.Equal(t, now.Add(-2*time.Hour), sinceTime.Time)
+}
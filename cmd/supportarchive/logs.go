@@ -62,13 +62,18 @@ func (lc logCollector) Do() error {
 	}
 
 	podGetOptions := metav1.GetOptions{}
+	filter := getArchiveFilter()
 
 	for _, podItem := range podList.Items {
+		if !filter.includesPod(podItem) {
+			continue
+		}
+
 		pod, err := lc.pods.Get(lc.ctx, podItem.Name, podGetOptions)
 		if err != nil {
 			logErrorf(lc.log, err, "Unable to get pod info for %s", podItem.Name)
 		} else {
-			lc.collectPodLogs(pod)
+			lc.collectPodLogs(pod, filter.sinceTime())
 		}
 	}
 
@@ -95,11 +100,12 @@ func (lc logCollector) getPodList(labelKey string) (*corev1.PodList, error) {
 	return podList, nil
 }
 
-func (lc logCollector) collectPodLogs(pod *corev1.Pod) {
+func (lc logCollector) collectPodLogs(pod *corev1.Pod, sinceTime *metav1.Time) {
 	for _, container := range pod.Spec.Containers {
 		podLogOpts := corev1.PodLogOptions{
 			Container: container.Name,
 			Follow:    false,
+			SinceTime: sinceTime,
 		}
 		lc.collectContainerLogs(pod, container, podLogOpts)
 
@@ -0,0 +1,214 @@
+package supportarchive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/cmd/supportarchive/remotecommand"
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	clientgocorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	nodeDiagnosticsCollectorName = "nodeDiagnosticsCollector"
+
+	csiDriverComponentLabel = "csi-driver"
+	csiServerContainerName  = "server"
+	oneAgentContainerName   = "dynatrace-oneagent"
+
+	// the OneAgent pods mount the root of the host to /mnt/root
+	hostRootDir        = "/mnt/root"
+	oneAgentHostLogDir = hostRootDir + "/var/log/dynatrace"
+	oneAgentCtlPath    = hostRootDir + "/opt/dynatrace/oneagent/agent/tools/oneagentctl"
+
+	csiTreeFileName       = "csi-tree.txt"
+	csiDiskUsageFileName  = "csi-disk-usage.txt"
+	oneAgentCtlFileName   = "oneagentctl.txt"
+	csiTreeMaxDepth       = 3
+	shell                 = "/usr/bin/sh"
+	oneAgentCtlGetOptions = "--get-server --get-tenant --get-host-id --get-host-group --get-network-zone --get-proxy --get-auto-update-enabled --get-host-properties"
+
+	// only the most recently written host logs are collected and each of them is cut to its end, so the archive stays small on busy nodes
+	maxHostLogFiles    = 20
+	maxHostLogFileSize = 10 * Mebi
+)
+
+// nodeDiagnosticsCollector collects the state of the CSI driver data directory and the OneAgent host files of every node,
+// so stuck mounts and failing host agents can be analyzed without access to the nodes.
+type nodeDiagnosticsCollector struct {
+	ctx                   context.Context
+	pods                  clientgocorev1.PodInterface
+	remoteCommandExecutor remotecommand.Executor
+	config                *rest.Config
+	collectorCommon
+	appName string
+}
+
+func newNodeDiagnosticsCollector(context context.Context, config *rest.Config, command remotecommand.Executor, log logd.Logger, supportArchive archiver, pods clientgocorev1.PodInterface, appName string) collector { //nolint:revive
+	return nodeDiagnosticsCollector{
+		collectorCommon: collectorCommon{
+			log:            log,
+			supportArchive: supportArchive,
+		},
+		ctx:                   context,
+		config:                config,
+		pods:                  pods,
+		appName:               appName,
+		remoteCommandExecutor: command,
+	}
+}
+
+func (ndc nodeDiagnosticsCollector) Name() string {
+	return nodeDiagnosticsCollectorName
+}
+
+func (ndc nodeDiagnosticsCollector) Do() error {
+	if !installconfig.GetModules().Supportability {
+		logInfof(ndc.log, "%s", installconfig.GetModuleValidationErrorMessage("Node Diagnostics Collection"))
+
+		return nil
+	}
+
+	logInfof(ndc.log, "Starting node diagnostics collection")
+
+	filter := getArchiveFilter()
+
+	csiPods, err := ndc.getPodList(map[string]string{
+		labels.AppNameLabel:      ndc.appName,
+		labels.AppComponentLabel: csiDriverComponentLabel,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, csiPod := range csiPods.Items {
+		if filter.includesPod(csiPod) {
+			ndc.collectCSIDiagnostics(csiPod)
+		}
+	}
+
+	oneAgentPods, err := ndc.getPodList(map[string]string{
+		labels.AppManagedByLabel: ndc.appName,
+		labels.AppComponentLabel: labels.OneAgentComponentLabel,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, oneAgentPod := range oneAgentPods.Items {
+		if filter.includesPod(oneAgentPod) {
+			ndc.collectOneAgentDiagnostics(oneAgentPod, filter)
+		}
+	}
+
+	return nil
+}
+
+func (ndc nodeDiagnosticsCollector) getPodList(matchLabels map[string]string) (*corev1.PodList, error) {
+	podList, err := ndc.pods.List(ndc.ctx, metav1.ListOptions{
+		LabelSelector: apilabels.Set(matchLabels).String(),
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return podList, nil
+}
+
+// collectCSIDiagnostics runs the hidden CSI diagnostics commands of the operator binary, as the CSI driver image has no shell tools to list the data directory.
+func (ndc nodeDiagnosticsCollector) collectCSIDiagnostics(pod corev1.Pod) {
+	ndc.collectCommandOutput(pod, csiServerContainerName, []string{operatorBinary, use, csiTreeUse}, csiTreeFileName)
+	ndc.collectCommandOutput(pod, csiServerContainerName, []string{operatorBinary, use, csiDiskUsageUse}, csiDiskUsageFileName)
+}
+
+func (ndc nodeDiagnosticsCollector) collectOneAgentDiagnostics(pod corev1.Pod, filter archiveFilter) {
+	ndc.collectCommandOutput(pod, oneAgentContainerName, shellCommand(buildOneAgentCtlCommand()), path.Join(pod.Name, oneAgentCtlFileName))
+
+	stdOut, err := ndc.exec(pod, oneAgentContainerName, shellCommand(buildFindLogFilesCommand(oneAgentHostLogDir, filter)))
+	if err != nil {
+		logErrorf(ndc.log, err, "log files lookup failed, podName: %s", pod.Name)
+
+		return
+	}
+
+	for _, logFilePath := range strings.Split(stdOut.String(), "\n") {
+		logFilePath = strings.TrimSpace(logFilePath)
+		if logFilePath == "" || logFilePath == fileNotFoundMarker {
+			continue
+		}
+
+		fileName := path.Join(pod.Name, strings.TrimPrefix(logFilePath, hostRootDir))
+		ndc.collectCommandOutput(pod, oneAgentContainerName, shellCommand(buildReadLogFileCommand(logFilePath)), fileName)
+	}
+}
+
+func (ndc nodeDiagnosticsCollector) collectCommandOutput(pod corev1.Pod, containerName string, command []string, fileName string) {
+	stdOut, err := ndc.exec(pod, containerName, command)
+	if err != nil {
+		logErrorf(ndc.log, err, "failed to collect %s from pod: %s", fileName, pod.Name)
+
+		return
+	}
+
+	zipFilePath := BuildNodeFilePath(pod.Spec.NodeName, fileName)
+
+	err = ndc.supportArchive.addFile(zipFilePath, stdOut)
+	if err != nil {
+		logErrorf(ndc.log, err, "error writing to tarball")
+
+		return
+	}
+
+	logInfof(ndc.log, "Successfully collected node diagnostics %s", zipFilePath)
+}
+
+func (ndc nodeDiagnosticsCollector) exec(pod corev1.Pod, containerName string, command []string) (*bytes.Buffer, error) {
+	stdOut, stdErr, err := ndc.remoteCommandExecutor.Exec(ndc.ctx, ndc.config, pod.Name, pod.Namespace, containerName, command)
+	if err != nil {
+		if stdErr != nil && stdErr.Len() > 0 {
+			return nil, errors.Wrap(err, stdErr.String())
+		}
+
+		return nil, err
+	}
+
+	return stdOut, nil
+}
+
+func shellCommand(command string) []string {
+	return []string{shell, "-c", command}
+}
+
+func buildOneAgentCtlCommand() string {
+	return fmt.Sprintf("if [ -x '%[1]s' ]; then for option in %[2]s; do echo \"$option: $('%[1]s' $option 2>&1)\"; done; else echo '%[3]s'; fi", oneAgentCtlPath, oneAgentCtlGetOptions, fileNotFoundMarker)
+}
+
+// buildFindLogFilesCommand finds the most recently written log files of the host agent, only the ones written within the time window of the filter.
+func buildFindLogFilesCommand(dir string, filter archiveFilter) string {
+	modifiedWithin := ""
+	if filter.since > 0 {
+		modifiedWithin = fmt.Sprintf(" -mmin -%d", int(filter.since.Minutes())+1)
+	}
+
+	return fmt.Sprintf("if [ -d '%[1]s' ]; then find '%[1]s' -type f -name '*.log'%[2]s -exec ls -t {} + | head -n %[3]d; else echo '%[4]s'; fi",
+		dir, modifiedWithin, maxHostLogFiles, fileNotFoundMarker)
+}
+
+// buildReadLogFileCommand reads the end of the log file, the most recent entries are the relevant ones.
+func buildReadLogFileCommand(logFilePath string) string {
+	return fmt.Sprintf("tail -c %d '%s'", maxHostLogFileSize, logFilePath)
+}
+
+func BuildNodeFilePath(nodeName string, fileName string) string {
+	return fmt.Sprintf("%s/%s/%s", NodesDirectoryName, nodeName, fileName)
+}
@@ -0,0 +1,112 @@
+package supportarchive
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	mocks "github.com/Dynatrace/dynatrace-operator/test/mocks/cmd/supportarchive/remotecommand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	testNodeName        = "node-1"
+	testCSIPodName      = "dynatrace-oneagent-csi-driver-abcde"
+	testOneAgentPodName = "dynakube-oneagent-abcde"
+	testOneAgentLogFile = oneAgentHostLogDir + "/oneagent/os/ruxitagent_host_1.log"
+)
+
+func newTestNodePod(name string, podLabels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testOperatorNamespace,
+			Labels:    podLabels,
+		},
+		Spec: corev1.PodSpec{NodeName: testNodeName},
+	}
+}
+
+func mockExec(rce *mocks.Executor, podName, containerName string, command []string, stdOut string) {
+	rce.On("Exec", mock.Anything, mock.Anything, podName, testOperatorNamespace, containerName, command).
+		Return(bytes.NewBufferString(stdOut), &bytes.Buffer{}, nil)
+}
+
+func TestNodeDiagnosticsCollector(t *testing.T) {
+	fakeClientSet := fake.NewSimpleClientset(
+		newTestNodePod(testCSIPodName, map[string]string{
+			labels.AppNameLabel:      defaultOperatorAppName,
+			labels.AppComponentLabel: csiDriverComponentLabel,
+		}),
+		newTestNodePod(testOneAgentPodName, map[string]string{
+			labels.AppManagedByLabel: defaultOperatorAppName,
+			labels.AppComponentLabel: labels.OneAgentComponentLabel,
+		}),
+	)
+
+	rce := mocks.NewExecutor(t)
+
+	mockExec(rce, testCSIPodName, csiServerContainerName, []string{operatorBinary, use, csiTreeUse}, "== /data/codemodules\n")
+	mockExec(rce, testCSIPodName, csiServerContainerName, []string{operatorBinary, use, csiDiskUsageUse}, "1.2Gi /data/codemodules/1.2.3\n")
+	mockExec(rce, testOneAgentPodName, oneAgentContainerName, shellCommand(buildOneAgentCtlCommand()), "--get-host-id: 123\n")
+	mockExec(rce, testOneAgentPodName, oneAgentContainerName, shellCommand(buildFindLogFilesCommand(oneAgentHostLogDir, archiveFilter{})), testOneAgentLogFile+"\n")
+	mockExec(rce, testOneAgentPodName, oneAgentContainerName, shellCommand(buildReadLogFileCommand(testOneAgentLogFile)), "host agent started\n")
+
+	logBuffer := bytes.Buffer{}
+	buffer := bytes.Buffer{}
+	supportArchive := newZipArchive(bufio.NewWriter(&buffer))
+
+	collector := newNodeDiagnosticsCollector(context.Background(),
+		nil,
+		rce,
+		newSupportArchiveLogger(&logBuffer),
+		supportArchive,
+		fakeClientSet.CoreV1().Pods(testOperatorNamespace),
+		defaultOperatorAppName)
+
+	require.NoError(t, collector.Do())
+	require.NoError(t, supportArchive.Close())
+
+	zipReader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.NoError(t, err)
+
+	fileNames := make([]string, 0, len(zipReader.File))
+	for _, file := range zipReader.File {
+		fileNames = append(fileNames, file.Name)
+	}
+
+	assert.Equal(t, []string{
+		"nodes/node-1/csi-tree.txt",
+		"nodes/node-1/csi-disk-usage.txt",
+		"nodes/node-1/dynakube-oneagent-abcde/oneagentctl.txt",
+		"nodes/node-1/dynakube-oneagent-abcde/var/log/dynatrace/oneagent/os/ruxitagent_host_1.log",
+	}, fileNames)
+
+	f, err := zipReader.Open("nodes/node-1/dynakube-oneagent-abcde/var/log/dynatrace/oneagent/os/ruxitagent_host_1.log")
+	require.NoError(t, err)
+
+	contents, err := io.ReadAll(f)
+	require.NoError(t, err)
+	f.Close()
+	assert.Equal(t, "host agent started\n", string(contents))
+}
+
+func TestBuildFindLogFilesCommand(t *testing.T) {
+	assert.NotContains(t, buildFindLogFilesCommand(oneAgentHostLogDir, archiveFilter{}), "-mmin")
+	assert.Contains(t, buildFindLogFilesCommand(oneAgentHostLogDir, archiveFilter{since: 2 * time.Hour}), "-mmin -121")
+	assert.Contains(t, buildFindLogFilesCommand(oneAgentHostLogDir, archiveFilter{}), "head -n 20")
+}
+
+func TestBuildReadLogFileCommand(t *testing.T) {
+	assert.Equal(t, "tail -c 10485760 '"+testOneAgentLogFile+"'", buildReadLogFileCommand(testOneAgentLogFile))
+}
@@ -0,0 +1,180 @@
+package supportarchive
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	redactedValue = "<REDACTED>"
+	secretKind    = "Secret"
+)
+
+type redactionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// defaultRedactionRules scrub the credentials, which may end up in logs and manifests.
+var defaultRedactionRules = []redactionRule{
+	// Dynatrace API, PaaS and data ingest tokens
+	{pattern: regexp.MustCompile(`dt0[a-z][0-9]{2}\.[A-Za-z0-9]+(\.[A-Za-z0-9]+)?`), replacement: redactedValue},
+	// credentials in proxy and other URLs
+	{pattern: regexp.MustCompile(`(://)[^/\s:@]+:[^/\s@]+@`), replacement: "${1}" + redactedValue + "@"},
+	// authorization headers
+	{pattern: regexp.MustCompile(`(?i)((?:bearer|api-token|basic)\s+)[A-Za-z0-9._~+/=-]+`), replacement: "${1}" + redactedValue},
+}
+
+// sensitiveKeys are the fields of manifests, which are redacted regardless of their value.
+var sensitiveKeys = map[string]bool{
+	"apitoken":        true,
+	"paastoken":       true,
+	"dataingesttoken": true,
+	"token":           true,
+	"password":        true,
+	"auth":            true,
+}
+
+// sensitiveEnvNameMarkers are checked against the names of env vars, their values are redacted.
+var sensitiveEnvNameMarkers = []string{"TOKEN", "PASSWORD", "SECRET", "PROXY"}
+
+type redactor struct {
+	rules []redactionRule
+}
+
+// newRedactor combines the default rules with the user configured patterns, which are replaced completely.
+func newRedactor(userPatterns []string) (*redactor, error) {
+	rules := append([]redactionRule{}, defaultRedactionRules...)
+
+	for _, userPattern := range userPatterns {
+		pattern, err := regexp.Compile(userPattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid redaction pattern '%s'", userPattern)
+		}
+
+		rules = append(rules, redactionRule{pattern: pattern, replacement: redactedValue})
+	}
+
+	return &redactor{rules: rules}, nil
+}
+
+func (r *redactor) redactText(text []byte) []byte {
+	for _, rule := range r.rules {
+		text = rule.pattern.ReplaceAll(text, []byte(rule.replacement))
+	}
+
+	return text
+}
+
+// redactReader redacts the content line by line, so large logs don't have to be kept in memory.
+// The returned reader has to be closed, otherwise the redacting goroutine leaks.
+func (r *redactor) redactReader(reader io.Reader) io.ReadCloser {
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		bufferedReader := bufio.NewReader(reader)
+
+		for {
+			line, err := bufferedReader.ReadBytes('\n')
+			if len(line) > 0 {
+				_, writeErr := pipeWriter.Write(r.redactText(line))
+				if writeErr != nil {
+					pipeWriter.CloseWithError(writeErr)
+
+					return
+				}
+			}
+
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+
+				pipeWriter.CloseWithError(err)
+
+				return
+			}
+		}
+	}()
+
+	return pipeReader
+}
+
+// redactingArchive redacts every file added to the support archive.
+type redactingArchive struct {
+	archive  archiver
+	redactor *redactor
+}
+
+func newRedactingArchive(archive archiver, redactor *redactor) archiver {
+	return redactingArchive{archive: archive, redactor: redactor}
+}
+
+func (r redactingArchive) addFile(fileName string, reader io.Reader) error {
+	redacted := r.redactor.redactReader(reader)
+	defer redacted.Close()
+
+	return r.archive.addFile(fileName, redacted)
+}
+
+// redactObject scrubs the data of secrets, the sensitive fields and the values of sensitive env vars of a manifest.
+func redactObject(object map[string]any) {
+	if object["kind"] == secretKind {
+		for _, field := range []string{"data", "stringData"} {
+			if data, ok := object[field].(map[string]any); ok {
+				for key := range data {
+					data[key] = redactedValue
+				}
+			}
+		}
+	}
+
+	redactValue(object)
+}
+
+func redactValue(value any) {
+	switch typed := value.(type) {
+	case map[string]any:
+		if isSensitiveEnvVar(typed) {
+			typed["value"] = redactedValue
+		}
+
+		for key, nested := range typed {
+			if _, isString := nested.(string); isString && sensitiveKeys[strings.ToLower(key)] {
+				typed[key] = redactedValue
+
+				continue
+			}
+
+			redactValue(nested)
+		}
+	case []any:
+		for _, nested := range typed {
+			redactValue(nested)
+		}
+	}
+}
+
+func isSensitiveEnvVar(envVar map[string]any) bool {
+	name, ok := envVar["name"].(string)
+	if !ok {
+		return false
+	}
+
+	if _, hasValue := envVar["value"].(string); !hasValue {
+		return false
+	}
+
+	name = strings.ToUpper(name)
+	for _, marker := range sensitiveEnvNameMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -59,7 +59,13 @@ func (collector k8sResourceCollector) Do() error {
 			continue
 		}
 
+		filter := getArchiveFilter()
+
 		for _, resource := range resourceList.Items {
+			if !filter.includesObject(resource) {
+				continue
+			}
+
 			numberOfStorages++
 
 			collector.storeObject(resource)
@@ -206,6 +212,10 @@ func (collector k8sResourceCollector) getMutatingWebhookConfiguration(mutatingWe
 }
 
 func (collector k8sResourceCollector) storeObject(resource unstructured.Unstructured) {
+	if redactFlagValue {
+		redactObject(resource.Object)
+	}
+
 	yamlManifest, err := yaml.Marshal(resource)
 	if err != nil {
 		logErrorf(collector.log, err, "Failed to marshal %s %s/%s", resource.GetKind(), collector.namespace, resource.GetName())
@@ -2,8 +2,12 @@ package troubleshoot
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
@@ -14,6 +18,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/version"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -26,20 +31,40 @@ const (
 	dynakubeFlagShorthand  = "d"
 	namespaceFlagName      = "namespace"
 	namespaceFlagShorthand = "n"
+	outputFlagName         = "output"
+	outputFlagShorthand    = "o"
+	networkNodeFlagName    = "network-node"
+	skipNetworkFlagName    = "skip-network-checks"
+
+	outputText  = "text"
+	outputJSON  = "json"
+	outputJUnit = "junit"
 )
 
+// ErrReportFailed is returned if checks of a structured report failed, the failures are already part of the report, so it must not be logged to stdout.
+var ErrReportFailed = errors.New("troubleshoot checks failed")
+
 var (
 	dynakubeFlagValue  string
 	namespaceFlagValue string
+	outputFlagValue    string
+
+	networkNodeFlagValue string
+	skipNetworkFlagValue bool
 )
 
 func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:  use,
 		RunE: run(),
+		// failed checks are part of the report, the usage would only hide them
+		SilenceUsage:  true,
+		SilenceErrors: true,
 	}
 
 	addFlags(cmd)
+	cmd.AddCommand(newNetworkProbeCommand())
+	cmd.AddCommand(newPodCommand())
 
 	return cmd
 }
@@ -47,6 +72,11 @@ func New() *cobra.Command {
 func addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&dynakubeFlagValue, dynakubeFlagName, dynakubeFlagShorthand, "", "Specify a different Dynakube name.")
 	cmd.PersistentFlags().StringVarP(&namespaceFlagValue, namespaceFlagName, namespaceFlagShorthand, env.DefaultNamespace(), "Specify a different Namespace.")
+	cmd.PersistentFlags().StringVarP(&outputFlagValue, outputFlagName, outputFlagShorthand, outputText,
+		fmt.Sprintf("Output format, one of '%s', '%s' or '%s'. Structured formats are written to stdout, the log to stderr.", outputText, outputJSON, outputJUnit))
+	cmd.Flags().StringVar(&networkNodeFlagValue, networkNodeFlagName, "",
+		fmt.Sprintf("Check the network from the given node. A temporary secret and pod with the prefix '%s' are created in the namespace of the operator for it.", networkProbeNamePrefix))
+	cmd.Flags().BoolVar(&skipNetworkFlagValue, skipNetworkFlagName, false, "Skip the checks of the network endpoints.")
 }
 
 func clusterOptions(opts *cluster.Options) {
@@ -55,50 +85,170 @@ func clusterOptions(opts *cluster.Options) {
 
 func run() func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		version.LogVersion()
-		logd.LogBaseLoggerSettings()
+		writeReport, err := getReportWriter(outputFlagValue)
+		if err != nil {
+			return err
+		}
+
+		logOut := io.Writer(os.Stdout)
+
+		if writeReport != nil {
+			// stdout must only contain the report, so it can be processed by other tools, this includes the logs of the dtclient
+			logOut = os.Stderr
+			logd.SetBaseLoggerOutput(os.Stderr)
+		} else {
+			version.LogVersion()
+			logd.LogBaseLoggerSettings()
+		}
 
 		kubeConfig, err := config.GetConfig()
 		if err != nil {
 			return err
 		}
 
-		log := NewTroubleshootLoggerToWriter(os.Stdout)
+		log := NewTroubleshootLoggerToWriter(logOut)
+
+		// on interrupts the checks are canceled, so the network probe pod and secret are still deleted
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
-		RunTroubleshootCmd(cmd.Context(), log, namespaceFlagValue, kubeConfig)
+		report := RunTroubleshootCmd(ctx, log, namespaceFlagValue, kubeConfig)
+
+		return finishReport(writeReport, report)
+	}
+}
+
+// finishReport writes the report in the structured formats to stdout and fails if any check failed.
+func finishReport(writeReport func(io.Writer, *Report) error, report *Report) error {
+	return finishReportTo(os.Stdout, writeReport, report)
+}
+
+func finishReportTo(out io.Writer, writeReport func(io.Writer, *Report) error, report *Report) error {
+	if writeReport == nil {
+		if failed := report.FailedCount(); failed > 0 {
+			return errors.Errorf("%d troubleshoot check(s) failed", failed)
+		}
 
 		return nil
 	}
+
+	err := writeReport(out, report)
+	if err != nil {
+		return err
+	}
+
+	if failed := report.FailedCount(); failed > 0 {
+		return errors.Wrapf(ErrReportFailed, "%d check(s) failed", failed)
+	}
+
+	return nil
+}
+
+func getReportWriter(output string) (func(io.Writer, *Report) error, error) {
+	switch output {
+	case outputText:
+		return nil, nil
+	case outputJSON:
+		return writeJSONReport, nil
+	case outputJUnit:
+		return writeJUnitReport, nil
+	default:
+		return nil, errors.Errorf("unknown output format '%s', supported formats are '%s', '%s' and '%s'", output, outputText, outputJSON, outputJUnit)
+	}
 }
 
-func RunTroubleshootCmd(ctx context.Context, log logd.Logger, namespaceName string, kubeConfig *rest.Config) {
+// RunTroubleshootCmd runs all checks and returns their results, the progress is logged to log.
+func RunTroubleshootCmd(ctx context.Context, log logd.Logger, namespaceName string, kubeConfig *rest.Config) *Report {
+	report := &Report{}
+
 	err := checkOneAgentAPM(log, kubeConfig)
+	report.record(checkNameOneAgentAPM, "", "Delete the OneAgentAPM objects or fully uninstall the OneAgent Operator.", err, "OneAgentAPM does not exist")
+
 	if err != nil {
 		logErrorf(log, "prerequisite checks failed, aborting (%v)", err)
 
-		return
+		return report
 	}
 
 	apiReader, err := GetK8SClusterAPIReader(kubeConfig)
 	if err != nil {
-		return
+		logErrorf(log, "failed to connect to the cluster, aborting (%v)", err)
+		report.failed(checkNameCluster, "", "Make sure the kubeconfig is valid and the cluster is reachable.", err)
+
+		return report
 	}
 
 	err = checkNamespace(ctx, log, apiReader, namespaceName)
+	report.record(checkNameNamespace, "", fmt.Sprintf("Provide the namespace of the operator with '--%s <namespace>'.", namespaceFlagName), err, "using namespace '%s'", namespaceName)
+
 	if err != nil {
 		logErrorf(log, "prerequisite checks failed, aborting (%v)", err)
 
-		return
+		return report
 	}
 
 	dks, err := getDynakubes(ctx, log, apiReader, namespaceName, dynakubeFlagValue)
-	if checkCRD(log, err) != nil {
+
+	err = checkCRD(log, err)
+	report.record(checkNameCRD, "", "Make sure the CRDs of the installed operator version are applied. "+dynakubeNotValidMessage(), err, "CRD for Dynakube exists")
+
+	if err != nil {
 		logErrorf(log, "error during getting dynakubes: %v", err)
 
-		return
+		return report
+	}
+
+	if len(dks) == 0 {
+		report.failed(checkNameDynakube, "", dynakubeNotValidMessage(), errors.Errorf("no Dynakubes found in namespace '%s'", namespaceName))
+
+		return report
+	}
+
+	runChecksForAllDynakubes(ctx, log, apiReader, &http.Client{}, dks, report)
+
+	if skipNetworkFlagValue {
+		logInfof(log, "skipping network checks, as '--%s' is set", skipNetworkFlagName)
+
+		return report
+	}
+
+	probe, err := getNetworkProber(log, kubeConfig, namespaceName)
+	if err != nil {
+		logErrorf(log, "network checks failed, aborting (%v)", err)
+		report.failed(checkNameNetworkPrefix+networkCheckLoggerName, "", "Make sure the kubeconfig is valid and the cluster is reachable.", err)
+
+		return report
+	}
+
+	for i := range dks {
+		if !hasValidTokens(report, dynakubeKey(&dks[i])) {
+			logInfof(log, "skipping network checks of '%s:%s' Dynakube, as its tokens aren't valid", dks[i].Namespace, dks[i].Name)
+
+			continue
+		}
+
+		runNetworkChecksForDynakube(ctx, log, apiReader, probe, &dks[i], report)
+	}
+
+	runNetworkChecksForEdgeConnects(ctx, log, apiReader, probe, namespaceName, report)
+
+	return report
+}
+
+func getNetworkProber(log logd.Logger, kubeConfig *rest.Config, namespaceName string) (networkProber, error) {
+	if networkNodeFlagValue == "" {
+		return localNetworkProber, nil
+	}
+
+	logInfof(log, "network checks run in a pod on node '%s', a temporary secret and pod with the prefix '%s' are created in namespace '%s' and deleted afterwards",
+		networkNodeFlagValue, networkProbeNamePrefix, namespaceName)
+
+	clientset, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
 
-	runChecksForAllDynakubes(ctx, log, apiReader, &http.Client{}, dks)
+	return newPodNetworkProber(clientset, namespaceName, networkNodeFlagValue), nil
 }
 
 func GetK8SClusterAPIReader(kubeConfig *rest.Config) (client.Reader, error) {
@@ -110,22 +260,22 @@ func GetK8SClusterAPIReader(kubeConfig *rest.Config) (client.Reader, error) {
 	return k8scluster.GetAPIReader(), nil
 }
 
-func runChecksForAllDynakubes(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, httpClient *http.Client, dynakubes []dynakube.DynaKube) {
+func runChecksForAllDynakubes(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, httpClient *http.Client, dynakubes []dynakube.DynaKube, report *Report) { //nolint:revive
 	for _, dk := range dynakubes {
-		err := runChecksForDynakube(ctx, baseLog, apiReader, httpClient, dk)
+		err := runChecksForDynakube(ctx, baseLog, apiReader, httpClient, dk, report)
 		if err != nil {
 			logErrorf(baseLog, "Error in DynaKube %s/%s", dk.Namespace, dk.Name)
 		}
 	}
 }
 
-func runChecksForDynakube(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, httpClient *http.Client, dk dynakube.DynaKube) error {
+func runChecksForDynakube(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, httpClient *http.Client, dk dynakube.DynaKube, report *Report) error { //nolint:revive
 	log := baseLog.WithName(dynakubeCheckLoggerName)
 
 	logNewCheckf(log, "checking if '%s:%s' Dynakube is configured correctly", dk.Namespace, dk.Name)
 	logInfof(log, "using '%s:%s' Dynakube", dk.Namespace, dk.Name)
 
-	pullSecret, err := checkDynakube(ctx, baseLog, apiReader, &dk)
+	pullSecret, err := checkDynakube(ctx, baseLog, apiReader, &dk, report)
 	if err != nil {
 		return errors.Wrapf(err, "'%s:%s' Dynakube isn't valid. %s",
 			dk.Namespace, dk.Name, dynakubeNotValidMessage())
@@ -135,20 +285,33 @@ func runChecksForDynakube(ctx context.Context, baseLog logd.Logger, apiReader cl
 
 	keychain, err := dockerkeychain.NewDockerKeychain(ctx, apiReader, pullSecret)
 	if err != nil {
+		report.failed(checkNamePullSecret, dynakubeKey(&dk), "Make sure the pull secret of the Dynakube is a valid docker config.", err)
+
 		return err
 	}
 
 	transport, err := createTransport(ctx, apiReader, &dk, httpClient)
 	if err != nil {
+		report.failed(checkNameDynakube, dynakubeKey(&dk), "Make sure the proxy and trusted CA settings of the Dynakube are valid.", err)
+
 		return err
 	}
 
-	err = verifyAllImagesAvailable(ctx, log, keychain, transport, &dk)
-	if err != nil {
-		return err
+	verifyAllImagesAvailable(ctx, log, keychain, transport, &dk, report)
+
+	err = checkProxySettings(ctx, log, apiReader, &dk)
+
+	switch {
+	case err != nil:
+		report.failed(checkNameProxy, dynakubeKey(&dk), "Make sure the proxy secret referenced by the Dynakube exists and is valid.", err)
+	case getEnvProxySettings() != nil:
+		report.warning(checkNameProxy, dynakubeKey(&dk), "Make sure the proxy settings of the environment match the Dynakube.",
+			"proxy settings in the environment are used for codeModules image pulls")
+	default:
+		report.passed(checkNameProxy, dynakubeKey(&dk), "proxy settings are valid")
 	}
 
-	return checkProxySettings(ctx, log, apiReader, &dk)
+	return err
 }
 
 func createTransport(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube, httpClient *http.Client) (*http.Transport, error) {
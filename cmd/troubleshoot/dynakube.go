@@ -25,28 +25,45 @@ const (
 
 const dynakubeCheckLoggerName = "dynakube"
 
-func checkDynakube(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, dk *dynakube.DynaKube) (corev1.Secret, error) {
+func checkDynakube(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, dk *dynakube.DynaKube, report *Report) (corev1.Secret, error) {
+	dkKey := dynakubeKey(dk)
+
 	dynatraceAPISecretTokens, err := checkIfDynatraceAPISecretHasAPIToken(ctx, baseLog, apiReader, dk)
+	report.record(checkNameAPIToken, dkKey, fmt.Sprintf("Add a valid '%s' to the '%s:%s' secret.", dtclient.APIToken, dk.Namespace, dk.Tokens()),
+		err, "secret '%s:%s' contains the required tokens", dk.Namespace, dk.Tokens())
+
 	if err != nil {
 		return corev1.Secret{}, err
 	}
 
 	err = checkDynatraceAPITokenScopes(ctx, baseLog, apiReader, dynatraceAPISecretTokens, dk)
+	report.record(checkNameTokenScopes, dkKey, "Make sure the tokens have all scopes required by the enabled features of the Dynakube.",
+		err, "token scopes are valid")
+
 	if err != nil {
 		return corev1.Secret{}, err
 	}
 
 	err = checkAPIURLForLatestAgentVersion(ctx, baseLog, apiReader, dk, dynatraceAPISecretTokens)
+	report.record(checkNameAPIURL, dkKey, "Make sure the 'apiUrl' of the Dynakube is correct and reachable from the cluster.",
+		err, "latest agent version can be pulled from '%s'", dk.APIURL())
+
 	if err != nil {
 		return corev1.Secret{}, err
 	}
 
+	pullSecretRemediation := fmt.Sprintf("Make sure the '%s:%s' pull secret exists and contains a valid '%s'.", dk.Namespace, dk.PullSecretName(), dtpullsecret.DockerConfigJSON)
+
 	pullSecret, err := checkPullSecretExists(ctx, baseLog, apiReader, dk)
 	if err != nil {
+		report.failed(checkNamePullSecret, dkKey, pullSecretRemediation, err)
+
 		return corev1.Secret{}, err
 	}
 
 	err = checkPullSecretHasRequiredTokens(baseLog, dk, pullSecret)
+	report.record(checkNamePullSecret, dkKey, pullSecretRemediation, err, "pull secret '%s:%s' is valid", dk.Namespace, dk.PullSecretName())
+
 	if err != nil {
 		return corev1.Secret{}, err
 	}
@@ -54,6 +71,10 @@ func checkDynakube(ctx context.Context, baseLog logd.Logger, apiReader client.Re
 	return pullSecret, nil
 }
 
+func dynakubeKey(dk *dynakube.DynaKube) string {
+	return dk.Namespace + "/" + dk.Name
+}
+
 func getSelectedDynakube(ctx context.Context, apiReader client.Reader, namespaceName, dynakubeName string) (dynakube.DynaKube, error) {
 	var dk dynakube.DynaKube
 
@@ -101,6 +122,12 @@ func checkIfDynatraceAPISecretHasAPIToken(ctx context.Context, baseLog logd.Logg
 		return nil, errors.Wrapf(err, "'%s:%s' secret is missing or invalid", dk.Namespace, dk.Tokens())
 	}
 
+	if tokens.UsesPlatformAuth() {
+		logInfof(log, "secret contains platform authentication, it is used instead of 'apiToken'")
+
+		return tokens, nil
+	}
+
 	_, hasAPIToken := tokens[dtclient.APIToken]
 	if !hasAPIToken {
 		return nil, errors.New(fmt.Sprintf("'%s' token is missing in '%s:%s' secret", dtclient.APIToken, dk.Namespace, dk.Tokens()))
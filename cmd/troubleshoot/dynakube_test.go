@@ -10,6 +10,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -109,6 +110,23 @@ func TestDynatraceSecret(t *testing.T) {
 		_, err := checkIfDynatraceAPISecretHasAPIToken(context.Background(), getNullLogger(t), clt, dk)
 		require.Errorf(t, err, "Dynatrace secret found")
 	})
+	t.Run("Dynatrace secret does not exist => failed check is reported", func(t *testing.T) {
+		dk := testNewDynakubeBuilder(testNamespace, testDynakube).build()
+		clt := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(dk, testBuildNamespace(testNamespace)).
+			Build()
+
+		report := &Report{}
+		_, err := checkDynakube(context.Background(), getNullLogger(t), clt, dk, report)
+		require.Error(t, err)
+
+		require.Len(t, report.Results, 1)
+		assert.Equal(t, checkNameAPIToken, report.Results[0].Name)
+		assert.Equal(t, testNamespace+"/"+testDynakube, report.Results[0].DynaKube)
+		assert.Equal(t, StatusFailed, report.Results[0].Status)
+		assert.NotEmpty(t, report.Results[0].Remediation)
+	})
 
 	t.Run("Dynatrace secret has apiToken token", func(t *testing.T) {
 		dk := testNewDynakubeBuilder(testNamespace, testDynakube).withTokens(testDynatraceSecret).build()
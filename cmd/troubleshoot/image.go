@@ -10,6 +10,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -30,29 +31,42 @@ type Auths struct {
 
 type ImagePullFunc func(image string) error
 
-func verifyAllImagesAvailable(ctx context.Context, baseLog logd.Logger, keychain authn.Keychain, transport *http.Transport, dk *dynakube.DynaKube) error {
+const imageRemediation = "Make sure the image exists and can be pulled with the pull secret of the Dynakube. Check the proxy and trusted CA settings if a private registry is used."
+
+func verifyAllImagesAvailable(ctx context.Context, baseLog logd.Logger, keychain authn.Keychain, transport *http.Transport, dk *dynakube.DynaKube, report *Report) { //nolint:revive
 	log := baseLog.WithName("imagepull")
 
 	imagePullFunc := CreateImagePullFunc(ctx, keychain, transport)
 
+	verify := func(comp component, proxyWarning bool) {
+		err := verifyImageIsAvailable(log, imagePullFunc, dk, comp, proxyWarning)
+
+		image, _ := comp.getImage(dk)
+		if err == nil && image == "" {
+			report.passed(checkNameImagePrefix+comp.String(), dynakubeKey(dk), "no %s image configured", comp)
+
+			return
+		}
+
+		report.record(checkNameImagePrefix+comp.String(), dynakubeKey(dk), imageRemediation, err, "%s image %s can be pulled", comp, image)
+	}
+
 	if dk.OneAgent().IsDaemonsetRequired() {
-		verifyImageIsAvailable(log, imagePullFunc, dk, componentOneAgent, false)
-		verifyImageIsAvailable(log, imagePullFunc, dk, componentCodeModules, true)
+		verify(componentOneAgent, false)
+		verify(componentCodeModules, true)
 	}
 
 	if dk.ActiveGate().IsEnabled() {
-		verifyImageIsAvailable(log, imagePullFunc, dk, componentActiveGate, false)
+		verify(componentActiveGate, false)
 	}
-
-	return nil
 }
 
-func verifyImageIsAvailable(log logd.Logger, pullImage ImagePullFunc, dk *dynakube.DynaKube, comp component, proxyWarning bool) {
+func verifyImageIsAvailable(log logd.Logger, pullImage ImagePullFunc, dk *dynakube.DynaKube, comp component, proxyWarning bool) error {
 	image, isCustomImage := comp.getImage(dk)
 	if comp.SkipImageCheck(image) {
 		logErrorf(log, "Unknown %s image", comp.String())
 
-		return
+		return errors.Errorf("unknown %s image", comp.String())
 	}
 
 	componentName := comp.Name(isCustomImage)
@@ -61,7 +75,7 @@ func verifyImageIsAvailable(log logd.Logger, pullImage ImagePullFunc, dk *dynaku
 	if image == "" {
 		logInfof(log, "No %s image configured", componentName)
 
-		return
+		return nil
 	}
 
 	if dk.HasProxy() && proxyWarning {
@@ -75,9 +89,13 @@ func verifyImageIsAvailable(log logd.Logger, pullImage ImagePullFunc, dk *dynaku
 	err := pullImage(image)
 	if err != nil {
 		logErrorf(log, "Pulling %s image %s failed: %v", componentName, image, err)
-	} else {
-		logOkf(log, "%s image %s can be successfully pulled", componentName, image)
+
+		return errors.Wrapf(err, "pulling %s image %s failed", componentName, image)
 	}
+
+	logOkf(log, "%s image %s can be successfully pulled", componentName, image)
+
+	return nil
 }
 
 func CreateImagePullFunc(ctx context.Context, keychain authn.Keychain, transport *http.Transport) ImagePullFunc {
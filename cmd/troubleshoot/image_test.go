@@ -170,7 +170,7 @@ func TestImagePullable(t *testing.T) {
 			logOutput := runWithTestLogger(func(log logd.Logger) {
 				ctx := context.Background()
 				clt := fake.NewClient(secret)
-				pullSecret, _ := checkDynakube(ctx, log, clt, test.dk)
+				pullSecret, _ := checkDynakube(ctx, log, clt, test.dk, &Report{})
 				keychain, _ := dockerkeychain.NewDockerKeychain(context.Background(), fake.NewClient(secret), pullSecret)
 
 				transport, _ := createTransport(ctx, clt, test.dk, dockerServer.Client())
@@ -262,7 +262,7 @@ func TestImageNotPullable(t *testing.T) {
 			logOutput := runWithTestLogger(func(log logd.Logger) {
 				ctx := context.Background()
 				clt := fake.NewClient(secret)
-				pullSecret, _ := checkDynakube(ctx, log, clt, test.dk)
+				pullSecret, _ := checkDynakube(ctx, log, clt, test.dk, &Report{})
 				keychain, _ := dockerkeychain.NewDockerKeychain(context.Background(), fake.NewClient(secret), pullSecret)
 
 				transport, _ := createTransport(ctx, clt, test.dk, dockerServer.Client())
@@ -302,7 +302,7 @@ func TestOneAgentCodeModulesImageNotPullable(t *testing.T) {
 		logOutput := runWithTestLogger(func(log logd.Logger) {
 			ctx := context.Background()
 			clt := fake.NewClient(secret)
-			pullSecret, _ := checkDynakube(ctx, log, clt, &dk)
+			pullSecret, _ := checkDynakube(ctx, log, clt, &dk, &Report{})
 			keychain, _ := dockerkeychain.NewDockerKeychain(context.Background(), fake.NewClient(secret), pullSecret)
 
 			transport, _ := createTransport(ctx, clt, &dk, dockerServer.Client())
@@ -323,7 +323,7 @@ func TestOneAgentCodeModulesImageNotPullable(t *testing.T) {
 		logOutput := runWithTestLogger(func(log logd.Logger) {
 			ctx := context.Background()
 			clt := fake.NewClient(secret)
-			pullSecret, _ := checkDynakube(ctx, log, clt, &dk)
+			pullSecret, _ := checkDynakube(ctx, log, clt, &dk, &Report{})
 			keychain, _ := dockerkeychain.NewDockerKeychain(context.Background(), fake.NewClient(secret), pullSecret)
 			transport, _ := createTransport(ctx, clt, &dk, dockerServer.Client())
 			pullImage := CreateImagePullFunc(ctx, keychain, transport)
@@ -0,0 +1,257 @@
+package troubleshoot
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	networkProbeTimeout = 10 * time.Second
+	defaultHTTPSPort    = 443
+)
+
+// networkEndpoint is a host a component has to reach to work.
+type networkEndpoint struct {
+	Component string `json:"component"`
+	Host      string `json:"host"`
+	Port      uint32 `json:"port"`
+
+	// TLS enables the TLS handshake including the verification of the certificate chain
+	TLS bool `json:"tls,omitempty"`
+
+	// UseProxy routes the connection through the proxy of the networkSettings, if the host isn't excluded by NoProxy
+	UseProxy bool `json:"useProxy,omitempty"`
+
+	// InCluster endpoints are only resolvable from inside the cluster
+	InCluster bool `json:"inCluster,omitempty"`
+}
+
+func (e networkEndpoint) address() string {
+	return net.JoinHostPort(e.Host, strconv.FormatUint(uint64(e.Port), 10))
+}
+
+func (e networkEndpoint) String() string {
+	return e.address()
+}
+
+// networkSettings are the proxy and certificate settings used to reach the endpoints.
+type networkSettings struct {
+	Proxy      string `json:"proxy,omitempty"`
+	NoProxy    string `json:"noProxy,omitempty"`
+	TrustedCAs []byte `json:"trustedCAs,omitempty"`
+}
+
+type probeResult struct {
+	Error    string          `json:"error,omitempty"`
+	Endpoint networkEndpoint `json:"endpoint"`
+}
+
+// probeEndpoints probes all endpoints one after another, a failing endpoint doesn't stop the others from being probed.
+func probeEndpoints(ctx context.Context, endpoints []networkEndpoint, settings networkSettings) []probeResult {
+	results := make([]probeResult, 0, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		result := probeResult{Endpoint: endpoint}
+
+		err := probeEndpoint(ctx, endpoint, settings)
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// probeEndpoint connects to the endpoint the same way the components do:
+// the host is resolved and connected to directly or a tunnel is opened via the proxy,
+// afterwards the TLS handshake verifies the certificate chain against the system and trusted CAs.
+func probeEndpoint(ctx context.Context, endpoint networkEndpoint, settings networkSettings) error {
+	ctx, cancel := context.WithTimeout(ctx, networkProbeTimeout)
+	defer cancel()
+
+	proxyURL, err := getEndpointProxy(endpoint, settings)
+	if err != nil {
+		return err
+	}
+
+	var conn net.Conn
+
+	if proxyURL != nil {
+		conn, err = dialViaProxy(ctx, proxyURL, endpoint.address())
+	} else {
+		conn, err = dialDirect(ctx, endpoint)
+	}
+
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if !endpoint.TLS {
+		return nil
+	}
+
+	return verifyTLSHandshake(ctx, conn, endpoint.Host, settings.TrustedCAs)
+}
+
+func getEndpointProxy(endpoint networkEndpoint, settings networkSettings) (*url.URL, error) {
+	if !endpoint.UseProxy || settings.Proxy == "" {
+		return nil, nil
+	}
+
+	proxyConfig := httpproxy.Config{
+		HTTPProxy:  settings.Proxy,
+		HTTPSProxy: settings.Proxy,
+		NoProxy:    settings.NoProxy,
+	}
+
+	proxyURL, err := proxyConfig.ProxyFunc()(&url.URL{Scheme: "https", Host: endpoint.address()})
+
+	return proxyURL, errors.Wrap(err, "invalid proxy settings")
+}
+
+func dialDirect(ctx context.Context, endpoint networkEndpoint) (net.Conn, error) {
+	_, err := net.DefaultResolver.LookupHost(ctx, endpoint.Host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve '%s'", endpoint.Host)
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint.address())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to '%s'", endpoint.address())
+	}
+
+	return conn, nil
+}
+
+// dialViaProxy opens a tunnel to the address using the HTTP CONNECT method.
+func dialViaProxy(ctx context.Context, proxyURL *url.URL, address string) (net.Conn, error) {
+	var dialer net.Dialer
+
+	proxyAddress := proxyURL.Host
+	if proxyURL.Port() == "" {
+		proxyAddress = net.JoinHostPort(proxyURL.Hostname(), "80")
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to proxy '%s'", proxyAddress)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	connectReq := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		connectReq.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	err = connectReq.Write(conn)
+	if err != nil {
+		conn.Close()
+
+		return nil, errors.Wrapf(err, "failed to send CONNECT request to proxy '%s'", proxyAddress)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), connectReq)
+	if err != nil {
+		conn.Close()
+
+		return nil, errors.Wrapf(err, "failed to read CONNECT response of proxy '%s'", proxyAddress)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+
+		return nil, errors.Errorf("proxy '%s' refused to connect to '%s': %s", proxyAddress, address, resp.Status)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	return conn, nil
+}
+
+func verifyTLSHandshake(ctx context.Context, conn net.Conn, serverName string, trustedCAs []byte) error {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		return errors.Wrap(err, "couldn't read system certificates")
+	}
+
+	if len(trustedCAs) > 0 && !rootCAs.AppendCertsFromPEM(trustedCAs) {
+		return errors.New("failed to append trusted CAs")
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName: serverName,
+		RootCAs:    rootCAs,
+		MinVersion: tls.VersionTLS12,
+	})
+
+	err = tlsConn.HandshakeContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "TLS handshake with '%s' failed", serverName)
+	}
+
+	return nil
+}
+
+// parseEndpointURL parses a single URL of the communication endpoints of the connection info, the port defaults to 443.
+func parseEndpointURL(rawURL string) (networkEndpoint, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Hostname() == "" {
+		return networkEndpoint{}, errors.Errorf("invalid endpoint '%s'", rawURL)
+	}
+
+	endpoint := networkEndpoint{
+		Host: parsedURL.Hostname(),
+		Port: defaultHTTPSPort,
+		TLS:  parsedURL.Scheme == "https",
+	}
+
+	if parsedURL.Port() != "" {
+		port, err := strconv.ParseUint(parsedURL.Port(), 10, 32)
+		if err != nil {
+			return networkEndpoint{}, errors.Errorf("invalid port of endpoint '%s'", rawURL)
+		}
+
+		endpoint.Port = uint32(port)
+	}
+
+	return endpoint, nil
+}
+
+func networkEndpointRemediation(endpoint networkEndpoint) string {
+	if endpoint.InCluster {
+		return fmt.Sprintf("Make sure the '%s' service has ready endpoints and no NetworkPolicy blocks traffic to port %d.", endpoint.Host, endpoint.Port)
+	}
+
+	return fmt.Sprintf("Make sure firewalls and the proxy allow connections from the cluster to '%s'. "+
+		"If a proxy intercepts TLS traffic, add its CA to the trustedCAs.", endpoint.address())
+}
@@ -0,0 +1,327 @@
+package troubleshoot
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha2/edgeconnect"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	networkCheckLoggerName   = "network"
+	checkNameNetworkPrefix   = "network/"
+	kubernetesServiceHostEnv = "KUBERNETES_SERVICE_HOST"
+
+	componentOneAgentCommunication   = "OneAgent"
+	componentActiveGateCommunication = "ActiveGate"
+	componentActiveGateService       = "ActiveGateService"
+	componentTelemetryIngestService  = "TelemetryIngestService"
+	componentEdgeConnect             = "EdgeConnect"
+)
+
+// networkProber probes the endpoints, either from where troubleshoot runs or from a pod on a node of the cluster.
+type networkProber func(ctx context.Context, endpoints []networkEndpoint, settings networkSettings) ([]probeResult, error)
+
+func localNetworkProber(ctx context.Context, endpoints []networkEndpoint, settings networkSettings) ([]probeResult, error) {
+	return probeEndpoints(ctx, endpoints, settings), nil
+}
+
+// isRunningInCluster checks if troubleshoot runs inside a pod, otherwise the in-cluster services can't be resolved.
+func isRunningInCluster() bool {
+	return os.Getenv(kubernetesServiceHostEnv) != ""
+}
+
+func runNetworkChecksForDynakube(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, probe networkProber, dk *dynakube.DynaKube, report *Report) { //nolint:revive
+	log := baseLog.WithName(networkCheckLoggerName)
+
+	logNewCheckf(log, "checking if the endpoints of '%s:%s' Dynakube are reachable ...", dk.Namespace, dk.Name)
+
+	endpoints, err := collectDynakubeEndpoints(ctx, apiReader, dk)
+	if err != nil {
+		logErrorf(log, "failed to determine the endpoints: %v", err)
+		report.failed(checkNameNetworkPrefix+checkNameDynakube, dynakubeKey(dk), "Make sure the connection info can be queried with the tokens of the Dynakube.", err)
+
+		return
+	}
+
+	settings := networkSettings{}
+
+	if dk.HasProxy() {
+		settings.Proxy, err = dk.Proxy(ctx, apiReader)
+		if err != nil {
+			report.failed(checkNameNetworkPrefix+checkNameProxy, dynakubeKey(dk), "Make sure the proxy secret referenced by the Dynakube exists and is valid.", err)
+
+			return
+		}
+
+		settings.NoProxy = dk.FF().GetNoProxy()
+	}
+
+	if dk.Spec.TrustedCAs != "" {
+		settings.TrustedCAs, err = dk.TrustedCAs(ctx, apiReader)
+		if err != nil {
+			report.failed(checkNameNetworkPrefix+checkNameDynakube, dynakubeKey(dk), "Make sure the trustedCAs ConfigMap of the Dynakube exists and is valid.", err)
+
+			return
+		}
+	}
+
+	runNetworkProbes(ctx, log, probe, endpoints, settings, dynakubeKey(dk), report)
+}
+
+// collectDynakubeEndpoints returns the endpoints the components of the Dynakube connect to.
+func collectDynakubeEndpoints(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube) ([]networkEndpoint, error) {
+	var endpoints []networkEndpoint
+
+	oneAgentEnabled := dk.OneAgent().IsDaemonsetRequired() || dk.OneAgent().IsApplicationMonitoringMode()
+	if !oneAgentEnabled && !dk.ActiveGate().IsEnabled() && !dk.TelemetryIngest().IsEnabled() {
+		return nil, nil
+	}
+
+	tokens, err := token.NewReader(apiReader, dk).ReadTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtc, err := dynatraceclient.NewBuilder(apiReader).
+		SetDynakube(*dk).
+		SetTokens(tokens).
+		Build(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build DynatraceAPI client")
+	}
+
+	if oneAgentEnabled {
+		connectionInfo, err := dtc.GetOneAgentConnectionInfo(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the OneAgent connection info")
+		}
+
+		for _, host := range connectionInfo.CommunicationHosts {
+			endpoints = append(endpoints, networkEndpoint{
+				Component: componentOneAgentCommunication,
+				Host:      host.Host,
+				Port:      host.Port,
+				TLS:       host.Protocol == "https",
+				UseProxy:  dk.NeedsOneAgentProxy(),
+			})
+		}
+	}
+
+	if dk.ActiveGate().IsEnabled() {
+		connectionInfo, err := dtc.GetActiveGateConnectionInfo(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the ActiveGate connection info")
+		}
+
+		for _, rawURL := range strings.Split(connectionInfo.Endpoints, ",") {
+			if strings.TrimSpace(rawURL) == "" {
+				continue
+			}
+
+			endpoint, err := parseEndpointURL(strings.TrimSpace(rawURL))
+			if err != nil {
+				return nil, err
+			}
+
+			endpoint.Component = componentActiveGateCommunication
+			endpoint.UseProxy = dk.NeedsActiveGateProxy()
+			endpoints = append(endpoints, endpoint)
+		}
+
+		serviceEndpoints, err := collectServiceEndpoints(ctx, apiReader, dk.Namespace, capability.BuildServiceName(dk.Name), componentActiveGateService)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints = append(endpoints, serviceEndpoints...)
+	}
+
+	if dk.TelemetryIngest().IsEnabled() {
+		serviceEndpoints, err := collectServiceEndpoints(ctx, apiReader, dk.Namespace, dk.TelemetryIngest().GetServiceName(), componentTelemetryIngestService)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints = append(endpoints, serviceEndpoints...)
+	}
+
+	return endpoints, nil
+}
+
+// collectServiceEndpoints returns the TCP ports of the service, only the connection is checked as the services don't use publicly trusted certificates.
+func collectServiceEndpoints(ctx context.Context, apiReader client.Reader, namespace, name, component string) ([]networkEndpoint, error) {
+	var service corev1.Service
+
+	err := apiReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &service)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get service '%s:%s'", namespace, name)
+	}
+
+	endpoints := make([]networkEndpoint, 0, len(service.Spec.Ports))
+
+	for _, port := range service.Spec.Ports {
+		if port.Protocol != "" && port.Protocol != corev1.ProtocolTCP {
+			continue
+		}
+
+		endpoints = append(endpoints, networkEndpoint{
+			Component: component,
+			Host:      name + "." + namespace,
+			Port:      uint32(port.Port), //nolint:gosec
+			InCluster: true,
+		})
+	}
+
+	return endpoints, nil
+}
+
+func runNetworkChecksForEdgeConnects(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, probe networkProber, namespace string, report *Report) { //nolint:revive
+	log := baseLog.WithName(networkCheckLoggerName)
+
+	var edgeConnects edgeconnect.EdgeConnectList
+
+	err := apiReader.List(ctx, &edgeConnects, client.InNamespace(namespace))
+	if err != nil {
+		// the EdgeConnect CRD is optional, so it's not an error if it's missing
+		logInfof(log, "skipping EdgeConnect network checks: %v", err)
+
+		return
+	}
+
+	for _, ec := range edgeConnects.Items {
+		logNewCheckf(log, "checking if the API server of '%s:%s' EdgeConnect is reachable ...", ec.Namespace, ec.Name)
+
+		key := ec.Namespace + "/" + ec.Name
+
+		settings, err := getEdgeConnectNetworkSettings(ctx, apiReader, &ec)
+		if err != nil {
+			report.failed(checkNameNetworkPrefix+componentEdgeConnect, key, "Make sure the proxy and certificate settings of the EdgeConnect are valid.", err)
+
+			continue
+		}
+
+		endpoint := networkEndpoint{
+			Component: componentEdgeConnect,
+			Host:      ec.Spec.APIServer,
+			Port:      defaultHTTPSPort,
+			TLS:       true,
+			UseProxy:  true,
+		}
+
+		runNetworkProbes(ctx, log, probe, []networkEndpoint{endpoint}, settings, key, report)
+	}
+}
+
+func getEdgeConnectNetworkSettings(ctx context.Context, apiReader client.Reader, ec *edgeconnect.EdgeConnect) (networkSettings, error) {
+	settings := networkSettings{}
+
+	if ec.Spec.Proxy != nil && ec.Spec.Proxy.Host != "" {
+		proxyURL := url.URL{Scheme: "http", Host: ec.Spec.Proxy.Host}
+		if ec.Spec.Proxy.Port != 0 {
+			proxyURL.Host = (networkEndpoint{Host: ec.Spec.Proxy.Host, Port: ec.Spec.Proxy.Port}).address()
+		}
+
+		user, password, err := ec.ProxyAuth(ctx, apiReader)
+		if err != nil {
+			return settings, err
+		}
+
+		if user != "" {
+			proxyURL.User = url.UserPassword(user, password)
+		}
+
+		settings.Proxy = proxyURL.String()
+		settings.NoProxy = ec.Spec.Proxy.NoProxy
+	}
+
+	if ec.Spec.CaCertsRef != "" {
+		trustedCAs, err := ec.TrustedCAs(ctx, apiReader)
+		if err != nil {
+			return settings, err
+		}
+
+		settings.TrustedCAs = trustedCAs
+	}
+
+	return settings, nil
+}
+
+func runNetworkProbes(ctx context.Context, log logd.Logger, probe networkProber, endpoints []networkEndpoint, settings networkSettings, key string, report *Report) { //nolint:revive
+	if !isRunningInCluster() && networkNodeFlagValue == "" {
+		endpoints = skipInClusterEndpoints(log, endpoints, key, report)
+	}
+
+	if len(endpoints) == 0 {
+		logInfof(log, "no endpoints to check")
+
+		return
+	}
+
+	results, err := probe(ctx, endpoints, settings)
+	if err != nil {
+		logErrorf(log, "failed to probe the endpoints: %v", err)
+		report.failed(checkNameNetworkPrefix+networkCheckLoggerName, key, "Make sure the network probe pod can be started on the node.", err)
+
+		return
+	}
+
+	for _, result := range results {
+		name := checkNameNetworkPrefix + result.Endpoint.Component + "/" + result.Endpoint.String()
+
+		if result.Error != "" {
+			logErrorf(log, "%s endpoint %s is not reachable: %s", result.Endpoint.Component, result.Endpoint, result.Error)
+			report.failed(name, key, networkEndpointRemediation(result.Endpoint), errors.New(result.Error))
+
+			continue
+		}
+
+		logOkf(log, "%s endpoint %s is reachable", result.Endpoint.Component, result.Endpoint)
+		report.passed(name, key, "%s endpoint %s is reachable", result.Endpoint.Component, result.Endpoint)
+	}
+}
+
+func skipInClusterEndpoints(log logd.Logger, endpoints []networkEndpoint, key string, report *Report) []networkEndpoint {
+	remaining := make([]networkEndpoint, 0, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		if !endpoint.InCluster {
+			remaining = append(remaining, endpoint)
+
+			continue
+		}
+
+		logWarningf(log, "%s endpoint %s can only be checked from inside the cluster", endpoint.Component, endpoint)
+		report.warning(checkNameNetworkPrefix+endpoint.Component+"/"+endpoint.String(), key,
+			"Run troubleshoot inside the cluster or use '--"+networkNodeFlagName+" <node>'.",
+			"%s endpoint %s wasn't checked, as troubleshoot runs outside of the cluster", endpoint.Component, endpoint)
+	}
+
+	return remaining
+}
+
+// hasValidTokens checks if the tokens of the dynakube passed their checks, as they are needed to query the endpoints.
+// Other failed checks don't skip the network checks, as they may be caused by the network itself.
+func hasValidTokens(report *Report, key string) bool {
+	for _, result := range report.Results {
+		if result.DynaKube != key || result.Status != StatusFailed {
+			continue
+		}
+
+		if result.Name == checkNameAPIToken || result.Name == checkNameTokenScopes {
+			return false
+		}
+	}
+
+	return true
+}
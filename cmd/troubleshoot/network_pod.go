@@ -0,0 +1,201 @@
+package troubleshoot
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	networkProbeUse            = "network-probe"
+	networkProbeRequestEnv     = "NETWORK_PROBE_REQUEST"
+	networkProbeRequestKey     = "request"
+	networkProbeNamePrefix     = "dynatrace-troubleshoot-network-"
+	networkProbePollInterval   = 2 * time.Second
+	networkProbePodTimeout     = 3 * time.Minute
+	operatorDeploymentName     = "dynatrace-operator"
+	operatorContainerName      = "operator"
+	networkProbeActiveDeadline = int64(networkProbePodTimeout / time.Second)
+)
+
+// networkProbeRequest is handed over to the network probe pod, it contains everything needed to probe the endpoints.
+type networkProbeRequest struct {
+	Endpoints []networkEndpoint `json:"endpoints"`
+	Settings  networkSettings   `json:"settings"`
+}
+
+// newNetworkProbeCommand is run by the network probe pod, it probes the endpoints of the request and writes the results as JSON to stdout.
+func newNetworkProbeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    networkProbeUse,
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var request networkProbeRequest
+
+			err := json.Unmarshal([]byte(os.Getenv(networkProbeRequestEnv)), &request)
+			if err != nil {
+				return errors.Wrap(err, "invalid network probe request")
+			}
+
+			results := probeEndpoints(cmd.Context(), request.Endpoints, request.Settings)
+
+			return errors.WithStack(json.NewEncoder(os.Stdout).Encode(results))
+		},
+	}
+}
+
+// newPodNetworkProber probes the endpoints from a short-lived pod on the node, so the network of the node is used.
+// The request is passed via a secret, as it may contain the proxy credentials.
+func newPodNetworkProber(clientset kubernetes.Interface, namespace, nodeName string) networkProber {
+	return func(ctx context.Context, endpoints []networkEndpoint, settings networkSettings) ([]probeResult, error) {
+		operatorDeployment, err := getOperatorDeployment(ctx, clientset, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		request, err := json.Marshal(networkProbeRequest{Endpoints: endpoints, Settings: settings})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		secret, err := clientset.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: networkProbeNamePrefix,
+				Namespace:    namespace,
+			},
+			Data: map[string][]byte{networkProbeRequestKey: request},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create network probe secret")
+		}
+
+		defer func() {
+			_ = clientset.CoreV1().Secrets(namespace).Delete(context.Background(), secret.Name, metav1.DeleteOptions{})
+		}()
+
+		pod, err := buildNetworkProbePod(operatorDeployment, nodeName, secret.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		pod, err = clientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create network probe pod")
+		}
+
+		defer func() {
+			_ = clientset.CoreV1().Pods(namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+		}()
+
+		err = waitForPodCompletion(ctx, clientset, namespace, pod.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		logs, err := clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get logs of network probe pod '%s'", pod.Name)
+		}
+
+		var results []probeResult
+
+		err = json.Unmarshal(logs, &results)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unexpected output of network probe pod '%s': %s", pod.Name, string(logs))
+		}
+
+		return results, nil
+	}
+}
+
+func getOperatorDeployment(ctx context.Context, clientset kubernetes.Interface, namespace string) (*appsv1.Deployment, error) {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, operatorDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the operator deployment, which is the template of the network probe pod")
+	}
+
+	return deployment, nil
+}
+
+// buildNetworkProbePod uses the image, pull secrets and security context of the operator, so the pod is admitted wherever the operator is.
+func buildNetworkProbePod(operatorDeployment *appsv1.Deployment, nodeName, secretName string) (*corev1.Pod, error) {
+	templateSpec := operatorDeployment.Spec.Template.Spec
+
+	var operatorContainer *corev1.Container
+
+	for i := range templateSpec.Containers {
+		if templateSpec.Containers[i].Name == operatorContainerName {
+			operatorContainer = &templateSpec.Containers[i]
+		}
+	}
+
+	if operatorContainer == nil {
+		return nil, errors.Errorf("container '%s' not found in deployment '%s'", operatorContainerName, operatorDeployment.Name)
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: networkProbeNamePrefix,
+			Namespace:    operatorDeployment.Namespace,
+		},
+		Spec: corev1.PodSpec{
+			NodeName:              nodeName,
+			RestartPolicy:         corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds: ptr.To(networkProbeActiveDeadline),
+			ImagePullSecrets:      templateSpec.ImagePullSecrets,
+			SecurityContext:       templateSpec.SecurityContext,
+			// the probe has to run on the chosen node, regardless of its taints
+			Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{
+				{
+					Name:            networkProbeUse,
+					Image:           operatorContainer.Image,
+					ImagePullPolicy: operatorContainer.ImagePullPolicy,
+					Args:            []string{use, networkProbeUse},
+					Env: []corev1.EnvVar{
+						{
+							Name: networkProbeRequestEnv,
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+									Key:                  networkProbeRequestKey,
+								},
+							},
+						},
+					},
+					SecurityContext: operatorContainer.SecurityContext,
+				},
+			},
+		},
+	}, nil
+}
+
+func waitForPodCompletion(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	err := wait.PollUntilContextTimeout(ctx, networkProbePollInterval, networkProbePodTimeout, true, func(ctx context.Context) (bool, error) {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			return true, nil
+		case corev1.PodFailed:
+			return false, errors.Errorf("network probe pod '%s' failed: %s", name, pod.Status.Message)
+		default:
+			return false, nil
+		}
+	})
+
+	return errors.Wrapf(err, "network probe pod '%s' didn't complete", name)
+}
@@ -0,0 +1,432 @@
+package troubleshoot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/bootstrapperconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	maputils "github.com/Dynatrace/dynatrace-operator/pkg/util/map"
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/handler/injection"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator"
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator/oneagent"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const (
+	podUse             = "pod <namespace>/<name>"
+	podCheckLoggerName = "pod"
+
+	checkNamePodPrefix       = "pod/"
+	checkNamePod             = checkNamePodPrefix + "exists"
+	checkNamePodNamespace    = checkNamePodPrefix + "namespace"
+	checkNamePodDynakube     = checkNamePodPrefix + "dynakube"
+	checkNamePodOneAgent     = checkNamePodPrefix + "oneAgent"
+	checkNamePodMetadata     = checkNamePodPrefix + "metadataEnrichment"
+	checkNamePodAnnotations  = checkNamePodPrefix + "annotations"
+	checkNamePodBootstrapper = checkNamePodPrefix + "bootstrapperConfig"
+	checkNamePodInjection    = checkNamePodPrefix + "injection"
+	checkNamePodCSI          = checkNamePodPrefix + "csi"
+
+	failedMountEventReason = "FailedMount"
+)
+
+// injectionReasonExplanations explains the reasons the webhook sets on a pod, when it didn't (fully) inject it.
+var injectionReasonExplanations = map[string]string{
+	injection.NoBootstrapperConfigReason: "the bootstrapper config secret wasn't replicated to the namespace of the pod when it was created",
+	injection.NoMutationNeededReason:     "neither OneAgent injection nor metadata enrichment is enabled for the pod",
+	oneagent.MissingTenantUUIDReason:     "the tenant UUID wasn't known yet, the Dynakube hadn't connected to the tenant when the pod was created",
+	metadata.OwnerLookupFailedReason:     "the owner of the pod couldn't be looked up, check the RBAC permissions of the webhook",
+}
+
+func newPodCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   podUse,
+		Short: "Explains why a pod was or wasn't injected by the webhook.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runPod,
+
+		// failed checks are part of the report, the usage would only hide them
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+}
+
+func runPod(cmd *cobra.Command, args []string) error {
+	podNamespace, podName, found := strings.Cut(args[0], "/")
+	if !found || podNamespace == "" || podName == "" {
+		return errors.Errorf("invalid pod '%s', expected '<namespace>/<name>'", args[0])
+	}
+
+	writeReport, err := getReportWriter(outputFlagValue)
+	if err != nil {
+		return err
+	}
+
+	logOut := os.Stdout
+	if writeReport != nil {
+		// stdout must only contain the report, this includes the logs of the base logger
+		logOut = os.Stderr
+		logd.SetBaseLoggerOutput(os.Stderr)
+	}
+
+	kubeConfig, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	apiReader, err := GetK8SClusterAPIReader(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	log := NewTroubleshootLoggerToWriter(logOut)
+
+	report := runPodChecks(cmd.Context(), log, apiReader, namespaceFlagValue, client.ObjectKey{Namespace: podNamespace, Name: podName})
+
+	return finishReport(writeReport, report)
+}
+
+// runPodChecks evaluates the same conditions as the webhook for the pod, in the order the webhook does.
+func runPodChecks(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, operatorNamespace string, podKey client.ObjectKey) *Report {
+	log := baseLog.WithName(podCheckLoggerName)
+	report := &Report{}
+	scope := podKey.String()
+
+	logNewCheckf(log, "checking the injection of pod '%s' ...", scope)
+
+	var pod corev1.Pod
+
+	err := apiReader.Get(ctx, podKey, &pod)
+	report.record(checkNamePod, scope, "Make sure the pod exists, pass it as '<namespace>/<name>'.", errors.Wrapf(err, "failed to get pod '%s'", scope), "pod '%s' exists", scope)
+
+	if err != nil {
+		logErrorf(log, "failed to get pod '%s': %v", scope, err)
+
+		return report
+	}
+
+	var namespace corev1.Namespace
+
+	err = apiReader.Get(ctx, client.ObjectKey{Name: pod.Namespace}, &namespace)
+	if err != nil {
+		report.failed(checkNamePodNamespace, scope, "Make sure the namespace of the pod exists.", errors.Wrapf(err, "failed to get namespace '%s'", pod.Namespace))
+
+		return report
+	}
+
+	dkName, ok := namespace.Labels[dtwebhook.InjectionInstanceLabel]
+	if !ok {
+		logErrorf(log, "namespace '%s' isn't monitored by any Dynakube", namespace.Name)
+		report.failed(checkNamePodNamespace, scope,
+			"Make sure the namespaceSelector of a Dynakube matches the labels of the namespace, the operator then adds the '"+dtwebhook.InjectionInstanceLabel+"' label.",
+			errors.Errorf("namespace '%s' doesn't have the '%s' label, so the webhook ignores its pods", namespace.Name, dtwebhook.InjectionInstanceLabel))
+
+		return report
+	}
+
+	logOkf(log, "namespace '%s' is monitored by Dynakube '%s'", namespace.Name, dkName)
+	report.passed(checkNamePodNamespace, scope, "namespace '%s' is monitored by Dynakube '%s'", namespace.Name, dkName)
+
+	var dk dynakube.DynaKube
+
+	err = apiReader.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: dkName}, &dk)
+	if err != nil {
+		logErrorf(log, "failed to get Dynakube '%s:%s': %v", operatorNamespace, dkName, err)
+		report.failed(checkNamePodDynakube, scope,
+			fmt.Sprintf("Make sure the Dynakube exists, provide the namespace of the operator with '--%s <namespace>'.", namespaceFlagName),
+			errors.Wrapf(err, "failed to get Dynakube '%s:%s'", operatorNamespace, dkName))
+
+		return report
+	}
+
+	report.passed(checkNamePodDynakube, scope, "using Dynakube '%s:%s'", dk.Namespace, dk.Name)
+
+	request := &dtwebhook.BaseRequest{Pod: &pod, Namespace: namespace, DynaKube: dk}
+
+	oneAgentEnabled := checkPodOneAgent(log, request, scope, report)
+	metadataEnabled := checkPodMetadataEnrichment(log, request, scope, report)
+
+	if !checkPodAnnotations(log, request, scope, report) || (!oneAgentEnabled && !metadataEnabled) {
+		logInfof(log, "the webhook doesn't inject pod '%s'", scope)
+
+		return report
+	}
+
+	checkPodBootstrapperConfig(ctx, log, apiReader, request, scope, report)
+	checkPodInjection(log, &pod, scope, report)
+	checkPodCSIVolumes(ctx, log, apiReader, &pod, scope, report)
+
+	return report
+}
+
+func checkPodOneAgent(log logd.Logger, request *dtwebhook.BaseRequest, scope string, report *Report) bool {
+	if oneagent.IsEnabled(request) {
+		logOkf(log, "OneAgent injection is enabled for the pod")
+		report.passed(checkNamePodOneAgent, scope, "OneAgent injection is enabled for the pod")
+
+		return true
+	}
+
+	oa := request.DynaKube.OneAgent()
+
+	var reason string
+
+	switch {
+	case !oa.IsAppInjectionNeeded():
+		reason = "the Dynakube doesn't use application monitoring or cloud native fullstack"
+	case !matchesNamespaceSelector(oa.GetNamespaceSelector(), request.Namespace.Labels):
+		reason = "the namespaceSelector of the OneAgent doesn't match the namespace of the pod"
+	default:
+		reason = fmt.Sprintf("the '%s' annotation of the pod or the automatic injection feature flag disables it", oneagent.AnnotationInject)
+	}
+
+	logInfof(log, "OneAgent injection is disabled for the pod, %s", reason)
+	report.warning(checkNamePodOneAgent, scope, "Enable OneAgent injection if the pod should be monitored by the OneAgent.", "OneAgent injection is disabled, %s", reason)
+
+	return false
+}
+
+func checkPodMetadataEnrichment(log logd.Logger, request *dtwebhook.BaseRequest, scope string, report *Report) bool {
+	if metadata.NewMutator(nil).IsEnabled(request) {
+		logOkf(log, "metadata enrichment is enabled for the pod")
+		report.passed(checkNamePodMetadata, scope, "metadata enrichment is enabled for the pod")
+
+		return true
+	}
+
+	me := request.DynaKube.MetadataEnrichment()
+
+	var reason string
+
+	switch {
+	case !me.IsEnabled():
+		reason = "it's disabled in the Dynakube"
+	case !matchesNamespaceSelector(me.GetNamespaceSelector(), request.Namespace.Labels):
+		reason = "the namespaceSelector of the metadata enrichment doesn't match the namespace of the pod"
+	default:
+		reason = fmt.Sprintf("the '%s' annotation of the pod or the automatic injection feature flag disables it", metadata.AnnotationInject)
+	}
+
+	logInfof(log, "metadata enrichment is disabled for the pod, %s", reason)
+	report.warning(checkNamePodMetadata, scope, "Enable metadata enrichment if the pod should be enriched.", "metadata enrichment is disabled, %s", reason)
+
+	return false
+}
+
+func matchesNamespaceSelector(namespaceSelector *metav1.LabelSelector, namespaceLabels map[string]string) bool {
+	if namespaceSelector.Size() == 0 {
+		return true
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(namespaceLabels))
+}
+
+// checkPodAnnotations checks the pod and container level opt-outs, it returns false if the webhook skips the pod because of them.
+func checkPodAnnotations(log logd.Logger, request *dtwebhook.BaseRequest, scope string, report *Report) bool {
+	if !maputils.GetFieldBool(request.Pod.Annotations, dtwebhook.AnnotationDynatraceInject, true) {
+		logErrorf(log, "injection is disabled by the '%s' annotation", dtwebhook.AnnotationDynatraceInject)
+		report.failed(checkNamePodAnnotations, scope,
+			fmt.Sprintf("Remove the '%s: \"false\"' annotation from the pod template.", dtwebhook.AnnotationDynatraceInject),
+			errors.Errorf("injection is disabled by the '%s' annotation", dtwebhook.AnnotationDynatraceInject))
+
+		return false
+	}
+
+	var excluded []string
+
+	for _, container := range request.Pod.Spec.Containers {
+		if dtwebhook.IsContainerExcludedFromInjection(request.DynaKube.Annotations, request.Pod.Annotations, container.Name) {
+			excluded = append(excluded, container.Name)
+		}
+	}
+
+	remediation := fmt.Sprintf("Check the '%s/<container>' annotations of the pod and Dynakube.", dtwebhook.AnnotationContainerInjection)
+
+	switch {
+	case len(excluded) == len(request.Pod.Spec.Containers):
+		logErrorf(log, "all containers are excluded from injection")
+		report.failed(checkNamePodAnnotations, scope, remediation, errors.New("all containers are excluded from injection"))
+
+		return false
+	case len(excluded) > 0:
+		logWarningf(log, "containers %v are excluded from injection", excluded)
+		report.warning(checkNamePodAnnotations, scope, remediation, "containers %v are excluded from injection", excluded)
+	default:
+		logOkf(log, "no containers are excluded from injection")
+		report.passed(checkNamePodAnnotations, scope, "no containers are excluded from injection")
+	}
+
+	return true
+}
+
+func checkPodBootstrapperConfig(ctx context.Context, log logd.Logger, apiReader client.Reader, request *dtwebhook.BaseRequest, scope string, report *Report) { //nolint:revive
+	dk := request.DynaKube
+
+	secrets := map[string]string{
+		consts.BootstrapperInitSecretName: bootstrapperconfig.GetSourceConfigSecretName(dk.Name),
+	}
+
+	if dk.IsAGCertificateNeeded() || dk.Spec.TrustedCAs != "" {
+		secrets[consts.BootstrapperInitCertsSecretName] = bootstrapperconfig.GetSourceCertsSecretName(dk.Name)
+	}
+
+	for _, name := range []string{consts.BootstrapperInitSecretName, consts.BootstrapperInitCertsSecretName} {
+		sourceName, ok := secrets[name]
+		if !ok {
+			continue
+		}
+
+		err := apiReader.Get(ctx, client.ObjectKey{Namespace: request.Pod.Namespace, Name: name}, &corev1.Secret{})
+		if err == nil {
+			logOkf(log, "secret '%s' exists in namespace '%s'", name, request.Pod.Namespace)
+			report.passed(checkNamePodBootstrapper, scope, "secret '%s' exists in namespace '%s'", name, request.Pod.Namespace)
+
+			continue
+		}
+
+		remediation := "Wait for the operator to replicate the secret, then restart the pod."
+
+		sourceErr := apiReader.Get(ctx, client.ObjectKey{Namespace: dk.Namespace, Name: sourceName}, &corev1.Secret{})
+		if sourceErr != nil {
+			remediation = fmt.Sprintf("The source secret '%s:%s' doesn't exist either, check the status of the Dynakube and the logs of the operator.", dk.Namespace, sourceName)
+		}
+
+		logErrorf(log, "secret '%s' is missing in namespace '%s'", name, request.Pod.Namespace)
+		report.failed(checkNamePodBootstrapper, scope, remediation, errors.Wrapf(err, "secret '%s' is missing in namespace '%s'", name, request.Pod.Namespace))
+	}
+}
+
+// checkPodInjection explains the outcome of the webhook using the annotations it set on the pod.
+func checkPodInjection(log logd.Logger, pod *corev1.Pod, scope string, report *Report) {
+	remediation := "Restart the pod once the cause is fixed, the webhook only injects pods on creation."
+
+	injected, seen := pod.Annotations[dtwebhook.AnnotationDynatraceInjected]
+	if !seen {
+		logErrorf(log, "pod wasn't handled by the webhook")
+		report.failed(checkNamePodInjection, scope,
+			"Restart the pod. If it still isn't handled, make sure the webhook is running and reachable from the API server.",
+			errors.New("pod wasn't handled by the webhook, it was created before the namespace was monitored or the webhook wasn't reachable"))
+
+		return
+	}
+
+	if injected != "true" {
+		reason := pod.Annotations[dtwebhook.AnnotationDynatraceReason]
+		logErrorf(log, "pod wasn't injected: %s", explainInjectionReason(reason))
+		report.failed(checkNamePodInjection, scope, remediation, errors.Errorf("pod wasn't injected: %s", explainInjectionReason(reason)))
+
+		return
+	}
+
+	var partialReasons []string
+
+	for _, annotation := range []string{oneagent.AnnotationReason, metadata.AnnotationReason} {
+		if reason, ok := pod.Annotations[annotation]; ok {
+			partialReasons = append(partialReasons, explainInjectionReason(reason))
+		}
+	}
+
+	if !hasInstallContainer(pod) {
+		logErrorf(log, "init container '%s' is missing", dtwebhook.InstallContainerName)
+		report.failed(checkNamePodInjection, scope, remediation,
+			errors.Errorf("pod is annotated as injected, but the init container '%s' is missing", dtwebhook.InstallContainerName))
+
+		return
+	}
+
+	if len(partialReasons) > 0 {
+		logWarningf(log, "pod was only partially injected: %s", strings.Join(partialReasons, "; "))
+		report.warning(checkNamePodInjection, scope, remediation, "pod was only partially injected: %s", strings.Join(partialReasons, "; "))
+
+		return
+	}
+
+	logOkf(log, "pod was injected")
+	report.passed(checkNamePodInjection, scope, "pod was injected")
+}
+
+func explainInjectionReason(reason string) string {
+	if explanation, ok := injectionReasonExplanations[reason]; ok {
+		return fmt.Sprintf("%s (%s)", explanation, reason)
+	}
+
+	if reason == "" {
+		return "no reason was given"
+	}
+
+	return reason
+}
+
+func hasInstallContainer(pod *corev1.Pod) bool {
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == dtwebhook.InstallContainerName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkPodCSIVolumes reports the mount failures of the CSI volumes of the pod, which keep the pod in ContainerCreating.
+func checkPodCSIVolumes(ctx context.Context, log logd.Logger, apiReader client.Reader, pod *corev1.Pod, scope string, report *Report) { //nolint:revive
+	var volumes []string
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.CSI != nil && volume.CSI.Driver == dtcsi.DriverName {
+			volumes = append(volumes, volume.Name)
+		}
+	}
+
+	if len(volumes) == 0 {
+		logInfof(log, "pod doesn't use the CSI driver")
+
+		return
+	}
+
+	var events corev1.EventList
+
+	err := apiReader.List(ctx, &events, client.InNamespace(pod.Namespace))
+	if err != nil {
+		report.failed(checkNamePodCSI, scope, "Make sure events can be listed.", errors.Wrap(err, "failed to list events"))
+
+		return
+	}
+
+	var failures []string
+
+	for _, event := range events.Items {
+		if event.InvolvedObject.UID == pod.UID && event.Reason == failedMountEventReason && strings.Contains(event.Message, dtcsi.DriverName) {
+			failures = append(failures, event.Message)
+		}
+	}
+
+	if len(failures) > 0 && pod.Status.Phase == corev1.PodPending {
+		logErrorf(log, "CSI volumes %v couldn't be mounted: %s", volumes, failures[len(failures)-1])
+		report.failed(checkNamePodCSI, scope,
+			"Make sure the CSI driver pod is running on the node of the pod and check its logs.",
+			errors.Errorf("CSI volumes %v couldn't be mounted: %s", volumes, failures[len(failures)-1]))
+
+		return
+	}
+
+	logOkf(log, "no mount failures of CSI volumes %v", volumes)
+	report.passed(checkNamePodCSI, scope, "no mount failures of CSI volumes %v", volumes)
+}
@@ -0,0 +1,158 @@
+package troubleshoot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/handler/injection"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testPodName = "app"
+	testPodUID  = "pod-uid"
+)
+
+var testPodKey = client.ObjectKey{Namespace: testOtherNamespace, Name: testPodName}
+
+func newTestPodNamespace(withLabel bool) *corev1.Namespace {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testOtherNamespace}}
+	if withLabel {
+		namespace.Labels = map[string]string{dtwebhook.InjectionInstanceLabel: testDynakube}
+	}
+
+	return namespace
+}
+
+func newTestPod(annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testPodName,
+			Namespace:   testOtherNamespace,
+			UID:         testPodUID,
+			Annotations: annotations,
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: dtwebhook.InstallContainerName}},
+			Containers:     []corev1.Container{{Name: "app"}},
+		},
+	}
+}
+
+func newTestAppMonitoringDynakube() *dynakube.DynaKube {
+	return &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: testDynakube, Namespace: testNamespace},
+		Spec: dynakube.DynaKubeSpec{
+			OneAgent: oneagent.Spec{ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{}},
+		},
+	}
+}
+
+func newTestBootstrapperSecret() *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: consts.BootstrapperInitSecretName, Namespace: testOtherNamespace}}
+}
+
+func getPodCheckResult(t *testing.T, report *Report, name string) CheckResult {
+	for _, result := range report.Results {
+		if result.Name == name {
+			return result
+		}
+	}
+
+	require.Failf(t, "check not found", "check '%s' not in report", name)
+
+	return CheckResult{}
+}
+
+func TestRunPodChecks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("injected pod passes", func(t *testing.T) {
+		pod := newTestPod(map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"})
+		clt := fake.NewClient(pod, newTestPodNamespace(true), newTestAppMonitoringDynakube(), newTestBootstrapperSecret())
+
+		report := runPodChecks(ctx, getNullLogger(t), clt, testNamespace, testPodKey)
+
+		assert.Equal(t, 0, report.FailedCount())
+		assert.Equal(t, StatusPassed, getPodCheckResult(t, report, checkNamePodOneAgent).Status)
+		assert.Equal(t, StatusPassed, getPodCheckResult(t, report, checkNamePodInjection).Status)
+	})
+	t.Run("missing pod => failed", func(t *testing.T) {
+		report := runPodChecks(ctx, getNullLogger(t), fake.NewClient(), testNamespace, testPodKey)
+
+		require.Len(t, report.Results, 1)
+		assert.Equal(t, StatusFailed, report.Results[0].Status)
+	})
+	t.Run("unmonitored namespace => failed", func(t *testing.T) {
+		clt := fake.NewClient(newTestPod(nil), newTestPodNamespace(false))
+
+		report := runPodChecks(ctx, getNullLogger(t), clt, testNamespace, testPodKey)
+
+		result := getPodCheckResult(t, report, checkNamePodNamespace)
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Contains(t, result.Message, dtwebhook.InjectionInstanceLabel)
+	})
+	t.Run("injection disabled by annotation => failed", func(t *testing.T) {
+		pod := newTestPod(map[string]string{dtwebhook.AnnotationDynatraceInject: "false"})
+		clt := fake.NewClient(pod, newTestPodNamespace(true), newTestAppMonitoringDynakube())
+
+		report := runPodChecks(ctx, getNullLogger(t), clt, testNamespace, testPodKey)
+
+		assert.Equal(t, StatusFailed, getPodCheckResult(t, report, checkNamePodAnnotations).Status)
+		assert.Equal(t, 1, report.FailedCount())
+	})
+	t.Run("namespace selector doesn't match => warning", func(t *testing.T) {
+		dk := newTestAppMonitoringDynakube()
+		dk.Spec.OneAgent.ApplicationMonitoring.NamespaceSelector = metav1.LabelSelector{MatchLabels: map[string]string{"monitor": "true"}}
+		clt := fake.NewClient(newTestPod(nil), newTestPodNamespace(true), dk)
+
+		report := runPodChecks(ctx, getNullLogger(t), clt, testNamespace, testPodKey)
+
+		result := getPodCheckResult(t, report, checkNamePodOneAgent)
+		assert.Equal(t, StatusWarning, result.Status)
+		assert.Contains(t, result.Message, "namespaceSelector")
+	})
+	t.Run("reason annotation is explained", func(t *testing.T) {
+		pod := newTestPod(map[string]string{
+			dtwebhook.AnnotationDynatraceInjected: "false",
+			dtwebhook.AnnotationDynatraceReason:   injection.NoBootstrapperConfigReason,
+		})
+		clt := fake.NewClient(pod, newTestPodNamespace(true), newTestAppMonitoringDynakube())
+
+		report := runPodChecks(ctx, getNullLogger(t), clt, testNamespace, testPodKey)
+
+		assert.Equal(t, StatusFailed, getPodCheckResult(t, report, checkNamePodBootstrapper).Status)
+
+		result := getPodCheckResult(t, report, checkNamePodInjection)
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Contains(t, result.Message, "bootstrapper config secret")
+	})
+	t.Run("failed CSI mount => failed", func(t *testing.T) {
+		pod := newTestPod(map[string]string{dtwebhook.AnnotationDynatraceInjected: "true"})
+		pod.Spec.Volumes = []corev1.Volume{{Name: "oneagent-bin", VolumeSource: corev1.VolumeSource{CSI: &corev1.CSIVolumeSource{Driver: dtcsi.DriverName}}}}
+		pod.Status.Phase = corev1.PodPending
+		event := &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "mount-failed", Namespace: testOtherNamespace},
+			InvolvedObject: corev1.ObjectReference{UID: testPodUID},
+			Reason:         failedMountEventReason,
+			Message:        "MountVolume.SetUp failed for volume \"oneagent-bin\" : kubernetes.io/csi: driver name " + dtcsi.DriverName + " not found",
+		}
+		clt := fake.NewClient(pod, event, newTestPodNamespace(true), newTestAppMonitoringDynakube(), newTestBootstrapperSecret())
+
+		report := runPodChecks(ctx, getNullLogger(t), clt, testNamespace, testPodKey)
+
+		result := getPodCheckResult(t, report, checkNamePodCSI)
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Contains(t, result.Message, "oneagent-bin")
+	})
+}
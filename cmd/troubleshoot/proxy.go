@@ -29,7 +29,7 @@ func checkProxySettings(ctx context.Context, baseLog logd.Logger, apiReader clie
 		if err != nil {
 			logErrorf(log, "Unexpected error when reading proxy settings from Dynakube: %v", err)
 
-			return nil
+			return err
 		}
 	}
 
@@ -0,0 +1,180 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type CheckStatus string
+
+const (
+	StatusPassed  CheckStatus = "passed"
+	StatusWarning CheckStatus = "warning"
+	StatusFailed  CheckStatus = "failed"
+)
+
+const (
+	checkNameOneAgentAPM = "oneAgentAPM"
+	checkNameCluster     = "cluster"
+	checkNameNamespace   = "namespace"
+	checkNameCRD         = "crd"
+	checkNameDynakube    = "dynakube"
+	checkNameAPIToken    = "apiToken"
+	checkNameTokenScopes = "tokenScopes"
+	checkNameAPIURL      = "apiUrl"
+	checkNamePullSecret  = "pullSecret"
+	checkNameImagePrefix = "image/"
+	checkNameProxy       = "proxy"
+
+	// clusterScope is used as DynaKube of the checks, which are not specific to a DynaKube
+	clusterScope = "cluster"
+)
+
+// CheckResult is the outcome of a single troubleshoot check.
+type CheckResult struct {
+	Name        string      `json:"name"`
+	DynaKube    string      `json:"dynakube,omitempty"`
+	Status      CheckStatus `json:"status"`
+	Message     string      `json:"message,omitempty"`
+	Remediation string      `json:"remediation,omitempty"`
+}
+
+// Report collects the results of all checks in the order they were run.
+type Report struct {
+	Results []CheckResult `json:"results"`
+}
+
+func (r *Report) passed(name, dk, format string, v ...any) {
+	r.add(CheckResult{Name: name, DynaKube: dk, Status: StatusPassed, Message: fmt.Sprintf(format, v...)})
+}
+
+func (r *Report) warning(name, dk, remediation, format string, v ...any) {
+	r.add(CheckResult{Name: name, DynaKube: dk, Status: StatusWarning, Message: fmt.Sprintf(format, v...), Remediation: remediation})
+}
+
+func (r *Report) failed(name, dk, remediation string, err error) {
+	r.add(CheckResult{Name: name, DynaKube: dk, Status: StatusFailed, Message: err.Error(), Remediation: remediation})
+}
+
+// record adds a passed or failed result depending on err.
+func (r *Report) record(name, dk, remediation string, err error, format string, v ...any) {
+	if err != nil {
+		r.failed(name, dk, remediation, err)
+	} else {
+		r.passed(name, dk, format, v...)
+	}
+}
+
+func (r *Report) add(result CheckResult) {
+	r.Results = append(r.Results, result)
+}
+
+// FailedCount returns the number of failed checks.
+func (r *Report) FailedCount() int {
+	count := 0
+
+	for _, result := range r.Results {
+		if result.Status == StatusFailed {
+			count++
+		}
+	}
+
+	return count
+}
+
+func writeJSONReport(out io.Writer, report *Report) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	return errors.WithStack(encoder.Encode(report))
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+}
+
+type junitTestCase struct {
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes one test suite per DynaKube, the cluster wide checks are grouped into their own suite.
+// JUnit has no concept of warnings, so they are reported as passed test cases with the warning as output.
+func writeJUnitReport(out io.Writer, report *Report) error {
+	suites := junitTestSuites{Name: use}
+	suiteIndex := map[string]int{}
+
+	for _, result := range report.Results {
+		scope := result.DynaKube
+		if scope == "" {
+			scope = clusterScope
+		}
+
+		index, ok := suiteIndex[scope]
+		if !ok {
+			index = len(suites.Suites)
+			suiteIndex[scope] = index
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: scope})
+		}
+
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: use + "." + scope,
+		}
+
+		switch result.Status {
+		case StatusFailed:
+			testCase.Failure = &junitFailure{Message: result.Message, Text: result.Remediation}
+			suites.Suites[index].Failures++
+			suites.Failures++
+		case StatusWarning:
+			testCase.SystemOut = strings.TrimSpace(fmt.Sprintf("%s: %s. %s", result.Status, result.Message, result.Remediation))
+		case StatusPassed:
+			testCase.SystemOut = result.Message
+		}
+
+		suites.Suites[index].TestCases = append(suites.Suites[index].TestCases, testCase)
+		suites.Suites[index].Tests++
+		suites.Tests++
+	}
+
+	_, err := io.WriteString(out, xml.Header)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "  ")
+
+	err = encoder.Encode(suites)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = io.WriteString(out, "\n")
+
+	return errors.WithStack(err)
+}
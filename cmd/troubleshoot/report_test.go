@@ -0,0 +1,109 @@
+package troubleshoot
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDynakubeKey = testNamespace + "/" + testDynakube
+
+func newTestReport() *Report {
+	report := &Report{}
+	report.record(checkNameNamespace, "", "remediation", nil, "using namespace '%s'", testNamespace)
+	report.record(checkNameAPIToken, testDynakubeKey, "add token", errors.New("token missing"), "ignored")
+	report.warning(checkNameProxy, testDynakubeKey, "check proxy", "proxy in environment")
+
+	return report
+}
+
+func TestReport(t *testing.T) {
+	report := newTestReport()
+
+	require.Len(t, report.Results, 3)
+	assert.Equal(t, CheckResult{Name: checkNameNamespace, Status: StatusPassed, Message: "using namespace '" + testNamespace + "'"}, report.Results[0])
+	assert.Equal(t, CheckResult{Name: checkNameAPIToken, DynaKube: testDynakubeKey, Status: StatusFailed, Message: "token missing", Remediation: "add token"}, report.Results[1])
+	assert.Equal(t, StatusWarning, report.Results[2].Status)
+	assert.Equal(t, 1, report.FailedCount())
+	assert.Equal(t, 0, (&Report{}).FailedCount())
+}
+
+func TestWriteJSONReport(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	require.NoError(t, writeJSONReport(out, newTestReport()))
+
+	var parsed Report
+
+	require.NoError(t, json.Unmarshal(out.Bytes(), &parsed))
+	assert.Equal(t, *newTestReport(), parsed)
+	assert.Contains(t, out.String(), `"status": "failed"`)
+	assert.Contains(t, out.String(), `"remediation": "add token"`)
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	require.NoError(t, writeJUnitReport(out, newTestReport()))
+
+	var parsed junitTestSuites
+
+	require.NoError(t, xml.Unmarshal(out.Bytes(), &parsed))
+	assert.Equal(t, 3, parsed.Tests)
+	assert.Equal(t, 1, parsed.Failures)
+	require.Len(t, parsed.Suites, 2)
+
+	assert.Equal(t, clusterScope, parsed.Suites[0].Name)
+	require.Len(t, parsed.Suites[0].TestCases, 1)
+	assert.Nil(t, parsed.Suites[0].TestCases[0].Failure)
+
+	assert.Equal(t, testDynakubeKey, parsed.Suites[1].Name)
+	assert.Equal(t, 2, parsed.Suites[1].Tests)
+	assert.Equal(t, 1, parsed.Suites[1].Failures)
+	require.NotNil(t, parsed.Suites[1].TestCases[0].Failure)
+	assert.Equal(t, "token missing", parsed.Suites[1].TestCases[0].Failure.Message)
+	assert.Equal(t, "add token", parsed.Suites[1].TestCases[0].Failure.Text)
+	assert.Contains(t, parsed.Suites[1].TestCases[1].SystemOut, "proxy in environment")
+}
+
+func TestFinishReport(t *testing.T) {
+	t.Run("text with failed checks => error", func(t *testing.T) {
+		err := finishReportTo(&bytes.Buffer{}, nil, newTestReport())
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrReportFailed)
+	})
+
+	t.Run("structured with failed checks => report written, not logged error", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := finishReportTo(out, writeJSONReport, newTestReport())
+		require.ErrorIs(t, err, ErrReportFailed)
+		assert.Contains(t, out.String(), `"status": "failed"`)
+	})
+
+	t.Run("no failed checks => no error", func(t *testing.T) {
+		require.NoError(t, finishReportTo(&bytes.Buffer{}, writeJSONReport, &Report{}))
+	})
+}
+
+func TestGetReportWriter(t *testing.T) {
+	writer, err := getReportWriter(outputText)
+	require.NoError(t, err)
+	assert.Nil(t, writer)
+
+	writer, err = getReportWriter(outputJSON)
+	require.NoError(t, err)
+	assert.NotNil(t, writer)
+
+	writer, err = getReportWriter(outputJUnit)
+	require.NoError(t, err)
+	assert.NotNil(t, writer)
+
+	_, err = getReportWriter("yaml")
+	require.Error(t, err)
+}
@@ -0,0 +1,11 @@
+package api
+
+// TokensHashAnnotation marks the objects, which contain the tokens, with the hash of the tokens they were generated from,
+// so rotated tokens are rolled out and the rollout can be tracked by the TokenRotation condition.
+func TokensHashAnnotation(tokensHash string, containsTokens bool) map[string]string {
+	if !containsTokens || tokensHash == "" {
+		return nil
+	}
+
+	return map[string]string{AnnotationTokensHash: tokensHash}
+}
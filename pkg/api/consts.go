@@ -5,4 +5,6 @@ const (
 	RawTag                         = "raw"
 	InternalFlagPrefix             = "internal.operator.dynatrace.com/"
 	AnnotationExtensionsSecretHash = InternalFlagPrefix + "extensions-secret-hash"
+	AnnotationTokensHash           = InternalFlagPrefix + "tokens-hash"
+	AnnotationNodeName             = InternalFlagPrefix + "node-name"
 )
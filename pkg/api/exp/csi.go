@@ -6,8 +6,10 @@ import (
 )
 
 const (
+	// Deprecated: Use CSIMaxMountTimeoutKey instead.
 	CSIMaxFailedMountAttemptsKey = FFPrefix + "max-csi-mount-attempts"
-	CSIMaxMountTimeoutKey        = FFPrefix + "max-csi-mount-timeout"
+
+	CSIMaxMountTimeoutKey = FFPrefix + "max-csi-mount-timeout"
 )
 
 const (
@@ -0,0 +1,110 @@
+package exp
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type flagKind int
+
+const (
+	boolFlag flagKind = iota
+	intFlag
+	nonNegativeIntFlag
+	durationFlag
+	stringFlag
+	stringListFlag
+	failurePolicyFlag
+)
+
+// knownFlags contains every feature flag the operator understands, together with the type of value it expects.
+var knownFlags = map[string]flagKind{
+	PublicRegistryKey:      boolFlag,
+	NoProxyKey:             stringFlag,
+	APIRequestThresholdKey: nonNegativeIntFlag,
+
+	AGDisableUpdatesKey:                       boolFlag,
+	AGIgnoreProxyKey:                          boolFlag,
+	AGUpdatesKey:                              boolFlag,
+	AGAppArmorKey:                             boolFlag,
+	AGAutomaticK8sAPIMonitoringKey:            boolFlag,
+	AGAutomaticK8sAPIMonitoringClusterNameKey: stringFlag,
+	AGK8sAppEnabledKey:                        boolFlag,
+	AGAutomaticTLSCertificateKey:              boolFlag,
+
+	CSIMaxFailedMountAttemptsKey: nonNegativeIntFlag,
+	CSIMaxMountTimeoutKey:        durationFlag,
+
+	InjectionDisableMetadataEnrichmentKey: boolFlag,
+	InjectionMetadataEnrichmentKey:        boolFlag,
+	InjectionIgnoredNamespacesKey:         stringListFlag,
+	InjectionAutomaticKey:                 boolFlag,
+	InjectionLabelVersionDetectionKey:     boolFlag,
+	InjectionFailurePolicyKey:             failurePolicyFlag,
+	InjectionSeccompKey:                   boolFlag,
+
+	OAProxyIgnoredKey:              boolFlag,
+	OAMaxUnavailableKey:            nonNegativeIntFlag,
+	OAInitialConnectRetryKey:       intFlag,
+	OAPrivilegedKey:                boolFlag,
+	OASkipLivenessProbeKey:         boolFlag,
+	OASecCompProfileKey:            stringFlag,
+	OANodeImagePullKey:             boolFlag,
+	OANodeImagePullTechnologiesKey: stringFlag,
+}
+
+// IsFeatureFlag returns true if the annotation uses the feature flag prefix, regardless of whether it is known.
+func IsFeatureFlag(annotation string) bool {
+	return strings.HasPrefix(annotation, FFPrefix)
+}
+
+// IsKnownFlag returns true if the annotation is a feature flag the operator understands.
+func IsKnownFlag(annotation string) bool {
+	_, ok := knownFlags[annotation]
+
+	return ok
+}
+
+// ValidateFlag checks if the value of a known feature flag can be parsed.
+// Without this check a malformed value silently falls back to the default of the feature flag.
+// Unknown feature flags are not checked, use IsKnownFlag for those.
+func ValidateFlag(annotation, raw string) error {
+	kind, ok := knownFlags[annotation]
+	if !ok {
+		return nil
+	}
+
+	switch kind {
+	case boolFlag:
+		if _, err := strconv.ParseBool(raw); err != nil {
+			return errors.Errorf("expected a boolean, got %q", raw)
+		}
+	case intFlag:
+		if _, err := strconv.Atoi(raw); err != nil {
+			return errors.Errorf("expected an integer, got %q", raw)
+		}
+	case nonNegativeIntFlag:
+		if val, err := strconv.Atoi(raw); err != nil || val < 0 {
+			return errors.Errorf("expected a non-negative integer, got %q", raw)
+		}
+	case durationFlag:
+		if val, err := time.ParseDuration(raw); err != nil || val < 0 {
+			return errors.Errorf("expected a non-negative duration (e.g. 10m), got %q", raw)
+		}
+	case stringListFlag:
+		if err := json.Unmarshal([]byte(raw), &[]string{}); err != nil {
+			return errors.Errorf("expected a JSON list of strings, got %q", raw)
+		}
+	case failurePolicyFlag:
+		if raw != silentPhrase && raw != failPhrase {
+			return errors.Errorf("expected %q or %q, got %q", silentPhrase, failPhrase, raw)
+		}
+	case stringFlag:
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package exp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFlag(t *testing.T) {
+	type testCase struct {
+		title string
+		key   string
+		in    string
+		valid bool
+	}
+
+	cases := []testCase{
+		{title: "bool", key: InjectionAutomaticKey, in: "false", valid: true},
+		{title: "malformed bool", key: InjectionAutomaticKey, in: "nope", valid: false},
+		{title: "int", key: OAInitialConnectRetryKey, in: "-1", valid: true},
+		{title: "malformed int", key: OAInitialConnectRetryKey, in: "1s", valid: false},
+		{title: "non-negative int", key: OAMaxUnavailableKey, in: "0", valid: true},
+		{title: "negative non-negative int", key: OAMaxUnavailableKey, in: "-1", valid: false},
+		{title: "duration", key: CSIMaxMountTimeoutKey, in: "2h", valid: true},
+		{title: "malformed duration", key: CSIMaxMountTimeoutKey, in: "10", valid: false},
+		{title: "string list", key: InjectionIgnoredNamespacesKey, in: `["^test$"]`, valid: true},
+		{title: "malformed string list", key: InjectionIgnoredNamespacesKey, in: "^test$", valid: false},
+		{title: "failure policy", key: InjectionFailurePolicyKey, in: failPhrase, valid: true},
+		{title: "malformed failure policy", key: InjectionFailurePolicyKey, in: "ignore", valid: false},
+		{title: "string", key: NoProxyKey, in: "anything", valid: true},
+		{title: "unknown flag is not checked", key: FFPrefix + "unknown", in: "anything", valid: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			err := ValidateFlag(c.key, c.in)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestIsKnownFlag(t *testing.T) {
+	assert.True(t, IsKnownFlag(InjectionAutomaticKey))
+	assert.True(t, IsKnownFlag(OANodeImagePullTechnologiesKey))
+	assert.False(t, IsKnownFlag(FFPrefix+"automatic-injections"))
+	assert.True(t, IsFeatureFlag(FFPrefix+"automatic-injections"))
+	assert.False(t, IsFeatureFlag(OANodeImagePullTechnologiesKey))
+}
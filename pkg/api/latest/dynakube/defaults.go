@@ -0,0 +1,113 @@
+package dynakube
+
+import (
+	v1beta6 "github.com/Dynatrace/dynatrace-operator/pkg/api/latest"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DynaKubeDefaults holds settings shared by several DynaKubes. A DynaKube references it via spec.defaultsRef,
+// every field the DynaKube leaves empty is taken from the DynaKubeDefaults.
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=dynakubedefaults,scope=Cluster,categories=dynatrace,shortName={dkd}
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type DynaKubeDefaults struct { //nolint:revive
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              DynaKubeDefaultsSpec `json:"spec,omitempty"`
+}
+
+// DynaKubeDefaultsSpec contains the defaults for a DynaKube.
+// Fields that reference other objects (proxy secret, trustedCAs ConfigMap, pull secret) are looked up in the namespace of the DynaKube.
+// +k8s:openapi-gen=true
+type DynaKubeDefaultsSpec struct { //nolint:revive
+	// Default for spec.proxy.
+	// +kubebuilder:validation:Optional
+	Proxy *value.Source `json:"proxy,omitempty"`
+
+	// Default for spec.trustedCAs.
+	// +kubebuilder:validation:Optional
+	TrustedCAs string `json:"trustedCAs,omitempty"`
+
+	// Default for spec.networkZone.
+	// +kubebuilder:validation:Optional
+	NetworkZone string `json:"networkZone,omitempty"`
+
+	// Default for spec.customPullSecret.
+	// +kubebuilder:validation:Optional
+	CustomPullSecret string `json:"customPullSecret,omitempty"`
+
+	// Defaults for the OneAgent, applied to whichever OneAgent mode the DynaKube uses.
+	// +kubebuilder:validation:Optional
+	OneAgent *OneAgentDefaultsSpec `json:"oneAgent,omitempty"`
+
+	// Defaults for the ActiveGate, only applied if the DynaKube enables the ActiveGate.
+	// +kubebuilder:validation:Optional
+	ActiveGate *ActiveGateDefaultsSpec `json:"activeGate,omitempty"`
+}
+
+type OneAgentDefaultsSpec struct {
+	// Default OneAgent image.
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// Default code modules image.
+	// +kubebuilder:validation:Optional
+	CodeModulesImage string `json:"codeModulesImage,omitempty"`
+
+	// Default priority class of the OneAgent pods.
+	// +kubebuilder:validation:Optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Default tolerations of the OneAgent pods.
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Default node selector of the OneAgent pods.
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Default resource requirements of the OneAgent container.
+	// +kubebuilder:validation:Optional
+	OneAgentResources *corev1.ResourceRequirements `json:"oneAgentResources,omitempty"`
+}
+
+type ActiveGateDefaultsSpec struct {
+	// Default ActiveGate image.
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// Default priority class of the ActiveGate pods.
+	// +kubebuilder:validation:Optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Default tolerations of the ActiveGate pods.
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Default node selector of the ActiveGate pods.
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Default resource requirements of the ActiveGate container.
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DynaKubeDefaultsList contains a list of DynaKubeDefaults
+// +kubebuilder:object:root=true
+type DynaKubeDefaultsList struct { //nolint:revive
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DynaKubeDefaults `json:"items"`
+}
+
+func init() {
+	v1beta6.SchemeBuilder.Register(&DynaKubeDefaults{}, &DynaKubeDefaultsList{})
+}
@@ -0,0 +1,123 @@
+package dynakube
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultsName returns the name of the DynaKubeDefaults referenced by the DynaKube.
+func (dk *DynaKube) DefaultsName() string {
+	return dk.Spec.DefaultsRef
+}
+
+// MergeDefaults fills the fields the DynaKube leaves empty with the values of the referenced DynaKubeDefaults.
+// The merge only happens in memory, the DynaKube in the cluster is not changed.
+func (dk *DynaKube) MergeDefaults(ctx context.Context, reader client.Reader) error {
+	if dk.DefaultsName() == "" {
+		return nil
+	}
+
+	var defaults DynaKubeDefaults
+
+	err := reader.Get(ctx, client.ObjectKey{Name: dk.DefaultsName()}, &defaults)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get DynaKubeDefaults %s", dk.DefaultsName())
+	}
+
+	dk.ApplyDefaults(defaults.Spec)
+
+	return nil
+}
+
+// ApplyDefaults fills the fields the DynaKube leaves empty with the values of the given DynaKubeDefaultsSpec.
+func (dk *DynaKube) ApplyDefaults(defaults DynaKubeDefaultsSpec) {
+	if dk.Spec.Proxy == nil && defaults.Proxy != nil {
+		dk.Spec.Proxy = defaults.Proxy.DeepCopy()
+	}
+
+	defaultTo(&dk.Spec.TrustedCAs, defaults.TrustedCAs)
+	defaultTo(&dk.Spec.NetworkZone, defaults.NetworkZone)
+	defaultTo(&dk.Spec.CustomPullSecret, defaults.CustomPullSecret)
+
+	if defaults.OneAgent != nil {
+		dk.applyOneAgentDefaults(*defaults.OneAgent)
+	}
+
+	if defaults.ActiveGate != nil && dk.ActiveGate().IsEnabled() {
+		dk.applyActiveGateDefaults(*defaults.ActiveGate)
+	}
+}
+
+func (dk *DynaKube) applyOneAgentDefaults(defaults OneAgentDefaultsSpec) {
+	var hostSpec *oneagent.HostInjectSpec
+
+	switch {
+	case dk.Spec.OneAgent.ClassicFullStack != nil:
+		hostSpec = dk.Spec.OneAgent.ClassicFullStack
+	case dk.Spec.OneAgent.HostMonitoring != nil:
+		hostSpec = dk.Spec.OneAgent.HostMonitoring
+	case dk.Spec.OneAgent.CloudNativeFullStack != nil:
+		hostSpec = &dk.Spec.OneAgent.CloudNativeFullStack.HostInjectSpec
+	}
+
+	if hostSpec != nil {
+		defaultTo(&hostSpec.Image, defaults.Image)
+		defaultTo(&hostSpec.PriorityClassName, defaults.PriorityClassName)
+		defaultTolerations(&hostSpec.Tolerations, defaults.Tolerations)
+		defaultNodeSelector(&hostSpec.NodeSelector, defaults.NodeSelector)
+		defaultResources(&hostSpec.OneAgentResources, defaults.OneAgentResources)
+	}
+
+	var appSpec *oneagent.AppInjectionSpec
+
+	switch {
+	case dk.Spec.OneAgent.CloudNativeFullStack != nil:
+		appSpec = &dk.Spec.OneAgent.CloudNativeFullStack.AppInjectionSpec
+	case dk.Spec.OneAgent.ApplicationMonitoring != nil:
+		appSpec = &dk.Spec.OneAgent.ApplicationMonitoring.AppInjectionSpec
+	}
+
+	if appSpec != nil {
+		defaultTo(&appSpec.CodeModulesImage, defaults.CodeModulesImage)
+	}
+}
+
+func (dk *DynaKube) applyActiveGateDefaults(defaults ActiveGateDefaultsSpec) {
+	agSpec := &dk.Spec.ActiveGate
+
+	defaultTo(&agSpec.Image, defaults.Image)
+	defaultTo(&agSpec.PriorityClassName, defaults.PriorityClassName)
+	defaultTolerations(&agSpec.Tolerations, defaults.Tolerations)
+	defaultNodeSelector(&agSpec.NodeSelector, defaults.NodeSelector)
+	defaultResources(&agSpec.Resources, defaults.Resources)
+}
+
+func defaultTo(field *string, defaultValue string) {
+	if *field == "" {
+		*field = defaultValue
+	}
+}
+
+func defaultTolerations(field *[]corev1.Toleration, defaultValue []corev1.Toleration) {
+	if len(*field) == 0 && len(defaultValue) > 0 {
+		*field = slices.Clone(defaultValue)
+	}
+}
+
+func defaultNodeSelector(field *map[string]string, defaultValue map[string]string) {
+	if len(*field) == 0 && len(defaultValue) > 0 {
+		*field = maps.Clone(defaultValue)
+	}
+}
+
+func defaultResources(field *corev1.ResourceRequirements, defaultValue *corev1.ResourceRequirements) {
+	if defaultValue != nil && len(field.Limits) == 0 && len(field.Requests) == 0 && len(field.Claims) == 0 {
+		*field = *defaultValue.DeepCopy()
+	}
+}
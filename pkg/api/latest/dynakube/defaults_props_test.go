@@ -0,0 +1,112 @@
+package dynakube
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func getTestDefaults() DynaKubeDefaultsSpec {
+	return DynaKubeDefaultsSpec{
+		Proxy:            &value.Source{Value: "http://proxy.example.com"},
+		TrustedCAs:       "default-cas",
+		NetworkZone:      "default-zone",
+		CustomPullSecret: "default-pull-secret",
+		OneAgent: &OneAgentDefaultsSpec{
+			Image:             "registry.example.com/oneagent:1.2.3",
+			CodeModulesImage:  "registry.example.com/codemodules:1.2.3",
+			PriorityClassName: "default-priority",
+			Tolerations:       []corev1.Toleration{{Key: "default", Operator: corev1.TolerationOpExists}},
+			OneAgentResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			},
+		},
+		ActiveGate: &ActiveGateDefaultsSpec{
+			Image:        "registry.example.com/activegate:1.2.3",
+			NodeSelector: map[string]string{"pool": "infra"},
+		},
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	t.Run("empty fields are filled", func(t *testing.T) {
+		dk := DynaKube{
+			Spec: DynaKubeSpec{
+				OneAgent: oneagent.Spec{CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{}},
+				ActiveGate: activegate.Spec{
+					Capabilities: []activegate.CapabilityDisplayName{activegate.KubeMonCapability.DisplayName},
+				},
+			},
+		}
+		defaults := getTestDefaults()
+
+		dk.ApplyDefaults(defaults)
+
+		assert.Equal(t, defaults.Proxy, dk.Spec.Proxy)
+		assert.NotSame(t, defaults.Proxy, dk.Spec.Proxy)
+		assert.Equal(t, "default-cas", dk.Spec.TrustedCAs)
+		assert.Equal(t, "default-zone", dk.Spec.NetworkZone)
+		assert.Equal(t, "default-pull-secret", dk.Spec.CustomPullSecret)
+
+		cloudNative := dk.Spec.OneAgent.CloudNativeFullStack
+		assert.Equal(t, defaults.OneAgent.Image, cloudNative.Image)
+		assert.Equal(t, defaults.OneAgent.CodeModulesImage, cloudNative.CodeModulesImage)
+		assert.Equal(t, defaults.OneAgent.PriorityClassName, cloudNative.PriorityClassName)
+		assert.Equal(t, defaults.OneAgent.Tolerations, cloudNative.Tolerations)
+		assert.Equal(t, *defaults.OneAgent.OneAgentResources, cloudNative.OneAgentResources)
+
+		assert.Equal(t, defaults.ActiveGate.Image, dk.Spec.ActiveGate.Image)
+		assert.Equal(t, defaults.ActiveGate.NodeSelector, dk.Spec.ActiveGate.NodeSelector)
+	})
+	t.Run("fields set in the DynaKube are kept", func(t *testing.T) {
+		tolerations := []corev1.Toleration{{Key: "own", Operator: corev1.TolerationOpExists}}
+		dk := DynaKube{
+			Spec: DynaKubeSpec{
+				Proxy:      &value.Source{ValueFrom: "own-proxy"},
+				TrustedCAs: "own-cas",
+				OneAgent: oneagent.Spec{ClassicFullStack: &oneagent.HostInjectSpec{
+					Image:       "own-image",
+					Tolerations: tolerations,
+				}},
+			},
+		}
+
+		dk.ApplyDefaults(getTestDefaults())
+
+		assert.Equal(t, "own-proxy", dk.Spec.Proxy.ValueFrom)
+		assert.Empty(t, dk.Spec.Proxy.Value)
+		assert.Equal(t, "own-cas", dk.Spec.TrustedCAs)
+		assert.Equal(t, "default-zone", dk.Spec.NetworkZone)
+		assert.Equal(t, "own-image", dk.Spec.OneAgent.ClassicFullStack.Image)
+		assert.Equal(t, tolerations, dk.Spec.OneAgent.ClassicFullStack.Tolerations)
+		assert.Equal(t, "default-priority", dk.Spec.OneAgent.ClassicFullStack.PriorityClassName)
+	})
+	t.Run("application monitoring only gets the code modules image", func(t *testing.T) {
+		dk := DynaKube{
+			Spec: DynaKubeSpec{
+				OneAgent: oneagent.Spec{ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{}},
+			},
+		}
+
+		dk.ApplyDefaults(getTestDefaults())
+
+		require.NotNil(t, dk.Spec.OneAgent.ApplicationMonitoring)
+		assert.Equal(t, "registry.example.com/codemodules:1.2.3", dk.Spec.OneAgent.ApplicationMonitoring.CodeModulesImage)
+		assert.Nil(t, dk.Spec.OneAgent.CloudNativeFullStack)
+		assert.Nil(t, dk.Spec.OneAgent.ClassicFullStack)
+	})
+	t.Run("disabled ActiveGate is not touched", func(t *testing.T) {
+		dk := DynaKube{}
+
+		dk.ApplyDefaults(getTestDefaults())
+
+		assert.Empty(t, dk.Spec.ActiveGate.Image)
+		assert.Empty(t, dk.Spec.ActiveGate.NodeSelector)
+	})
+}
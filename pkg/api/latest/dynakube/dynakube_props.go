@@ -27,7 +27,7 @@ const (
 var log = logd.Get().WithName("dynakube-v1beta6")
 
 func (dk *DynaKube) FF() *exp.FeatureFlags {
-	return exp.NewFlags(dk.Annotations)
+	return exp.NewFlags(dk.featureFlagAnnotations())
 }
 
 func (dk *DynaKube) RemovedFields() *conversion.RemovedFields {
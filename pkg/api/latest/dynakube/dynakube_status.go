@@ -41,11 +41,24 @@ type DynaKubeStatus struct { //nolint:revive
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:text"
 	UpdatedTimestamp metav1.Time `json:"updatedTimestamp,omitempty"`
 
-	// ProxyURLHash is the hashed value of what is in spec.proxy.
+	// ProxyURLHash is the hashed value of the resolved proxies of spec.proxy and spec.proxies, including the credentials.
 	// Used for setting it as an annotation value for components that use the proxy.
 	// This annotation will cause the component to be restarted if the proxy changes.
 	ProxyURLHash string `json:"proxyURLHash,omitempty"`
 
+	// TokensHash is the hashed value of the tokens in the secret referenced by spec.tokens.
+	// Used to detect the rotation of the tokens and set as an annotation value of the generated config secrets, which contain the tokens.
+	TokensHash string `json:"tokensHash,omitempty"`
+
+	// DataIngestTokenHash is the hashed value of the data ingest token in the secret referenced by spec.tokens.
+	// Used to detect the rotation of the data ingest token, which is the only token consumed by the pods of the components.
+	DataIngestTokenHash string `json:"dataIngestTokenHash,omitempty"`
+
+	// DataIngestTokenRolloutHash is set as an annotation value for the components that consume the data ingest token.
+	// This annotation will cause the component to be restarted if the data ingest token is rotated.
+	// It's only set after a rotation, so seeding the hashes on the first reconcile doesn't restart the components.
+	DataIngestTokenRolloutHash string `json:"dataIngestTokenRolloutHash,omitempty"`
+
 	// Observed state of Dynatrace API
 	DynatraceAPI DynatraceAPIStatus `json:"dynatraceApi,omitempty"`
 
@@ -13,6 +13,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/otlp"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/telemetryingest"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -30,6 +31,12 @@ const (
 
 	// DataIngestTokenConditionType identifies the DataIngest Token validity condition.
 	DataIngestTokenConditionType string = "DataIngestToken"
+
+	// TokenRotationConditionType identifies the progress of rolling out rotated tokens to the components.
+	TokenRotationConditionType string = "TokenRotation"
+
+	// CSIStorageBudgetConditionType identifies the nodes, on which the CSI driver refused to download CodeModules.
+	CSIStorageBudgetConditionType string = "CSIStorageBudget"
 )
 
 // Possible reasons for APIToken and PaaSToken conditions.
@@ -41,6 +48,22 @@ const (
 	ReasonTokenError string = "TokenError"
 )
 
+// Possible reasons for the TokenRotation condition.
+const (
+	// ReasonTokenRotationInProgress is set when the tokens changed and the components are being restarted.
+	ReasonTokenRotationInProgress string = "RotationInProgress"
+
+	// ReasonTokenRotationCompleted is set when all components have been restarted with the rotated tokens.
+	ReasonTokenRotationCompleted string = "RotationCompleted"
+)
+
+// Possible reasons for the CSIStorageBudget condition.
+const (
+	// ReasonStorageBudgetExceeded is set when a CodeModule would exceed the storage budget of the CSI driver on a node.
+	// It is also the reason of the events sent by the CSI provisioners, which the condition is aggregated from.
+	ReasonStorageBudgetExceeded string = "StorageBudgetExceeded"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // DynaKube is the Schema for the DynaKube API
@@ -78,6 +101,10 @@ type DynaKubeSpec struct { //nolint:revive
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Proxy",order=3,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	Proxy *value.Source `json:"proxy,omitempty"`
 
+	// Overrides the proxy settings per component, components without an override use the proxy of spec.proxy.
+	// +kubebuilder:validation:Optional
+	Proxies *ProxiesSpec `json:"proxies,omitempty"`
+
 	// General configuration about the LogMonitoring feature.
 	// +kubebuilder:validation:Optional
 	LogMonitoring *logmonitoring.Spec `json:"logMonitoring,omitempty"`
@@ -91,6 +118,13 @@ type DynaKubeSpec struct { //nolint:revive
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Dynatrace API Request Threshold",order=9,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	DynatraceAPIRequestThreshold *uint16 `json:"dynatraceApiRequestThreshold,omitempty"`
 
+	// Maintenance windows in which automatic updates of OneAgent, ActiveGate and code modules are applied.
+	// New versions found outside of the windows are kept pending until the next window starts.
+	// If no window is configured, updates are applied immediately.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maintenance Windows",order=10,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	MaintenanceWindows []maintenance.Window `json:"maintenanceWindows,omitempty"`
+
 	// When an (empty) ExtensionsSpec is provided, the extensions related components (extensions controller and extensions collector)
 	// are deployed by the operator.
 	// +kubebuilder:validation:Optional
@@ -159,6 +193,24 @@ type DynaKubeSpec struct { //nolint:revive
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Istio automatic management",order=9,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	EnableIstio bool `json:"enableIstio,omitempty"`
+
+	// Additional configuration of the Istio objects, only considered if enableIstio is set.
+	// +kubebuilder:validation:Optional
+	Istio *IstioSpec `json:"istio,omitempty"`
+
+	// When a NetworkPolicySpec is provided, the operator creates egress policies, which allow the OneAgent, ActiveGate
+	// and OTel collector to reach the Dynatrace communication hosts and their proxy, and keeps them up to date.
+	// +kubebuilder:validation:Optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// Typed replacement for the feature.dynatrace.com/* annotations. A field set here takes precedence over the
+	// corresponding annotation. Deprecated feature flags have no field here and are only read from the annotations.
+	// +kubebuilder:validation:Optional
+	Experimental *ExperimentalSpec `json:"experimental,omitempty"`
+
+	// Name of a cluster-scoped DynaKubeDefaults, whose values are used for every field this DynaKube leaves empty.
+	// +kubebuilder:validation:Optional
+	DefaultsRef string `json:"defaultsRef,omitempty"`
 }
 
 type TemplatesSpec struct {
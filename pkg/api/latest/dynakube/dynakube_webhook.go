@@ -11,3 +11,10 @@ func SetupWebhookWithManager(mgr ctrl.Manager, validator admission.CustomValidat
 		WithValidator(validator). // will create an endpoint at /validate-dynatrace-com-v1beta6-dynakube
 		Complete()
 }
+
+func SetupDefaultsWebhookWithManager(mgr ctrl.Manager, validator admission.CustomValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&DynaKubeDefaults{}).
+		WithValidator(validator). // will create an endpoint at /validate-dynatrace-com-v1beta6-dynakubedefaults
+		Complete()
+}
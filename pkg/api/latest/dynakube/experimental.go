@@ -0,0 +1,91 @@
+package dynakube
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExperimentalSpec mirrors the feature.dynatrace.com/* annotations, every field is named after its annotation.
+// Fields that are not set fall back to the annotation and after that to the default of the feature flag.
+// Deprecated feature flags have no field and keep working as annotations only: dynatrace-api-request-threshold and
+// oneagent-seccomp-profile have dedicated fields in the spec, activegate-updates and disable-activegate-updates are replaced by
+// the ActiveGate image and version, activegate-ignore-proxy and oneagent-ignore-proxy are replaced by no-proxy and
+// max-csi-mount-attempts is replaced by max-csi-mount-timeout.
+type ExperimentalSpec struct {
+	// Pull the images from the public Dynatrace registry instead of the tenant registry.
+	// +kubebuilder:validation:Optional
+	PublicRegistry *bool `json:"publicRegistry,omitempty"`
+
+	// NO_PROXY value used by the operator for its requests to the Dynatrace API.
+	// +kubebuilder:validation:Optional
+	NoProxy string `json:"noProxy,omitempty"`
+
+	// Enable AppArmor in the ActiveGate container.
+	// +kubebuilder:validation:Optional
+	ActiveGateAppArmor *bool `json:"activeGateAppArmor,omitempty"`
+
+	// Create the ActiveGate TLS certificate automatically. Defaults to true.
+	// +kubebuilder:validation:Optional
+	ActiveGateAutomaticTLSCertificate *bool `json:"activeGateAutomaticTlsCertificate,omitempty"`
+
+	// Ensure that the settings for monitoring this Kubernetes cluster exist in Dynatrace. Defaults to true.
+	// +kubebuilder:validation:Optional
+	AutomaticKubernetesAPIMonitoring *bool `json:"automaticKubernetesApiMonitoring,omitempty"`
+
+	// Custom cluster name used by the automatic Kubernetes API monitoring.
+	// +kubebuilder:validation:Optional
+	AutomaticKubernetesAPIMonitoringClusterName string `json:"automaticKubernetesApiMonitoringClusterName,omitempty"`
+
+	// Enable the Kubernetes app for this cluster automatically.
+	// +kubebuilder:validation:Optional
+	K8sAppEnabled *bool `json:"k8sAppEnabled,omitempty"`
+
+	// Maximum time the CSI driver retries to mount a volume, for example 10m.
+	// +kubebuilder:validation:Optional
+	CSIMaxMountTimeout *metav1.Duration `json:"csiMaxMountTimeout,omitempty"`
+
+	// Regular expressions of namespaces which are never injected.
+	// +kubebuilder:validation:Optional
+	IgnoredNamespaces []string `json:"ignoredNamespaces,omitempty"`
+
+	// Inject all pods in the selected namespaces, if false pods have to opt in one by one. Defaults to true.
+	// +kubebuilder:validation:Optional
+	AutomaticInjection *bool `json:"automaticInjection,omitempty"`
+
+	// Inject additional environment variables based on the version labels of the pod.
+	// +kubebuilder:validation:Optional
+	LabelVersionDetection *bool `json:"labelVersionDetection,omitempty"`
+
+	// Whether a failed injection keeps the pod from starting (fail) or not (silent). Defaults to silent.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=silent;fail
+	InjectionFailurePolicy string `json:"injectionFailurePolicy,omitempty"`
+
+	// Set the RuntimeDefault seccomp profile on the injected init-container.
+	// +kubebuilder:validation:Optional
+	InitContainerSeccompProfile *bool `json:"initContainerSeccompProfile,omitempty"`
+
+	// maxUnavailable of the rolling update of the OneAgent DaemonSet. Defaults to 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	OneAgentMaxUnavailable *int `json:"oneAgentMaxUnavailable,omitempty"`
+
+	// Initial connect retry of the OneAgent in milliseconds.
+	// +kubebuilder:validation:Optional
+	OneAgentInitialConnectRetryMs *int `json:"oneAgentInitialConnectRetryMs,omitempty"`
+
+	// Run the OneAgent container privileged.
+	// +kubebuilder:validation:Optional
+	OneAgentPrivileged *bool `json:"oneAgentPrivileged,omitempty"`
+
+	// Remove the liveness probe of the OneAgent container.
+	// +kubebuilder:validation:Optional
+	OneAgentSkipLivenessProbe *bool `json:"oneAgentSkipLivenessProbe,omitempty"`
+
+	// Download the code modules on the node using the OneAgent image.
+	// +kubebuilder:validation:Optional
+	NodeImagePull *bool `json:"nodeImagePull,omitempty"`
+
+	// Code module technologies downloaded by the node image pull, defaults to all.
+	// +kubebuilder:validation:Optional
+	NodeImagePullTechnologies string `json:"nodeImagePullTechnologies,omitempty"`
+}
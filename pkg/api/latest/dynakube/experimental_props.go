@@ -0,0 +1,169 @@
+package dynakube
+
+import (
+	"encoding/json"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// experimentalField connects a field of the ExperimentalSpec to its feature flag annotation.
+// get returns "" if the field is not set, set expects a value that passed exp.ValidateFlag.
+type experimentalField struct {
+	get func(spec *ExperimentalSpec) string
+	set func(spec *ExperimentalSpec, raw string)
+}
+
+var experimentalFields = map[string]experimentalField{
+	exp.PublicRegistryKey:                         boolField(func(spec *ExperimentalSpec) **bool { return &spec.PublicRegistry }),
+	exp.NoProxyKey:                                stringField(func(spec *ExperimentalSpec) *string { return &spec.NoProxy }),
+	exp.AGAppArmorKey:                             boolField(func(spec *ExperimentalSpec) **bool { return &spec.ActiveGateAppArmor }),
+	exp.AGAutomaticTLSCertificateKey:              boolField(func(spec *ExperimentalSpec) **bool { return &spec.ActiveGateAutomaticTLSCertificate }),
+	exp.AGAutomaticK8sAPIMonitoringKey:            boolField(func(spec *ExperimentalSpec) **bool { return &spec.AutomaticKubernetesAPIMonitoring }),
+	exp.AGAutomaticK8sAPIMonitoringClusterNameKey: stringField(func(spec *ExperimentalSpec) *string { return &spec.AutomaticKubernetesAPIMonitoringClusterName }),
+	exp.AGK8sAppEnabledKey:                        boolField(func(spec *ExperimentalSpec) **bool { return &spec.K8sAppEnabled }),
+	exp.CSIMaxMountTimeoutKey: {
+		get: func(spec *ExperimentalSpec) string {
+			if spec.CSIMaxMountTimeout == nil {
+				return ""
+			}
+
+			return spec.CSIMaxMountTimeout.Duration.String()
+		},
+		set: func(spec *ExperimentalSpec, raw string) {
+			duration, _ := time.ParseDuration(raw)
+			spec.CSIMaxMountTimeout = &metav1.Duration{Duration: duration}
+		},
+	},
+	exp.InjectionIgnoredNamespacesKey: {
+		get: func(spec *ExperimentalSpec) string {
+			if spec.IgnoredNamespaces == nil {
+				return ""
+			}
+
+			raw, _ := json.Marshal(spec.IgnoredNamespaces)
+
+			return string(raw)
+		},
+		set: func(spec *ExperimentalSpec, raw string) {
+			_ = json.Unmarshal([]byte(raw), &spec.IgnoredNamespaces)
+		},
+	},
+	exp.InjectionAutomaticKey:             boolField(func(spec *ExperimentalSpec) **bool { return &spec.AutomaticInjection }),
+	exp.InjectionLabelVersionDetectionKey: boolField(func(spec *ExperimentalSpec) **bool { return &spec.LabelVersionDetection }),
+	exp.InjectionFailurePolicyKey:         stringField(func(spec *ExperimentalSpec) *string { return &spec.InjectionFailurePolicy }),
+	exp.InjectionSeccompKey:               boolField(func(spec *ExperimentalSpec) **bool { return &spec.InitContainerSeccompProfile }),
+	exp.OAMaxUnavailableKey:               intField(func(spec *ExperimentalSpec) **int { return &spec.OneAgentMaxUnavailable }),
+	exp.OAInitialConnectRetryKey:          intField(func(spec *ExperimentalSpec) **int { return &spec.OneAgentInitialConnectRetryMs }),
+	exp.OAPrivilegedKey:                   boolField(func(spec *ExperimentalSpec) **bool { return &spec.OneAgentPrivileged }),
+	exp.OASkipLivenessProbeKey:            boolField(func(spec *ExperimentalSpec) **bool { return &spec.OneAgentSkipLivenessProbe }),
+	exp.OANodeImagePullKey:                boolField(func(spec *ExperimentalSpec) **bool { return &spec.NodeImagePull }),
+	exp.OANodeImagePullTechnologiesKey:    stringField(func(spec *ExperimentalSpec) *string { return &spec.NodeImagePullTechnologies }),
+}
+
+func boolField(field func(spec *ExperimentalSpec) **bool) experimentalField {
+	return experimentalField{
+		get: func(spec *ExperimentalSpec) string {
+			if *field(spec) == nil {
+				return ""
+			}
+
+			return strconv.FormatBool(**field(spec))
+		},
+		set: func(spec *ExperimentalSpec, raw string) {
+			val, _ := strconv.ParseBool(raw)
+			*field(spec) = ptr.To(val)
+		},
+	}
+}
+
+func intField(field func(spec *ExperimentalSpec) **int) experimentalField {
+	return experimentalField{
+		get: func(spec *ExperimentalSpec) string {
+			if *field(spec) == nil {
+				return ""
+			}
+
+			return strconv.Itoa(**field(spec))
+		},
+		set: func(spec *ExperimentalSpec, raw string) {
+			val, _ := strconv.Atoi(raw)
+			*field(spec) = ptr.To(val)
+		},
+	}
+}
+
+func stringField(field func(spec *ExperimentalSpec) *string) experimentalField {
+	return experimentalField{
+		get: func(spec *ExperimentalSpec) string {
+			return *field(spec)
+		},
+		set: func(spec *ExperimentalSpec, raw string) {
+			*field(spec) = raw
+		},
+	}
+}
+
+// HasExperimentalField returns true if the feature flag annotation has a counterpart in spec.experimental.
+func HasExperimentalField(annotation string) bool {
+	_, ok := experimentalFields[annotation]
+
+	return ok
+}
+
+// ExperimentalAnnotations returns the fields set in spec.experimental in their annotation form.
+func (dk *DynaKube) ExperimentalAnnotations() map[string]string {
+	annotations := map[string]string{}
+
+	if dk.Spec.Experimental == nil {
+		return annotations
+	}
+
+	for annotation, field := range experimentalFields {
+		if raw := field.get(dk.Spec.Experimental); raw != "" {
+			annotations[annotation] = raw
+		}
+	}
+
+	return annotations
+}
+
+// MigrateFeatureFlagAnnotations moves the feature flag annotations, which have a counterpart in spec.experimental, into spec.experimental.
+// Malformed annotations and annotations whose field is already set are kept, so the validation webhook can report them.
+func (dk *DynaKube) MigrateFeatureFlagAnnotations() {
+	for annotation, raw := range dk.Annotations {
+		field, ok := experimentalFields[annotation]
+		if !ok || exp.ValidateFlag(annotation, raw) != nil {
+			continue
+		}
+
+		if dk.Spec.Experimental == nil {
+			dk.Spec.Experimental = &ExperimentalSpec{}
+		} else if field.get(dk.Spec.Experimental) != "" {
+			continue
+		}
+
+		field.set(dk.Spec.Experimental, raw)
+		delete(dk.Annotations, annotation)
+	}
+}
+
+// featureFlagAnnotations merges the annotations with spec.experimental, spec.experimental takes precedence.
+func (dk *DynaKube) featureFlagAnnotations() map[string]string {
+	if dk.Spec.Experimental == nil {
+		return dk.Annotations
+	}
+
+	annotations := maps.Clone(dk.Annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	maps.Copy(annotations, dk.ExperimentalAnnotations())
+
+	return annotations
+}
@@ -0,0 +1,35 @@
+package dynakube
+
+type IstioSpec struct {
+	// Routes the traffic to the Dynatrace communication hosts through an Istio egress gateway instead of
+	// directly leaving the mesh. A Gateway and VirtualService chain is created for every component, the traffic to IP hosts
+	// is sent to the gateway with Istio mutual TLS.
+	// +kubebuilder:validation:Optional
+	EgressGateway *IstioEgressGatewaySpec `json:"egressGateway,omitempty"`
+
+	// When enabled, an Istio Sidecar resource is created in every namespace, which is injected by this DynaKube.
+	// It limits the outbound hosts of the workloads to their own namespace, the Istio control plane, the DynaKube namespace,
+	// the egress gateway namespace and the sidecarEgressHosts. The outbound traffic policy of the mesh is kept.
+	// Namespaces, which already have a Sidecar without workload selector, are skipped.
+	// +kubebuilder:validation:Optional
+	EnableSidecars bool `json:"enableSidecars,omitempty"`
+
+	// Additional hosts in the "namespace/dnsName" format, which are added to the generated Sidecars,
+	// e.g. the services of other namespaces, which are used by the injected workloads.
+	// +kubebuilder:validation:Optional
+	SidecarEgressHosts []string `json:"sidecarEgressHosts,omitempty"`
+}
+
+type IstioEgressGatewaySpec struct {
+	// Name of the egress gateway service.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace of the egress gateway service, defaults to istio-system.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Labels of the egress gateway pods, which are used as selector of the generated Gateway, defaults to istio: egressgateway.
+	// +kubebuilder:validation:Optional
+	Selector map[string]string `json:"selector,omitempty"`
+}
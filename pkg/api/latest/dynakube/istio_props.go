@@ -0,0 +1,30 @@
+package dynakube
+
+const (
+	DefaultIstioEgressGatewayNamespace = "istio-system"
+	defaultIstioEgressGatewayLabelKey  = "istio"
+	defaultIstioEgressGatewayLabel     = "egressgateway"
+)
+
+// IstioEgressGateway returns the configured egress gateway with the defaults applied, nil if the traffic leaves the mesh directly.
+func (dk *DynaKube) IstioEgressGateway() *IstioEgressGatewaySpec {
+	if !dk.Spec.EnableIstio || dk.Spec.Istio == nil || dk.Spec.Istio.EgressGateway == nil {
+		return nil
+	}
+
+	egressGateway := dk.Spec.Istio.EgressGateway.DeepCopy()
+
+	if egressGateway.Namespace == "" {
+		egressGateway.Namespace = DefaultIstioEgressGatewayNamespace
+	}
+
+	if len(egressGateway.Selector) == 0 {
+		egressGateway.Selector = map[string]string{defaultIstioEgressGatewayLabelKey: defaultIstioEgressGatewayLabel}
+	}
+
+	return egressGateway
+}
+
+func (dk *DynaKube) IsIstioSidecarsEnabled() bool {
+	return dk.Spec.EnableIstio && dk.Spec.Istio != nil && dk.Spec.Istio.EnableSidecars
+}
@@ -0,0 +1,13 @@
+package dynakube
+
+type NetworkPolicySpec struct {
+	// Creates CiliumNetworkPolicies, which restrict the egress to the FQDNs of the communication hosts, instead of NetworkPolicies.
+	// Requires Cilium to be installed.
+	// +kubebuilder:validation:Optional
+	EnableCilium bool `json:"enableCilium,omitempty"`
+
+	// Creates egress policies in the injected namespaces too. The injected pods aren't labeled, so the policies select every pod
+	// of these namespaces and block all egress that isn't needed by Dynatrace, additional policies have to allow the egress of the applications.
+	// +kubebuilder:validation:Optional
+	EnableInjectedNamespaces bool `json:"enableInjectedNamespaces,omitempty"`
+}
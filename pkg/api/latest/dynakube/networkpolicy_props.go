@@ -0,0 +1,13 @@
+package dynakube
+
+func (dk *DynaKube) IsNetworkPolicyEnabled() bool {
+	return dk.Spec.NetworkPolicy != nil
+}
+
+func (dk *DynaKube) IsInjectedNamespaceNetworkPolicyEnabled() bool {
+	return dk.IsNetworkPolicyEnabled() && dk.Spec.NetworkPolicy.EnableInjectedNamespaces
+}
+
+func (dk *DynaKube) IsCiliumNetworkPolicyEnabled() bool {
+	return dk.IsNetworkPolicyEnabled() && dk.Spec.NetworkPolicy.EnableCilium
+}
@@ -2,7 +2,9 @@ package oneagent
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/dtversion"
@@ -17,6 +19,10 @@ const (
 	PodNameOsAgent                        = "oneagent"
 	DefaultOneAgentImageRegistrySubPath   = "/linux/oneagent"
 	StorageVolumeDefaultHostPath          = "/var/opt/dynatrace"
+
+	DefaultCanaryPercentage         = 10
+	DefaultRolloutSoakTime          = time.Hour
+	DefaultRolloutMaxRestarts int32 = 3
 )
 
 func NewOneAgent(spec *Spec, status *Status, codeModulesStatus *CodeModulesStatus, //nolint:revive
@@ -69,6 +75,11 @@ func (oa *OneAgent) GetDaemonsetName() string {
 	return fmt.Sprintf("%s-%s", oa.name, PodNameOsAgent)
 }
 
+// GetCanaryDaemonsetName returns the name of the DaemonSet that runs the new version on the canary nodes during the canary phase of a rollout.
+func (oa *OneAgent) GetCanaryDaemonsetName() string {
+	return oa.GetDaemonsetName() + "-canary"
+}
+
 func (oa *OneAgent) IsPrivilegedNeeded() bool {
 	return oa.featureOneAgentPrivileged
 }
@@ -348,3 +359,67 @@ func (oa *OneAgent) GetHostPath() string {
 
 	return ""
 }
+
+func (oa *OneAgent) IsRolloutEnabled() bool {
+	return oa.Spec.Rollout != nil
+}
+
+func (oa *OneAgent) IsCanaryRolloutInProgress() bool {
+	return oa.Status.Rollout != nil && oa.Status.Rollout.Phase == RolloutPhaseCanary
+}
+
+func (oa *OneAgent) GetCanaryNodeSelector() map[string]string {
+	if oa.Spec.Rollout == nil {
+		return nil
+	}
+
+	return oa.Spec.Rollout.CanaryNodeSelector
+}
+
+func (oa *OneAgent) GetCanaryPercentage() int {
+	if oa.Spec.Rollout == nil || oa.Spec.Rollout.CanaryPercentage == nil {
+		return DefaultCanaryPercentage
+	}
+
+	return *oa.Spec.Rollout.CanaryPercentage
+}
+
+func (oa *OneAgent) GetRolloutSoakTime() time.Duration {
+	if oa.Spec.Rollout == nil || oa.Spec.Rollout.SoakTime == nil {
+		return DefaultRolloutSoakTime
+	}
+
+	return oa.Spec.Rollout.SoakTime.Duration
+}
+
+func (oa *OneAgent) GetRolloutMaxRestarts() int32 {
+	if oa.Spec.Rollout == nil || oa.Spec.Rollout.MaxRestarts == nil {
+		return DefaultRolloutMaxRestarts
+	}
+
+	return *oa.Spec.Rollout.MaxRestarts
+}
+
+// IsCanaryNode returns true if the node runs the new version during the canary phase of a rollout.
+func (oa *OneAgent) IsCanaryNode(nodeName string) bool {
+	return oa.IsCanaryRolloutInProgress() && slices.Contains(oa.Status.Rollout.CanaryNodes, nodeName)
+}
+
+// UseStableVersion replaces the OneAgent version in the status with the version before the rollout, if a rollout is in its canary phase.
+func (oa *OneAgent) UseStableVersion() {
+	if !oa.IsCanaryRolloutInProgress() || oa.Status.Rollout.PreviousVersion == nil {
+		return
+	}
+
+	oa.Status.VersionStatus = *oa.Status.Rollout.PreviousVersion
+}
+
+// UseStableCodeModules replaces the code modules version in the status with the version before the rollout,
+// if a rollout is in its canary phase and the node is not a canary node. Use an empty nodeName if the node is unknown.
+func (oa *OneAgent) UseStableCodeModules(nodeName string) {
+	if !oa.IsCanaryRolloutInProgress() || oa.Status.Rollout.PreviousCodeModules == nil || oa.IsCanaryNode(nodeName) {
+		return
+	}
+
+	oa.CodeModulesStatus.VersionStatus = *oa.Status.Rollout.PreviousCodeModules
+}
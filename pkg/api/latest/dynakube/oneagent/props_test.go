@@ -503,3 +503,68 @@ func TestOneAgent_IsAutoUpdateEnabled(t *testing.T) {
 		assert.Equal(t, tc.autoUpdateEnabled, oa.IsAutoUpdateEnabled(), tc.name)
 	})
 }
+
+func TestUseStableCodeModules(t *testing.T) {
+	stable := status.VersionStatus{Version: "1.0.0"}
+	latest := status.VersionStatus{Version: "2.0.0"}
+
+	createOneAgent := func(phase RolloutPhase) *OneAgent {
+		oaStatus := &Status{
+			Rollout: &RolloutStatus{
+				Phase:               phase,
+				CanaryNodes:         []string{"canary"},
+				PreviousCodeModules: stable.DeepCopy(),
+			},
+		}
+
+		return NewOneAgent(&Spec{Rollout: &RolloutSpec{}}, oaStatus, &CodeModulesStatus{VersionStatus: latest}, "", "", false, false, false)
+	}
+
+	t.Run("canary node => latest code modules", func(t *testing.T) {
+		oa := createOneAgent(RolloutPhaseCanary)
+		oa.UseStableCodeModules("canary")
+		assert.Equal(t, latest.Version, oa.GetCodeModulesVersion())
+	})
+
+	t.Run("other node => stable code modules", func(t *testing.T) {
+		oa := createOneAgent(RolloutPhaseCanary)
+		oa.UseStableCodeModules("other")
+		assert.Equal(t, stable.Version, oa.GetCodeModulesVersion())
+	})
+
+	t.Run("promoted => latest code modules", func(t *testing.T) {
+		oa := createOneAgent(RolloutPhasePromoted)
+		oa.UseStableCodeModules("other")
+		assert.Equal(t, latest.Version, oa.GetCodeModulesVersion())
+	})
+}
+
+func TestUseStableVersion(t *testing.T) {
+	stable := status.VersionStatus{Version: "1.0.0"}
+	latest := status.VersionStatus{Version: "2.0.0"}
+
+	createOneAgent := func(phase RolloutPhase) *OneAgent {
+		oaStatus := &Status{
+			VersionStatus: latest,
+			Rollout: &RolloutStatus{
+				Phase:           phase,
+				CanaryNodes:     []string{"canary"},
+				PreviousVersion: stable.DeepCopy(),
+			},
+		}
+
+		return NewOneAgent(&Spec{Rollout: &RolloutSpec{}}, oaStatus, &CodeModulesStatus{}, "", "", false, false, false)
+	}
+
+	t.Run("canary => stable version", func(t *testing.T) {
+		oa := createOneAgent(RolloutPhaseCanary)
+		oa.UseStableVersion()
+		assert.Equal(t, stable.Version, oa.GetVersion())
+	})
+
+	t.Run("promoted => latest version", func(t *testing.T) {
+		oa := createOneAgent(RolloutPhasePromoted)
+		oa.UseStableVersion()
+		assert.Equal(t, latest.Version, oa.GetVersion())
+	})
+}
@@ -48,6 +48,35 @@ type Spec struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Host Group",order=5,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
 	HostGroup string `json:"hostGroup,omitempty"`
+
+	// Roll out new OneAgent and code module versions to canary nodes first, promote them after a soak time and roll back on crash loops.
+	// Only applies to versions found by the automatic update, custom versions and images are rolled out immediately.
+	// Code modules only have a canary phase with the CSI driver, without it they are rolled out immediately.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rollout",order=6,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+type RolloutSpec struct {
+	// Nodes with these labels are the canary nodes. Takes precedence over canaryPercentage.
+	// +kubebuilder:validation:Optional
+	CanaryNodeSelector map[string]string `json:"canaryNodeSelector,omitempty"`
+
+	// Percentage of the OneAgent nodes used as canary nodes, at least one node is used. Defaults to 10.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	CanaryPercentage *int `json:"canaryPercentage,omitempty"`
+
+	// How long the canary nodes have to run the new version before it is promoted to all nodes. Defaults to 1h.
+	// +kubebuilder:validation:Optional
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+
+	// Number of restarts of a canary OneAgent container that is treated as a crash loop and rolls back the new version. Defaults to 3.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxRestarts *int32 `json:"maxRestarts,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
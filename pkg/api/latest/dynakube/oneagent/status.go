@@ -25,6 +25,38 @@ type Status struct {
 
 	// Information about OneAgent's connections
 	ConnectionInfoStatus ConnectionInfoStatus `json:"connectionInfoStatus,omitempty"`
+
+	// State of the rollout of a new OneAgent or code module version
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+type RolloutPhase string
+
+const (
+	RolloutPhaseCanary     RolloutPhase = "Canary"
+	RolloutPhasePromoted   RolloutPhase = "Promoted"
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+)
+
+// +kubebuilder:object:generate=true
+type RolloutStatus struct {
+	// Phase of the rollout (Canary, Promoted, RolledBack)
+	Phase RolloutPhase `json:"phase,omitempty"`
+
+	// Time the current phase started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// Nodes that run the new version during the canary phase
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+
+	// OneAgent version before the rollout, restored on rollback
+	PreviousVersion *status.VersionStatus `json:"previousVersion,omitempty"`
+
+	// Code modules version before the rollout, restored on rollback
+	PreviousCodeModules *status.VersionStatus `json:"previousCodeModules,omitempty"`
+
+	// Versions or images that were rolled back, they are not rolled out again
+	FailedVersions []string `json:"failedVersions,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
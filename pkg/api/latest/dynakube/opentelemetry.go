@@ -2,6 +2,7 @@ package dynakube
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/image"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -19,6 +20,10 @@ type OpenTelemetryCollectorSpec struct {
 	// +kubebuilder:validation:Optional
 	Replicas *int32 `json:"replicas"`
 
+	// Enables horizontal autoscaling of the OtelCollector, the number of replicas is then managed by a HorizontalPodAutoscaler instead of the replicas field
+	// +kubebuilder:validation:Optional
+	Autoscaling *OpenTelemetryCollectorAutoscalingSpec `json:"autoscaling,omitempty"`
+
 	// Overrides the default image
 	// +kubebuilder:validation:Optional
 	ImageRef image.Ref `json:"imageRef"`
@@ -38,3 +43,33 @@ type OpenTelemetryCollectorSpec struct {
 	// +kubebuilder:validation:Optional
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 }
+
+type OpenTelemetryCollectorAutoscalingSpec struct {
+	// Lower limit for the number of replicas, defaults to 1
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Upper limit for the number of replicas
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Target average CPU utilization in percent of the requested CPU, defaults to 80 if no other target is set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+
+	// Target average memory utilization in percent of the requested memory
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TargetMemoryUtilization *int32 `json:"targetMemoryUtilization,omitempty"`
+
+	// Additional metrics the OtelCollector is scaled on, e.g. a custom metric for the size of the exporter queue
+	// +kubebuilder:validation:Optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// Configures the scaling behavior in the up and down direction
+	// +kubebuilder:validation:Optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
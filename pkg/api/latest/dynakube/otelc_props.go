@@ -4,6 +4,31 @@ func (dk *DynaKube) OtelCollectorStatefulsetName() string {
 	return dk.Name + "-otel-collector"
 }
 
+const DefaultOtelCollectorTargetCPUUtilization int32 = 80
+
+func (dk *DynaKube) OtelCollectorHPAName() string {
+	return dk.OtelCollectorStatefulsetName()
+}
+
+func (dk *DynaKube) OtelCollectorPDBName() string {
+	return dk.OtelCollectorStatefulsetName()
+}
+
+// IsOtelCollectorAutoscalingEnabled returns true, if the replicas of the OtelCollector are managed by a HorizontalPodAutoscaler.
+func (dk *DynaKube) IsOtelCollectorAutoscalingEnabled() bool {
+	return dk.Spec.Templates.OpenTelemetryCollector.Autoscaling != nil
+}
+
+// OtelCollectorMinReplicas returns the lower limit for the number of replicas of the autoscaled OtelCollector.
+func (dk *DynaKube) OtelCollectorMinReplicas() int32 {
+	autoscaling := dk.Spec.Templates.OpenTelemetryCollector.Autoscaling
+	if autoscaling == nil || autoscaling.MinReplicas == nil {
+		return 1
+	}
+
+	return *autoscaling.MinReplicas
+}
+
 func (dk *DynaKube) IsAGCertificateNeeded() bool {
 	if dk.ActiveGate().IsEnabled() && dk.ActiveGate().HasCaCert() {
 		return true
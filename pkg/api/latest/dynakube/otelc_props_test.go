@@ -5,6 +5,7 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/activegate"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
 )
 
 func TestIsAGCertificateNeeded(t *testing.T) {
@@ -94,3 +95,22 @@ func TestIsCACertificateNeeded(t *testing.T) {
 		assert.False(t, dk.IsCACertificateNeeded())
 	})
 }
+
+func TestOtelCollectorMinReplicas(t *testing.T) {
+	t.Run("autoscaling disabled", func(t *testing.T) {
+		dk := &DynaKube{}
+		assert.False(t, dk.IsOtelCollectorAutoscalingEnabled())
+		assert.Equal(t, int32(1), dk.OtelCollectorMinReplicas())
+	})
+	t.Run("autoscaling without min replicas", func(t *testing.T) {
+		dk := &DynaKube{}
+		dk.Spec.Templates.OpenTelemetryCollector.Autoscaling = &OpenTelemetryCollectorAutoscalingSpec{MaxReplicas: 3}
+		assert.True(t, dk.IsOtelCollectorAutoscalingEnabled())
+		assert.Equal(t, int32(1), dk.OtelCollectorMinReplicas())
+	})
+	t.Run("autoscaling with min replicas", func(t *testing.T) {
+		dk := &DynaKube{}
+		dk.Spec.Templates.OpenTelemetryCollector.Autoscaling = &OpenTelemetryCollectorAutoscalingSpec{MinReplicas: ptr.To(int32(2)), MaxReplicas: 3}
+		assert.Equal(t, int32(2), dk.OtelCollectorMinReplicas())
+	})
+}
@@ -39,3 +39,17 @@ func (e *ExporterConfiguration) IsTracesEnabled() bool {
 func (e *ExporterConfiguration) IsLogsEnabled() bool {
 	return e.Spec != nil && e.Spec.Signals.Logs != nil
 }
+
+// GetProtocol returns the configured protocol, defaults to http/protobuf which is supported by the Dynatrace OTLP API.
+func (s *SignalExporterSpec) GetProtocol() Protocol {
+	if s.Protocol == "" {
+		return ProtocolHTTPProtobuf
+	}
+
+	return s.Protocol
+}
+
+// HasCustomEndpoint checks if the signal is sent to a custom endpoint instead of the Dynatrace environment.
+func (s *SignalExporterSpec) HasCustomEndpoint() bool {
+	return s.Endpoint != ""
+}
@@ -53,10 +53,53 @@ type SignalConfiguration struct {
 }
 
 // +kubebuilder:object:generate=true
-type MetricsSignal struct{}
+type MetricsSignal struct {
+	SignalExporterSpec `json:",inline"`
+}
 
 // +kubebuilder:object:generate=true
-type TracesSignal struct{}
+type TracesSignal struct {
+	SignalExporterSpec `json:",inline"`
+}
+
+// +kubebuilder:object:generate=true
+type LogsSignal struct {
+	SignalExporterSpec `json:",inline"`
+}
 
 // +kubebuilder:object:generate=true
-type LogsSignal struct{}
+
+// SignalExporterSpec customizes the OTLP exporter of a single signal, unset fields keep the defaults for sending the signal to Dynatrace.
+type SignalExporterSpec struct {
+	// Additional headers sent with each export request.
+	// If a custom endpoint is set, the Dynatrace API token is not sent, but it can be referenced via $(DT_API_TOKEN).
+	// +kubebuilder:validation:Optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Timeout of a single export request.
+	// +kubebuilder:validation:Optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Custom endpoint the signal is sent to instead of the Dynatrace environment, e.g. the telemetry ingest service of the DynaKube.
+	// For http/protobuf the full URL including the signal path (e.g. /v1/traces) is expected.
+	// +kubebuilder:validation:Optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Protocol used to send the signal, defaults to http/protobuf.
+	// grpc requires a custom endpoint, as the Dynatrace environment only accepts http/protobuf.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=grpc;http/protobuf
+	Protocol Protocol `json:"protocol,omitempty"`
+
+	// Compression of the export requests.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=gzip;none
+	Compression string `json:"compression,omitempty"`
+}
+
+type Protocol string
+
+const (
+	ProtocolGRPC         Protocol = "grpc"
+	ProtocolHTTPProtobuf Protocol = "http/protobuf"
+)
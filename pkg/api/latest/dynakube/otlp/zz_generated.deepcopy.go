@@ -18,7 +18,9 @@ limitations under the License.
 
 package otlp
 
-import ()
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExporterConfigurationSpec) DeepCopyInto(out *ExporterConfigurationSpec) {
@@ -45,6 +47,7 @@ func (in *ExporterConfigurationSpec) DeepCopy() *ExporterConfigurationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogsSignal) DeepCopyInto(out *LogsSignal) {
 	*out = *in
+	in.SignalExporterSpec.DeepCopyInto(&out.SignalExporterSpec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogsSignal.
@@ -60,6 +63,7 @@ func (in *LogsSignal) DeepCopy() *LogsSignal {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricsSignal) DeepCopyInto(out *MetricsSignal) {
 	*out = *in
+	in.SignalExporterSpec.DeepCopyInto(&out.SignalExporterSpec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsSignal.
@@ -78,17 +82,17 @@ func (in *SignalConfiguration) DeepCopyInto(out *SignalConfiguration) {
 	if in.Metrics != nil {
 		in, out := &in.Metrics, &out.Metrics
 		*out = new(MetricsSignal)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Traces != nil {
 		in, out := &in.Traces, &out.Traces
 		*out = new(TracesSignal)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Logs != nil {
 		in, out := &in.Logs, &out.Logs
 		*out = new(LogsSignal)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -102,9 +106,37 @@ func (in *SignalConfiguration) DeepCopy() *SignalConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SignalExporterSpec) DeepCopyInto(out *SignalExporterSpec) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SignalExporterSpec.
+func (in *SignalExporterSpec) DeepCopy() *SignalExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SignalExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TracesSignal) DeepCopyInto(out *TracesSignal) {
 	*out = *in
+	in.SignalExporterSpec.DeepCopyInto(&out.SignalExporterSpec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracesSignal.
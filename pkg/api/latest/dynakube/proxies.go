@@ -0,0 +1,49 @@
+package dynakube
+
+import "github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
+
+type ProxiesSpec struct {
+	// Proxy for the calls of the operator to the Dynatrace API and the registries.
+	// +kubebuilder:validation:Optional
+	Operator *ComponentProxySpec `json:"operator,omitempty"`
+
+	// Proxy for the OneAgents and the code modules of the injected pods.
+	// +kubebuilder:validation:Optional
+	OneAgent *ComponentProxySpec `json:"oneAgent,omitempty"`
+
+	// Proxy for the ActiveGate.
+	// +kubebuilder:validation:Optional
+	ActiveGate *ComponentProxySpec `json:"activeGate,omitempty"`
+
+	// Proxy for the OpenTelemetry collector.
+	// +kubebuilder:validation:Optional
+	OtelCollector *ComponentProxySpec `json:"otelCollector,omitempty"`
+
+	// Proxy for the Extension Execution Controller, it isn't covered by spec.proxy.
+	// +kubebuilder:validation:Optional
+	ExtensionExecutionController *ComponentProxySpec `json:"extensionExecutionController,omitempty"`
+
+	// Proxy for the log monitoring module.
+	// +kubebuilder:validation:Optional
+	LogMonitoring *ComponentProxySpec `json:"logMonitoring,omitempty"`
+}
+
+type ComponentProxySpec struct {
+	// URL of the proxy, either directly or from a secret with the field proxy.
+	// +kubebuilder:validation:Optional
+	URL *value.Source `json:"url,omitempty"`
+
+	// Name of a secret with the fields username and password, which are used to authenticate at the proxy.
+	// +kubebuilder:validation:Optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// Name of a ConfigMap with a proxy auto-config file in the field proxy.pac, only supported for the operator.
+	// The operator evaluates the FindProxyForURL function of the file for every request, all functions of the PAC standard
+	// are supported except for weekdayRange, dateRange and timeRange.
+	// +kubebuilder:validation:Optional
+	PACConfigMap string `json:"pacConfigMap,omitempty"`
+
+	// Disables the proxy for the component, also the proxy of spec.proxy isn't used.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty"`
+}
@@ -19,17 +19,44 @@ package dynakube
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	ProxyKey   = "proxy"
-	NoProxyKey = "noProxy"
+	ProxyKey         = "proxy"
+	NoProxyKey       = "noProxy"
+	ProxyUsernameKey = "username"
+	ProxyPasswordKey = "password"
+	ProxyPACKey      = "proxy.pac"
 )
 
+type ProxyComponent string
+
+const (
+	OperatorProxyComponent      ProxyComponent = "operator"
+	OneAgentProxyComponent      ProxyComponent = "oneagent"
+	ActiveGateProxyComponent    ProxyComponent = "activegate"
+	OtelCollectorProxyComponent ProxyComponent = "otel-collector"
+	EECProxyComponent           ProxyComponent = "eec"
+	LogMonitoringProxyComponent ProxyComponent = "logmonitoring"
+)
+
+// ProxyComponents lists the components, which can have their own proxy.
+var ProxyComponents = []ProxyComponent{
+	OperatorProxyComponent,
+	OneAgentProxyComponent,
+	ActiveGateProxyComponent,
+	OtelCollectorProxyComponent,
+	EECProxyComponent,
+	LogMonitoringProxyComponent,
+}
+
 func (dk *DynaKube) Proxy(ctx context.Context, kubeReader client.Reader) (string, error) {
 	if dk.Spec.Proxy == nil {
 		return "", nil
@@ -38,14 +65,157 @@ func (dk *DynaKube) Proxy(ctx context.Context, kubeReader client.Reader) (string
 	if dk.Spec.Proxy.Value != "" {
 		return dk.Spec.Proxy.Value, nil
 	} else if dk.Spec.Proxy.ValueFrom != "" {
-		return dk.proxyURLFromUserSecret(ctx, kubeReader)
+		return dk.proxyURLFromUserSecret(ctx, kubeReader, dk.Spec.Proxy.ValueFrom)
 	}
 
 	return "", nil
 }
 
+// ComponentProxy returns the proxy URL the component has to use, including the credentials of the override.
+// Components without an override fall back to spec.proxy, except for the EEC.
+func (dk *DynaKube) ComponentProxy(ctx context.Context, kubeReader client.Reader, component ProxyComponent) (string, error) {
+	if !dk.HasComponentProxy(component) {
+		return "", nil
+	}
+
+	override := dk.ProxyOverride(component)
+
+	var (
+		proxyURL string
+		err      error
+	)
+
+	switch {
+	case dk.HasProxyOverride(component) && override.URL.Value != "":
+		proxyURL = override.URL.Value
+	case dk.HasProxyOverride(component):
+		proxyURL, err = dk.proxyURLFromUserSecret(ctx, kubeReader, override.URL.ValueFrom)
+	default:
+		proxyURL, err = dk.Proxy(ctx, kubeReader)
+	}
+
+	if err != nil || proxyURL == "" || override == nil || override.CredentialsSecret == "" {
+		return proxyURL, err
+	}
+
+	credentials, err := dk.ProxyCredentials(ctx, kubeReader, component)
+	if err != nil {
+		return "", err
+	}
+
+	return addProxyCredentials(proxyURL, credentials)
+}
+
+// ProxyCredentials returns the credentials of the credentials secret of the component override, nil if there is none.
+func (dk *DynaKube) ProxyCredentials(ctx context.Context, kubeReader client.Reader, component ProxyComponent) (*url.Userinfo, error) {
+	override := dk.ProxyOverride(component)
+	if override == nil || override.CredentialsSecret == "" {
+		return nil, nil
+	}
+
+	var credentialsSecret corev1.Secret
+
+	err := kubeReader.Get(ctx, client.ObjectKey{Name: override.CredentialsSecret, Namespace: dk.Namespace}, &credentialsSecret)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get proxy credentials from %s secret", override.CredentialsSecret))
+	}
+
+	username, hasKey := credentialsSecret.Data[ProxyUsernameKey]
+	if !hasKey {
+		return nil, errors.Errorf("missing token %s in proxy credentials secret %s", ProxyUsernameKey, override.CredentialsSecret)
+	}
+
+	password, hasKey := credentialsSecret.Data[ProxyPasswordKey]
+	if !hasKey {
+		return nil, errors.Errorf("missing token %s in proxy credentials secret %s", ProxyPasswordKey, override.CredentialsSecret)
+	}
+
+	return url.UserPassword(string(username), string(password)), nil
+}
+
+// ProxyPAC returns the proxy auto-config file of the operator, empty if none is configured.
+func (dk *DynaKube) ProxyPAC(ctx context.Context, kubeReader client.Reader) (string, error) {
+	configMapName := dk.ProxyPACConfigMap()
+	if configMapName == "" {
+		return "", nil
+	}
+
+	var pacConfigMap corev1.ConfigMap
+
+	err := kubeReader.Get(ctx, client.ObjectKey{Name: configMapName, Namespace: dk.Namespace}, &pacConfigMap)
+	if err != nil {
+		return "", errors.WithMessage(err, fmt.Sprintf("failed to get proxy auto-config from %s configmap", configMapName))
+	}
+
+	pac, hasKey := pacConfigMap.Data[ProxyPACKey]
+	if !hasKey {
+		return "", errors.Errorf("missing field %s in proxy auto-config configmap %s", ProxyPACKey, configMapName)
+	}
+
+	return pac, nil
+}
+
+// ProxyPACConfigMap returns the name of the ConfigMap with the proxy auto-config file of the operator.
+func (dk *DynaKube) ProxyPACConfigMap() string {
+	override := dk.ProxyOverride(OperatorProxyComponent)
+	if override == nil || override.Disabled {
+		return ""
+	}
+
+	return override.PACConfigMap
+}
+
+// ProxyOverride returns the proxy settings of the component from spec.proxies, nil if there are none.
+func (dk *DynaKube) ProxyOverride(component ProxyComponent) *ComponentProxySpec {
+	if dk.Spec.Proxies == nil {
+		return nil
+	}
+
+	switch component {
+	case OperatorProxyComponent:
+		return dk.Spec.Proxies.Operator
+	case OneAgentProxyComponent:
+		return dk.Spec.Proxies.OneAgent
+	case ActiveGateProxyComponent:
+		return dk.Spec.Proxies.ActiveGate
+	case OtelCollectorProxyComponent:
+		return dk.Spec.Proxies.OtelCollector
+	case EECProxyComponent:
+		return dk.Spec.Proxies.ExtensionExecutionController
+	case LogMonitoringProxyComponent:
+		return dk.Spec.Proxies.LogMonitoring
+	}
+
+	return nil
+}
+
+// HasProxyOverride is true if the component uses its own proxy URL instead of the one of spec.proxy.
+func (dk *DynaKube) HasProxyOverride(component ProxyComponent) bool {
+	override := dk.ProxyOverride(component)
+
+	return override != nil && !override.Disabled && hasSourceValue(override.URL)
+}
+
+// HasComponentProxy is true if the component has to use a proxy, either its own or the one of spec.proxy.
+func (dk *DynaKube) HasComponentProxy(component ProxyComponent) bool {
+	override := dk.ProxyOverride(component)
+
+	switch {
+	case override != nil && override.Disabled:
+		return false
+	case dk.HasProxyOverride(component):
+		return true
+	case component == OperatorProxyComponent && dk.ProxyPACConfigMap() != "":
+		return true
+	case component == EECProxyComponent:
+		return false
+	}
+
+	return dk.HasProxy()
+}
+
 func (dk *DynaKube) HasProxy() bool {
-	return dk.Spec.Proxy != nil && (dk.Spec.Proxy.Value != "" || dk.Spec.Proxy.ValueFrom != "")
+	return hasSourceValue(dk.Spec.Proxy)
 }
 
 func (dk *DynaKube) NeedsCustomNoProxy() bool {
@@ -53,16 +223,14 @@ func (dk *DynaKube) NeedsCustomNoProxy() bool {
 }
 
 func (dk *DynaKube) NeedsActiveGateProxy() bool {
-	return !dk.FF().AGIgnoresProxy() && dk.HasProxy() && dk.ActiveGate().IsEnabled() //nolint:staticcheck
+	return !dk.FF().AGIgnoresProxy() && dk.HasComponentProxy(ActiveGateProxyComponent) && dk.ActiveGate().IsEnabled() //nolint:staticcheck
 }
 
 func (dk *DynaKube) NeedsOneAgentProxy() bool {
-	return !dk.FF().OneAgentIgnoresProxy() && dk.HasProxy() //nolint:staticcheck
+	return !dk.FF().OneAgentIgnoresProxy() && dk.HasComponentProxy(OneAgentProxyComponent) //nolint:staticcheck
 }
 
-func (dk *DynaKube) proxyURLFromUserSecret(ctx context.Context, kubeReader client.Reader) (string, error) {
-	secretName := dk.Spec.Proxy.ValueFrom
-
+func (dk *DynaKube) proxyURLFromUserSecret(ctx context.Context, kubeReader client.Reader, secretName string) (string, error) {
 	var proxySecret corev1.Secret
 
 	err := kubeReader.Get(ctx, client.ObjectKey{Name: secretName, Namespace: dk.Namespace}, &proxySecret)
@@ -79,3 +247,22 @@ func (dk *DynaKube) proxyURLFromUserSecret(ctx context.Context, kubeReader clien
 
 	return string(proxy), nil
 }
+
+func hasSourceValue(source *value.Source) bool {
+	return source != nil && (source.Value != "" || source.ValueFrom != "")
+}
+
+func addProxyCredentials(proxyURL string, credentials *url.Userinfo) (string, error) {
+	if !strings.Contains(proxyURL, "://") {
+		proxyURL = "http://" + proxyURL
+	}
+
+	parsedURL, err := url.Parse(proxyURL)
+	if err != nil {
+		return "", errors.New("could not parse proxy URL")
+	}
+
+	parsedURL.User = credentials
+
+	return parsedURL.String(), nil
+}
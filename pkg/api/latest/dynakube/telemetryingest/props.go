@@ -1,9 +1,17 @@
 package telemetryingest
 
-import "github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
+import (
+	"slices"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 const (
 	ServiceNameSuffix = "-telemetry-ingest"
+
+	// PipelineExtensionsConfigMapKey is the key of the pipeline extensions in the ConfigMap referenced by PipelineExtensionsRef.
+	PipelineExtensionsConfigMapKey = "pipelines.yaml"
 )
 
 func (spec *Spec) GetProtocols() otelcgen.Protocols {
@@ -47,3 +55,41 @@ func (ts *TelemetryIngest) GetServiceName() string {
 func (ts *TelemetryIngest) IsEnabled() bool {
 	return ts.Spec != nil
 }
+
+func (ts *TelemetryIngest) IsPrometheusEnabled() bool {
+	return ts.IsEnabled() && slices.Contains(ts.GetProtocols(), otelcgen.PrometheusProtocol)
+}
+
+// GetPrometheusNamespaceSelector returns the selector for the namespaces scraped by the prometheus protocol, nil means all namespaces.
+func (ts *TelemetryIngest) GetPrometheusNamespaceSelector() *metav1.LabelSelector {
+	if !ts.IsEnabled() || ts.Prometheus == nil {
+		return nil
+	}
+
+	return ts.Prometheus.NamespaceSelector
+}
+
+func (ts *TelemetryIngest) GetSendingQueue() *SendingQueueSpec {
+	if !ts.IsEnabled() || ts.Exporter == nil {
+		return nil
+	}
+
+	return ts.Exporter.SendingQueue
+}
+
+func (ts *TelemetryIngest) GetRetry() *RetrySpec {
+	if !ts.IsEnabled() || ts.Exporter == nil {
+		return nil
+	}
+
+	return ts.Exporter.Retry
+}
+
+// IsPersistentQueueEnabled checks if the sending queue of the exporter is persisted in a volume of the collector.
+func (ts *TelemetryIngest) IsPersistentQueueEnabled() bool {
+	return ts.GetSendingQueue() != nil && ts.GetSendingQueue().Persistent
+}
+
+func (ts *TelemetryIngest) HasPipelineExtensions() bool {
+	return ts.IsEnabled() && ts.PipelineExtensionsRef != ""
+}
@@ -1,5 +1,10 @@
 package telemetryingest
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 type TelemetryIngest struct {
 	*Spec
 
@@ -17,4 +22,71 @@ type Spec struct {
 
 	// +kubebuilder:validation:Optional
 	Protocols []string `json:"protocols,omitempty"`
+
+	// Configuration of the prometheus protocol, which scrapes the metrics of pods annotated with prometheus.io/scrape: "true".
+	// The scrape targets are sharded by the configured replicas of the OTel Collector, so the protocol can't be combined with its autoscaling.
+	// +kubebuilder:validation:Optional
+	Prometheus *PrometheusSpec `json:"prometheus,omitempty"`
+
+	// Configuration of the exporter, which sends the telemetry data to Dynatrace.
+	// +kubebuilder:validation:Optional
+	Exporter *ExporterSpec `json:"exporter,omitempty"`
+
+	// Name of a ConfigMap in the DynaKube namespace, which contains additional receivers and processors for the pipelines of the OTel collector
+	// under the key `pipelines.yaml`. Supported processors are attributes, filter, probabilistic_sampler, redaction, resource, tail_sampling and transform.
+	// +kubebuilder:validation:Optional
+	PipelineExtensionsRef string `json:"pipelineExtensionsRef,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type PrometheusSpec struct {
+	// Only pods in namespaces matching the selector are scraped. If not set, pods of all namespaces are scraped.
+	// +kubebuilder:validation:Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type ExporterSpec struct {
+	// Buffers the telemetry data, while Dynatrace can't be reached.
+	// +kubebuilder:validation:Optional
+	SendingQueue *SendingQueueSpec `json:"sendingQueue,omitempty"`
+
+	// Retries exports, which failed because Dynatrace couldn't be reached.
+	// +kubebuilder:validation:Optional
+	Retry *RetrySpec `json:"retry,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type SendingQueueSpec struct {
+	// Maximum number of batches kept in the queue, defaults to the collector default of 1000.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	QueueSize *int64 `json:"queueSize,omitempty"`
+
+	// Defines the storage of the persistent queue, defaults to 1Gi. Only used if persistent is enabled.
+	// +kubebuilder:validation:Optional
+	VolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"volumeClaimTemplate,omitempty"`
+
+	// Persists the queue in a PersistentVolumeClaim per collector replica, so queued data survives restarts of the collector.
+	// +kubebuilder:validation:Optional
+	Persistent bool `json:"persistent,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type RetrySpec struct {
+	// Time to wait before the first retry, defaults to the collector default of 5s.
+	// +kubebuilder:validation:Optional
+	InitialInterval *metav1.Duration `json:"initialInterval,omitempty"`
+
+	// Upper bound of the time between retries, defaults to the collector default of 30s.
+	// +kubebuilder:validation:Optional
+	MaxInterval *metav1.Duration `json:"maxInterval,omitempty"`
+
+	// Maximum time spent retrying a batch before it is dropped, 0s retries forever. Defaults to the collector default of 300s.
+	// +kubebuilder:validation:Optional
+	MaxElapsedTime *metav1.Duration `json:"maxElapsedTime,omitempty"`
 }
@@ -24,12 +24,68 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/logmonitoring"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/otlp"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/telemetryingest"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/autoscaling/v2"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveGateDefaultsSpec) DeepCopyInto(out *ActiveGateDefaultsSpec) {
+	*out = *in
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveGateDefaultsSpec.
+func (in *ActiveGateDefaultsSpec) DeepCopy() *ActiveGateDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveGateDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentProxySpec) DeepCopyInto(out *ComponentProxySpec) {
+	*out = *in
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(value.Source)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentProxySpec.
+func (in *ComponentProxySpec) DeepCopy() *ComponentProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynaKube) DeepCopyInto(out *DynaKube) {
 	*out = *in
@@ -57,6 +113,94 @@ func (in *DynaKube) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynaKubeDefaults) DeepCopyInto(out *DynaKubeDefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynaKubeDefaults.
+func (in *DynaKubeDefaults) DeepCopy() *DynaKubeDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(DynaKubeDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DynaKubeDefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynaKubeDefaultsList) DeepCopyInto(out *DynaKubeDefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DynaKubeDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynaKubeDefaultsList.
+func (in *DynaKubeDefaultsList) DeepCopy() *DynaKubeDefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(DynaKubeDefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DynaKubeDefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynaKubeDefaultsSpec) DeepCopyInto(out *DynaKubeDefaultsSpec) {
+	*out = *in
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(value.Source)
+		**out = **in
+	}
+	if in.OneAgent != nil {
+		in, out := &in.OneAgent, &out.OneAgent
+		*out = new(OneAgentDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveGate != nil {
+		in, out := &in.ActiveGate, &out.ActiveGate
+		*out = new(ActiveGateDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynaKubeDefaultsSpec.
+func (in *DynaKubeDefaultsSpec) DeepCopy() *DynaKubeDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DynaKubeDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynaKubeList) DeepCopyInto(out *DynaKubeList) {
 	*out = *in
@@ -98,6 +242,11 @@ func (in *DynaKubeSpec) DeepCopyInto(out *DynaKubeSpec) {
 		*out = new(value.Source)
 		**out = **in
 	}
+	if in.Proxies != nil {
+		in, out := &in.Proxies, &out.Proxies
+		*out = new(ProxiesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LogMonitoring != nil {
 		in, out := &in.LogMonitoring, &out.LogMonitoring
 		*out = new(logmonitoring.Spec)
@@ -113,6 +262,11 @@ func (in *DynaKubeSpec) DeepCopyInto(out *DynaKubeSpec) {
 		*out = new(uint16)
 		**out = **in
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]maintenance.Window, len(*in))
+		copy(*out, *in)
+	}
 	if in.Extensions != nil {
 		in, out := &in.Extensions, &out.Extensions
 		*out = new(extensions.Spec)
@@ -131,6 +285,21 @@ func (in *DynaKubeSpec) DeepCopyInto(out *DynaKubeSpec) {
 	in.OneAgent.DeepCopyInto(&out.OneAgent)
 	in.Templates.DeepCopyInto(&out.Templates)
 	in.ActiveGate.DeepCopyInto(&out.ActiveGate)
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(IstioSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		**out = **in
+	}
+	if in.Experimental != nil {
+		in, out := &in.Experimental, &out.Experimental
+		*out = new(ExperimentalSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynaKubeSpec.
@@ -155,7 +324,7 @@ func (in *DynaKubeStatus) DeepCopyInto(out *DynaKubeStatus) {
 	in.DynatraceAPI.DeepCopyInto(&out.DynatraceAPI)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -188,6 +357,234 @@ func (in *DynatraceAPIStatus) DeepCopy() *DynatraceAPIStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExperimentalSpec) DeepCopyInto(out *ExperimentalSpec) {
+	*out = *in
+	if in.PublicRegistry != nil {
+		in, out := &in.PublicRegistry, &out.PublicRegistry
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ActiveGateAppArmor != nil {
+		in, out := &in.ActiveGateAppArmor, &out.ActiveGateAppArmor
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ActiveGateAutomaticTLSCertificate != nil {
+		in, out := &in.ActiveGateAutomaticTLSCertificate, &out.ActiveGateAutomaticTLSCertificate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutomaticKubernetesAPIMonitoring != nil {
+		in, out := &in.AutomaticKubernetesAPIMonitoring, &out.AutomaticKubernetesAPIMonitoring
+		*out = new(bool)
+		**out = **in
+	}
+	if in.K8sAppEnabled != nil {
+		in, out := &in.K8sAppEnabled, &out.K8sAppEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CSIMaxMountTimeout != nil {
+		in, out := &in.CSIMaxMountTimeout, &out.CSIMaxMountTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.IgnoredNamespaces != nil {
+		in, out := &in.IgnoredNamespaces, &out.IgnoredNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutomaticInjection != nil {
+		in, out := &in.AutomaticInjection, &out.AutomaticInjection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LabelVersionDetection != nil {
+		in, out := &in.LabelVersionDetection, &out.LabelVersionDetection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InitContainerSeccompProfile != nil {
+		in, out := &in.InitContainerSeccompProfile, &out.InitContainerSeccompProfile
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OneAgentMaxUnavailable != nil {
+		in, out := &in.OneAgentMaxUnavailable, &out.OneAgentMaxUnavailable
+		*out = new(int)
+		**out = **in
+	}
+	if in.OneAgentInitialConnectRetryMs != nil {
+		in, out := &in.OneAgentInitialConnectRetryMs, &out.OneAgentInitialConnectRetryMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.OneAgentPrivileged != nil {
+		in, out := &in.OneAgentPrivileged, &out.OneAgentPrivileged
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OneAgentSkipLivenessProbe != nil {
+		in, out := &in.OneAgentSkipLivenessProbe, &out.OneAgentSkipLivenessProbe
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeImagePull != nil {
+		in, out := &in.NodeImagePull, &out.NodeImagePull
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentalSpec.
+func (in *ExperimentalSpec) DeepCopy() *ExperimentalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExperimentalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioEgressGatewaySpec) DeepCopyInto(out *IstioEgressGatewaySpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioEgressGatewaySpec.
+func (in *IstioEgressGatewaySpec) DeepCopy() *IstioEgressGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioEgressGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioSpec) DeepCopyInto(out *IstioSpec) {
+	*out = *in
+	if in.EgressGateway != nil {
+		in, out := &in.EgressGateway, &out.EgressGateway
+		*out = new(IstioEgressGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SidecarEgressHosts != nil {
+		in, out := &in.SidecarEgressHosts, &out.SidecarEgressHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioSpec.
+func (in *IstioSpec) DeepCopy() *IstioSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySpec) DeepCopyInto(out *NetworkPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySpec.
+func (in *NetworkPolicySpec) DeepCopy() *NetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OneAgentDefaultsSpec) DeepCopyInto(out *OneAgentDefaultsSpec) {
+	*out = *in
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OneAgentResources != nil {
+		in, out := &in.OneAgentResources, &out.OneAgentResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OneAgentDefaultsSpec.
+func (in *OneAgentDefaultsSpec) DeepCopy() *OneAgentDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OneAgentDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenTelemetryCollectorAutoscalingSpec) DeepCopyInto(out *OpenTelemetryCollectorAutoscalingSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilization != nil {
+		in, out := &in.TargetCPUUtilization, &out.TargetCPUUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetMemoryUtilization != nil {
+		in, out := &in.TargetMemoryUtilization, &out.TargetMemoryUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(v2.HorizontalPodAutoscalerBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTelemetryCollectorAutoscalingSpec.
+func (in *OpenTelemetryCollectorAutoscalingSpec) DeepCopy() *OpenTelemetryCollectorAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenTelemetryCollectorAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenTelemetryCollectorSpec) DeepCopyInto(out *OpenTelemetryCollectorSpec) {
 	*out = *in
@@ -210,18 +607,23 @@ func (in *OpenTelemetryCollectorSpec) DeepCopyInto(out *OpenTelemetryCollectorSp
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(OpenTelemetryCollectorAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ImageRef = in.ImageRef
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
+		*out = make([]v1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.TopologySpreadConstraints != nil {
 		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -238,6 +640,51 @@ func (in *OpenTelemetryCollectorSpec) DeepCopy() *OpenTelemetryCollectorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxiesSpec) DeepCopyInto(out *ProxiesSpec) {
+	*out = *in
+	if in.Operator != nil {
+		in, out := &in.Operator, &out.Operator
+		*out = new(ComponentProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OneAgent != nil {
+		in, out := &in.OneAgent, &out.OneAgent
+		*out = new(ComponentProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveGate != nil {
+		in, out := &in.ActiveGate, &out.ActiveGate
+		*out = new(ComponentProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OtelCollector != nil {
+		in, out := &in.OtelCollector, &out.OtelCollector
+		*out = new(ComponentProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtensionExecutionController != nil {
+		in, out := &in.ExtensionExecutionController, &out.ExtensionExecutionController
+		*out = new(ComponentProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogMonitoring != nil {
+		in, out := &in.LogMonitoring, &out.LogMonitoring
+		*out = new(ComponentProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxiesSpec.
+func (in *ProxiesSpec) DeepCopy() *ProxiesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxiesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplatesSpec) DeepCopyInto(out *TemplatesSpec) {
 	*out = *in
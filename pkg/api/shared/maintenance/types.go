@@ -0,0 +1,20 @@
+package maintenance
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +kubebuilder:object:generate=true
+type Window struct {
+	// Cron expression (minute hour day-of-month month day-of-week) for the start of the maintenance window.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:example:="0 22 * * 1-5"
+	Schedule string `json:"schedule"`
+
+	// Duration of the maintenance window, e.g. 4h.
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// IANA time zone the schedule is evaluated in (the default value is: UTC).
+	// +kubebuilder:example:="Europe/Vienna"
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
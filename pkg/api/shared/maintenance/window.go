@@ -0,0 +1,120 @@
+package maintenance
+
+import (
+	"time"
+	// the operator image has no zoneinfo, so the time zones of the windows are resolved from the embedded database
+	_ "time/tzdata"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Validate checks that the schedule, the duration and the time zone of the window can be used.
+func (window Window) Validate() error {
+	_, err := window.parse()
+
+	return err
+}
+
+// IsOpen returns true if now is inside the window.
+func (window Window) IsOpen(now time.Time) (bool, error) {
+	schedule, err := window.parse()
+	if err != nil {
+		return false, err
+	}
+
+	// the window is open, if the schedule started it during the last duration
+	start := schedule.Next(now.In(schedule.location).Add(-window.Duration.Duration))
+
+	return !start.After(now), nil
+}
+
+// NextStart returns the next time the window opens after now.
+func (window Window) NextStart(now time.Time) (time.Time, error) {
+	schedule, err := window.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return schedule.Next(now.In(schedule.location)), nil
+}
+
+// IsOpen returns true if no windows are configured or now is inside one of them,
+// invalid windows are ignored as they are rejected by the validation webhook.
+func IsOpen(windows []Window, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+
+	for _, window := range windows {
+		if open, err := window.IsOpen(now); err == nil && open {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextStart returns the earliest start of the windows after now, nil is returned if no valid window is configured.
+func NextStart(windows []Window, now time.Time) *time.Time {
+	var next *time.Time
+
+	for _, window := range windows {
+		start, err := window.NextStart(now)
+		if err != nil {
+			continue
+		}
+
+		if next == nil || start.Before(*next) {
+			next = &start
+		}
+	}
+
+	return next
+}
+
+// PendingVersion returns the status of a version that is kept pending until the next window starts.
+func PendingVersion(windows []Window, now time.Time, imageID, version string) *status.PendingVersionStatus {
+	pending := &status.PendingVersionStatus{
+		ImageID: imageID,
+		Version: version,
+	}
+
+	if next := NextStart(windows, now); next != nil {
+		pending.NextMaintenanceWindow = &metav1.Time{Time: *next}
+	}
+
+	return pending
+}
+
+type locatedSchedule struct {
+	cron.Schedule
+
+	location *time.Location
+}
+
+func (window Window) parse() (locatedSchedule, error) {
+	if window.Duration.Duration <= 0 {
+		return locatedSchedule{}, errors.Errorf("duration of maintenance window must be positive, got %s", window.Duration.Duration)
+	}
+
+	location := time.UTC
+
+	if window.TimeZone != "" {
+		var err error
+
+		location, err = time.LoadLocation(window.TimeZone)
+		if err != nil {
+			return locatedSchedule{}, errors.Wrapf(err, "invalid time zone %s of maintenance window", window.TimeZone)
+		}
+	}
+
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return locatedSchedule{}, errors.Wrapf(err, "invalid schedule %s of maintenance window", window.Schedule)
+	}
+
+	return locatedSchedule{Schedule: schedule, location: location}, nil
+}
@@ -0,0 +1,100 @@
+package maintenance
+
+import (
+	"go/build"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWindowValidate(t *testing.T) {
+	t.Run("valid window", func(t *testing.T) {
+		window := Window{Schedule: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}, TimeZone: "Europe/Vienna"}
+		require.NoError(t, window.Validate())
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		window := Window{Schedule: "every night", Duration: metav1.Duration{Duration: time.Hour}}
+		require.Error(t, window.Validate())
+	})
+
+	t.Run("invalid time zone", func(t *testing.T) {
+		window := Window{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"}
+		require.Error(t, window.Validate())
+	})
+
+	t.Run("missing duration", func(t *testing.T) {
+		window := Window{Schedule: "0 22 * * *"}
+		require.Error(t, window.Validate())
+	})
+
+	t.Run("time zone database is embedded", func(t *testing.T) {
+		// the host zoneinfo is always used before the embedded one, so only the import can be checked reliably
+		pkg, err := build.ImportDir(".", 0)
+		require.NoError(t, err)
+		assert.Contains(t, pkg.Imports, "time/tzdata")
+
+		t.Setenv("ZONEINFO", t.TempDir())
+
+		window := Window{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Asia/Tokyo"}
+		require.NoError(t, window.Validate())
+	})
+}
+
+func TestIsOpen(t *testing.T) {
+	nightly := Window{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+
+	t.Run("no windows => always open", func(t *testing.T) {
+		assert.True(t, IsOpen(nil, time.Now()))
+	})
+
+	t.Run("inside window", func(t *testing.T) {
+		assert.True(t, IsOpen([]Window{nightly}, time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC)))
+		assert.True(t, IsOpen([]Window{nightly}, time.Date(2025, 1, 2, 1, 59, 0, 0, time.UTC)))
+	})
+
+	t.Run("outside window", func(t *testing.T) {
+		assert.False(t, IsOpen([]Window{nightly}, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)))
+		assert.False(t, IsOpen([]Window{nightly}, time.Date(2025, 1, 2, 2, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("time zone is respected", func(t *testing.T) {
+		vienna := nightly
+		vienna.TimeZone = "Europe/Vienna"
+
+		// 22:00 in Vienna is 21:00 UTC during winter time
+		assert.True(t, IsOpen([]Window{vienna}, time.Date(2025, 1, 1, 21, 30, 0, 0, time.UTC)))
+		assert.False(t, IsOpen([]Window{vienna}, time.Date(2025, 1, 2, 1, 30, 0, 0, time.UTC)))
+	})
+
+	t.Run("one of multiple windows is open", func(t *testing.T) {
+		weekend := Window{Schedule: "0 8 * * 6", Duration: metav1.Duration{Duration: 8 * time.Hour}}
+		// 2025-01-04 is a Saturday
+		assert.True(t, IsOpen([]Window{nightly, weekend}, time.Date(2025, 1, 4, 12, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("invalid windows are never open", func(t *testing.T) {
+		assert.False(t, IsOpen([]Window{{Schedule: "invalid"}}, time.Now()))
+	})
+}
+
+func TestNextStart(t *testing.T) {
+	nightly := Window{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+	weekend := Window{Schedule: "0 8 * * 6", Duration: metav1.Duration{Duration: 8 * time.Hour}}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	next := NextStart([]Window{weekend, nightly}, now)
+	require.NotNil(t, next)
+	assert.True(t, time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC).Equal(*next))
+
+	assert.Nil(t, NextStart(nil, now))
+
+	pending := PendingVersion([]Window{nightly}, now, "repo@sha256:new", "1.2.3")
+	assert.Equal(t, "1.2.3", pending.Version)
+	assert.Equal(t, "repo@sha256:new", pending.ImageID)
+	require.NotNil(t, pending.NextMaintenanceWindow)
+	assert.True(t, next.Equal(pending.NextMaintenanceWindow.Time))
+}
@@ -26,6 +26,17 @@ type VersionStatus struct {
 	Version string `json:"version,omitempty"`
 	// Image type
 	Type string `json:"type,omitempty"`
+	// Version that was found outside of the maintenance windows, it is applied in the next maintenance window
+	Pending *PendingVersionStatus `json:"pending,omitempty"`
+}
+
+type PendingVersionStatus struct {
+	// Start of the next maintenance window
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+	// Image ID of the pending version
+	ImageID string `json:"imageID,omitempty"`
+	// Pending image version
+	Version string `json:"version,omitempty"`
 }
 
 // IsZero returns true if the VersionStatus fields are not initialized.
@@ -20,6 +20,25 @@ package status
 
 import ()
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingVersionStatus) DeepCopyInto(out *PendingVersionStatus) {
+	*out = *in
+	if in.NextMaintenanceWindow != nil {
+		in, out := &in.NextMaintenanceWindow, &out.NextMaintenanceWindow
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingVersionStatus.
+func (in *PendingVersionStatus) DeepCopy() *PendingVersionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingVersionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VersionStatus) DeepCopyInto(out *VersionStatus) {
 	*out = *in
@@ -27,6 +46,11 @@ func (in *VersionStatus) DeepCopyInto(out *VersionStatus) {
 		in, out := &in.LastProbeTimestamp, &out.LastProbeTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = new(PendingVersionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionStatus.
@@ -6,6 +6,7 @@ package edgeconnect
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/image"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/proxy"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha2"
@@ -42,6 +43,11 @@ type EdgeConnectSpec struct { //nolint:revive
 	// Enables automatic restarts of EdgeConnect pods in case a new version is available (the default value is: true)
 	AutoUpdate *bool `json:"autoUpdate"`
 
+	// Maintenance windows in which automatic updates of EdgeConnect are applied, new versions found outside of the windows are kept pending.
+	// If no window is configured, updates are applied immediately.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []maintenance.Window `json:"maintenanceWindows,omitempty"`
+
 	// Overrides the default image
 	ImageRef image.Ref `json:"imageRef,omitempty"`
 
@@ -19,6 +19,7 @@ limitations under the License.
 package edgeconnect
 
 import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/proxy"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -133,6 +134,11 @@ func (in *EdgeConnectSpec) DeepCopyInto(out *EdgeConnectSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]maintenance.Window, len(*in))
+		copy(*out, *in)
+	}
 	out.ImageRef = in.ImageRef
 	out.OAuth = in.OAuth
 	in.Resources.DeepCopyInto(&out.Resources)
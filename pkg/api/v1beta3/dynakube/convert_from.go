@@ -1,6 +1,8 @@
 package dynakube
 
 import (
+	"maps"
+
 	dkconversion "github.com/Dynatrace/dynatrace-operator/pkg/api/conversion"
 	dynakubelatest "github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	extensionslatest "github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/extensions"
@@ -39,6 +41,7 @@ func (dst *DynaKube) fromBase(src *dynakubelatest.DynaKube) {
 
 	dst.ObjectMeta = *src.ObjectMeta.DeepCopy() // DeepCopy mainly relevant for testing
 	dkconversion.CleanupAnnotations(dst.Annotations)
+	maps.Copy(dst.Annotations, src.ExperimentalAnnotations())
 
 	dst.Spec.Proxy = src.Spec.Proxy
 	dst.Spec.DynatraceAPIRequestThreshold = src.Spec.DynatraceAPIRequestThreshold
@@ -44,6 +44,23 @@ func TestConvertFrom(t *testing.T) {
 		compareBase(t, to, from)
 	})
 
+	t.Run("migrate experimental from latest to v1beta5", func(t *testing.T) {
+		from := getNewDynakubeBase()
+		from.Spec.Experimental = &dynakubelatest.ExperimentalSpec{
+			AutomaticInjection: ptr.To(false),
+			IgnoredNamespaces:  []string{"^ignored$"},
+		}
+		to := DynaKube{}
+
+		err := to.ConvertFrom(&from)
+		require.NoError(t, err)
+
+		assert.Equal(t, "false", to.Annotations[exp.InjectionAutomaticKey])
+		assert.Equal(t, `["^ignored$"]`, to.Annotations[exp.InjectionIgnoredNamespacesKey])
+		assert.False(t, to.FF().IsAutomaticInjection())
+		compareBase(t, to, from)
+	})
+
 	t.Run("migrate metadata-enrichment from latest to v1beta5", func(t *testing.T) {
 		from := getNewDynakubeBase()
 		to := DynaKube{}
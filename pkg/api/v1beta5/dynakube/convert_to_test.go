@@ -40,6 +40,27 @@ func TestConvertTo(t *testing.T) {
 		compareBase(t, from, to)
 	})
 
+	t.Run("migrate feature flags from v1beta5 to latest", func(t *testing.T) {
+		from := getOldDynakubeBase()
+		from.Annotations[exp.InjectionFailurePolicyKey] = "fail"
+		from.Annotations[exp.OAMaxUnavailableKey] = "not-a-number"
+		to := dynakubelatest.DynaKube{}
+
+		err := from.ConvertTo(&to)
+		require.NoError(t, err)
+
+		require.NotNil(t, to.Spec.Experimental)
+		assert.Equal(t, ptr.To(true), to.Spec.Experimental.AutomaticKubernetesAPIMonitoring)
+		assert.Equal(t, "fail", to.Spec.Experimental.InjectionFailurePolicy)
+		assert.Nil(t, to.Spec.Experimental.OneAgentMaxUnavailable)
+		assert.NotContains(t, to.Annotations, exp.AGAutomaticK8sAPIMonitoringKey)
+		assert.NotContains(t, to.Annotations, exp.InjectionFailurePolicyKey)
+		assert.Equal(t, "not-a-number", to.Annotations[exp.OAMaxUnavailableKey])
+		assert.Equal(t, "true", to.Annotations[exp.AGIgnoreProxyKey]) //nolint:staticcheck
+		assert.Equal(t, from.FF().GetInjectionFailurePolicy(), to.FF().GetInjectionFailurePolicy())
+		compareBase(t, from, to)
+	})
+
 	t.Run("migrate metadata-enrichment from v1beta5 to latest", func(t *testing.T) {
 		from := getOldDynakubeBase()
 		to := dynakubelatest.DynaKube{}
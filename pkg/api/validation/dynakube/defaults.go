@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/validation"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	errorDynaKubeDefaultsNotFound = `The DynaKubeDefaults %s referenced in spec.defaultsRef does not exist.`
+
+	referencingDynaKubeMessage = `DynaKube %s/%s: %s`
+)
+
+// withDefaults returns a copy of the DynaKube with the referenced DynaKubeDefaults merged into it,
+// so the validators check the spec the operator is going to reconcile.
+func (v *Validator) withDefaults(ctx context.Context, dk *dynakube.DynaKube) (*dynakube.DynaKube, error) {
+	if dk.DefaultsName() == "" {
+		return dk, nil
+	}
+
+	merged := dk.DeepCopy()
+
+	err := merged.MergeDefaults(ctx, v.apiReader)
+	if k8serrors.IsNotFound(err) {
+		log.Info("requested dynakube references a missing DynaKubeDefaults", "name", dk.Name, "namespace", dk.Namespace, "defaults", dk.DefaultsName())
+
+		return nil, errors.New(validation.SumErrors([]string{fmt.Sprintf(errorDynaKubeDefaultsNotFound, dk.DefaultsName())}, "DynaKube"))
+	} else if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// DefaultsValidator validates the DynaKubes referencing a DynaKubeDefaults with the new defaults merged into them,
+// as a change of the DynaKubeDefaults changes the spec the operator reconciles for all of them.
+type DefaultsValidator struct {
+	validator *Validator
+}
+
+func NewDefaultsValidator(apiReader client.Reader, cfg *rest.Config) admission.CustomValidator {
+	return &DefaultsValidator{
+		validator: &Validator{
+			apiReader: apiReader,
+			cfg:       cfg,
+			modules:   installconfig.GetModules(),
+		},
+	}
+}
+
+func (dv *DefaultsValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	defaults, err := getDynaKubeDefaults(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return dv.validateReferencingDynaKubes(ctx, defaults)
+}
+
+func (dv *DefaultsValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	defaults, err := getDynaKubeDefaults(newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	return dv.validateReferencingDynaKubes(ctx, defaults)
+}
+
+func (dv *DefaultsValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (dv *DefaultsValidator) validateReferencingDynaKubes(ctx context.Context, defaults *dynakube.DynaKubeDefaults) (admission.Warnings, error) {
+	var dkList dynakube.DynaKubeList
+
+	err := dv.validator.apiReader.List(ctx, &dkList)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var (
+		errMessages []string
+		warnings    admission.Warnings
+	)
+
+	for i := range dkList.Items {
+		dk := &dkList.Items[i]
+		if dk.DefaultsName() != defaults.Name {
+			continue
+		}
+
+		merged := dk.DeepCopy()
+		merged.ApplyDefaults(defaults.Spec)
+
+		for _, errMsg := range dv.validator.runValidators(ctx, validatorErrorFuncs, merged) {
+			errMessages = append(errMessages, fmt.Sprintf(referencingDynaKubeMessage, dk.Namespace, dk.Name, errMsg))
+		}
+
+		for _, warning := range dv.validator.runValidators(ctx, validatorWarningFuncs, merged) {
+			warnings = append(warnings, fmt.Sprintf(referencingDynaKubeMessage, dk.Namespace, dk.Name, warning))
+		}
+	}
+
+	if len(errMessages) != 0 {
+		return warnings, errors.New(validation.SumErrors(errMessages, "DynaKubeDefaults"))
+	}
+
+	return warnings, nil
+}
+
+func getDynaKubeDefaults(obj runtime.Object) (*dynakube.DynaKubeDefaults, error) {
+	defaults, ok := obj.(*dynakube.DynaKubeDefaults)
+	if !ok {
+		return nil, errors.Errorf("unexpected object of type %T, expected DynaKubeDefaults", obj)
+	}
+
+	return defaults, nil
+}
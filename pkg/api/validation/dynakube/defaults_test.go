@@ -0,0 +1,119 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestDynaKubeDefaults(t *testing.T) {
+	const defaultsName = "fleet-defaults"
+
+	t.Run("missing DynaKubeDefaults", func(t *testing.T) {
+		assertDenied(t,
+			[]string{fmt.Sprintf(errorDynaKubeDefaultsNotFound, defaultsName)},
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL:      testAPIURL,
+					DefaultsRef: defaultsName,
+				},
+			})
+	})
+	t.Run("merged result is validated", func(t *testing.T) {
+		assertDenied(t,
+			[]string{errorInvalidProxyURL},
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL:      testAPIURL,
+					DefaultsRef: defaultsName,
+				},
+			},
+			&dynakube.DynaKubeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: defaultsName},
+				Spec: dynakube.DynaKubeDefaultsSpec{
+					Proxy: &value.Source{Value: "http://proxy.example.com:notaport"},
+				},
+			})
+	})
+	t.Run("valid DynaKubeDefaults", func(t *testing.T) {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL:      testAPIURL,
+				DefaultsRef: defaultsName,
+			},
+		}
+
+		assertAllowed(t, dk,
+			&dynakube.DynaKubeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: defaultsName},
+				Spec: dynakube.DynaKubeDefaultsSpec{
+					Proxy: &value.Source{Value: "http://proxy.example.com:3128"},
+				},
+			})
+
+		assert.Nil(t, dk.Spec.Proxy, "the DynaKube in the request must not be changed")
+	})
+}
+
+func TestDefaultsValidator(t *testing.T) {
+	const defaultsName = "fleet-defaults"
+
+	ctx := context.Background()
+
+	createDynaKube := func(name, defaultsRef string) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Spec: dynakube.DynaKubeSpec{
+				APIURL:      testAPIURL,
+				DefaultsRef: defaultsRef,
+			},
+		}
+	}
+
+	createDefaults := func(proxy string) *dynakube.DynaKubeDefaults {
+		return &dynakube.DynaKubeDefaults{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultsName},
+			Spec: dynakube.DynaKubeDefaultsSpec{
+				Proxy: &value.Source{Value: proxy},
+			},
+		}
+	}
+
+	t.Run("invalid defaults for referencing DynaKube => denied", func(t *testing.T) {
+		validator := NewDefaultsValidator(fake.NewClient(createDynaKube("referencing", defaultsName)), &rest.Config{})
+
+		_, err := validator.ValidateCreate(ctx, createDefaults("http://proxy.example.com:notaport"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf(referencingDynaKubeMessage, testNamespace, "referencing", errorInvalidProxyURL))
+
+		_, err = validator.ValidateUpdate(ctx, createDefaults("http://proxy.example.com:3128"), createDefaults("http://proxy.example.com:notaport"))
+		require.Error(t, err)
+	})
+
+	t.Run("DynaKube sets the field itself => allowed", func(t *testing.T) {
+		dk := createDynaKube("referencing", defaultsName)
+		dk.Spec.Proxy = &value.Source{Value: "http://proxy.example.com:3128"}
+		validator := NewDefaultsValidator(fake.NewClient(dk), &rest.Config{})
+
+		_, err := validator.ValidateUpdate(ctx, createDefaults("http://proxy.example.com:3128"), createDefaults("http://proxy.example.com:notaport"))
+		require.NoError(t, err)
+	})
+
+	t.Run("no referencing DynaKube => allowed", func(t *testing.T) {
+		validator := NewDefaultsValidator(fake.NewClient(createDynaKube("other", "other-defaults"), createDynaKube("none", "")), &rest.Config{})
+
+		_, err := validator.ValidateCreate(ctx, createDefaults("http://proxy.example.com:notaport"))
+		require.NoError(t, err)
+	})
+}
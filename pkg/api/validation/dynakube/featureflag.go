@@ -3,6 +3,8 @@ package validation
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
@@ -11,12 +13,22 @@ import (
 
 const (
 	warningFeatureFlagDeprecated = `Feature flag %s is deprecated.`
+	warningFeatureFlagUnknown    = `Feature flag %s is unknown and has no effect, check it for typos.`
+	warningFeatureFlagOverridden = `Feature flag %s is ignored, because the corresponding field in spec.experimental is set.`
+	errorFeatureFlagInvalidValue = `The value of feature flag %s is invalid: %s.`
 )
 
+// deprecatedFeatureFlags also lists every known feature flag that has no counterpart in spec.experimental.
 var deprecatedFeatureFlags = []string{
-	exp.OAProxyIgnoredKey,   //nolint:staticcheck
-	exp.AGUpdatesKey,        //nolint:staticcheck
-	exp.AGDisableUpdatesKey, //nolint:staticcheck
+	exp.APIRequestThresholdKey,                //nolint:staticcheck
+	exp.OAProxyIgnoredKey,                     //nolint:staticcheck
+	exp.OASecCompProfileKey,                   //nolint:staticcheck
+	exp.AGUpdatesKey,                          //nolint:staticcheck
+	exp.AGDisableUpdatesKey,                   //nolint:staticcheck
+	exp.AGIgnoreProxyKey,                      //nolint:staticcheck
+	exp.CSIMaxFailedMountAttemptsKey,          //nolint:staticcheck
+	exp.InjectionDisableMetadataEnrichmentKey, //nolint:staticcheck
+	exp.InjectionMetadataEnrichmentKey,        //nolint:staticcheck
 }
 
 func deprecatedFeatureFlag(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
@@ -30,3 +42,55 @@ func deprecatedFeatureFlag(_ context.Context, _ *Validator, dk *dynakube.DynaKub
 
 	return results.String()
 }
+
+func invalidFeatureFlagValue(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	messages := []string{}
+
+	for _, flag := range sortedFeatureFlags(dk) {
+		if err := exp.ValidateFlag(flag, dk.Annotations[flag]); err != nil {
+			log.Info("requested dynakube has a feature flag with an invalid value", "name", dk.Name, "namespace", dk.Namespace, "flag", flag)
+
+			messages = append(messages, fmt.Sprintf(errorFeatureFlagInvalidValue, flag, err.Error()))
+		}
+	}
+
+	return strings.Join(messages, ";")
+}
+
+func unknownFeatureFlag(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	messages := []string{}
+
+	for _, flag := range sortedFeatureFlags(dk) {
+		if !exp.IsKnownFlag(flag) {
+			messages = append(messages, fmt.Sprintf(warningFeatureFlagUnknown, flag))
+		}
+	}
+
+	return strings.Join(messages, ";")
+}
+
+func overriddenFeatureFlag(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	experimental := dk.ExperimentalAnnotations()
+	messages := []string{}
+
+	for _, flag := range sortedFeatureFlags(dk) {
+		if _, ok := experimental[flag]; ok {
+			messages = append(messages, fmt.Sprintf(warningFeatureFlagOverridden, flag))
+		}
+	}
+
+	return strings.Join(messages, ";")
+}
+
+// sortedFeatureFlags returns the feature flag annotations of the DynaKube in a stable order, so the messages don't change between requests.
+func sortedFeatureFlags(dk *dynakube.DynaKube) []string {
+	flags := []string{}
+
+	for _, annotation := range slices.Sorted(maps.Keys(dk.Annotations)) {
+		if exp.IsFeatureFlag(annotation) || exp.IsKnownFlag(annotation) {
+			flags = append(flags, annotation)
+		}
+	}
+
+	return flags
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 func TestDeprecatedFeatureFlag(t *testing.T) {
@@ -55,3 +57,78 @@ func DeprecatedFeatureFlagWithNoAnnotations(t *testing.T) {
 
 	assert.Empty(t, result)
 }
+
+func TestInvalidFeatureFlagValue(t *testing.T) {
+	t.Run("valid values are allowed", func(t *testing.T) {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+				Annotations: map[string]string{
+					exp.InjectionAutomaticKey:     "false",
+					exp.OAMaxUnavailableKey:       "3",
+					exp.CSIMaxMountTimeoutKey:     "5m",
+					exp.InjectionFailurePolicyKey: "fail",
+					"other-annotation":            "whatever",
+				},
+			},
+		}
+
+		assert.Empty(t, invalidFeatureFlagValue(context.Background(), nil, dk))
+	})
+	t.Run("malformed values are denied", func(t *testing.T) {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+				Annotations: map[string]string{
+					exp.InjectionAutomaticKey:     "yes",
+					exp.InjectionFailurePolicyKey: "Fail",
+				},
+			},
+		}
+
+		result := invalidFeatureFlagValue(context.Background(), nil, dk)
+
+		assert.Contains(t, result, fmt.Sprintf(errorFeatureFlagInvalidValue, exp.InjectionAutomaticKey, `expected a boolean, got "yes"`))
+		assert.Contains(t, result, exp.InjectionFailurePolicyKey)
+	})
+}
+
+func TestUnknownFeatureFlag(t *testing.T) {
+	dk := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+			Annotations: map[string]string{
+				exp.FFPrefix + "automatic-injections": "false",
+				exp.InjectionAutomaticKey:             "false",
+				"other-annotation":                    "true",
+			},
+		},
+	}
+
+	result := unknownFeatureFlag(context.Background(), nil, dk)
+
+	assert.Equal(t, fmt.Sprintf(warningFeatureFlagUnknown, exp.FFPrefix+"automatic-injections"), result)
+}
+
+func TestOverriddenFeatureFlag(t *testing.T) {
+	dk := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+			Annotations: map[string]string{
+				exp.InjectionAutomaticKey: "false",
+				exp.OAPrivilegedKey:       "true",
+			},
+		},
+		Spec: dynakube.DynaKubeSpec{
+			Experimental: &dynakube.ExperimentalSpec{
+				AutomaticInjection: ptr.To(true),
+			},
+		},
+	}
+
+	result := overriddenFeatureFlag(context.Background(), nil, dk)
+
+	assert.Equal(t, fmt.Sprintf(warningFeatureFlagOverridden, exp.InjectionAutomaticKey), result)
+	assert.True(t, dk.FF().IsAutomaticInjection())
+	assert.True(t, dk.FF().IsOneAgentPrivileged())
+}
@@ -10,6 +10,7 @@ import (
 const (
 	errorNoResources           = `No resources for istio available`
 	errorFailToInitIstioClient = `Failed to initialize istio client`
+	warningIgnoredIstioConfig  = `The istio configuration is ignored, because enableIstio is not set.`
 )
 
 func noResourcesAvailable(_ context.Context, dv *Validator, dk *dynakube.DynaKube) string {
@@ -27,3 +28,11 @@ func noResourcesAvailable(_ context.Context, dv *Validator, dk *dynakube.DynaKub
 
 	return ""
 }
+
+func ignoredIstioConfig(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if !dk.Spec.EnableIstio && dk.Spec.Istio != nil {
+		return warningIgnoredIstioConfig
+	}
+
+	return ""
+}
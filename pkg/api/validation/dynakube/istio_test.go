@@ -16,3 +16,15 @@ func TestNoResourcesAvailable(t *testing.T) {
 		})
 	})
 }
+
+func TestIgnoredIstioConfig(t *testing.T) {
+	t.Run("istio config without enableIstio => warning", func(t *testing.T) {
+		assertAllowedWithWarnings(t, 1, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testAPIURL,
+				Istio:  &dynakube.IstioSpec{EgressGateway: &dynakube.IstioEgressGatewaySpec{Name: "istio-egressgateway"}},
+			},
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+)
+
+const (
+	errorInvalidMaintenanceWindow = `The DynaKube's specification has an invalid maintenance window at index %d: %s.`
+)
+
+func invalidMaintenanceWindows(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	messages := []string{}
+
+	for i, window := range dk.Spec.MaintenanceWindows {
+		if err := window.Validate(); err != nil {
+			log.Info("requested dynakube has an invalid maintenance window", "name", dk.Name, "namespace", dk.Namespace, "schedule", window.Schedule)
+
+			messages = append(messages, fmt.Sprintf(errorInvalidMaintenanceWindow, i, err.Error()))
+		}
+	}
+
+	return strings.Join(messages, ";")
+}
@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInvalidMaintenanceWindows(t *testing.T) {
+	newDynakube := func(windows ...maintenance.Window) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testName,
+				Namespace: testNamespace,
+			},
+			Spec: dynakube.DynaKubeSpec{
+				APIURL:             testAPIURL,
+				MaintenanceWindows: windows,
+			},
+		}
+	}
+
+	t.Run("valid maintenance window", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, newDynakube(maintenance.Window{
+			Schedule: "0 22 * * 1-5",
+			Duration: metav1.Duration{Duration: 4 * time.Hour},
+			TimeZone: "Europe/Vienna",
+		}))
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		assertDenied(t, []string{"invalid maintenance window at index 1", "invalid schedule after business hours"}, newDynakube(
+			maintenance.Window{Schedule: "@daily", Duration: metav1.Duration{Duration: time.Hour}},
+			maintenance.Window{Schedule: "after business hours", Duration: metav1.Duration{Duration: time.Hour}},
+		))
+	})
+
+	t.Run("invalid time zone", func(t *testing.T) {
+		assertDenied(t, []string{"invalid time zone"}, newDynakube(
+			maintenance.Window{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+		))
+	})
+}
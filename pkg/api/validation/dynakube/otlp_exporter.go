@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/otlp"
+)
+
+const (
+	errorInvalidOTLPExporterEndpoint = `The DynaKube's specification has an invalid OTLP exporter endpoint '%s' for the %s signal. Make sure to specify an absolute http or https URL.`
+	errorGRPCWithoutCustomEndpoint   = `The DynaKube's specification uses the grpc protocol for the %s signal without a custom endpoint. The Dynatrace environment only accepts http/protobuf, either use http/protobuf or specify a custom endpoint.`
+)
+
+type signalExporter struct {
+	spec *otlp.SignalExporterSpec
+	name string
+}
+
+func invalidOTLPExporterEndpoint(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	for _, signal := range getSignalExporters(dk) {
+		if !signal.spec.HasCustomEndpoint() {
+			continue
+		}
+
+		if !isValidOTLPEndpoint(signal.spec.Endpoint) {
+			log.Info("invalid OTLP exporter endpoint", "endpoint", signal.spec.Endpoint, "signal", signal.name)
+
+			return fmt.Sprintf(errorInvalidOTLPExporterEndpoint, signal.spec.Endpoint, signal.name)
+		}
+	}
+
+	return ""
+}
+
+func invalidOTLPExporterProtocol(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	for _, signal := range getSignalExporters(dk) {
+		if signal.spec.GetProtocol() == otlp.ProtocolGRPC && !signal.spec.HasCustomEndpoint() {
+			log.Info("grpc OTLP exporter without custom endpoint", "signal", signal.name)
+
+			return fmt.Sprintf(errorGRPCWithoutCustomEndpoint, signal.name)
+		}
+	}
+
+	return ""
+}
+
+func getSignalExporters(dk *dynakube.DynaKube) []signalExporter {
+	spec := dk.Spec.OTLPExporterConfiguration
+	if spec == nil {
+		return nil
+	}
+
+	signals := []signalExporter{}
+
+	if spec.Signals.Traces != nil {
+		signals = append(signals, signalExporter{name: "traces", spec: &spec.Signals.Traces.SignalExporterSpec})
+	}
+
+	if spec.Signals.Metrics != nil {
+		signals = append(signals, signalExporter{name: "metrics", spec: &spec.Signals.Metrics.SignalExporterSpec})
+	}
+
+	if spec.Signals.Logs != nil {
+		signals = append(signals, signalExporter{name: "logs", spec: &spec.Signals.Logs.SignalExporterSpec})
+	}
+
+	return signals
+}
+
+func isValidOTLPEndpoint(endpoint string) bool {
+	parsedURL, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+
+	return (parsedURL.Scheme == "http" || parsedURL.Scheme == "https") && parsedURL.Host != ""
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/otlp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOTLPExporterEndpoint(t *testing.T) {
+	newDynakube := func(signals otlp.SignalConfiguration) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testName,
+				Namespace: testNamespace,
+			},
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testAPIURL,
+				OTLPExporterConfiguration: &otlp.ExporterConfigurationSpec{
+					Signals: signals,
+				},
+			},
+		}
+	}
+
+	t.Run("default endpoints", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, newDynakube(otlp.SignalConfiguration{
+			Traces:  &otlp.TracesSignal{},
+			Metrics: &otlp.MetricsSignal{},
+			Logs:    &otlp.LogsSignal{},
+		}))
+	})
+	t.Run("valid custom endpoint", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, newDynakube(otlp.SignalConfiguration{
+			Traces: &otlp.TracesSignal{SignalExporterSpec: otlp.SignalExporterSpec{
+				Endpoint: "http://collector.observability:4318/v1/traces",
+			}},
+		}))
+	})
+	t.Run("relative custom endpoint", func(t *testing.T) {
+		endpoint := "collector.observability:4318"
+		assertDenied(t, []string{fmt.Sprintf(errorInvalidOTLPExporterEndpoint, endpoint, "metrics")}, newDynakube(otlp.SignalConfiguration{
+			Metrics: &otlp.MetricsSignal{SignalExporterSpec: otlp.SignalExporterSpec{
+				Endpoint: endpoint,
+			}},
+		}))
+	})
+	t.Run("unsupported scheme", func(t *testing.T) {
+		endpoint := "ftp://collector.observability/v1/logs"
+		assertDenied(t, []string{fmt.Sprintf(errorInvalidOTLPExporterEndpoint, endpoint, "logs")}, newDynakube(otlp.SignalConfiguration{
+			Logs: &otlp.LogsSignal{SignalExporterSpec: otlp.SignalExporterSpec{
+				Endpoint: endpoint,
+			}},
+		}))
+	})
+	t.Run("grpc with custom endpoint", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, newDynakube(otlp.SignalConfiguration{
+			Traces: &otlp.TracesSignal{SignalExporterSpec: otlp.SignalExporterSpec{
+				Endpoint: "http://collector.observability:4317",
+				Protocol: otlp.ProtocolGRPC,
+			}},
+		}))
+	})
+	t.Run("grpc without custom endpoint", func(t *testing.T) {
+		assertDenied(t, []string{fmt.Sprintf(errorGRPCWithoutCustomEndpoint, "metrics")}, newDynakube(otlp.SignalConfiguration{
+			Metrics: &otlp.MetricsSignal{SignalExporterSpec: otlp.SignalExporterSpec{
+				Protocol: otlp.ProtocolGRPC,
+			}},
+		}))
+	})
+}
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"regexp"
 
@@ -14,6 +15,9 @@ const (
 	errorInvalidEvalCharacter = `The DynaKube's specification has an invalid Proxy password value set. Make sure you don't use forbidden characters: space, apostrophe, backtick, comma, ampersand, equals sign, plus sign, percent sign, backslash.`
 
 	errorMissingProxySecret = `Error occurred while reading PROXY secret indicated in the Dynakube specification`
+
+	errorPACConfigMapNotSupported   = `The DynaKube's specification sets a proxy auto-config for the %s, which is only supported for the operator.`
+	errorProxyCredentialsWithoutURL = `The DynaKube's specification sets proxy credentials for the %s, but neither spec.proxy nor a proxy URL for the component is set.`
 )
 
 func invalidActiveGateProxyURL(ctx context.Context, dv *Validator, dk *dynakube.DynaKube) string {
@@ -29,6 +33,40 @@ func invalidActiveGateProxyURL(ctx context.Context, dv *Validator, dk *dynakube.
 	return ""
 }
 
+// invalidProxyOverrides checks the proxies of spec.proxies, the credentials are part of the resolved URL.
+func invalidProxyOverrides(ctx context.Context, dv *Validator, dk *dynakube.DynaKube) string {
+	for _, component := range dynakube.ProxyComponents {
+		override := dk.ProxyOverride(component)
+		if override == nil || override.Disabled {
+			continue
+		}
+
+		if component != dynakube.OperatorProxyComponent && override.PACConfigMap != "" {
+			return fmt.Sprintf(errorPACConfigMapNotSupported, component)
+		}
+
+		usesPAC := component == dynakube.OperatorProxyComponent && dk.ProxyPACConfigMap() != ""
+		if override.CredentialsSecret != "" && !dk.HasProxyOverride(component) && !dk.HasProxy() && !usesPAC {
+			return fmt.Sprintf(errorProxyCredentialsWithoutURL, component)
+		}
+
+		if !dk.HasProxyOverride(component) {
+			continue
+		}
+
+		proxyURL, err := dk.ComponentProxy(ctx, dv.apiReader, component)
+		if err != nil {
+			return errors.Wrap(err, errorMissingProxySecret).Error()
+		}
+
+		if message := validateProxyURL(proxyURL, errorInvalidProxyURL, errorInvalidEvalCharacter); message != "" {
+			return message
+		}
+	}
+
+	return ""
+}
+
 // proxyURL is valid if
 // 1) encoded
 // 2) password does not contain '` characters.
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
@@ -184,3 +185,91 @@ func TestInvalidActiveGateProxy(t *testing.T) {
 		assert.False(t, isStringValidForAG("\xF0\x9F\x98\x80"))
 	})
 }
+
+func TestInvalidProxyOverrides(t *testing.T) {
+	const testCredentialsSecret = "proxy-credentials"
+
+	credentialsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testCredentialsSecret,
+			Namespace: testNamespace,
+		},
+		Data: map[string][]byte{
+			dynakube.ProxyUsernameKey: []byte("user"),
+			dynakube.ProxyPasswordKey: []byte("password"),
+		},
+	}
+
+	t.Run("valid overrides", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t,
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL: testAPIURL,
+					Proxies: &dynakube.ProxiesSpec{
+						Operator:   &dynakube.ComponentProxySpec{PACConfigMap: "proxy-pac", CredentialsSecret: testCredentialsSecret},
+						ActiveGate: &dynakube.ComponentProxySpec{URL: &value.Source{Value: validEncodedProxyURLNoPassword}, CredentialsSecret: testCredentialsSecret},
+						OneAgent:   &dynakube.ComponentProxySpec{Disabled: true},
+					},
+				},
+			},
+			credentialsSecret)
+	})
+
+	t.Run("invalid override url", func(t *testing.T) {
+		assertDenied(t,
+			[]string{errorInvalidProxyURL},
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL: testAPIURL,
+					Proxies: &dynakube.ProxiesSpec{
+						OneAgent: &dynakube.ComponentProxySpec{URL: &value.Source{Value: invalidPlainTextProxyURL}},
+					},
+				},
+			})
+	})
+
+	t.Run("missing credentials secret", func(t *testing.T) {
+		assertDenied(t,
+			[]string{errorMissingProxySecret},
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL: testAPIURL,
+					Proxies: &dynakube.ProxiesSpec{
+						OneAgent: &dynakube.ComponentProxySpec{URL: &value.Source{Value: validEncodedProxyURLNoPassword}, CredentialsSecret: testCredentialsSecret},
+					},
+				},
+			})
+	})
+
+	t.Run("proxy auto-config for other component", func(t *testing.T) {
+		assertDenied(t,
+			[]string{fmt.Sprintf(errorPACConfigMapNotSupported, dynakube.OneAgentProxyComponent)},
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL: testAPIURL,
+					Proxies: &dynakube.ProxiesSpec{
+						OneAgent: &dynakube.ComponentProxySpec{PACConfigMap: "proxy-pac"},
+					},
+				},
+			})
+	})
+
+	t.Run("credentials without proxy url", func(t *testing.T) {
+		assertDenied(t,
+			[]string{fmt.Sprintf(errorProxyCredentialsWithoutURL, dynakube.ActiveGateProxyComponent)},
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL: testAPIURL,
+					Proxies: &dynakube.ProxiesSpec{
+						ActiveGate: &dynakube.ComponentProxySpec{CredentialsSecret: testCredentialsSecret},
+					},
+				},
+			},
+			credentialsSecret)
+	})
+}
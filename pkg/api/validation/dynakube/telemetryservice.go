@@ -3,7 +3,6 @@ package validation
 import (
 	"context"
 	"fmt"
-	"slices"
 	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
@@ -11,6 +10,9 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	agconsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -25,6 +27,13 @@ const (
 	errorTelemetryIngestServiceNameInUse     = `The DynaKube's specification enables the TelemetryIngest feature, the telemetry service name is already used by other Dynakube.`
 	errorTelemetryIngestForbiddenServiceName = `The DynaKube's specification enables the TelemetryIngest feature, the telemetry service name is incorrect because of forbidden suffix.`
 	errorOtelCollectorMissingImage           = `The Dynakube's specification specifies the OTel Collector, but no image repository/tag is configured.`
+	errorOtelCollectorInvalidAutoscaling     = `The Dynakube's specification enables autoscaling of the OTel Collector, but minReplicas (%d) is greater than maxReplicas (%d).`
+	errorTelemetryIngestPrometheusAutoscaled = `The DynaKube's specification enables the prometheus protocol of the TelemetryIngest feature together with autoscaling of the OTel Collector. The scrape targets are sharded by the configured replicas, please disable the autoscaling.`
+	errorTelemetryIngestInvalidPipelineExts  = `The DynaKube's specification enables the TelemetryIngest feature, but the pipeline extensions in ConfigMap '%s' are invalid: %s`
+	warningTelemetryIngestMissingPipelineExt = `The DynaKube's specification enables the TelemetryIngest feature with pipeline extensions, but the ConfigMap '%s' with the key '%s' can't be found. The OTel Collector configuration will not be updated until it is created.`
+	warningTelemetryIngestPrometheusIgnored  = `The DynaKube's specification configures the prometheus protocol of the TelemetryIngest feature, but the protocol is not enabled. The configuration is ignored.`
+	warningOtelCollectorAutoscalingNoRequest = `The Dynakube's specification enables autoscaling of the OTel Collector based on %s utilization, but no %s request is set in the resources of the OTel Collector. The utilization can't be calculated without it.`
+	warningOtelCollectorReplicasIgnored      = `The Dynakube's specification sets the replicas of the OTel Collector together with its autoscaling. The replicas are managed by the autoscaling, the configured replicas are ignored.`
 )
 
 func emptyTelemetryIngestProtocolsList(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
@@ -49,7 +58,7 @@ func unknownTelemetryIngestProtocols(_ context.Context, _ *Validator, dk *dynaku
 	var unknownProtocols []string
 
 	for _, protocol := range dk.TelemetryIngest().GetProtocols() {
-		if !slices.Contains(otelcgen.RegisteredProtocols, protocol) {
+		if !otelcgen.IsSupportedProtocol(protocol) {
 			unknownProtocols = append(unknownProtocols, string(protocol))
 		}
 	}
@@ -184,3 +193,119 @@ func missingOtelCollectorImage(_ context.Context, _ *Validator, dk *dynakube.Dyn
 
 	return ""
 }
+
+func invalidOtelCollectorAutoscaling(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if !dk.IsOtelCollectorAutoscalingEnabled() {
+		return ""
+	}
+
+	maxReplicas := dk.Spec.Templates.OpenTelemetryCollector.Autoscaling.MaxReplicas
+	if dk.OtelCollectorMinReplicas() > maxReplicas {
+		return fmt.Sprintf(errorOtelCollectorInvalidAutoscaling, dk.OtelCollectorMinReplicas(), maxReplicas)
+	}
+
+	return ""
+}
+
+func otelCollectorAutoscalingWithoutResourceRequests(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if !dk.IsOtelCollectorAutoscalingEnabled() {
+		return ""
+	}
+
+	autoscaling := dk.Spec.Templates.OpenTelemetryCollector.Autoscaling
+	requests := dk.Spec.Templates.OpenTelemetryCollector.Resources.Requests
+
+	// without any target the OTel Collector is scaled on its CPU utilization
+	cpuTargetSet := autoscaling.TargetCPUUtilization != nil || (autoscaling.TargetMemoryUtilization == nil && len(autoscaling.Metrics) == 0)
+	if _, ok := requests[corev1.ResourceCPU]; cpuTargetSet && !ok {
+		return fmt.Sprintf(warningOtelCollectorAutoscalingNoRequest, corev1.ResourceCPU, corev1.ResourceCPU)
+	}
+
+	if _, ok := requests[corev1.ResourceMemory]; autoscaling.TargetMemoryUtilization != nil && !ok {
+		return fmt.Sprintf(warningOtelCollectorAutoscalingNoRequest, corev1.ResourceMemory, corev1.ResourceMemory)
+	}
+
+	return ""
+}
+
+func ignoredOtelCollectorReplicas(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if !dk.IsOtelCollectorAutoscalingEnabled() || dk.Spec.Templates.OpenTelemetryCollector.Replicas == nil {
+		return ""
+	}
+
+	return warningOtelCollectorReplicasIgnored
+}
+
+func invalidTelemetryIngestPipelineExtensions(ctx context.Context, dv *Validator, dk *dynakube.DynaKube) string {
+	if !dk.TelemetryIngest().HasPipelineExtensions() {
+		return ""
+	}
+
+	data, found, err := getPipelineExtensionsData(ctx, dv, dk)
+	if err != nil {
+		log.Info("error occurred while reading the pipeline extensions", "err", err.Error())
+
+		return ""
+	} else if !found {
+		return ""
+	}
+
+	extensions, err := otelcgen.ParsePipelineExtensions([]byte(data))
+	if err == nil {
+		_, err = otelcgen.NewConfig("", dk.TelemetryIngest().GetProtocols(), otelcgen.WithPipelineExtensions(*extensions))
+	}
+
+	if err != nil {
+		return fmt.Sprintf(errorTelemetryIngestInvalidPipelineExts, dk.TelemetryIngest().PipelineExtensionsRef, err.Error())
+	}
+
+	return ""
+}
+
+func missingTelemetryIngestPipelineExtensions(ctx context.Context, dv *Validator, dk *dynakube.DynaKube) string {
+	if !dk.TelemetryIngest().HasPipelineExtensions() {
+		return ""
+	}
+
+	_, found, err := getPipelineExtensionsData(ctx, dv, dk)
+	if err != nil || found {
+		return ""
+	}
+
+	return fmt.Sprintf(warningTelemetryIngestMissingPipelineExt, dk.TelemetryIngest().PipelineExtensionsRef, telemetryingest.PipelineExtensionsConfigMapKey)
+}
+
+func getPipelineExtensionsData(ctx context.Context, dv *Validator, dk *dynakube.DynaKube) (string, bool, error) {
+	var configMap corev1.ConfigMap
+
+	err := dv.apiReader.Get(ctx, types.NamespacedName{Name: dk.TelemetryIngest().PipelineExtensionsRef, Namespace: dk.Namespace}, &configMap)
+	if k8serrors.IsNotFound(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+
+	data, ok := configMap.Data[telemetryingest.PipelineExtensionsConfigMapKey]
+
+	return data, ok, nil
+}
+
+func autoscaledTelemetryIngestPrometheus(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if !dk.TelemetryIngest().IsEnabled() || !dk.TelemetryIngest().IsPrometheusEnabled() || !dk.IsOtelCollectorAutoscalingEnabled() {
+		return ""
+	}
+
+	return errorTelemetryIngestPrometheusAutoscaled
+}
+
+func ignoredTelemetryIngestPrometheusConfig(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if !dk.TelemetryIngest().IsEnabled() || dk.TelemetryIngest().Prometheus == nil {
+		return ""
+	}
+
+	if !dk.TelemetryIngest().IsPrometheusEnabled() {
+		return warningTelemetryIngestPrometheusIgnored
+	}
+
+	return ""
+}
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
@@ -9,7 +10,10 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	agconsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -378,3 +382,139 @@ func TestImages(t *testing.T) {
 			})
 	})
 }
+
+func TestOtelCollectorAutoscaling(t *testing.T) {
+	newDynakube := func(autoscaling *dynakube.OpenTelemetryCollectorAutoscalingSpec, requests corev1.ResourceList) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL:          testAPIURL,
+				TelemetryIngest: &telemetryingest.Spec{},
+				Templates: dynakube.TemplatesSpec{
+					OpenTelemetryCollector: dynakube.OpenTelemetryCollectorSpec{
+						ImageRef: image.Ref{
+							Repository: "test-repo",
+							Tag:        "test-tag",
+						},
+						Autoscaling: autoscaling,
+						Resources:   corev1.ResourceRequirements{Requests: requests},
+					},
+				},
+			},
+		}
+	}
+
+	cpuRequest := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+
+	t.Run("valid autoscaling", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, newDynakube(&dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			MinReplicas: ptr.To(int32(2)),
+			MaxReplicas: 5,
+		}, cpuRequest))
+	})
+	t.Run("min replicas greater than max replicas", func(t *testing.T) {
+		assertDenied(t, []string{fmt.Sprintf(errorOtelCollectorInvalidAutoscaling, 6, 5)}, newDynakube(&dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			MinReplicas: ptr.To(int32(6)),
+			MaxReplicas: 5,
+		}, cpuRequest))
+	})
+	t.Run("cpu utilization target without cpu request", func(t *testing.T) {
+		assertAllowedWithWarnings(t, 1, newDynakube(&dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			MaxReplicas: 5,
+		}, nil))
+	})
+	t.Run("memory utilization target without memory request", func(t *testing.T) {
+		assertAllowedWithWarnings(t, 1, newDynakube(&dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			MaxReplicas:             5,
+			TargetMemoryUtilization: ptr.To(int32(80)),
+		}, cpuRequest))
+	})
+	t.Run("replicas together with autoscaling", func(t *testing.T) {
+		dk := newDynakube(&dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			MaxReplicas: 5,
+		}, cpuRequest)
+		dk.Spec.Templates.OpenTelemetryCollector.Replicas = ptr.To(int32(3))
+
+		assertAllowedWithWarnings(t, 1, dk)
+	})
+}
+
+func TestTelemetryIngestPipelineExtensions(t *testing.T) {
+	newDynakube := func() *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testAPIURL,
+				TelemetryIngest: &telemetryingest.Spec{
+					PipelineExtensionsRef: "extensions",
+				},
+				Templates: dynakube.TemplatesSpec{
+					OpenTelemetryCollector: dynakube.OpenTelemetryCollectorSpec{
+						ImageRef: image.Ref{
+							Repository: "test-repo",
+							Tag:        "test-tag",
+						},
+					},
+				},
+			},
+		}
+	}
+	newConfigMap := func(data string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "extensions", Namespace: testNamespace},
+			Data:       map[string]string{telemetryingest.PipelineExtensionsConfigMapKey: data},
+		}
+	}
+
+	t.Run("valid pipeline extensions", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, newDynakube(), newConfigMap("processors: {filter/drop: {}}\npipelines: {logs: {processors: [filter/drop]}}"))
+	})
+	t.Run("invalid pipeline extensions", func(t *testing.T) {
+		assertDenied(t, []string{"pipeline extensions in ConfigMap 'extensions' are invalid", "batch/custom"}, newDynakube(), newConfigMap("processors: {batch/custom: {}}"))
+	})
+	t.Run("unparsable pipeline extensions", func(t *testing.T) {
+		assertDenied(t, []string{"pipeline extensions in ConfigMap 'extensions' are invalid"}, newDynakube(), newConfigMap("exporters: {}"))
+	})
+	t.Run("missing configmap", func(t *testing.T) {
+		assertAllowedWithWarnings(t, 1, newDynakube())
+	})
+}
+
+func TestTelemetryIngestPrometheus(t *testing.T) {
+	newDynakube := func(protocols []string) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testAPIURL,
+				TelemetryIngest: &telemetryingest.Spec{
+					Protocols: protocols,
+					Prometheus: &telemetryingest.PrometheusSpec{
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"scrape": "true"}},
+					},
+				},
+				Templates: dynakube.TemplatesSpec{
+					OpenTelemetryCollector: dynakube.OpenTelemetryCollectorSpec{
+						ImageRef: image.Ref{
+							Repository: "test-repo",
+							Tag:        "test-tag",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("prometheus protocol is supported", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, newDynakube([]string{string(otelcgen.OtlpProtocol), string(otelcgen.PrometheusProtocol)}))
+	})
+	t.Run("prometheus config without protocol", func(t *testing.T) {
+		assertAllowedWithWarnings(t, 1, newDynakube([]string{string(otelcgen.OtlpProtocol)}))
+	})
+	t.Run("prometheus protocol with autoscaling", func(t *testing.T) {
+		dk := newDynakube([]string{string(otelcgen.PrometheusProtocol)})
+		dk.Spec.Templates.OpenTelemetryCollector.Autoscaling = &dynakube.OpenTelemetryCollectorAutoscalingSpec{MaxReplicas: 5}
+		dk.Spec.Templates.OpenTelemetryCollector.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+
+		assertDenied(t, []string{errorTelemetryIngestPrometheusAutoscaled}, dk)
+	})
+}
@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	errorOAuthClientWithoutCustomPullSecret = `The token secret '%s' only contains an OAuth client, whose bearer tokens expire and can't be used to pull images from the Dynatrace registry. Set spec.customPullSecret or add a platform, paas or api token to the token secret.`
+)
+
+// oAuthClientWithoutCustomPullSecret rejects DynaKubes whose pull secret can't be generated from the token secret.
+// A missing token secret is not reported, as it may be created after the DynaKube.
+func oAuthClientWithoutCustomPullSecret(ctx context.Context, dv *Validator, dk *dynakube.DynaKube) string {
+	if dk.Spec.CustomPullSecret != "" || (!dk.OneAgent().IsDaemonsetRequired() && !dk.ActiveGate().IsEnabled()) {
+		return ""
+	}
+
+	var tokens corev1.Secret
+
+	err := dv.apiReader.Get(ctx, types.NamespacedName{Name: dk.Tokens(), Namespace: dk.Namespace}, &tokens)
+	if err != nil {
+		return ""
+	}
+
+	if len(tokens.Data[dtclient.OAuthClientID]) == 0 {
+		return ""
+	}
+
+	for _, registryToken := range []string{dtclient.PaasToken, dtclient.APIToken, dtclient.PlatformToken} {
+		if len(tokens.Data[registryToken]) != 0 {
+			return ""
+		}
+	}
+
+	log.Info("requested dynakube has an OAuth client only token secret without a custom pull secret", "name", dk.Name, "namespace", dk.Namespace)
+
+	return fmt.Sprintf(errorOAuthClientWithoutCustomPullSecret, dk.Tokens())
+}
@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOAuthClientWithoutCustomPullSecret(t *testing.T) {
+	newDynakube := func(customPullSecret string) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL:           testAPIURL,
+				CustomPullSecret: customPullSecret,
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+				},
+			},
+		}
+	}
+	newTokenSecret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+			Data:       data,
+		}
+	}
+	oAuthClient := map[string][]byte{
+		dtclient.OAuthClientID:     []byte("client-id"),
+		dtclient.OAuthClientSecret: []byte("client-secret"),
+	}
+
+	t.Run("oauth client without custom pull secret", func(t *testing.T) {
+		assertDenied(t, []string{fmt.Sprintf(errorOAuthClientWithoutCustomPullSecret, testName)}, newDynakube(""), newTokenSecret(oAuthClient))
+	})
+	t.Run("oauth client with custom pull secret", func(t *testing.T) {
+		assertAllowed(t, newDynakube("pull-secret"), newTokenSecret(oAuthClient))
+	})
+	t.Run("oauth client with platform token", func(t *testing.T) {
+		assertAllowed(t, newDynakube(""), newTokenSecret(map[string][]byte{
+			dtclient.OAuthClientID:     []byte("client-id"),
+			dtclient.OAuthClientSecret: []byte("client-secret"),
+			dtclient.PlatformToken:     []byte("platform-token"),
+		}))
+	})
+	t.Run("missing token secret", func(t *testing.T) {
+		assertAllowed(t, newDynakube(""))
+	})
+}
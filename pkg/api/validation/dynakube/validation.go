@@ -40,6 +40,8 @@ var (
 		duplicateActiveGateCapabilities,
 		mutuallyExclusiveActiveGatePVsettings,
 		invalidActiveGateProxyURL,
+		invalidProxyOverrides,
+		oAuthClientWithoutCustomPullSecret,
 		conflictingOneAgentConfiguration,
 		conflictingOneAgentNodeSelector,
 		conflictingNamespaceSelector,
@@ -65,6 +67,13 @@ var (
 		forbiddenTelemetryIngestServiceNameSuffix,
 		conflictingTelemetryIngestServiceNames,
 		missingOtelCollectorImage,
+		invalidOtelCollectorAutoscaling,
+		autoscaledTelemetryIngestPrometheus,
+		invalidTelemetryIngestPipelineExtensions,
+		invalidOTLPExporterEndpoint,
+		invalidOTLPExporterProtocol,
+		invalidFeatureFlagValue,
+		invalidMaintenanceWindows,
 	}
 	validatorWarningFuncs = []validatorFunc{
 		missingActiveGateMemoryLimit,
@@ -72,12 +81,19 @@ var (
 		conflictingHostGroupSettings,
 		deprecatedAutoUpdate,
 		deprecatedFeatureFlag,
+		unknownFeatureFlag,
+		overriddenFeatureFlag,
 		ignoredLogMonitoringTemplate,
 		conflictingAPIURLForExtensions,
 		logMonitoringWithoutK8SMonitoring,
 		kspmWithoutK8SMonitoring,
 		noMappedHostPaths,
 		extensionsWithoutK8SMonitoring,
+		otelCollectorAutoscalingWithoutResourceRequests,
+		ignoredOtelCollectorReplicas,
+		missingTelemetryIngestPipelineExtensions,
+		ignoredTelemetryIngestPrometheusConfig,
+		ignoredIstioConfig,
 	}
 	updateValidatorErrorFuncs = []updateValidatorFunc{
 		IsMutatedAPIURL,
@@ -101,6 +117,11 @@ func (v *Validator) ValidateCreate(ctx context.Context, obj runtime.Object) (war
 		return
 	}
 
+	dk, err = v.withDefaults(ctx, dk)
+	if err != nil {
+		return
+	}
+
 	errMessages := v.runValidators(ctx, validatorErrorFuncs, dk)
 	warnings = v.runValidators(ctx, validatorWarningFuncs, dk)
 
@@ -122,6 +143,11 @@ func (v *Validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.O
 		return
 	}
 
+	newDk, err = v.withDefaults(ctx, newDk)
+	if err != nil {
+		return
+	}
+
 	errMessages := v.runValidators(ctx, validatorErrorFuncs, newDk)
 	warnings = v.runValidators(ctx, validatorWarningFuncs, newDk)
 
@@ -23,5 +23,9 @@ func SetupWebhookWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	return latest.SetupWebhookWithManager(mgr, validator)
+	if err := latest.SetupWebhookWithManager(mgr, validator); err != nil {
+		return err
+	}
+
+	return latest.SetupDefaultsWebhookWithManager(mgr, NewDefaultsValidator(mgr.GetAPIReader(), mgr.GetConfig()))
 }
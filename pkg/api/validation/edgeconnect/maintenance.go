@@ -0,0 +1,27 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha2/edgeconnect"
+)
+
+const (
+	errorInvalidMaintenanceWindow = `The EdgeConnect's specification has an invalid maintenance window at index %d: %s.`
+)
+
+func invalidMaintenanceWindows(_ context.Context, _ *Validator, ec *edgeconnect.EdgeConnect) string {
+	messages := []string{}
+
+	for i, window := range ec.Spec.MaintenanceWindows {
+		if err := window.Validate(); err != nil {
+			log.Info("requested edgeconnect has an invalid maintenance window", "name", ec.Name, "namespace", ec.Namespace, "schedule", window.Schedule)
+
+			messages = append(messages, fmt.Sprintf(errorInvalidMaintenanceWindow, i, err.Error()))
+		}
+	}
+
+	return strings.Join(messages, ";")
+}
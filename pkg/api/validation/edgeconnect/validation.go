@@ -31,6 +31,7 @@ var validatorErrorFuncs = []validatorFunc{
 	checkHostPatternsValue,
 	isInvalidServiceName,
 	automationRequiresProvisionerValidation,
+	invalidMaintenanceWindows,
 }
 
 func New(apiReader client.Reader, cfg *rest.Config) admission.CustomValidator {
@@ -34,7 +34,7 @@ func (dtc *dynatraceClient) GetActiveGateAuthToken(ctx context.Context, dynakube
 		return nil, errors.WithStack(err)
 	}
 
-	response, err := dtc.httpClient.Do(request)
+	response, err := dtc.doRequest(request)
 	defer utils.CloseBodyAfterRequest(response)
 
 	if err != nil {
@@ -66,7 +66,7 @@ func (dtc *dynatraceClient) createAuthTokenRequest(ctx context.Context, dynakube
 		return nil, err
 	}
 
-	request, err := createBaseRequest(
+	request, err := dtc.createBaseRequest(
 		ctx,
 		dtc.getActiveGateAuthTokenURL(),
 		http.MethodPost,
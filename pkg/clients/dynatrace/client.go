@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/logmonitoring"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/pac"
 	"github.com/pkg/errors"
 	"golang.org/x/net/http/httpproxy"
 )
@@ -63,6 +64,10 @@ type Client interface {
 	// GetTokenScopes returns the list of scopes assigned to a token if successful.
 	GetTokenScopes(ctx context.Context, token string) (TokenScopes, error)
 
+	// GetPlatformTokenScopes returns the API token scopes equivalent to the scopes granted for the platform authentication.
+	// Returns nil without an error, if the granted scopes can't be determined.
+	GetPlatformTokenScopes(ctx context.Context) (TokenScopes, error)
+
 	// GetActiveGateConnectionInfo returns AgentTenantInfo for ActiveGate that holds UUID, Tenant Token and Endpoints
 	GetActiveGateConnectionInfo(ctx context.Context) (ActiveGateConnectionInfo, error)
 
@@ -149,7 +154,7 @@ type NewFunc func(url, apiToken, paasToken string, opts ...Option) (Client, erro
 var _ NewFunc = NewClient
 
 // NewClient creates a REST client for the given API base URL and authentication tokens.
-// Returns an error if a token or the URL is empty.
+// Returns an error if the URL is empty or neither a token nor platform authentication is provided.
 //
 // The API base URL is different for managed and SaaS environments:
 //   - SaaS: https://{environment-id}.live.dynatrace.com/api
@@ -161,10 +166,6 @@ func NewClient(url, apiToken, paasToken string, opts ...Option) (Client, error)
 		return nil, errors.New("url is empty")
 	}
 
-	if len(apiToken) == 0 && len(paasToken) == 0 {
-		return nil, errors.New("tokens are empty")
-	}
-
 	url = strings.TrimSuffix(url, "/")
 
 	dc := &dynatraceClient{
@@ -183,6 +184,12 @@ func NewClient(url, apiToken, paasToken string, opts ...Option) (Client, error)
 		opt(dc)
 	}
 
+	dc.setupOAuthTokenSource()
+
+	if len(apiToken) == 0 && len(paasToken) == 0 && !dc.usesPlatformAuth() {
+		return nil, errors.New("tokens are empty")
+	}
+
 	return dc, nil
 }
 
@@ -227,6 +234,18 @@ func Proxy(proxyURL string, noProxy string) Option {
 	}
 }
 
+// PACProxy selects the proxy of every request with the proxy auto-config script, the credentials are added to the selected proxy.
+func PACProxy(script *pac.Script, credentials *url.Userinfo) Option {
+	return func(dtclient *dynatraceClient) {
+		if script == nil {
+			return
+		}
+
+		transport := dtclient.httpClient.Transport.(*http.Transport)
+		transport.Proxy = script.ProxyFunc(credentials)
+	}
+}
+
 func proxyWrapper(proxyConfig httpproxy.Config) func(req *http.Request) (*url.URL, error) {
 	return func(req *http.Request) (*url.URL, error) {
 		return proxyConfig.ProxyFunc()(req.URL)
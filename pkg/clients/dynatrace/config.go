@@ -2,8 +2,29 @@ package dynatrace
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
 	log = logd.Get().WithName("dtclient")
+
+	apiRequestsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "api_client",
+		Name:      "requests_total",
+		Help:      "Number of requests sent to the Dynatrace API",
+	}, []string{"method", "endpoint", "code"})
+
+	apiRequestDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dynatrace",
+		Subsystem: "api_client",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests sent to the Dynatrace API in seconds",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint"})
 )
+
+func init() {
+	metrics.Registry.MustRegister(apiRequestsMetric, apiRequestDurationMetric)
+}
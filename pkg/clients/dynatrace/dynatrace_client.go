@@ -9,16 +9,21 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/clients/utils"
 	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 const APITokenHeader = "Api-Token "
 
+var versionPathRegex = regexp.MustCompile(`/version/[^/]+`)
+
 type HostNotFoundErr struct {
 	IP string
 }
@@ -42,6 +47,13 @@ type dynatraceClient struct {
 
 	hostCache map[string]hostInfo
 
+	// Set if platform authentication is used, takes precedence over the API and PaaS tokens.
+	tokenSource oauth2.TokenSource
+	oAuthConfig *clientcredentials.Config
+
+	// Set if a platform token is used, its scopes are looked up there.
+	tokenIntrospectionURL string
+
 	url       string
 	apiToken  string
 	paasToken string
@@ -69,40 +81,71 @@ func (dtc *dynatraceClient) makeRequest(ctx context.Context, url string, tokenTy
 		return nil, errors.WithMessage(err, "error initializing http request")
 	}
 
-	var authHeader string
+	var token string
 
 	switch tokenType {
 	case dynatraceAPIToken:
-		if dtc.apiToken == "" {
+		if dtc.apiToken == "" && !dtc.usesPlatformAuth() {
 			return nil, errors.Errorf("not able to set token since api token is empty for request: %s", url)
 		}
 
-		authHeader = APITokenHeader + dtc.apiToken
+		token = dtc.apiToken
 	case dynatracePaaSToken:
-		if dtc.paasToken == "" {
+		if dtc.paasToken == "" && !dtc.usesPlatformAuth() {
 			return nil, errors.Errorf("not able to set token since paas token is empty for request: %s", url)
 		}
 
-		authHeader = APITokenHeader + dtc.paasToken
+		token = dtc.paasToken
 	case installerURLToken:
-		return dtc.httpClient.Do(req)
+		return dtc.doRequest(req)
 	default:
 		return nil, errors.Errorf("unknown token type (%d), unable to determine token to set in headers", tokenType)
 	}
 
-	req.Header.Add("Authorization", authHeader)
+	err = dtc.setAuthorizationHeader(req, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtc.doRequest(req)
+}
+
+// doRequest sends the request and records the API call metrics for it.
+func (dtc *dynatraceClient) doRequest(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	endpoint := endpointMetricLabel(req.URL.Path)
+
+	resp, err := dtc.httpClient.Do(req)
+
+	apiRequestDurationMetric.WithLabelValues(req.Method, endpoint).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	apiRequestsMetric.WithLabelValues(req.Method, endpoint, code).Inc()
 
-	return dtc.httpClient.Do(req)
+	return resp, err
 }
 
-func createBaseRequest(ctx context.Context, url, method, apiToken string, body io.Reader) (*http.Request, error) {
+// endpointMetricLabel replaces the version part of installer paths, to keep the cardinality of the metrics low.
+func endpointMetricLabel(path string) string {
+	return versionPathRegex.ReplaceAllString(path, "/version/{version}")
+}
+
+func (dtc *dynatraceClient) createBaseRequest(ctx context.Context, url, method, apiToken string, body io.Reader) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error initializing http request")
 	}
 
 	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Authorization", APITokenHeader+apiToken)
+
+	err = dtc.setAuthorizationHeader(req, apiToken)
+	if err != nil {
+		return nil, err
+	}
 
 	if method == http.MethodPost {
 		req.Header.Add("Content-Type", "application/json")
@@ -12,6 +12,7 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
 	"github.com/Dynatrace/dynatrace-operator/pkg/clients/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -66,6 +67,41 @@ func TestMakeRequest(t *testing.T) {
 	})
 }
 
+func TestRequestMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	dynatraceServer := httptest.NewServer(dynatraceServerHandler(t))
+	defer dynatraceServer.Close()
+
+	dc := &dynatraceClient{
+		url:       dynatraceServer.URL,
+		apiToken:  apiToken,
+		paasToken: paasToken,
+
+		hostCache:  make(map[string]hostInfo),
+		httpClient: http.DefaultClient,
+	}
+
+	t.Run("request is counted per endpoint and status code", func(t *testing.T) {
+		endpoint := "/v1/deployment/installer/agent/connectioninfo"
+		counter := apiRequestsMetric.WithLabelValues(http.MethodGet, endpoint, "200")
+		before := testutil.ToFloat64(counter)
+
+		resp, err := dc.makeRequest(ctx, dc.url+endpoint, dynatraceAPIToken)
+		require.NoError(t, err)
+
+		defer utils.CloseBodyAfterRequest(resp)
+
+		assert.InDelta(t, before+1, testutil.ToFloat64(counter), 0)
+	})
+
+	t.Run("versions are removed from the endpoint label", func(t *testing.T) {
+		label := endpointMetricLabel("/api/v1/deployment/installer/agent/unix/paas/version/1.2.3.4-5")
+
+		assert.Equal(t, "/api/v1/deployment/installer/agent/unix/paas/version/{version}", label)
+	})
+}
+
 func TestGetResponseOrServerError(t *testing.T) {
 	ctx := context.Background()
 
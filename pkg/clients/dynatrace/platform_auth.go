@@ -0,0 +1,214 @@
+package dynatrace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/clients/utils"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	PlatformToken     = "platformToken"
+	OAuthClientID     = "oAuthClientId"
+	OAuthClientSecret = "oAuthClientSecret"
+	// OAuthTokenURL is optional, DefaultOAuthTokenURL is used if it isn't set, e.g. managed or sprint environments need a different one.
+	// The scopes of a platform token are looked up at the introspection endpoint next to it.
+	OAuthTokenURL = "oAuthTokenUrl"
+
+	BearerTokenHeader    = "Bearer "
+	DefaultOAuthTokenURL = "https://sso.dynatrace.com/sso/oauth2/token"
+
+	oAuthScopeExtraKey = "scope"
+
+	tokenIntrospectionPath = "/introspect"
+)
+
+// Platform scopes that are equivalent to the API token scopes
+const (
+	PlatformScopeInstallerDownload     = "environment-api:deployment:download"
+	PlatformScopeDataExport            = "environment-api:entities:read"
+	PlatformScopeMetricsIngest         = "storage:metrics:write"
+	PlatformScopeLogsIngest            = "storage:logs:write"
+	PlatformScopeSettingsRead          = "settings:objects:read"
+	PlatformScopeSettingsWrite         = "settings:objects:write"
+	PlatformScopeActiveGateTokenCreate = "environment-api:activegate-tokens:create"
+)
+
+var platformScopesForAPITokenScopes = map[string]string{
+	TokenScopeInstallerDownload:     PlatformScopeInstallerDownload,
+	TokenScopeDataExport:            PlatformScopeDataExport,
+	TokenScopeMetricsIngest:         PlatformScopeMetricsIngest,
+	TokenScopeLogsIngest:            PlatformScopeLogsIngest,
+	TokenScopeSettingsRead:          PlatformScopeSettingsRead,
+	TokenScopeSettingsWrite:         PlatformScopeSettingsWrite,
+	TokenScopeActiveGateTokenCreate: PlatformScopeActiveGateTokenCreate,
+}
+
+// PlatformTokenAuth creates an Option that authenticates all requests with the given platform token instead of the API and PaaS tokens.
+// The scopes of the platform token are looked up via the token introspection endpoint (RFC 7662) of the given token URL.
+func PlatformTokenAuth(platformToken, tokenURL string) Option {
+	return func(c *dynatraceClient) {
+		if platformToken == "" {
+			return
+		}
+
+		if tokenURL == "" {
+			tokenURL = DefaultOAuthTokenURL
+		}
+
+		c.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: platformToken})
+		c.tokenIntrospectionURL = strings.TrimSuffix(tokenURL, "/") + tokenIntrospectionPath
+	}
+}
+
+// OAuthClientAuth creates an Option that authenticates all requests with a bearer token requested via the OAuth client credentials flow.
+// The bearer token is refreshed automatically before it expires.
+func OAuthClientAuth(clientID, clientSecret, tokenURL string) Option {
+	return func(c *dynatraceClient) {
+		if clientID == "" || clientSecret == "" {
+			return
+		}
+
+		if tokenURL == "" {
+			tokenURL = DefaultOAuthTokenURL
+		}
+
+		c.oAuthConfig = &clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     tokenURL,
+			Scopes:       platformScopes(),
+		}
+	}
+}
+
+// setupOAuthTokenSource has to run after all other options were applied, so the token requests use the same proxy and certificates as the API requests.
+func (dtc *dynatraceClient) setupOAuthTokenSource() {
+	if dtc.oAuthConfig == nil {
+		return
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, dtc.httpClient)
+	dtc.tokenSource = dtc.oAuthConfig.TokenSource(ctx)
+}
+
+func (dtc *dynatraceClient) usesPlatformAuth() bool {
+	return dtc.tokenSource != nil
+}
+
+// setAuthorizationHeader sets the bearer token if platform authentication is configured, otherwise the given API or PaaS token is used.
+func (dtc *dynatraceClient) setAuthorizationHeader(req *http.Request, apiToken string) error {
+	if !dtc.usesPlatformAuth() {
+		req.Header.Set("Authorization", APITokenHeader+apiToken)
+
+		return nil
+	}
+
+	token, err := dtc.tokenSource.Token()
+	if err != nil {
+		return errors.WithMessage(err, "failed to get bearer token for platform authentication")
+	}
+
+	req.Header.Set("Authorization", BearerTokenHeader+token.AccessToken)
+
+	return nil
+}
+
+// GetPlatformTokenScopes returns the API token scopes equivalent to the scopes granted to the platform token or OAuth client.
+// If the token response of the OAuth client contains no scopes, the requested ones were granted (RFC 6749 section 5.1).
+func (dtc *dynatraceClient) GetPlatformTokenScopes(ctx context.Context) (TokenScopes, error) {
+	if !dtc.usesPlatformAuth() {
+		return nil, errors.New("platform authentication is not configured")
+	}
+
+	token, err := dtc.tokenSource.Token()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get bearer token for platform authentication")
+	}
+
+	if dtc.oAuthConfig == nil {
+		return dtc.introspectPlatformToken(ctx, token.AccessToken)
+	}
+
+	grantedScopes, ok := token.Extra(oAuthScopeExtraKey).(string)
+	if !ok {
+		grantedScopes = strings.Join(dtc.oAuthConfig.Scopes, " ")
+	}
+
+	return apiTokenScopesFromPlatformScopes(strings.Fields(grantedScopes)), nil
+}
+
+// introspectPlatformToken looks up the scopes of a platform token, as they are not part of the token itself.
+func (dtc *dynatraceClient) introspectPlatformToken(ctx context.Context, platformToken string) (TokenScopes, error) {
+	form := url.Values{"token": {platformToken}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dtc.tokenIntrospectionURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.WithMessage(err, "error initializing http request")
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Authorization", BearerTokenHeader+platformToken)
+
+	resp, err := dtc.doRequest(req)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error making introspection request for the platform token")
+	}
+
+	defer utils.CloseBodyAfterRequest(resp)
+
+	data, err := dtc.getServerResponseData(resp)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var introspection struct {
+		Scope  string `json:"scope"`
+		Active bool   `json:"active"`
+	}
+
+	if err := json.Unmarshal(data, &introspection); err != nil {
+		return nil, errors.WithMessage(err, "failed to parse the introspection response of the platform token")
+	}
+
+	if !introspection.Active {
+		return nil, errors.New("the platform token is expired or revoked")
+	}
+
+	return apiTokenScopesFromPlatformScopes(strings.Fields(introspection.Scope)), nil
+}
+
+func apiTokenScopesFromPlatformScopes(grantedScopes []string) TokenScopes {
+	scopes := TokenScopes{}
+
+	for apiTokenScope, platformScope := range platformScopesForAPITokenScopes {
+		if slices.Contains(grantedScopes, platformScope) {
+			scopes = append(scopes, apiTokenScope)
+		}
+	}
+
+	slices.Sort(scopes)
+
+	return scopes
+}
+
+func platformScopes() []string {
+	scopes := make([]string, 0, len(platformScopesForAPITokenScopes))
+	for _, platformScope := range platformScopesForAPITokenScopes {
+		if !slices.Contains(scopes, platformScope) {
+			scopes = append(scopes, platformScope)
+		}
+	}
+
+	slices.Sort(scopes)
+
+	return scopes
+}
@@ -0,0 +1,208 @@
+package dynatrace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPlatformToken     = "platform-token"
+	testOAuthClientID     = "client-id"
+	testOAuthClientSecret = "client-secret"
+	testBearerToken       = "bearer-token"
+)
+
+func TestPlatformTokenAuth(t *testing.T) {
+	ctx := context.Background()
+
+	newServer := func(t *testing.T, introspection map[string]any, authHeader *string) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/sso/oauth2/token/introspect", func(writer http.ResponseWriter, request *http.Request) {
+			require.NoError(t, request.ParseForm())
+			assert.Equal(t, testPlatformToken, request.Form.Get("token"))
+
+			writer.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(writer).Encode(introspection)
+		})
+		mux.HandleFunc("/v1/events", func(writer http.ResponseWriter, request *http.Request) {
+			*authHeader = request.Header.Get("Authorization")
+			writer.WriteHeader(http.StatusOK)
+		})
+
+		return httptest.NewServer(mux)
+	}
+
+	t.Run("platform token is sent as bearer token", func(t *testing.T) {
+		var authHeader string
+
+		server := newServer(t, nil, &authHeader)
+		defer server.Close()
+
+		dtc, err := NewClient(server.URL, "", "", PlatformTokenAuth(testPlatformToken, ""))
+		require.NoError(t, err)
+
+		err = dtc.SendEvent(ctx, &EventData{EventType: MarkedForTerminationEvent})
+		require.NoError(t, err)
+		assert.Equal(t, BearerTokenHeader+testPlatformToken, authHeader)
+	})
+	t.Run("scopes of platform token are introspected", func(t *testing.T) {
+		var authHeader string
+
+		server := newServer(t, map[string]any{
+			"active": true,
+			"scope":  strings.Join([]string{PlatformScopeInstallerDownload, PlatformScopeSettingsWrite, "unrelated:scope"}, " "),
+		}, &authHeader)
+		defer server.Close()
+
+		dtc, err := NewClient(server.URL, "", "", PlatformTokenAuth(testPlatformToken, server.URL+"/sso/oauth2/token"))
+		require.NoError(t, err)
+
+		scopes, err := dtc.GetPlatformTokenScopes(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, TokenScopes{TokenScopeInstallerDownload, TokenScopeSettingsWrite}, scopes)
+	})
+	t.Run("inactive platform token is an error", func(t *testing.T) {
+		var authHeader string
+
+		server := newServer(t, map[string]any{"active": false}, &authHeader)
+		defer server.Close()
+
+		dtc, err := NewClient(server.URL, "", "", PlatformTokenAuth(testPlatformToken, server.URL+"/sso/oauth2/token"))
+		require.NoError(t, err)
+
+		_, err = dtc.GetPlatformTokenScopes(ctx)
+		require.EqualError(t, err, "the platform token is expired or revoked")
+	})
+	t.Run("default introspection url is used", func(t *testing.T) {
+		dtc, err := NewClient("https://example.com", "", "", PlatformTokenAuth(testPlatformToken, ""))
+		require.NoError(t, err)
+		assert.Equal(t, DefaultOAuthTokenURL+"/introspect", dtc.(*dynatraceClient).tokenIntrospectionURL)
+	})
+	t.Run("empty platform token is ignored", func(t *testing.T) {
+		_, err := NewClient("https://example.com", "", "", PlatformTokenAuth("", ""))
+		require.Error(t, err)
+	})
+}
+
+func TestOAuthClientAuth(t *testing.T) {
+	ctx := context.Background()
+
+	newServer := func(t *testing.T, grantedScopes string, authHeader *string) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/sso/oauth2/token", func(writer http.ResponseWriter, request *http.Request) {
+			require.NoError(t, request.ParseForm())
+			assert.Equal(t, "client_credentials", request.Form.Get("grant_type"))
+
+			writer.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(writer).Encode(map[string]any{
+				"access_token": testBearerToken,
+				"token_type":   "Bearer",
+				"expires_in":   300,
+				"scope":        grantedScopes,
+			})
+		})
+		mux.HandleFunc("/v1/events", func(writer http.ResponseWriter, request *http.Request) {
+			*authHeader = request.Header.Get("Authorization")
+			writer.WriteHeader(http.StatusOK)
+		})
+
+		return httptest.NewServer(mux)
+	}
+
+	t.Run("bearer token of oauth client is used", func(t *testing.T) {
+		var authHeader string
+
+		server := newServer(t, "", &authHeader)
+		defer server.Close()
+
+		dtc, err := NewClient(server.URL, "", "", OAuthClientAuth(testOAuthClientID, testOAuthClientSecret, server.URL+"/sso/oauth2/token"))
+		require.NoError(t, err)
+
+		err = dtc.SendEvent(ctx, &EventData{EventType: MarkedForTerminationEvent})
+		require.NoError(t, err)
+		assert.Equal(t, BearerTokenHeader+testBearerToken, authHeader)
+	})
+	t.Run("granted scopes are translated to api token scopes", func(t *testing.T) {
+		var authHeader string
+
+		grantedScopes := strings.Join([]string{PlatformScopeDataExport, PlatformScopeSettingsRead, "unrelated:scope"}, " ")
+		server := newServer(t, grantedScopes, &authHeader)
+		defer server.Close()
+
+		dtc, err := NewClient(server.URL, "", "", OAuthClientAuth(testOAuthClientID, testOAuthClientSecret, server.URL+"/sso/oauth2/token"))
+		require.NoError(t, err)
+
+		scopes, err := dtc.GetPlatformTokenScopes(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, TokenScopes{TokenScopeDataExport, TokenScopeSettingsRead}, scopes)
+	})
+	t.Run("failing token request is returned", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		dtc, err := NewClient(server.URL, "", "", OAuthClientAuth(testOAuthClientID, testOAuthClientSecret, server.URL))
+		require.NoError(t, err)
+
+		err = dtc.SendEvent(ctx, &EventData{EventType: MarkedForTerminationEvent})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get bearer token for platform authentication")
+	})
+	t.Run("requested scopes are granted if the response contains none", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(writer).Encode(map[string]any{
+				"access_token": testBearerToken,
+				"token_type":   "Bearer",
+				"expires_in":   300,
+			})
+		}))
+		defer server.Close()
+
+		dtc, err := NewClient(server.URL, "", "", OAuthClientAuth(testOAuthClientID, testOAuthClientSecret, server.URL))
+		require.NoError(t, err)
+
+		scopes, err := dtc.GetPlatformTokenScopes(ctx)
+		require.NoError(t, err)
+		assert.Len(t, scopes, len(platformScopesForAPITokenScopes))
+	})
+	t.Run("oauth client takes precedence over api token", func(t *testing.T) {
+		var authHeader string
+
+		server := newServer(t, "", &authHeader)
+		defer server.Close()
+
+		dtc, err := NewClient(server.URL, "api-token", "paas-token", OAuthClientAuth(testOAuthClientID, testOAuthClientSecret, server.URL+"/sso/oauth2/token"))
+		require.NoError(t, err)
+
+		request, err := dtc.(*dynatraceClient).createBaseRequest(ctx, server.URL, http.MethodGet, "api-token", nil)
+		require.NoError(t, err)
+		assert.Equal(t, BearerTokenHeader+testBearerToken, request.Header.Get("Authorization"))
+	})
+	t.Run("custom token url is used", func(t *testing.T) {
+		dtc, err := NewClient("https://example.com", "", "", OAuthClientAuth(testOAuthClientID, testOAuthClientSecret, "https://sso.example.com/token"))
+		require.NoError(t, err)
+		assert.Equal(t, "https://sso.example.com/token", dtc.(*dynatraceClient).oAuthConfig.TokenURL)
+	})
+	t.Run("default token url is used", func(t *testing.T) {
+		dtc, err := NewClient("https://example.com", "", "", OAuthClientAuth(testOAuthClientID, testOAuthClientSecret, ""))
+		require.NoError(t, err)
+		assert.Equal(t, DefaultOAuthTokenURL, dtc.(*dynatraceClient).oAuthConfig.TokenURL)
+	})
+}
+
+func TestGetPlatformTokenScopesWithoutPlatformAuth(t *testing.T) {
+	dtc, err := NewClient("https://example.com", "api-token", "paas-token")
+	require.NoError(t, err)
+
+	_, err = dtc.GetPlatformTokenScopes(context.Background())
+	require.Error(t, err)
+}
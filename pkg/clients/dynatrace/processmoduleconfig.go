@@ -162,7 +162,7 @@ func (dtc *dynatraceClient) GetProcessModuleConfig(ctx context.Context, prevRevi
 		return nil, err
 	}
 
-	resp, err := dtc.httpClient.Do(req)
+	resp, err := dtc.doRequest(req)
 
 	if dtc.checkProcessModuleConfigRequestStatus(resp) {
 		return &ProcessModuleConfig{}, nil
@@ -197,7 +197,11 @@ func (dtc *dynatraceClient) createProcessModuleConfigRequest(ctx context.Context
 
 	req.URL.RawQuery = query.Encode()
 	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", APITokenHeader+dtc.paasToken)
+
+	err = dtc.setAuthorizationHeader(req, dtc.paasToken)
+	if err != nil {
+		return nil, err
+	}
 
 	return req, nil
 }
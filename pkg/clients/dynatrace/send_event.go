@@ -48,9 +48,13 @@ func (dtc *dynatraceClient) SendEvent(ctx context.Context, eventData *EventData)
 	}
 
 	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", APITokenHeader+dtc.apiToken)
 
-	response, err := dtc.httpClient.Do(req)
+	err = dtc.setAuthorizationHeader(req, dtc.apiToken)
+	if err != nil {
+		return err
+	}
+
+	response, err := dtc.doRequest(req)
 	if err != nil {
 		return errors.WithMessage(err, "error making post request to dynatrace api")
 	}
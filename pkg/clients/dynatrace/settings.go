@@ -63,7 +63,7 @@ func (dtc *dynatraceClient) GetK8sClusterME(ctx context.Context, kubeSystemUUID
 		return K8sClusterME{}, errors.New("no kube-system namespace UUID given")
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getSettingsURL(true), http.MethodGet, dtc.apiToken, nil)
+	req, err := dtc.createBaseRequest(ctx, dtc.getSettingsURL(true), http.MethodGet, dtc.apiToken, nil)
 	if err != nil {
 		return K8sClusterME{}, err
 	}
@@ -75,7 +75,7 @@ func (dtc *dynatraceClient) GetK8sClusterME(ctx context.Context, kubeSystemUUID
 	q.Add(filterQueryParam, fmt.Sprintf("value.clusterId='%s'", kubeSystemUUID))
 	req.URL.RawQuery = q.Encode()
 
-	res, err := dtc.httpClient.Do(req)
+	res, err := dtc.doRequest(req)
 	defer utils.CloseBodyAfterRequest(res)
 
 	if err != nil {
@@ -108,7 +108,7 @@ func (dtc *dynatraceClient) GetSettingsForMonitoredEntity(ctx context.Context, m
 		return GetSettingsResponse{TotalCount: 0}, nil
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getSettingsURL(true), http.MethodGet, dtc.apiToken, nil)
+	req, err := dtc.createBaseRequest(ctx, dtc.getSettingsURL(true), http.MethodGet, dtc.apiToken, nil)
 	if err != nil {
 		return GetSettingsResponse{}, err
 	}
@@ -118,7 +118,7 @@ func (dtc *dynatraceClient) GetSettingsForMonitoredEntity(ctx context.Context, m
 	q.Add(scopesQueryParam, monitoredEntity.ID)
 	req.URL.RawQuery = q.Encode()
 
-	res, err := dtc.httpClient.Do(req)
+	res, err := dtc.doRequest(req)
 	defer utils.CloseBodyAfterRequest(res)
 
 	if err != nil {
@@ -142,7 +142,7 @@ func (dtc *dynatraceClient) GetSettingsForLogModule(ctx context.Context, monitor
 		return GetLogMonSettingsResponse{TotalCount: 0}, nil
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getSettingsURL(true), http.MethodGet, dtc.apiToken, nil)
+	req, err := dtc.createBaseRequest(ctx, dtc.getSettingsURL(true), http.MethodGet, dtc.apiToken, nil)
 	if err != nil {
 		return GetLogMonSettingsResponse{}, err
 	}
@@ -152,7 +152,7 @@ func (dtc *dynatraceClient) GetSettingsForLogModule(ctx context.Context, monitor
 	q.Add(scopesQueryParam, monitoredEntity)
 	req.URL.RawQuery = q.Encode()
 
-	res, err := dtc.httpClient.Do(req)
+	res, err := dtc.doRequest(req)
 	defer utils.CloseBodyAfterRequest(res)
 
 	if err != nil {
@@ -42,7 +42,7 @@ func (dtc *dynatraceClient) GetRulesSettings(ctx context.Context, kubeSystemUUID
 		scope = globalScope
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getEffectiveSettingsURL(true), http.MethodGet, dtc.apiToken, nil)
+	req, err := dtc.createBaseRequest(ctx, dtc.getEffectiveSettingsURL(true), http.MethodGet, dtc.apiToken, nil)
 	if err != nil {
 		return GetRulesSettingsResponse{}, err
 	}
@@ -52,7 +52,7 @@ func (dtc *dynatraceClient) GetRulesSettings(ctx context.Context, kubeSystemUUID
 	q.Add(scopeQueryParam, scope)
 	req.URL.RawQuery = q.Encode()
 
-	res, err := dtc.httpClient.Do(req)
+	res, err := dtc.doRequest(req)
 	defer utils.CloseBodyAfterRequest(res)
 
 	if err != nil {
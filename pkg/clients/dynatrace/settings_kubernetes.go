@@ -57,12 +57,12 @@ func (dtc *dynatraceClient) performCreateOrUpdateKubernetesSetting(ctx context.C
 		return "", err
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getSettingsURL(false), http.MethodPost, dtc.apiToken, bytes.NewReader(bodyData))
+	req, err := dtc.createBaseRequest(ctx, dtc.getSettingsURL(false), http.MethodPost, dtc.apiToken, bytes.NewReader(bodyData))
 	if err != nil {
 		return "", err
 	}
 
-	res, err := dtc.httpClient.Do(req)
+	res, err := dtc.doRequest(req)
 	defer utils.CloseBodyAfterRequest(res)
 
 	if err != nil {
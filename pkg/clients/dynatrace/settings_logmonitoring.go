@@ -48,12 +48,12 @@ func (dtc *dynatraceClient) performCreateLogMonSetting(ctx context.Context, body
 		return "", err
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getSettingsURL(false), http.MethodPost, dtc.apiToken, bytes.NewReader(bodyData))
+	req, err := dtc.createBaseRequest(ctx, dtc.getSettingsURL(false), http.MethodPost, dtc.apiToken, bytes.NewReader(bodyData))
 	if err != nil {
 		return "", err
 	}
 
-	res, err := dtc.httpClient.Do(req)
+	res, err := dtc.doRequest(req)
 	defer utils.CloseBodyAfterRequest(res)
 
 	if err != nil {
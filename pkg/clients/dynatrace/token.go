@@ -44,7 +44,7 @@ func (dtc *dynatraceClient) GetTokenScopes(ctx context.Context, token string) (T
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Authorization", APITokenHeader+token)
 
-	resp, err := dtc.httpClient.Do(req)
+	resp, err := dtc.doRequest(req)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error making post request to dynatrace api")
 	}
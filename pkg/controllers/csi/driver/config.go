@@ -8,6 +8,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+const nodeMetricLabel = "node"
+
 var (
 	log               = logd.Get().WithName("csi-driver")
 	memoryUsageMetric = prometheus.NewGauge(prometheus.GaugeOpts{
@@ -17,8 +19,34 @@ var (
 		Help:      "Memory usage of the csi driver in bytes",
 	})
 	memoryMetricTick = 5000 * time.Millisecond
+
+	sharedBinariesDiskUsageMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dynatrace",
+		Subsystem: "csi_driver",
+		Name:      "shared_binaries_disk_usage_bytes",
+		Help:      "Disk usage of the CodeModules shared between the app volumes of the node in bytes",
+	}, []string{nodeMetricLabel})
+	dataDirAvailableMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dynatrace",
+		Subsystem: "csi_driver",
+		Name:      "data_dir_available_bytes",
+		Help:      "Available disk space of the filesystem holding the csi data dir in bytes",
+	}, []string{nodeMetricLabel})
+	appMountsMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dynatrace",
+		Subsystem: "csi_driver",
+		Name:      "app_mounts",
+		Help:      "Number of app volumes prepared on the node",
+	}, []string{nodeMetricLabel})
+	overlayMountsMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dynatrace",
+		Subsystem: "csi_driver",
+		Name:      "overlay_mounts",
+		Help:      "Number of overlays mounted for app volumes on the node",
+	}, []string{nodeMetricLabel})
+	diskMetricTick = time.Minute
 )
 
 func init() {
-	metrics.Registry.MustRegister(memoryUsageMetric)
+	metrics.Registry.MustRegister(memoryUsageMetric, sharedBinariesDiskUsageMetric, dataDirAvailableMetric, appMountsMetric, overlayMountsMetric)
 }
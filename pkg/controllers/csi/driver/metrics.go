@@ -0,0 +1,54 @@
+package csidriver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+)
+
+// updateDiskMetrics exports the disk usage of the shared CodeModules and the number of app volumes of the node,
+// so that nodes running out of disk under the csi data dir can be noticed before volumes fail.
+func (srv *Server) updateDiskMetrics() {
+	node := srv.opts.NodeID
+
+	sharedBinariesSize, err := metadata.DirSize(srv.fs.Fs, srv.path.AgentSharedBinaryDirBase())
+	if err != nil {
+		log.Info("failed to determine disk usage of shared binaries", "err", err.Error())
+	} else {
+		sharedBinariesDiskUsageMetric.WithLabelValues(node).Set(float64(sharedBinariesSize))
+	}
+
+	stats, err := srv.diskStats(srv.opts.RootDir)
+	if err != nil {
+		log.Info("failed to determine disk stats of the csi data dir", "path", srv.opts.RootDir, "err", err.Error())
+	} else {
+		dataDirAvailableMetric.WithLabelValues(node).Set(float64(stats.available))
+	}
+
+	appMounts, err := srv.fs.ReadDir(srv.path.AppMountsBaseDir())
+	if err != nil && !os.IsNotExist(err) {
+		log.Info("failed to read app mounts dir", "err", err.Error())
+	} else {
+		appMountsMetric.WithLabelValues(node).Set(float64(len(appMounts)))
+	}
+
+	mountPoints, err := srv.mounter.List()
+	if err != nil {
+		log.Info("failed to list mount points", "err", err.Error())
+
+		return
+	}
+
+	overlayMounts := 0
+	appMountsBaseDir := srv.path.AppMountsBaseDir() + string(filepath.Separator)
+
+	for _, mountPoint := range mountPoints {
+		if mountPoint.Type == "overlay" && strings.HasPrefix(mountPoint.Path, appMountsBaseDir) {
+			overlayMounts++
+		}
+	}
+
+	overlayMountsMetric.WithLabelValues(node).Set(float64(overlayMounts))
+}
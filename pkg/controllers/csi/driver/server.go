@@ -56,6 +56,7 @@ type Server struct {
 	mounter mount.Interface
 
 	publishers map[string]csivolumes.Publisher
+	diskStats  diskStatsFunc
 	opts       dtcsi.CSIOptions
 	path       metadata.PathResolver
 }
@@ -65,10 +66,11 @@ var _ csi.NodeServer = &Server{}
 
 func NewServer(opts dtcsi.CSIOptions) *Server {
 	return &Server{
-		opts:    opts,
-		fs:      afero.Afero{Fs: afero.NewOsFs()},
-		mounter: mount.New(""),
-		path:    metadata.PathResolver{RootDir: opts.RootDir},
+		opts:      opts,
+		fs:        afero.Afero{Fs: afero.NewOsFs()},
+		mounter:   mount.New(""),
+		path:      metadata.PathResolver{RootDir: opts.RootDir},
+		diskStats: statfsDiskStats,
 	}
 }
 
@@ -111,6 +113,9 @@ func (srv *Server) Start(ctx context.Context) error {
 
 	go func() {
 		ticker := time.NewTicker(memoryMetricTick)
+		diskTicker := time.NewTicker(diskMetricTick)
+
+		srv.updateDiskMetrics()
 
 		done := false
 		for !done {
@@ -126,6 +131,8 @@ func (srv *Server) Start(ctx context.Context) error {
 
 				runtime.ReadMemStats(&m)
 				memoryUsageMetric.Set(float64(m.Alloc))
+			case <-diskTicker.C:
+				srv.updateDiskMetrics()
 			}
 		}
 	}()
@@ -277,11 +284,18 @@ func (srv *Server) NodeGetInfo(context.Context, *csi.NodeGetInfoRequest) (*csi.N
 }
 
 func (srv *Server) NodeGetCapabilities(context.Context, *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
-	return &csi.NodeGetCapabilitiesResponse{Capabilities: []*csi.NodeServiceCapability{}}, nil
+	return &csi.NodeGetCapabilitiesResponse{Capabilities: []*csi.NodeServiceCapability{
+		nodeServiceCapability(csi.NodeServiceCapability_RPC_GET_VOLUME_STATS),
+		nodeServiceCapability(csi.NodeServiceCapability_RPC_VOLUME_CONDITION),
+	}}, nil
 }
 
-func (srv *Server) NodeGetVolumeStats(context.Context, *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "")
+func nodeServiceCapability(capability csi.NodeServiceCapability_RPC_Type) *csi.NodeServiceCapability {
+	return &csi.NodeServiceCapability{
+		Type: &csi.NodeServiceCapability_Rpc{
+			Rpc: &csi.NodeServiceCapability_RPC{Type: capability},
+		},
+	}
 }
 
 func (srv *Server) NodeExpandVolume(context.Context, *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
@@ -0,0 +1,181 @@
+package csidriver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	mount "k8s.io/mount-utils"
+)
+
+const lowerDirOption = "lowerdir="
+
+type diskStats struct {
+	total     int64
+	available int64
+}
+
+type diskStatsFunc func(path string) (diskStats, error)
+
+func (srv *Server) NodeGetVolumeStats(_ context.Context, req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
+	volumeID := req.GetVolumeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	volumePath := req.GetVolumePath()
+	if volumePath == "" {
+		return nil, status.Error(codes.InvalidArgument, "Volume path missing in request")
+	}
+
+	if _, err := srv.fs.Stat(volumePath); os.IsNotExist(err) {
+		return nil, status.Error(codes.NotFound, "Volume path not found: "+volumePath)
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	mountPoints, err := srv.mounter.List()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list mount points: "+err.Error())
+	}
+
+	usedDir := volumePath
+	condition := srv.hostVolumeCondition(volumePath, mountPoints)
+
+	if srv.isAppVolume(volumeID) {
+		usedDir = srv.path.AppMountVarDir(volumeID)
+		condition = srv.appVolumeCondition(volumeID, volumePath, mountPoints)
+	}
+
+	if condition.GetAbnormal() {
+		log.Info("abnormal volume detected", "volumeID", volumeID, "volumePath", volumePath, "reason", condition.GetMessage())
+	}
+
+	used, err := metadata.DirSize(srv.fs.Fs, usedDir)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to determine volume usage: "+err.Error())
+	}
+
+	usage := &csi.VolumeUsage{
+		Unit: csi.VolumeUsage_BYTES,
+		Used: used,
+	}
+
+	stats, err := srv.diskStats(srv.opts.RootDir)
+	if err != nil {
+		log.Info("failed to determine disk stats of the csi data dir", "path", srv.opts.RootDir, "err", err.Error())
+	} else {
+		usage.Total = stats.total
+		usage.Available = stats.available
+	}
+
+	return &csi.NodeGetVolumeStatsResponse{
+		Usage:           []*csi.VolumeUsage{usage},
+		VolumeCondition: condition,
+	}, nil
+}
+
+func (srv *Server) isAppVolume(volumeID string) bool {
+	stat, err := srv.fs.Stat(srv.path.AppMountForID(volumeID))
+
+	return err == nil && stat.IsDir()
+}
+
+// appVolumeCondition checks that the overlay and the bind mount of an app volume are still in place,
+// that the CodeModule used as the lowerdir still exists and that the pod-info symlink can be resolved.
+func (srv *Server) appVolumeCondition(volumeID, volumePath string, mountPoints []mount.MountPoint) *csi.VolumeCondition {
+	overlay := findMountPoint(mountPoints, srv.path.AppMountMappedDir(volumeID))
+	if overlay == nil {
+		return abnormalCondition("overlay of the volume is not mounted")
+	}
+
+	if findMountPoint(mountPoints, volumePath) == nil {
+		return abnormalCondition("volume path is not mounted")
+	}
+
+	lowerDir := getLowerDir(*overlay)
+	if lowerDir != "" {
+		if _, err := srv.fs.Stat(lowerDir); err != nil {
+			return abnormalCondition("lowerdir of the overlay is missing: " + lowerDir)
+		}
+	}
+
+	podInfoSymlink, err := srv.fs.ReadFile(srv.path.OverlayVarPodInfo(volumeID))
+	if err == nil && len(podInfoSymlink) > 0 {
+		if isBrokenSymlink(srv.fs.Fs, string(podInfoSymlink)) {
+			return abnormalCondition("pod-info symlink is broken: " + string(podInfoSymlink))
+		}
+	}
+
+	return &csi.VolumeCondition{Message: "volume is healthy"}
+}
+
+func (srv *Server) hostVolumeCondition(volumePath string, mountPoints []mount.MountPoint) *csi.VolumeCondition {
+	if findMountPoint(mountPoints, volumePath) == nil {
+		return abnormalCondition("volume path is not mounted")
+	}
+
+	return &csi.VolumeCondition{Message: "volume is healthy"}
+}
+
+func abnormalCondition(message string) *csi.VolumeCondition {
+	return &csi.VolumeCondition{Abnormal: true, Message: message}
+}
+
+func findMountPoint(mountPoints []mount.MountPoint, path string) *mount.MountPoint {
+	for i := range mountPoints {
+		if filepath.Clean(mountPoints[i].Path) == filepath.Clean(path) {
+			return &mountPoints[i]
+		}
+	}
+
+	return nil
+}
+
+func getLowerDir(mountPoint mount.MountPoint) string {
+	for _, opt := range mountPoint.Opts {
+		if lowerDir, ok := strings.CutPrefix(opt, lowerDirOption); ok {
+			return lowerDir
+		}
+	}
+
+	return ""
+}
+
+func isBrokenSymlink(fs afero.Fs, path string) bool {
+	lstater, ok := fs.(afero.Lstater)
+	if !ok {
+		return false
+	}
+
+	info, _, err := lstater.LstatIfPossible(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+
+	_, err = fs.Stat(path)
+
+	return err != nil
+}
+
+func statfsDiskStats(path string) (diskStats, error) {
+	var stat unix.Statfs_t
+
+	err := unix.Statfs(path, &stat)
+	if err != nil {
+		return diskStats{}, errors.WithStack(err)
+	}
+
+	return diskStats{
+		total:     int64(stat.Blocks) * int64(stat.Bsize), //nolint:gosec
+		available: int64(stat.Bavail) * int64(stat.Bsize), //nolint:gosec
+	}, nil
+}
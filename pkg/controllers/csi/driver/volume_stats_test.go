@@ -0,0 +1,165 @@
+package csidriver
+
+import (
+	"context"
+	"testing"
+
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	mount "k8s.io/mount-utils"
+)
+
+const (
+	testRootDir    = "/csi"
+	testVolumeID   = "test-volume-id"
+	testVolumePath = "/kubelet/pods/test/volumes/mount"
+	testNodeID     = "test-node"
+	testLowerDir   = "/csi/codemodules/1.2.3"
+)
+
+func TestNodeGetCapabilities(t *testing.T) {
+	srv := &Server{}
+
+	resp, err := srv.NodeGetCapabilities(context.Background(), &csi.NodeGetCapabilitiesRequest{})
+	require.NoError(t, err)
+
+	capabilities := []csi.NodeServiceCapability_RPC_Type{}
+	for _, capability := range resp.GetCapabilities() {
+		capabilities = append(capabilities, capability.GetRpc().GetType())
+	}
+
+	assert.ElementsMatch(t, []csi.NodeServiceCapability_RPC_Type{
+		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+	}, capabilities)
+}
+
+func TestNodeGetVolumeStats(t *testing.T) {
+	ctx := context.Background()
+	request := &csi.NodeGetVolumeStatsRequest{VolumeId: testVolumeID, VolumePath: testVolumePath}
+
+	t.Run("missing volume id => invalid argument", func(t *testing.T) {
+		srv := createTestServer(t, nil)
+
+		_, err := srv.NodeGetVolumeStats(ctx, &csi.NodeGetVolumeStatsRequest{VolumePath: testVolumePath})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("missing volume path => not found", func(t *testing.T) {
+		srv := createTestServer(t, nil)
+
+		_, err := srv.NodeGetVolumeStats(ctx, request)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("healthy app volume => usage of upper dir", func(t *testing.T) {
+		srv := createTestServer(t, createAppVolumeMountPoints())
+		createAppVolume(t, srv)
+
+		resp, err := srv.NodeGetVolumeStats(ctx, request)
+		require.NoError(t, err)
+
+		assert.False(t, resp.GetVolumeCondition().GetAbnormal())
+		require.Len(t, resp.GetUsage(), 1)
+		assert.Equal(t, csi.VolumeUsage_BYTES, resp.GetUsage()[0].GetUnit())
+		assert.EqualValues(t, len("agent log")+len(srv.path.AppMountPodInfoDir("dk", "ns", "pod")), resp.GetUsage()[0].GetUsed())
+		assert.EqualValues(t, 1000, resp.GetUsage()[0].GetTotal())
+		assert.EqualValues(t, 400, resp.GetUsage()[0].GetAvailable())
+	})
+
+	t.Run("overlay not mounted => abnormal", func(t *testing.T) {
+		srv := createTestServer(t, createAppVolumeMountPoints()[1:])
+		createAppVolume(t, srv)
+
+		resp, err := srv.NodeGetVolumeStats(ctx, request)
+		require.NoError(t, err)
+
+		assert.True(t, resp.GetVolumeCondition().GetAbnormal())
+		assert.Contains(t, resp.GetVolumeCondition().GetMessage(), "overlay")
+	})
+
+	t.Run("lowerdir missing => abnormal", func(t *testing.T) {
+		srv := createTestServer(t, createAppVolumeMountPoints())
+		createAppVolume(t, srv)
+		require.NoError(t, srv.fs.RemoveAll(testLowerDir))
+
+		resp, err := srv.NodeGetVolumeStats(ctx, request)
+		require.NoError(t, err)
+
+		assert.True(t, resp.GetVolumeCondition().GetAbnormal())
+		assert.Contains(t, resp.GetVolumeCondition().GetMessage(), testLowerDir)
+	})
+
+	t.Run("host volume not mounted => abnormal", func(t *testing.T) {
+		srv := createTestServer(t, nil)
+		require.NoError(t, srv.fs.WriteFile(testVolumePath+"/osagent.log", []byte("host"), 0644))
+
+		resp, err := srv.NodeGetVolumeStats(ctx, request)
+		require.NoError(t, err)
+
+		assert.True(t, resp.GetVolumeCondition().GetAbnormal())
+		assert.EqualValues(t, len("host"), resp.GetUsage()[0].GetUsed())
+	})
+}
+
+func TestUpdateDiskMetrics(t *testing.T) {
+	srv := createTestServer(t, createAppVolumeMountPoints())
+	createAppVolume(t, srv)
+	require.NoError(t, srv.fs.WriteFile(srv.path.AgentSharedBinaryDirForAgent("1.2.3")+"/agent.so", []byte("12345"), 0644))
+
+	srv.updateDiskMetrics()
+
+	assert.InDelta(t, 5, testutil.ToFloat64(sharedBinariesDiskUsageMetric.WithLabelValues(testNodeID)), 0)
+	assert.InDelta(t, 400, testutil.ToFloat64(dataDirAvailableMetric.WithLabelValues(testNodeID)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(appMountsMetric.WithLabelValues(testNodeID)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(overlayMountsMetric.WithLabelValues(testNodeID)), 0)
+}
+
+func createTestServer(t *testing.T, mountPoints []mount.MountPoint) *Server {
+	t.Helper()
+
+	return &Server{
+		fs:      afero.Afero{Fs: afero.NewMemMapFs()},
+		mounter: mount.NewFakeMounter(mountPoints),
+		path:    metadata.PathResolver{RootDir: testRootDir},
+		opts:    dtcsi.CSIOptions{RootDir: testRootDir, NodeID: testNodeID},
+		diskStats: func(string) (diskStats, error) {
+			return diskStats{total: 1000, available: 400}, nil
+		},
+	}
+}
+
+func createAppVolume(t *testing.T, srv *Server) {
+	t.Helper()
+
+	require.NoError(t, srv.fs.MkdirAll(testVolumePath, 0755))
+	require.NoError(t, srv.fs.MkdirAll(testLowerDir, 0755))
+	require.NoError(t, srv.fs.MkdirAll(srv.path.AppMountMappedDir(testVolumeID), 0755))
+	require.NoError(t, srv.fs.WriteFile(srv.path.AppMountVarDir(testVolumeID)+"/agent.log", []byte("agent log"), 0644))
+	require.NoError(t, srv.fs.WriteFile(srv.path.OverlayVarPodInfo(testVolumeID), []byte(srv.path.AppMountPodInfoDir("dk", "ns", "pod")), 0644))
+}
+
+func createAppVolumeMountPoints() []mount.MountPoint {
+	path := metadata.PathResolver{RootDir: testRootDir}
+
+	return []mount.MountPoint{
+		{
+			Device: "overlay",
+			Path:   path.AppMountMappedDir(testVolumeID),
+			Type:   "overlay",
+			Opts:   []string{"lowerdir=" + testLowerDir, "upperdir=" + path.AppMountVarDir(testVolumeID)},
+		},
+		{
+			Device: path.AppMountMappedDir(testVolumeID),
+			Path:   testVolumePath,
+			Opts:   []string{"bind"},
+		},
+	}
+}
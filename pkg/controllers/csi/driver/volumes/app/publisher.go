@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	csivolumes "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/driver/volumes"
@@ -57,6 +58,12 @@ func (pub *Publisher) PublishVolume(ctx context.Context, volumeCfg *csivolumes.V
 		return &csi.NodePublishVolumeResponse{}, nil
 	}
 
+	if volumeCfg.CodeModuleVersion != "" {
+		if err := pub.requestPinnedCodeModule(volumeCfg); err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to request pinned CodeModule: %s", err))
+		}
+	}
+
 	if !pub.isCodeModuleAvailable(volumeCfg) {
 		return nil, status.Error(
 			codes.Unavailable,
@@ -99,13 +106,42 @@ func (pub *Publisher) hasRetryLimitReached(volumeCfg *csivolumes.VolumeConfig) b
 	return pub.time.Now().After(limit)
 }
 
-// isCodeModuleAvailable checks if the LatestAgentBinaryForDynaKube folder exists or not
+// requestPinnedCodeModule creates or refreshes the request file of the pinned CodeModule.
+// The provisioner installs every requested CodeModule, the cleanup keeps it as long as the request file is refreshed.
+func (pub *Publisher) requestPinnedCodeModule(volumeCfg *csivolumes.VolumeConfig) error {
+	requestFile := pub.path.CodeModuleRequestForDynaKube(volumeCfg.DynakubeName, volumeCfg.CodeModuleVersion)
+
+	err := pub.fs.MkdirAll(filepath.Dir(requestFile), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	err = pub.fs.WriteFile(requestFile, nil, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	now := pub.time.Now().Time
+
+	return pub.fs.Chtimes(requestFile, now, now)
+}
+
+// codeModuleDir returns the symlink to the CodeModule used by the volume, either the pinned or the latest one
+func (pub *Publisher) codeModuleDir(volumeCfg *csivolumes.VolumeConfig) string {
+	if volumeCfg.CodeModuleVersion != "" {
+		return pub.path.PinnedAgentBinaryForDynaKube(volumeCfg.DynakubeName, volumeCfg.CodeModuleVersion)
+	}
+
+	return pub.path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName)
+}
+
+// isCodeModuleAvailable checks if the folder of the CodeModule exists or not
 func (pub *Publisher) isCodeModuleAvailable(volumeCfg *csivolumes.VolumeConfig) bool {
-	binDir := pub.path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName)
+	binDir := pub.codeModuleDir(volumeCfg)
 
 	stat, err := pub.fs.Stat(binDir)
 	if errors.Is(err, os.ErrNotExist) {
-		log.Info("no CodeModule is available to mount yet, will retry later", "dynakube", volumeCfg.DynakubeName)
+		log.Info("no CodeModule is available to mount yet, will retry later", "dynakube", volumeCfg.DynakubeName, "pinned", volumeCfg.CodeModuleVersion)
 
 		return false
 	} else if err != nil {
@@ -137,13 +173,13 @@ func (pub *Publisher) mountCodeModule(volumeCfg *csivolumes.VolumeConfig) error
 		return err
 	}
 
-	lowerDir := pub.path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName)
+	lowerDir := pub.codeModuleDir(volumeCfg)
 
 	linker, ok := pub.fs.Fs.(afero.LinkReader)
 	if ok { // will only be !ok during unit testing
 		lowerDir, err = linker.ReadlinkIfPossible(lowerDir)
 		if err != nil {
-			log.Info("failed to read symlink for latest CodeModule", "symlink", lowerDir)
+			log.Info("failed to read symlink for CodeModule", "symlink", lowerDir)
 
 			return err
 		}
@@ -169,6 +205,12 @@ func (pub *Publisher) mountCodeModule(volumeCfg *csivolumes.VolumeConfig) error
 		return err
 	}
 
+	// the storage budget of the provisioner evicts the least recently mounted CodeModules first
+	now := pub.time.Now().Time
+	if err := pub.fs.Chtimes(lowerDir, now, now); err != nil {
+		log.Info("failed to refresh the last usage of the CodeModule", "path", lowerDir, "err", err.Error())
+	}
+
 	err = pub.addPodInfoSymlink(volumeCfg)
 	if err != nil {
 		return err
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -130,8 +131,63 @@ func TestPublishVolume(t *testing.T) {
 		assert.Equal(t, "overlay", bindMount.Device) // this is set to "overlay" by the FakeMounter to mimic a linux FS
 		assert.Equal(t, volumeCfg.TargetPath, bindMount.Path)
 	})
+
+	t.Run("early return (with error) - pinned binary not present, request is recorded", func(t *testing.T) {
+		fs := getTestFs(t)
+		mounter := mount.NewFakeMounter([]mount.MountPoint{})
+		volumeCfg := getTestVolumeConfig(t)
+		volumeCfg.CodeModuleVersion = testPinnedVersion
+
+		// only the latest binary is present
+		require.NoError(t, fs.MkdirAll(path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName), os.ModePerm))
+
+		pub := NewPublisher(fs, mounter, path)
+
+		resp, err := pub.PublishVolume(ctx, &volumeCfg)
+		require.Error(t, err)
+		require.Nil(t, resp)
+
+		assert.Empty(t, mounter.MountPoints)
+
+		requested, _ := fs.Exists(path.CodeModuleRequestForDynaKube(volumeCfg.DynakubeName, testPinnedVersion))
+		assert.True(t, requested)
+	})
+
+	t.Run("happy path - pinned binary", func(t *testing.T) {
+		fs := getTestFs(t)
+		mounter := mount.NewFakeMounter([]mount.MountPoint{})
+		volumeCfg := getTestVolumeConfig(t)
+		volumeCfg.CodeModuleVersion = testPinnedVersion
+
+		binaryDir := path.PinnedAgentBinaryForDynaKube(volumeCfg.DynakubeName, testPinnedVersion)
+		require.NoError(t, fs.MkdirAll(binaryDir, os.ModePerm))
+		require.NoError(t, fs.MkdirAll(path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName), os.ModePerm))
+
+		requestFile := path.CodeModuleRequestForDynaKube(volumeCfg.DynakubeName, testPinnedVersion)
+		require.NoError(t, fs.MkdirAll(filepath.Dir(requestFile), os.ModePerm))
+		require.NoError(t, fs.WriteFile(requestFile, nil, os.ModePerm))
+
+		oldTime := time.Now().Add(-time.Hour)
+		require.NoError(t, fs.Chtimes(requestFile, oldTime, oldTime))
+
+		pub := NewPublisher(fs, mounter, path)
+
+		resp, err := pub.PublishVolume(ctx, &volumeCfg)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+
+		require.Len(t, mounter.MountPoints, 2)
+		assert.Equal(t, "lowerdir="+binaryDir, mounter.MountPoints[0].Opts[0])
+
+		// request is refreshed
+		stat, err := fs.Stat(requestFile)
+		require.NoError(t, err)
+		assert.True(t, stat.ModTime().After(oldTime))
+	})
 }
 
+const testPinnedVersion = "1.300.0.20240101-000000"
+
 func getTestFs(t *testing.T) afero.Afero {
 	t.Helper()
 
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/pin"
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -17,6 +18,9 @@ const (
 	CSIVolumeAttributeModeField     = "mode"
 	CSIVolumeAttributeDynakubeField = "dynakube"
 	CSIVolumeAttributeRetryTimeout  = "retryTimeout"
+
+	// CSIVolumeAttributeCodeModuleVersion is set if the pod requests a pinned code module version or image digest
+	CSIVolumeAttributeCodeModuleVersion = "codeModuleVersion"
 )
 
 // Represents the basic information about a volume
@@ -33,6 +37,9 @@ type VolumeConfig struct {
 	Mode         string
 	DynakubeName string
 	RetryTimeout time.Duration
+
+	// CodeModuleVersion is the pinned version or image digest, empty means the latest code module of the DynaKube is used
+	CodeModuleVersion string
 }
 
 // Transforms the NodePublishVolumeRequest into a VolumeConfig
@@ -103,6 +110,16 @@ func ParseNodePublishVolumeRequest(req *csi.NodePublishVolumeRequest) (VolumeCon
 
 	volumeConfig.RetryTimeout = retryTimeout
 
+	codeModuleVersion := volCtx[CSIVolumeAttributeCodeModuleVersion]
+	if codeModuleVersion != "" {
+		// the value is used as a directory name, so it can't be trusted blindly
+		if err := pin.Validate(codeModuleVersion); err != nil {
+			return volumeConfig, status.Error(codes.InvalidArgument, "The codeModuleVersion attribute has incorrect format")
+		}
+	}
+
+	volumeConfig.CodeModuleVersion = codeModuleVersion
+
 	return volumeConfig, nil
 }
 
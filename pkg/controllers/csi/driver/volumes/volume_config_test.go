@@ -208,5 +208,51 @@ func TestCSIDriverServer_ParsePublishVolumeRequest(t *testing.T) {
 		assert.Equal(t, request.GetVolumeContext()[CSIVolumeAttributeModeField], volumeCfg.Mode)
 		assert.Equal(t, request.GetVolumeContext()[CSIVolumeAttributeDynakubeField], volumeCfg.DynakubeName)
 		assert.NotNil(t, volumeCfg.RetryTimeout)
+		assert.Empty(t, volumeCfg.CodeModuleVersion)
+	})
+
+	t.Run("pinned code module version", func(t *testing.T) {
+		request := &csi.NodePublishVolumeRequest{
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+			},
+			VolumeId:   testVolumeID,
+			TargetPath: testTargetPath,
+			VolumeContext: map[string]string{
+				PodNameContextKey:                   testPodUID,
+				PodNamespaceContextKey:              testNs,
+				CSIVolumeAttributeModeField:         "test",
+				CSIVolumeAttributeDynakubeField:     "dk",
+				CSIVolumeAttributeCodeModuleVersion: "1.300.0.20240101-000000",
+			},
+		}
+		volumeCfg, err := ParseNodePublishVolumeRequest(request)
+
+		require.NoError(t, err)
+		assert.Equal(t, "1.300.0.20240101-000000", volumeCfg.CodeModuleVersion)
+	})
+
+	t.Run("malformed pinned code module version", func(t *testing.T) {
+		request := &csi.NodePublishVolumeRequest{
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+			},
+			VolumeId:   testVolumeID,
+			TargetPath: testTargetPath,
+			VolumeContext: map[string]string{
+				PodNameContextKey:                   testPodUID,
+				PodNamespaceContextKey:              testNs,
+				CSIVolumeAttributeModeField:         "test",
+				CSIVolumeAttributeDynakubeField:     "dk",
+				CSIVolumeAttributeCodeModuleVersion: "../../etc",
+			},
+		}
+		_, err := ParseNodePublishVolumeRequest(request)
+
+		require.EqualError(t, err, "rpc error: code = InvalidArgument desc = The codeModuleVersion attribute has incorrect format")
 	})
 }
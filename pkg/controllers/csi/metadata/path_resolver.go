@@ -48,6 +48,24 @@ func (pr PathResolver) LatestAgentBinaryForDynaKube(dynakubeName string) string
 	return filepath.Join(pr.DynaKubeDir(dynakubeName), "latest-codemodule")
 }
 
+// PinnedAgentBinariesForDynaKube is the directory containing a symlink for every pinned code module of the DynaKube
+func (pr PathResolver) PinnedAgentBinariesForDynaKube(dynakubeName string) string {
+	return filepath.Join(pr.DynaKubeDir(dynakubeName), "pinned-codemodules")
+}
+
+func (pr PathResolver) PinnedAgentBinaryForDynaKube(dynakubeName, versionOrDigest string) string {
+	return filepath.Join(pr.PinnedAgentBinariesForDynaKube(dynakubeName), versionOrDigest)
+}
+
+// CodeModuleRequestsForDynaKube is the directory where the publisher records which pinned code modules are requested by pods
+func (pr PathResolver) CodeModuleRequestsForDynaKube(dynakubeName string) string {
+	return filepath.Join(pr.DynaKubeDir(dynakubeName), "codemodule-requests")
+}
+
+func (pr PathResolver) CodeModuleRequestForDynaKube(dynakubeName, versionOrDigest string) string {
+	return filepath.Join(pr.CodeModuleRequestsForDynaKube(dynakubeName), versionOrDigest)
+}
+
 func (pr PathResolver) AgentTempUnzipRootDir() string {
 	return pr.Base("tmp_zip")
 }
@@ -0,0 +1,34 @@
+package metadata
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// DirSize sums up the size of all files in the directory, symlinks are not followed.
+func DirSize(fs afero.Fs, path string) (int64, error) {
+	var size int64
+
+	err := afero.Walk(fs, path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) { // files can be removed while walking
+				return nil
+			}
+
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return size, nil
+}
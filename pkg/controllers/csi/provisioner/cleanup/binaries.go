@@ -1,17 +1,22 @@
 package cleanup
 
 import (
+	"os"
 	"strings"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"golang.org/x/exp/maps"
 )
 
+// pinnedRequestTTL is how long a pinned binary is kept after the last pod requested it, as long as it is not mounted anymore
+const pinnedRequestTTL = 24 * time.Hour
+
 func (c *Cleaner) removeUnusedBinaries(dks []dynakube.DynaKube, fsState fsState) {
 	c.removeOldBinarySymlinks(dks, fsState)
 
-	keptBins, err := c.collectStillMountedBins()
+	inUseBins, err := c.collectStillMountedBins()
 	if err != nil {
 		return
 	}
@@ -19,10 +24,24 @@ func (c *Cleaner) removeUnusedBinaries(dks []dynakube.DynaKube, fsState fsState)
 	relevantLatestBins := c.collectRelevantLatestBins(dks)
 
 	for k, v := range relevantLatestBins {
+		inUseBins[k] = v
+	}
+
+	keptBins := maps.Clone(inUseBins)
+
+	relevantPinnedBins, err := c.collectRelevantPinnedBins(dks, fsState)
+	if err != nil {
+		return
+	}
+
+	for k, v := range relevantPinnedBins {
 		keptBins[k] = v
 	}
 
 	c.removeOldSharedBinaries(keptBins)
+
+	// pinned binaries that are not mounted anymore are only kept as long as they fit into the storage budget
+	c.enforceStorageBudget(inUseBins)
 }
 
 func (c *Cleaner) removeOldSharedBinaries(keptBins map[string]bool) {
@@ -115,3 +134,88 @@ func (c *Cleaner) collectRelevantLatestBins(dks []dynakube.DynaKube) map[string]
 
 	return latestBins
 }
+
+// collectRelevantPinnedBins keeps the pinned binaries that were requested recently.
+// Outdated requests are removed together with their symlink, if the binary is still mounted it is kept by collectStillMountedBins.
+// If the pinned binaries can't be listed an error is returned, as without them the unused binaries can't be determined.
+func (c *Cleaner) collectRelevantPinnedBins(dks []dynakube.DynaKube, fsState fsState) (map[string]bool, error) {
+	pinnedBins := map[string]bool{}
+
+	relevantDks := map[string]bool{}
+
+	for _, dk := range dks {
+		if dk.OneAgent().IsAppInjectionNeeded() {
+			relevantDks[dk.Name] = true
+		}
+	}
+
+	for _, dkName := range fsState.pinnedDks {
+		if !relevantDks[dkName] {
+			c.removeAllPinned(dkName)
+
+			continue
+		}
+
+		requested := c.collectRequestedPins(dkName)
+
+		pinnedLinks, err := c.fs.ReadDir(c.path.PinnedAgentBinariesForDynaKube(dkName))
+		if err != nil && !os.IsNotExist(err) {
+			log.Info("failed to list the pinned binaries, skipping unused binaries cleanup", "dynakube", dkName)
+
+			return nil, err
+		}
+
+		for _, pinnedLink := range pinnedLinks {
+			pinnedPath := c.path.PinnedAgentBinaryForDynaKube(dkName, pinnedLink.Name())
+
+			if requested[pinnedLink.Name()] {
+				c.addRelevantPath(pinnedPath, pinnedBins)
+
+				continue
+			}
+
+			if err := c.fs.Remove(pinnedPath); err == nil {
+				log.Info("removed outdated pinned bin symlink", "path", pinnedPath)
+			}
+		}
+	}
+
+	if len(pinnedBins) > 0 {
+		log.Info("binaries to keep because they are pinned by existing workloads", "paths", strings.Join(maps.Keys(pinnedBins), ","))
+	}
+
+	return pinnedBins, nil
+}
+
+// collectRequestedPins returns the pins that were requested within the pinnedRequestTTL and removes the outdated requests.
+func (c *Cleaner) collectRequestedPins(dkName string) map[string]bool {
+	requested := map[string]bool{}
+
+	requests, err := c.fs.ReadDir(c.path.CodeModuleRequestsForDynaKube(dkName))
+	if err != nil {
+		return requested
+	}
+
+	for _, request := range requests {
+		if c.time.Now().Sub(request.ModTime()) < pinnedRequestTTL {
+			requested[request.Name()] = true
+
+			continue
+		}
+
+		requestPath := c.path.CodeModuleRequestForDynaKube(dkName, request.Name())
+		if err := c.fs.Remove(requestPath); err == nil {
+			log.Info("removed outdated pinned codemodule request", "path", requestPath)
+		}
+	}
+
+	return requested
+}
+
+func (c *Cleaner) removeAllPinned(dkName string) {
+	for _, dir := range []string{c.path.CodeModuleRequestsForDynaKube(dkName), c.path.PinnedAgentBinariesForDynaKube(dkName)} {
+		if err := c.fs.RemoveAll(dir); err == nil {
+			log.Info("removed pinned codemodules of old dynakube", "path", dir)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package cleanup
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
@@ -159,6 +160,69 @@ func TestCollectRelevantLatestBins(t *testing.T) {
 	})
 }
 
+func TestCollectRelevantPinnedBins(t *testing.T) {
+	const pinned = "1.300.0.20240101-000000"
+
+	t.Run("recent request -> keep", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		dk := createAppMonDk(t, "appmon", "url")
+		cleaner.createPinnedDirs(t, dk.Name, pinned, time.Now())
+
+		relevantBins, err := cleaner.collectRelevantPinnedBins([]dynakube.DynaKube{dk}, fsState{pinnedDks: []string{dk.Name}})
+		require.NoError(t, err)
+
+		require.Len(t, relevantBins, 1)
+
+		exists, _ := cleaner.fs.Exists(cleaner.path.CodeModuleRequestForDynaKube(dk.Name, pinned))
+		require.True(t, exists)
+	})
+
+	t.Run("outdated request -> remove request and symlink", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		dk := createAppMonDk(t, "appmon", "url")
+		cleaner.createPinnedDirs(t, dk.Name, pinned, time.Now().Add(-2*pinnedRequestTTL))
+
+		relevantBins, err := cleaner.collectRelevantPinnedBins([]dynakube.DynaKube{dk}, fsState{pinnedDks: []string{dk.Name}})
+		require.NoError(t, err)
+
+		require.Empty(t, relevantBins)
+
+		exists, _ := cleaner.fs.Exists(cleaner.path.CodeModuleRequestForDynaKube(dk.Name, pinned))
+		require.False(t, exists)
+
+		exists, _ = cleaner.fs.Exists(cleaner.path.PinnedAgentBinaryForDynaKube(dk.Name, pinned))
+		require.False(t, exists)
+	})
+
+	t.Run("no dk -> remove everything", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		cleaner.createPinnedDirs(t, "old", pinned, time.Now())
+
+		relevantBins, err := cleaner.collectRelevantPinnedBins([]dynakube.DynaKube{}, fsState{pinnedDks: []string{"old"}})
+		require.NoError(t, err)
+
+		require.Empty(t, relevantBins)
+
+		exists, _ := cleaner.fs.Exists(cleaner.path.CodeModuleRequestsForDynaKube("old"))
+		require.False(t, exists)
+
+		exists, _ = cleaner.fs.Exists(cleaner.path.PinnedAgentBinariesForDynaKube("old"))
+		require.False(t, exists)
+	})
+
+	t.Run("pinned bins can't be listed -> error", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		dk := createAppMonDk(t, "appmon", "url")
+		require.NoError(t, cleaner.fs.MkdirAll(cleaner.path.DynaKubeDir(dk.Name), os.ModePerm))
+		require.NoError(t, cleaner.fs.WriteFile(cleaner.path.PinnedAgentBinariesForDynaKube(dk.Name), nil, os.ModePerm))
+
+		relevantBins, err := cleaner.collectRelevantPinnedBins([]dynakube.DynaKube{dk}, fsState{pinnedDks: []string{dk.Name}})
+
+		require.Error(t, err)
+		require.Nil(t, relevantBins)
+	})
+}
+
 func TestRemoveOldBinarySymlinks(t *testing.T) {
 	t.Run("no dk -> remove everything", func(t *testing.T) {
 		cleaner := createCleaner(t)
@@ -277,6 +341,17 @@ func createBaseDk(t *testing.T, name, apiURL string) dynakube.DynaKube {
 	}
 }
 
+func (c *Cleaner) createPinnedDirs(t *testing.T, dkName, pinned string, requestedAt time.Time) {
+	t.Helper()
+
+	require.NoError(t, c.fs.MkdirAll(c.path.PinnedAgentBinaryForDynaKube(dkName, pinned), os.ModePerm))
+	require.NoError(t, c.fs.MkdirAll(c.path.CodeModuleRequestsForDynaKube(dkName), os.ModePerm))
+
+	requestPath := c.path.CodeModuleRequestForDynaKube(dkName, pinned)
+	require.NoError(t, c.fs.WriteFile(requestPath, nil, os.ModePerm))
+	require.NoError(t, c.fs.Chtimes(requestPath, requestedAt, requestedAt))
+}
+
 func (c *Cleaner) createSharedBinDir(t *testing.T, version string) {
 	t.Helper()
 
@@ -0,0 +1,253 @@
+package cleanup
+
+import (
+	"context"
+	"encoding/base64"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	storageBudgetEnv = "STORAGE_BUDGET"
+
+	// evictedSuffix marks a CodeModule that is being evicted, if the eviction is interrupted the leftover is removed by the next cleanup
+	evictedSuffix = ".evicted"
+)
+
+// ErrStorageBudgetExceeded is returned if a new CodeModule doesn't fit into the storage budget, even after the unused ones were evicted.
+var ErrStorageBudgetExceeded = errors.New("storage budget for CodeModules exceeded")
+
+// binaryUsage is the disk usage of a CodeModule stored under the AgentSharedBinaryDirBase.
+// lastUsed is the modification time of the folder, which is refreshed every time the CodeModule is mounted.
+type binaryUsage struct {
+	lastUsed time.Time
+	path     string
+	version  string
+	size     int64
+}
+
+// getStorageBudget returns the max bytes the CodeModules may use on the node, 0 means no limit.
+func getStorageBudget() int64 {
+	rawBudget := os.Getenv(storageBudgetEnv)
+	if rawBudget == "" {
+		return 0
+	}
+
+	budget, err := resource.ParseQuantity(rawBudget)
+	if err != nil || budget.Sign() <= 0 {
+		log.Info("storage budget could not be parsed, CodeModules are stored without limit", "env", storageBudgetEnv, "value", rawBudget)
+
+		return 0
+	}
+
+	return budget.Value()
+}
+
+// ReserveStorage makes sure that a new CodeModule fits into the storage budget before it is installed into the targetDir.
+// The size of the new CodeModule is estimated by the largest one already stored, the least recently used CodeModules are evicted to make room for it.
+// CodeModules that are still mounted or are the latest of a DynaKube are never evicted, if they alone exceed the budget ErrStorageBudgetExceeded is returned.
+func (c *Cleaner) ReserveStorage(ctx context.Context, targetDir string) error {
+	if c.storageBudget <= 0 {
+		return nil
+	}
+
+	if exists, _ := c.fs.DirExists(targetDir); exists {
+		return nil
+	}
+
+	usages, err := c.collectBinaryUsage()
+	if err != nil {
+		return err
+	}
+
+	required := estimateBinarySize(usages)
+	if totalUsage(usages)+required <= c.storageBudget {
+		return nil
+	}
+
+	dks, err := metadata.GetRelevantDynaKubes(ctx, c.apiReader)
+	if err != nil {
+		return err
+	}
+
+	inUseBins, err := c.collectStillMountedBins()
+	if err != nil {
+		return err
+	}
+
+	maps.Copy(inUseBins, c.collectRelevantLatestBins(dks))
+
+	usages = c.evictBinaries(usages, inUseBins, required)
+
+	if used := totalUsage(usages); used+required > c.storageBudget {
+		return errors.Wrapf(ErrStorageBudgetExceeded, "CodeModule %s needs about %s, but %s of the %s budget are used by CodeModules in use",
+			filepath.Base(targetDir), formatBytes(required), formatBytes(used), formatBytes(c.storageBudget))
+	}
+
+	return nil
+}
+
+// enforceStorageBudget evicts the least recently used CodeModules that are not in use until the budget is met and reports the usage of the remaining ones.
+func (c *Cleaner) enforceStorageBudget(inUseBins map[string]bool) {
+	usages, err := c.collectBinaryUsage()
+	if err != nil {
+		log.Info("failed to determine the disk usage of the shared binaries, skipping storage budget enforcement", "err", err.Error())
+
+		return
+	}
+
+	if c.storageBudget > 0 {
+		usages = c.evictBinaries(usages, inUseBins, 0)
+
+		if used := totalUsage(usages); used > c.storageBudget {
+			log.Info("storage budget exceeded by CodeModules in use", "used", formatBytes(used), "budget", formatBytes(c.storageBudget))
+		}
+	}
+
+	reportBinaryUsage(usages)
+}
+
+// evictBinaries removes the least recently used CodeModules, which are not in use, until the required bytes fit into the budget.
+// The usage of the remaining CodeModules is returned.
+func (c *Cleaner) evictBinaries(usages []binaryUsage, inUseBins map[string]bool, required int64) []binaryUsage {
+	used := totalUsage(usages)
+	remaining := make([]binaryUsage, 0, len(usages))
+
+	for _, usage := range usages {
+		if used+required <= c.storageBudget || inUseBins[usage.path] {
+			remaining = append(remaining, usage)
+
+			continue
+		}
+
+		if err := c.evictBinary(usage.path); err != nil {
+			log.Info("failed to evict shared binary", "path", usage.path, "err", err.Error())
+
+			remaining = append(remaining, usage)
+
+			continue
+		}
+
+		used -= usage.size
+
+		log.Info("evicted least recently used shared binary to stay within the storage budget", "path", usage.path, "size", formatBytes(usage.size), "lastUsed", usage.lastUsed)
+	}
+
+	return remaining
+}
+
+// evictBinary removes the CodeModule, unless it got mounted since the mounts were collected.
+// The CodeModule is moved aside before the mounts are checked again, so a pod can't mount it in between, a pod that tries it is retried by the kubelet.
+func (c *Cleaner) evictBinary(binDir string) error {
+	evictedDir := binDir + evictedSuffix
+
+	err := c.fs.Rename(binDir, evictedDir)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	mountedBins, err := c.collectStillMountedBins()
+	if err != nil || mountedBins[binDir] {
+		if renameErr := c.fs.Rename(evictedDir, binDir); renameErr != nil {
+			return errors.WithStack(renameErr)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return errors.Errorf("shared binary %s got mounted in the meantime", binDir)
+	}
+
+	return errors.WithStack(c.fs.RemoveAll(evictedDir))
+}
+
+// collectBinaryUsage returns the usage of every stored CodeModule, sorted from the least to the most recently used one.
+func (c *Cleaner) collectBinaryUsage() ([]binaryUsage, error) {
+	sharedBins, err := c.fs.ReadDir(c.path.AgentSharedBinaryDirBase())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	usages := make([]binaryUsage, 0, len(sharedBins))
+
+	for _, dir := range sharedBins {
+		if !dir.IsDir() {
+			continue
+		}
+
+		path := c.path.AgentSharedBinaryDirForAgent(dir.Name())
+
+		size, err := metadata.DirSize(c.fs.Fs, path)
+		if err != nil {
+			return nil, err
+		}
+
+		usages = append(usages, binaryUsage{
+			path:     path,
+			version:  binaryVersion(dir.Name()),
+			size:     size,
+			lastUsed: dir.ModTime(),
+		})
+	}
+
+	slices.SortFunc(usages, func(a, b binaryUsage) int {
+		return a.lastUsed.Compare(b.lastUsed)
+	})
+
+	return usages, nil
+}
+
+func reportBinaryUsage(usages []binaryUsage) {
+	codeModulesDiskUsageMetric.Reset()
+
+	for _, usage := range usages {
+		codeModulesDiskUsageMetric.WithLabelValues(usage.version).Add(float64(usage.size))
+
+		log.Info("disk usage of shared binary", "version", usage.version, "size", formatBytes(usage.size), "lastUsed", usage.lastUsed)
+	}
+}
+
+// binaryVersion returns the version of the CodeModule, for CodeModules installed from an image the folder name is the base64 encoded image URI.
+func binaryVersion(dirName string) string {
+	imageURI, err := base64.StdEncoding.DecodeString(dirName)
+	if err != nil {
+		return dirName
+	}
+
+	return string(imageURI)
+}
+
+// estimateBinarySize returns the size of the largest stored CodeModule, the size of a new CodeModule is not known before it is downloaded.
+func estimateBinarySize(usages []binaryUsage) int64 {
+	var largest int64
+
+	for _, usage := range usages {
+		largest = max(largest, usage.size)
+	}
+
+	return largest
+}
+
+func totalUsage(usages []binaryUsage) int64 {
+	var total int64
+
+	for _, usage := range usages {
+		total += usage.size
+	}
+
+	return total
+}
+
+func formatBytes(bytes int64) string {
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
@@ -0,0 +1,181 @@
+package cleanup
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/mount-utils"
+)
+
+func TestGetStorageBudget(t *testing.T) {
+	t.Run("not set => no limit", func(t *testing.T) {
+		t.Setenv(storageBudgetEnv, "")
+
+		assert.Zero(t, getStorageBudget())
+	})
+
+	t.Run("quantity => bytes", func(t *testing.T) {
+		t.Setenv(storageBudgetEnv, "15Gi")
+
+		assert.EqualValues(t, 15*1024*1024*1024, getStorageBudget())
+	})
+
+	t.Run("invalid => no limit", func(t *testing.T) {
+		t.Setenv(storageBudgetEnv, "a lot")
+
+		assert.Zero(t, getStorageBudget())
+	})
+}
+
+func TestReserveStorage(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("no budget => nothing evicted", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		cleaner.createSharedBinWithSize(t, "old", 100, now.Add(-time.Hour))
+
+		require.NoError(t, cleaner.ReserveStorage(ctx, cleaner.path.AgentSharedBinaryDirForAgent("new")))
+		cleaner.assertSharedBinExists(t, "old", true)
+	})
+
+	t.Run("fits into budget => nothing evicted", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		cleaner.storageBudget = 200
+		cleaner.createSharedBinWithSize(t, "old", 100, now.Add(-time.Hour))
+
+		require.NoError(t, cleaner.ReserveStorage(ctx, cleaner.path.AgentSharedBinaryDirForAgent("new")))
+		cleaner.assertSharedBinExists(t, "old", true)
+	})
+
+	t.Run("already installed => nothing evicted", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		cleaner.storageBudget = 150
+		cleaner.createSharedBinWithSize(t, "old", 100, now.Add(-time.Hour))
+
+		require.NoError(t, cleaner.ReserveStorage(ctx, cleaner.path.AgentSharedBinaryDirForAgent("old")))
+		cleaner.assertSharedBinExists(t, "old", true)
+	})
+
+	t.Run("exceeds budget => least recently used binary evicted", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		cleaner.storageBudget = 300
+		cleaner.createSharedBinWithSize(t, "oldest", 100, now.Add(-3*time.Hour))
+		cleaner.createSharedBinWithSize(t, "older", 100, now.Add(-2*time.Hour))
+		cleaner.createSharedBinWithSize(t, "recent", 100, now.Add(-time.Hour))
+
+		require.NoError(t, cleaner.ReserveStorage(ctx, cleaner.path.AgentSharedBinaryDirForAgent("new")))
+		cleaner.assertSharedBinExists(t, "oldest", false)
+		cleaner.assertSharedBinExists(t, "older", true)
+		cleaner.assertSharedBinExists(t, "recent", true)
+	})
+
+	t.Run("mounted binaries are not evicted", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		cleaner.storageBudget = 300
+		cleaner.createSharedBinWithSize(t, "mounted", 100, now.Add(-3*time.Hour))
+		cleaner.createSharedBinWithSize(t, "older", 100, now.Add(-2*time.Hour))
+		cleaner.createSharedBinWithSize(t, "recent", 100, now.Add(-time.Hour))
+		cleaner.mounter = mount.NewFakeMounter([]mount.MountPoint{createOverlayMountPoint(cleaner.path.AgentSharedBinaryDirForAgent("mounted"))})
+
+		require.NoError(t, cleaner.ReserveStorage(ctx, cleaner.path.AgentSharedBinaryDirForAgent("new")))
+		cleaner.assertSharedBinExists(t, "mounted", true)
+		cleaner.assertSharedBinExists(t, "older", false)
+		cleaner.assertSharedBinExists(t, "recent", true)
+	})
+
+	t.Run("only binaries in use => budget exceeded", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		cleaner.storageBudget = 150
+		cleaner.createSharedBinWithSize(t, "mounted", 100, now.Add(-time.Hour))
+		cleaner.mounter = mount.NewFakeMounter([]mount.MountPoint{createOverlayMountPoint(cleaner.path.AgentSharedBinaryDirForAgent("mounted"))})
+
+		err := cleaner.ReserveStorage(ctx, cleaner.path.AgentSharedBinaryDirForAgent("new"))
+		require.ErrorIs(t, err, ErrStorageBudgetExceeded)
+		assert.Contains(t, err.Error(), "new")
+		cleaner.assertSharedBinExists(t, "mounted", true)
+	})
+}
+
+func TestEvictBinaries(t *testing.T) {
+	now := time.Now()
+
+	t.Run("mounted since the mounts were collected => not evicted", func(t *testing.T) {
+		cleaner := createCleaner(t)
+		cleaner.storageBudget = 100
+		cleaner.createSharedBinWithSize(t, "mounted", 100, now.Add(-2*time.Hour))
+		cleaner.createSharedBinWithSize(t, "unused", 100, now.Add(-time.Hour))
+		cleaner.mounter = mount.NewFakeMounter([]mount.MountPoint{createOverlayMountPoint(cleaner.path.AgentSharedBinaryDirForAgent("mounted"))})
+
+		usages, err := cleaner.collectBinaryUsage()
+		require.NoError(t, err)
+
+		remaining := cleaner.evictBinaries(usages, map[string]bool{}, 0)
+
+		require.Len(t, remaining, 1)
+		assert.Equal(t, cleaner.path.AgentSharedBinaryDirForAgent("mounted"), remaining[0].path)
+		cleaner.assertSharedBinExists(t, "mounted", true)
+		cleaner.assertSharedBinExists(t, "unused", false)
+
+		agentExists, err := cleaner.fs.Exists(filepath.Join(cleaner.path.AgentSharedBinaryDirForAgent("mounted"), "agent.so"))
+		require.NoError(t, err)
+		assert.True(t, agentExists)
+
+		evictedExists, err := cleaner.fs.DirExists(cleaner.path.AgentSharedBinaryDirForAgent("mounted") + evictedSuffix)
+		require.NoError(t, err)
+		assert.False(t, evictedExists)
+	})
+}
+
+func TestEnforceStorageBudget(t *testing.T) {
+	now := time.Now()
+	image := "registry.example.com/codemodules:1.2.3"
+
+	cleaner := createCleaner(t)
+	cleaner.storageBudget = 200
+	cleaner.createSharedBinWithSize(t, "oldest", 100, now.Add(-3*time.Hour))
+	cleaner.createSharedBinWithSize(t, "in-use", 100, now.Add(-2*time.Hour))
+	cleaner.createSharedBinWithSize(t, base64.StdEncoding.EncodeToString([]byte(image)), 50, now.Add(-time.Hour))
+
+	cleaner.enforceStorageBudget(map[string]bool{cleaner.path.AgentSharedBinaryDirForAgent("in-use"): true})
+
+	cleaner.assertSharedBinExists(t, "oldest", false)
+	cleaner.assertSharedBinExists(t, "in-use", true)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(codeModulesDiskUsageMetric))
+	assert.InDelta(t, 100, testutil.ToFloat64(codeModulesDiskUsageMetric.WithLabelValues("in-use")), 0)
+	assert.InDelta(t, 50, testutil.ToFloat64(codeModulesDiskUsageMetric.WithLabelValues(image)), 0)
+}
+
+func (c *Cleaner) createSharedBinWithSize(t *testing.T, version string, size int, lastUsed time.Time) {
+	t.Helper()
+
+	binDir := c.path.AgentSharedBinaryDirForAgent(version)
+	require.NoError(t, c.fs.MkdirAll(binDir, os.ModePerm))
+	require.NoError(t, c.fs.WriteFile(filepath.Join(binDir, "agent.so"), make([]byte, size), os.ModePerm))
+	require.NoError(t, c.fs.Chtimes(binDir, lastUsed, lastUsed))
+}
+
+func (c *Cleaner) assertSharedBinExists(t *testing.T, version string, expected bool) {
+	t.Helper()
+
+	exists, err := c.fs.DirExists(c.path.AgentSharedBinaryDirForAgent(version))
+	require.NoError(t, err)
+	assert.Equal(t, expected, exists, version)
+}
+
+func createOverlayMountPoint(lowerDir string) mount.MountPoint {
+	return mount.MountPoint{
+		Device: "overlay",
+		Path:   "/appmounts/volume/mapped",
+		Type:   "overlay",
+		Opts:   []string{"lowerdir=" + lowerDir},
+	}
+}
@@ -2,8 +2,20 @@ package cleanup
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
-	log = logd.Get().WithName("csi-cleanup")
+	log                        = logd.Get().WithName("csi-cleanup")
+	codeModulesDiskUsageMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dynatrace",
+		Subsystem: "csi_provisioner",
+		Name:      "codemodule_disk_usage_bytes",
+		Help:      "Disk space used by a CodeModule version stored on the node",
+	}, []string{"version"})
 )
+
+func init() {
+	metrics.Registry.MustRegister(codeModulesDiskUsageMetric)
+}
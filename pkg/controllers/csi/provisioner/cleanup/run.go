@@ -6,6 +6,7 @@ import (
 
 	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	"github.com/spf13/afero"
 	"k8s.io/mount-utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -16,6 +17,10 @@ type Cleaner struct {
 	apiReader client.Reader
 	mounter   mount.Interface
 	path      metadata.PathResolver
+	time      *timeprovider.Provider
+
+	// storageBudget is the max bytes the CodeModules may use on the node, 0 means no limit
+	storageBudget int64
 }
 
 // fsState collects all the "top-level" folders we care about and categorizes them
@@ -26,6 +31,8 @@ type fsState struct {
 	binDks []string
 	// hostDks are dynakube-dirs that contain the folder that was mounted to Host OneAgents
 	hostDks []string
+	// pinnedDks are dynakube-dirs that contain requests for or symlinks to pinned codemodule binaries
+	pinnedDks []string
 }
 
 func New(fs afero.Afero, apiReader client.Reader, path metadata.PathResolver, mounter mount.Interface) *Cleaner {
@@ -34,6 +41,9 @@ func New(fs afero.Afero, apiReader client.Reader, path metadata.PathResolver, mo
 		apiReader: apiReader,
 		path:      path,
 		mounter:   mounter,
+		time:      timeprovider.New(),
+
+		storageBudget: getStorageBudget(),
 	}
 }
 
@@ -141,7 +151,14 @@ func (c *Cleaner) getFilesystemState() (fsState fsState, err error) { //nolint:r
 			fsState.hostDks = append(fsState.hostDks, fileInfo.Name())
 		}
 
-		if !binExists && !hostExists {
+		requestsExist, _ := c.fs.Exists(c.path.CodeModuleRequestsForDynaKube(fileInfo.Name()))
+		pinnedExist, _ := c.fs.Exists(c.path.PinnedAgentBinariesForDynaKube(fileInfo.Name()))
+
+		if requestsExist || pinnedExist {
+			fsState.pinnedDks = append(fsState.pinnedDks, fileInfo.Name())
+		}
+
+		if !binExists && !hostExists && !requestsExist && !pinnedExist {
 			unknownDirs = append(unknownDirs, c.path.DynaKubeDir(fileInfo.Name()))
 		}
 	}
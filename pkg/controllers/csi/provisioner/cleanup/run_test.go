@@ -6,6 +6,7 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -150,6 +151,7 @@ func createCleaner(t *testing.T) *Cleaner {
 		mounter:   mount.NewFakeMounter(nil),
 		apiReader: fake.NewClient(),
 		path:      metadata.PathResolver{},
+		time:      timeprovider.New(),
 	}
 }
 
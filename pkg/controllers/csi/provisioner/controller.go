@@ -31,10 +31,12 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/image"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/job"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	batchv1 "k8s.io/api/batch/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/mount-utils"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -54,9 +56,10 @@ type jobInstallerBuilder func(context.Context, afero.Fs, *job.Properties) instal
 
 // OneAgentProvisioner reconciles a DynaKube object
 type OneAgentProvisioner struct {
-	apiReader  client.Reader
-	kubeClient client.Client
-	fs         afero.Fs
+	apiReader     client.Reader
+	kubeClient    client.Client
+	eventRecorder record.EventRecorder
+	fs            afero.Fs
 
 	dynatraceClientBuilder dynatraceclient.Builder
 	urlInstallerBuilder    urlInstallerBuilder
@@ -74,6 +77,7 @@ func NewOneAgentProvisioner(mgr manager.Manager, opts dtcsi.CSIOptions) *OneAgen
 	return &OneAgentProvisioner{
 		apiReader:              mgr.GetAPIReader(),
 		kubeClient:             mgr.GetClient(),
+		eventRecorder:          mgr.GetEventRecorderFor("dynatrace-csi-provisioner"),
 		fs:                     fs,
 		path:                   path,
 		dynatraceClientBuilder: dynatraceclient.NewBuilder(mgr.GetAPIReader()),
@@ -111,6 +115,14 @@ func (provisioner *OneAgentProvisioner) Reconcile(ctx context.Context, request r
 		return reconcile.Result{}, err
 	}
 
+	err = dk.MergeDefaults(ctx, provisioner.apiReader)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// during a canary rollout only the canary nodes get the new code modules
+	dk.OneAgent().UseStableCodeModules(env.GetNodeName())
+
 	if !isProvisionerNeeded(&dk) {
 		log.Info("CSI driver provisioner not needed")
 
@@ -137,7 +149,15 @@ func (provisioner *OneAgentProvisioner) Reconcile(ctx context.Context, request r
 	}
 
 	err = provisioner.installAgent(ctx, dk)
-	if err != nil && errors.Is(err, errNotReady) {
+	if errors.Is(err, cleanup.ErrStorageBudgetExceeded) {
+		log.Info("CodeModule not installed, it would exceed the storage budget", "dynakube", dk.Name, "reason", err.Error())
+
+		provisioner.sendStorageBudgetExceededEvent(&dk, err)
+
+		_ = provisioner.cleaner.Run(ctx)
+
+		return reconcile.Result{RequeueAfter: defaultRequeueDuration}, nil
+	} else if err != nil && errors.Is(err, errNotReady) {
 		log.Info(err.Error(), "dynakube", dk.Name)
 
 		return reconcile.Result{RequeueAfter: notReadyRequeueDuration}, nil
@@ -17,6 +17,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/image"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/job"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	dtclientmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/clients/dynatrace"
 	dtbuildermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/controllers/dynakube/dynatraceclient"
 	installermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/injection/codemodule/installer"
@@ -26,6 +27,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/mount-utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -187,6 +189,38 @@ func TestReconcile(t *testing.T) {
 	})
 }
 
+func TestReconcileStorageBudget(t *testing.T) {
+	ctx := context.Background()
+
+	t.Setenv(env.NodeName, "test-node")
+	t.Setenv("STORAGE_BUDGET", "150")
+
+	dk := createDynaKubeWithImage(t)
+	prov := createProvisioner(t, dk)
+	installer := installermock.NewInstaller(t)
+	prov.imageInstallerBuilder = mockImageInstallerBuilder(t, installer)
+
+	recorder := record.NewFakeRecorder(10)
+	prov.eventRecorder = recorder
+
+	mountedBin := prov.path.AgentSharedBinaryDirForAgent("old-version")
+	require.NoError(t, afero.WriteFile(prov.fs, mountedBin+"/agent.so", make([]byte, 100), 0644))
+	prov.cleaner = cleanup.New(afero.Afero{Fs: prov.fs}, prov.apiReader, prov.path, mount.NewFakeMounter([]mount.MountPoint{
+		{Device: "overlay", Path: "/appmounts/volume/mapped", Opts: []string{"lowerdir=" + mountedBin}},
+	}))
+
+	result, err := prov.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(dk)})
+	require.NoError(t, err)
+	assert.Equal(t, defaultRequeueDuration, result.RequeueAfter)
+
+	installer.AssertNotCalled(t, "InstallAgent", mock.Anything, mock.Anything)
+
+	require.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, dynakube.ReasonStorageBudgetExceeded)
+	assert.Contains(t, event, "test-node")
+}
+
 func areFsDirsCreated(t *testing.T, prov OneAgentProvisioner, dk *dynakube.DynaKube) bool {
 	t.Helper()
 
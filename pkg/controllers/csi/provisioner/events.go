@@ -0,0 +1,17 @@
+package csiprovisioner
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// sendStorageBudgetExceededEvent reports that a CodeModule could not be installed on this node, because it would exceed the storage budget.
+// The operator aggregates these events of all nodes into the CSIStorageBudget condition of the DynaKube,
+// so the provisioners don't race the operator with their own status updates.
+func (provisioner *OneAgentProvisioner) sendStorageBudgetExceededEvent(dk *dynakube.DynaKube, budgetErr error) {
+	provisioner.eventRecorder.AnnotatedEventf(dk, map[string]string{api.AnnotationNodeName: env.GetNodeName()},
+		corev1.EventTypeWarning, dynakube.ReasonStorageBudgetExceeded,
+		"CodeModule not installed on node %s: %s", env.GetNodeName(), budgetErr.Error())
+}
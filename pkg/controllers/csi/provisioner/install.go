@@ -14,6 +14,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/job"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/symlink"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/pin"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/csijob"
 )
 
@@ -21,40 +22,104 @@ const notReadyRequeueDuration = 30 * time.Second
 
 var errNotReady = errors.New("download job is not ready yet")
 
+// codeModule describes a CodeModule to install, either the latest one of the DynaKube or a pinned one.
+type codeModule struct {
+	// version is downloaded by the url installer and used as fallback tag by the job installer
+	version string
+	// imageURI is pulled by the image and job installer
+	imageURI string
+	// dirName is the folder under AgentSharedBinaryDirBase the CodeModule is installed into
+	dirName string
+}
+
+func latestCodeModule(dk dynakube.DynaKube) codeModule {
+	latest := codeModule{
+		version: dk.OneAgent().GetCodeModulesVersion(),
+		dirName: dk.OneAgent().GetCodeModulesVersion(),
+	}
+
+	if dk.FF().IsNodeImagePull() {
+		latest.imageURI = dk.OneAgent().GetCustomCodeModulesImage()
+	} else if dk.OneAgent().GetCustomCodeModulesImage() != "" {
+		latest.imageURI = dk.OneAgent().GetCodeModulesImage()
+	}
+
+	if dk.OneAgent().GetCustomCodeModulesImage() != "" {
+		latest.dirName = encodeImageURI(dk.OneAgent().GetCodeModulesImage())
+	}
+
+	return latest
+}
+
+func pinnedCodeModule(pinned pin.CodeModule) codeModule {
+	if pinned.ImageURI != "" {
+		return codeModule{
+			version:  pinned.Version,
+			imageURI: pinned.ImageURI,
+			dirName:  encodeImageURI(pinned.ImageURI),
+		}
+	}
+
+	return codeModule{
+		version: pinned.Version,
+		dirName: pinned.Version,
+	}
+}
+
+// An image URI often contains one or several slashes, which is problematic when trying to use it as a folder name.
+// Easiest to just base64 encode it
+func encodeImageURI(imageURI string) string {
+	return base64.StdEncoding.EncodeToString([]byte(imageURI))
+}
+
 func (provisioner *OneAgentProvisioner) installAgent(ctx context.Context, dk dynakube.DynaKube) error {
-	agentInstaller, err := provisioner.getInstaller(ctx, dk)
+	targetDir, err := provisioner.installCodeModule(ctx, dk, latestCodeModule(dk))
 	if err != nil {
-		log.Info("failed to create CodeModule installer", "dk", dk.GetName())
+		return err
+	}
 
+	err = provisioner.createLatestVersionSymlink(dk, targetDir)
+	if err != nil {
 		return err
 	}
 
-	targetDir := provisioner.getTargetDir(dk)
+	return provisioner.installPinnedAgents(ctx, dk)
+}
 
-	ready, err := agentInstaller.InstallAgent(ctx, targetDir)
+func (provisioner *OneAgentProvisioner) installCodeModule(ctx context.Context, dk dynakube.DynaKube, codeModule codeModule) (string, error) {
+	targetDir := provisioner.getTargetDir(codeModule)
+
+	err := provisioner.cleaner.ReserveStorage(ctx, targetDir)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	if !ready {
-		return errNotReady
+	agentInstaller, err := provisioner.getInstaller(ctx, dk, codeModule)
+	if err != nil {
+		log.Info("failed to create CodeModule installer", "dk", dk.GetName())
+
+		return "", err
 	}
 
-	err = provisioner.createLatestVersionSymlink(dk, targetDir)
+	ready, err := agentInstaller.InstallAgent(ctx, targetDir)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	if !ready {
+		return "", errNotReady
 	}
 
-	return nil
+	return targetDir, nil
 }
 
-func (provisioner *OneAgentProvisioner) getInstaller(ctx context.Context, dk dynakube.DynaKube) (installer.Installer, error) {
+func (provisioner *OneAgentProvisioner) getInstaller(ctx context.Context, dk dynakube.DynaKube, codeModule codeModule) (installer.Installer, error) {
 	switch {
 	case dk.FF().IsNodeImagePull():
-		return provisioner.getJobInstaller(ctx, dk), nil
-	case dk.OneAgent().GetCustomCodeModulesImage() != "":
+		return provisioner.getJobInstaller(ctx, dk, codeModule), nil
+	case codeModule.imageURI != "":
 		props := &image.Properties{
-			ImageURI:     dk.OneAgent().GetCodeModulesImage(),
+			ImageURI:     codeModule.imageURI,
 			APIReader:    provisioner.apiReader,
 			Dynakube:     &dk,
 			PathResolver: provisioner.path,
@@ -78,7 +143,7 @@ func (provisioner *OneAgentProvisioner) getInstaller(ctx context.Context, dk dyn
 			Arch:          arch.Arch,
 			Flavor:        arch.Flavor,
 			Technologies:  []string{"all"},
-			TargetVersion: dk.OneAgent().GetCodeModulesVersion(),
+			TargetVersion: codeModule.version,
 			SkipMetadata:  true,
 			PathResolver:  provisioner.path,
 		}
@@ -89,10 +154,10 @@ func (provisioner *OneAgentProvisioner) getInstaller(ctx context.Context, dk dyn
 	}
 }
 
-func (provisioner *OneAgentProvisioner) getJobInstaller(ctx context.Context, dk dynakube.DynaKube) installer.Installer {
-	imageURI := dk.OneAgent().GetCustomCodeModulesImage()
+func (provisioner *OneAgentProvisioner) getJobInstaller(ctx context.Context, dk dynakube.DynaKube, codeModule codeModule) installer.Installer {
+	imageURI := codeModule.imageURI
 	if imageURI == "" {
-		imageURI = "public.ecr.aws/dynatrace/dynatrace-codemodules:" + dk.OneAgent().GetCodeModulesVersion()
+		imageURI = pin.PublicCodeModulesRepository + ":" + codeModule.version
 	}
 
 	pullSecrets := []string{}
@@ -113,18 +178,8 @@ func (provisioner *OneAgentProvisioner) getJobInstaller(ctx context.Context, dk
 	return provisioner.jobInstallerBuilder(ctx, provisioner.fs, props)
 }
 
-func (provisioner *OneAgentProvisioner) getTargetDir(dk dynakube.DynaKube) string {
-	var dirName string
-
-	if dk.OneAgent().GetCustomCodeModulesImage() != "" {
-		// An image URI often contains one or several slashes, which is problematic when trying to use it as a folder name.
-		// Easiest to just base64 encode it
-		dirName = base64.StdEncoding.EncodeToString([]byte(dk.OneAgent().GetCodeModulesImage()))
-	} else {
-		dirName = dk.OneAgent().GetCodeModulesVersion()
-	}
-
-	return provisioner.path.AgentSharedBinaryDirForAgent(dirName)
+func (provisioner *OneAgentProvisioner) getTargetDir(codeModule codeModule) string {
+	return provisioner.path.AgentSharedBinaryDirForAgent(codeModule.dirName)
 }
 
 func (provisioner *OneAgentProvisioner) createLatestVersionSymlink(dk dynakube.DynaKube, targetDir string) error {
@@ -1,9 +1,22 @@
 package csiprovisioner
 
 import (
+	"context"
 	"encoding/base64"
+	"os"
+	"strings"
 	"testing"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/image"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
+	installermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/injection/codemodule/installer"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,7 +25,7 @@ func TestGetTargetDir(t *testing.T) {
 		prov := createProvisioner(t)
 		dk := createDynaKubeWithVersion(t)
 
-		targetDir := prov.getTargetDir(*dk)
+		targetDir := prov.getTargetDir(latestCodeModule(*dk))
 		require.Contains(t, targetDir, dk.OneAgent().GetCodeModulesVersion())
 	})
 
@@ -21,7 +34,92 @@ func TestGetTargetDir(t *testing.T) {
 		dk := createDynaKubeWithImage(t)
 
 		expectedDir := base64.StdEncoding.EncodeToString([]byte(dk.OneAgent().GetCodeModulesImage()))
-		targetDir := prov.getTargetDir(*dk)
+		targetDir := prov.getTargetDir(latestCodeModule(*dk))
 		require.Contains(t, targetDir, expectedDir)
 	})
 }
+
+func TestInstallPinnedAgents(t *testing.T) {
+	ctx := context.Background()
+	pinnedVersion := "1.300.0.20240101-000000"
+
+	t.Run("no requests => nothing installed", func(t *testing.T) {
+		prov := createProvisioner(t)
+		dk := createDynaKubeWithImage(t)
+
+		require.NoError(t, prov.installPinnedAgents(ctx, *dk))
+	})
+
+	t.Run("requested version => installed from the pinned image", func(t *testing.T) {
+		prov := createProvisioner(t)
+		dk := createDynaKubeWithImage(t)
+		requestCodeModule(t, prov, dk, pinnedVersion)
+
+		expectedImage := "index.docker.io/library/test-image:" + pinnedVersion
+		expectedDir := prov.path.AgentSharedBinaryDirForAgent(base64.StdEncoding.EncodeToString([]byte(expectedImage)))
+
+		m := installermock.NewInstaller(t)
+		m.On("InstallAgent", mock.Anything, expectedDir).Return(true, nil)
+		prov.imageInstallerBuilder = func(_ context.Context, _ afero.Fs, props *image.Properties) (installer.Installer, error) {
+			assert.Equal(t, expectedImage, props.ImageURI)
+
+			return m, nil
+		}
+
+		require.NoError(t, prov.installPinnedAgents(ctx, *dk))
+	})
+
+	t.Run("requested version not ready => not ready", func(t *testing.T) {
+		dk := createDynaKubeWithVersion(t)
+		prov := createProvisioner(t, dk, createToken(t, dk))
+		requestCodeModule(t, prov, dk, pinnedVersion)
+		prov.dynatraceClientBuilder = mockSuccessfulDtClientBuilder(t)
+		prov.urlInstallerBuilder = func(_ afero.Fs, _ dtclient.Client, props *url.Properties) installer.Installer {
+			assert.Equal(t, pinnedVersion, props.TargetVersion)
+
+			return createNotReadyInstaller(t)
+		}
+
+		require.ErrorIs(t, prov.installPinnedAgents(ctx, *dk), errNotReady)
+	})
+
+	t.Run("failed install => other requests still installed", func(t *testing.T) {
+		prov := createProvisioner(t)
+		dk := createDynaKubeWithImage(t)
+		failingVersion := "1.200.0.20230101-000000"
+		requestCodeModule(t, prov, dk, failingVersion)
+		requestCodeModule(t, prov, dk, pinnedVersion)
+
+		failing := installermock.NewInstaller(t)
+		failing.On("InstallAgent", mock.Anything, mock.Anything).Return(false, errors.New("download failed"))
+
+		successful := createSuccessfulInstaller(t)
+		prov.imageInstallerBuilder = func(_ context.Context, _ afero.Fs, props *image.Properties) (installer.Installer, error) {
+			if strings.HasSuffix(props.ImageURI, failingVersion) {
+				return failing, nil
+			}
+
+			return successful, nil
+		}
+
+		err := prov.installPinnedAgents(ctx, *dk)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), failingVersion)
+		successful.AssertCalled(t, "InstallAgent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unresolvable request => skipped", func(t *testing.T) {
+		prov := createProvisioner(t)
+		dk := createDynaKubeWithVersion(t)
+		requestCodeModule(t, prov, dk, "sha256:"+strings.Repeat("a", 64))
+
+		require.NoError(t, prov.installPinnedAgents(ctx, *dk))
+	})
+}
+
+func requestCodeModule(t *testing.T, prov OneAgentProvisioner, dk *dynakube.DynaKube, versionOrDigest string) {
+	t.Helper()
+
+	require.NoError(t, prov.fs.MkdirAll(prov.path.CodeModuleRequestsForDynaKube(dk.Name), os.ModePerm))
+	require.NoError(t, afero.WriteFile(prov.fs, prov.path.CodeModuleRequestForDynaKube(dk.Name, versionOrDigest), nil, os.ModePerm))
+}
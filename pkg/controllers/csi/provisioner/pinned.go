@@ -0,0 +1,91 @@
+package csiprovisioner
+
+import (
+	"context"
+	goerrors "errors"
+	"os"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/cleanup"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/symlink"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/pin"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// installPinnedAgents installs every CodeModule that was requested by a pod via the pin.Annotation.
+// The requests are recorded by the CSI publisher, a request that can't be resolved, installed or doesn't fit into the storage budget is skipped, so it doesn't block the other ones.
+func (provisioner *OneAgentProvisioner) installPinnedAgents(ctx context.Context, dk dynakube.DynaKube) error {
+	requests, err := afero.ReadDir(provisioner.fs, provisioner.path.CodeModuleRequestsForDynaKube(dk.GetName()))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	notReady := false
+
+	var budgetErr error
+
+	var installErrs []error
+
+	for _, request := range requests {
+		pinned, err := pin.Resolve(&dk, request.Name())
+		if err != nil {
+			log.Info("skipping requested CodeModule", "dk", dk.GetName(), "pin", request.Name(), "reason", err.Error())
+
+			continue
+		}
+
+		targetDir, err := provisioner.installCodeModule(ctx, dk, pinnedCodeModule(pinned))
+		if errors.Is(err, errNotReady) {
+			notReady = true
+
+			continue
+		} else if errors.Is(err, cleanup.ErrStorageBudgetExceeded) {
+			log.Info("skipping requested CodeModule", "dk", dk.GetName(), "pin", request.Name(), "reason", err.Error())
+
+			budgetErr = err
+
+			continue
+		} else if err != nil {
+			log.Info("failed to install requested CodeModule", "dk", dk.GetName(), "pin", request.Name(), "reason", err.Error())
+
+			installErrs = append(installErrs, errors.WithMessagef(err, "failed to install pinned CodeModule %s", pinned.Pin))
+
+			continue
+		}
+
+		err = provisioner.createPinnedVersionSymlink(dk, pinned.Pin, targetDir)
+		if err != nil {
+			installErrs = append(installErrs, errors.WithMessagef(err, "failed to link pinned CodeModule %s", pinned.Pin))
+		}
+	}
+
+	if len(installErrs) > 0 {
+		return goerrors.Join(installErrs...)
+	}
+
+	if budgetErr != nil {
+		return budgetErr
+	}
+
+	if notReady {
+		return errNotReady
+	}
+
+	return nil
+}
+
+func (provisioner *OneAgentProvisioner) createPinnedVersionSymlink(dk dynakube.DynaKube, versionOrDigest, targetDir string) error {
+	symlinkPath := provisioner.path.PinnedAgentBinaryForDynaKube(dk.GetName(), versionOrDigest)
+	if err := provisioner.fs.MkdirAll(provisioner.path.PinnedAgentBinariesForDynaKube(dk.GetName()), os.ModePerm); err != nil {
+		return err
+	}
+
+	if err := symlink.Remove(provisioner.fs, symlinkPath); err != nil {
+		return err
+	}
+
+	return symlink.Create(provisioner.fs, targetDir, symlinkPath)
+}
@@ -42,7 +42,7 @@ func (mod ProxyModifier) getVolumes() []corev1.Volume {
 			Name: proxy.SecretVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: proxy.BuildSecretName(mod.dk.Name),
+					SecretName: proxy.BuildSecretNameForComponent(&mod.dk, dynakube.ActiveGateProxyComponent),
 				},
 			},
 		},
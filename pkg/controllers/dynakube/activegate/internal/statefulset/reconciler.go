@@ -79,6 +79,18 @@ func (r *Reconciler) manageStatefulSet(ctx context.Context) error {
 	return nil
 }
 
+// BuildDesiredStatefulSet builds the ActiveGate StatefulSet for the given capability as the Reconciler would apply it, without touching the cluster.
+func BuildDesiredStatefulSet(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube, capability capability.Capability) (*appsv1.StatefulSet, error) {
+	r := &Reconciler{
+		apiReader:  apiReader,
+		dk:         dk,
+		capability: capability,
+		modifiers:  []builder.Modifier{},
+	}
+
+	return r.buildDesiredStatefulSet(ctx)
+}
+
 func (r *Reconciler) buildDesiredStatefulSet(ctx context.Context) (*appsv1.StatefulSet, error) {
 	kubeUID := types.UID(r.dk.Status.KubeSystemUUID)
 
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/telemetryingest"
@@ -96,6 +97,15 @@ func TestGetBaseObjectMeta(t *testing.T) {
 		require.NotEmpty(t, sts.Spec.Template.Labels)
 		assert.Equal(t, expectedTemplateAnnotations, sts.Spec.Template.Annotations)
 	})
+	t.Run("no tokens hash annotation, the tokens of the secret aren't consumed", func(t *testing.T) {
+		dk := getTestDynakube()
+		dk.Status.TokensHash = "tokens-hash"
+		multiCapability := capability.NewMultiCapability(&dk)
+		builder := NewStatefulSetBuilder(testKubeUID, testConfigHash, dk, multiCapability)
+		sts, _ := builder.CreateStatefulSet(nil)
+
+		assert.NotContains(t, sts.Spec.Template.Annotations, api.AnnotationTokensHash)
+	})
 	t.Run("has default(tenant-registry) node affinity", func(t *testing.T) {
 		dk := getTestDynakube()
 		dk.Status.ActiveGate.Source = status.TenantRegistryVersionSource
@@ -149,6 +149,17 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// BuildDesiredStatefulSet builds the ActiveGate StatefulSet as it would be applied by the Reconciler, without touching the cluster.
+// Returns nil, if no capability is enabled for the DynaKube.
+func BuildDesiredStatefulSet(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube) (*appsv1.StatefulSet, error) {
+	agCapability := capability.NewMultiCapability(dk)
+	if !agCapability.Enabled() {
+		return nil, nil
+	}
+
+	return statefulset.BuildDesiredStatefulSet(ctx, apiReader, dk, agCapability)
+}
+
 func (r *Reconciler) createActiveGateTenantConnectionInfoConfigMap(ctx context.Context) error {
 	if !r.dk.ActiveGate().IsEnabled() {
 		// TODO: Add clean up of the config map
@@ -9,6 +9,7 @@ import (
 const (
 	TokenReadyConditionMessage             = "Token ready"
 	TokenWithoutDataIngestConditionMessage = "Token ready, DataIngest token not provided"
+	TokenRotationCompletedConditionMessage = "All components were restarted with the rotated tokens"
 )
 
 func (controller *Controller) setConditionTokenError(dk *dynakube.DynaKube, err error) {
@@ -38,6 +39,28 @@ func (controller *Controller) setConditionTokenReady(dk *dynakube.DynaKube, data
 	controller.setAndLogCondition(dk, tokenErrorCondition)
 }
 
+func (controller *Controller) setConditionTokenRotationInProgress(dk *dynakube.DynaKube, message string) {
+	condition := metav1.Condition{
+		Type:    dynakube.TokenRotationConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  dynakube.ReasonTokenRotationInProgress,
+		Message: message,
+	}
+
+	_ = meta.SetStatusCondition(&dk.Status.Conditions, condition)
+}
+
+func (controller *Controller) setConditionTokenRotationCompleted(dk *dynakube.DynaKube) {
+	condition := metav1.Condition{
+		Type:    dynakube.TokenRotationConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  dynakube.ReasonTokenRotationCompleted,
+		Message: TokenRotationCompletedConditionMessage,
+	}
+
+	_ = meta.SetStatusCondition(&dk.Status.Conditions, condition)
+}
+
 // TODO: Probably should be removed, as most of this is done inside meta.SetStatusCondition (except the logging) the removeDeprecatedConditionTypes already did its job, as it has been in since forever
 func (controller *Controller) setAndLogCondition(dk *dynakube.DynaKube, newCondition metav1.Condition) {
 	controller.removeDeprecatedConditionTypes(dk)
@@ -15,12 +15,14 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceapi"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/events"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/extension"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/injection"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/istio"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/kspm"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring"
 	logmondaemonset "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/networkpolicy"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/proxy"
@@ -39,8 +41,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -61,15 +65,16 @@ func Add(mgr manager.Manager, _ string) error {
 }
 
 func NewController(mgr manager.Manager, clusterID string) *Controller {
-	return NewDynaKubeController(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetConfig(), clusterID)
+	return NewDynaKubeController(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetConfig(), mgr.GetEventRecorderFor("dynakube-controller"), clusterID)
 }
 
-func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, config *rest.Config, clusterID string) *Controller {
+func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, config *rest.Config, eventRecorder record.EventRecorder, clusterID string) *Controller {
 	return &Controller{
 		client:                 kubeClient,
 		apiReader:              apiReader,
 		fs:                     afero.Afero{Fs: afero.NewOsFs()},
 		config:                 config,
+		eventRecorder:          events.NewRecorder(eventRecorder),
 		operatorNamespace:      os.Getenv(env.PodNamespace),
 		clusterID:              clusterID,
 		dynatraceClientBuilder: dynatraceclient.NewBuilder(apiReader),
@@ -86,6 +91,7 @@ func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, co
 		logMonitoringReconcilerBuilder:      logmonitoring.NewReconciler,
 		proxyReconcilerBuilder:              proxy.NewReconciler,
 		kspmReconcilerBuilder:               kspm.NewReconciler,
+		networkPolicyReconcilerBuilder:      networkpolicy.NewReconciler,
 	}
 }
 
@@ -98,6 +104,9 @@ func (controller *Controller) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Secret{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(controller.mapTokenSecretToDynaKubes)).
+		Watches(&dynakube.DynaKubeDefaults{}, handler.EnqueueRequestsFromMapFunc(controller.mapDefaultsToDynaKubes)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(controller.mapPipelineExtensionsToDynaKubes)).
 		Complete(controller)
 }
 
@@ -112,6 +121,7 @@ type Controller struct {
 	dynatraceClientBuilder dynatraceclient.Builder
 	config                 *rest.Config
 	istioClientBuilder     istio.ClientBuilder
+	eventRecorder          events.EventRecorder
 
 	deploymentMetadataReconcilerBuilder deploymentmetadata.ReconcilerBuilder
 	activeGateReconcilerBuilder         activegate.ReconcilerBuilder
@@ -124,6 +134,7 @@ type Controller struct {
 	logMonitoringReconcilerBuilder      logmonitoring.ReconcilerBuilder
 	proxyReconcilerBuilder              proxy.ReconcilerBuilder
 	kspmReconcilerBuilder               kspm.ReconcilerBuilder
+	networkPolicyReconcilerBuilder      networkpolicy.ReconcilerBuilder
 
 	tokens            token.Tokens
 	operatorNamespace string
@@ -175,7 +186,13 @@ func (controller *Controller) getDynakubeOrCleanup(ctx context.Context, dkName,
 			return nil, errors.WithMessagef(err, "failed to list namespaces for dynakube %s", dkName)
 		}
 
-		return nil, controller.createDynakubeMapper(ctx, dk).UnmapFromDynaKube(namespaces)
+		deleteDynakubeMetrics(dkName)
+
+		return nil, goerrors.Join(
+			controller.createDynakubeMapper(ctx, dk).UnmapFromDynaKube(namespaces),
+			networkpolicy.Cleanup(ctx, controller.client, controller.apiReader, dk),
+			istio.CleanupSidecars(ctx, controller.client, controller.apiReader, dk),
+		)
 	} else if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -201,10 +218,17 @@ func (controller *Controller) handleError(
 		dk.Status.SetPhase(dynatracestatus.Error)
 		log.Error(err, "error reconciling DynaKube", "namespace", dk.Namespace, "name", dk.Name)
 
+		if !isComponentReconcileError(err) {
+			controller.eventRecorder.SendReconcileFailedEvent(dk, err)
+		}
+
 	default:
 		dk.Status.SetPhase(controller.determineDynaKubePhase(ctx, dk))
 	}
 
+	updatePhaseMetric(dk)
+	controller.sendStatusChangeEvents(dk, oldStatus)
+
 	if isStatusDifferent, err := hasher.IsDifferent(oldStatus, dk.Status); err != nil {
 		log.Error(err, "failed to generate hash for the status section")
 	} else if isStatusDifferent {
@@ -232,7 +256,11 @@ func (controller *Controller) setRequeueAfterIfNewIsShorter(requeueAfter time.Du
 func (controller *Controller) reconcileDynaKube(ctx context.Context, dk *dynakube.DynaKube) error {
 	var istioClient *istio.Client
 
-	var err error
+	err := dk.MergeDefaults(ctx, controller.apiReader)
+	if err != nil {
+		return err
+	}
+
 	if dk.Spec.EnableIstio {
 		istioClient, err = controller.setupIstioClient(dk)
 	}
@@ -271,7 +299,12 @@ func (controller *Controller) reconcileDynaKube(ctx context.Context, dk *dynakub
 		return err
 	}
 
-	return controller.reconcileComponents(ctx, dynatraceClient, istioClient, dk)
+	err = controller.reconcileComponents(ctx, dynatraceClient, istioClient, dk)
+	if err != nil {
+		return err
+	}
+
+	return controller.updateTokenRotationStatus(ctx, dk)
 }
 
 func (controller *Controller) setupIstioClient(dk *dynakube.DynaKube) (*istio.Client, error) {
@@ -296,12 +329,18 @@ func (controller *Controller) setupTokensAndClient(ctx context.Context, dk *dyna
 	tokens, err := tokenReader.ReadTokens(ctx)
 	if err != nil {
 		controller.setConditionTokenError(dk, err)
+		controller.eventRecorder.SendTokenVerificationFailedEvent(dk, err)
 
 		return nil, err
 	}
 
 	controller.tokens = tokens
 
+	err = controller.detectTokenRotation(dk, tokens)
+	if err != nil {
+		return nil, err
+	}
+
 	dynatraceClientBuilder := controller.dynatraceClientBuilder.
 		SetDynakube(*dk).
 		SetTokens(tokens)
@@ -316,6 +355,7 @@ func (controller *Controller) setupTokensAndClient(ctx context.Context, dk *dyna
 	err = controller.verifyTokens(ctx, dynatraceClient, dk)
 	if err != nil {
 		controller.setConditionTokenError(dk, err)
+		controller.eventRecorder.SendTokenVerificationFailedEvent(dk, err)
 
 		return nil, err
 	}
@@ -330,7 +370,9 @@ func (controller *Controller) reconcileComponents(ctx context.Context, dynatrace
 
 	log.Info("start reconciling ActiveGate")
 
-	err := controller.reconcileActiveGate(ctx, dk, dynatraceClient, istioClient)
+	err := controller.reconcileComponent(dk, activeGateComponent, func() error {
+		return controller.reconcileActiveGate(ctx, dk, dynatraceClient, istioClient)
+	})
 	if err != nil {
 		log.Info("could not reconcile ActiveGate")
 
@@ -339,7 +381,9 @@ func (controller *Controller) reconcileComponents(ctx context.Context, dynatrace
 
 	extensionReconciler := controller.extensionReconcilerBuilder(controller.client, controller.apiReader, dk)
 
-	err = extensionReconciler.Reconcile(ctx)
+	err = controller.reconcileComponent(dk, extensionComponent, func() error {
+		return extensionReconciler.Reconcile(ctx)
+	})
 	if err != nil {
 		log.Info("could not reconcile Extensions")
 
@@ -350,7 +394,9 @@ func (controller *Controller) reconcileComponents(ctx context.Context, dynatrace
 
 	otelcReconciler := controller.otelcReconcilerBuilder(controller.client, controller.apiReader, dk)
 
-	err = otelcReconciler.Reconcile(ctx)
+	err = controller.reconcileComponent(dk, otelcComponent, func() error {
+		return otelcReconciler.Reconcile(ctx)
+	})
 	if err != nil {
 		log.Info("could not reconcile otelc")
 
@@ -361,7 +407,9 @@ func (controller *Controller) reconcileComponents(ctx context.Context, dynatrace
 
 	logMonitoringReconciler := controller.logMonitoringReconcilerBuilder(controller.client, controller.apiReader, dynatraceClient, dk)
 
-	err = logMonitoringReconciler.Reconcile(ctx)
+	err = controller.reconcileComponent(dk, logMonitoringComponent, func() error {
+		return logMonitoringReconciler.Reconcile(ctx)
+	})
 	if err != nil {
 		if errors.Is(err, oaconnectioninfo.NoOneAgentCommunicationHostsError) || errors.Is(err, logmondaemonset.KubernetesSettingsNotAvailableError) {
 			controller.setRequeueAfterIfNewIsShorter(fastUpdateInterval)
@@ -376,13 +424,17 @@ func (controller *Controller) reconcileComponents(ctx context.Context, dynatrace
 
 	log.Info("start reconciling app injection")
 
-	err = controller.injectionReconcilerBuilder(
+	injectionReconciler := controller.injectionReconcilerBuilder(
 		controller.client,
 		controller.apiReader,
 		dynatraceClient,
 		istioClient,
 		dk,
-	).Reconcile(ctx)
+	)
+
+	err = controller.reconcileComponent(dk, injectionComponent, func() error {
+		return injectionReconciler.Reconcile(ctx)
+	})
 	if err != nil {
 		if errors.Is(err, oaconnectioninfo.NoOneAgentCommunicationHostsError) {
 			// missing communication hosts is not an error per se, just make sure next the reconciliation is happening ASAP
@@ -399,15 +451,18 @@ func (controller *Controller) reconcileComponents(ctx context.Context, dynatrace
 
 	log.Info("start reconciling OneAgent")
 
-	err = controller.oneAgentReconcilerBuilder(
+	oneAgentReconciler := controller.oneAgentReconcilerBuilder(
 		controller.client,
 		controller.apiReader,
 		dynatraceClient,
 		dk,
 		controller.tokens,
 		controller.clusterID,
-	).
-		Reconcile(ctx)
+	)
+
+	err = controller.reconcileComponent(dk, oneAgentComponent, func() error {
+		return oneAgentReconciler.Reconcile(ctx)
+	})
 	if err != nil {
 		if errors.Is(err, oaconnectioninfo.NoOneAgentCommunicationHostsError) {
 			// missing communication hosts is not an error per se, just make sure next the reconciliation is happening ASAP
@@ -424,16 +479,59 @@ func (controller *Controller) reconcileComponents(ctx context.Context, dynatrace
 
 	kspmReconciler := controller.kspmReconcilerBuilder(controller.client, controller.apiReader, dk)
 
-	err = kspmReconciler.Reconcile(ctx)
+	err = controller.reconcileComponent(dk, kspmComponent, func() error {
+		return kspmReconciler.Reconcile(ctx)
+	})
 	if err != nil {
 		log.Info("could not reconcile kspm")
 
 		componentErrors = append(componentErrors, err)
 	}
 
+	networkPolicyReconciler := controller.networkPolicyReconcilerBuilder(controller.client, controller.apiReader, dk)
+
+	err = controller.reconcileComponent(dk, networkPolicyComponent, func() error {
+		return networkPolicyReconciler.Reconcile(ctx)
+	})
+	if err != nil {
+		log.Info("could not reconcile network policies")
+
+		componentErrors = append(componentErrors, err)
+	}
+
 	return goerrors.Join(componentErrors...)
 }
 
+// componentReconcileError marks the errors, for which a ComponentReconcileFailed event was already sent,
+// so handleError doesn't send a ReconcileFailed event for the same error.
+type componentReconcileError struct {
+	error
+}
+
+func (e componentReconcileError) Unwrap() error {
+	return e.error
+}
+
+func isComponentReconcileError(err error) bool {
+	var componentErr componentReconcileError
+
+	return errors.As(err, &componentErr)
+}
+
+// reconcileComponent runs the reconcile function of a component, records its metrics and sends an event in case it failed.
+func (controller *Controller) reconcileComponent(dk *dynakube.DynaKube, component string, reconcile func() error) error {
+	err := measureComponentReconcile(dk, component, reconcile)
+	if err != nil &&
+		!errors.Is(err, oaconnectioninfo.NoOneAgentCommunicationHostsError) &&
+		!errors.Is(err, logmondaemonset.KubernetesSettingsNotAvailableError) {
+		controller.eventRecorder.SendComponentReconcileFailedEvent(dk, component, err)
+
+		return componentReconcileError{err}
+	}
+
+	return err
+}
+
 func (controller *Controller) createDynakubeMapper(ctx context.Context, dk *dynakube.DynaKube) *mapper.DynakubeMapper {
 	dkMapper := mapper.NewDynakubeMapper(ctx, controller.client, controller.apiReader, controller.operatorNamespace, dk)
 
@@ -23,10 +23,12 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/istio"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/kspm"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/networkpolicy"
 	oneagentcontroller "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/proxy"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator"
 	dtclientmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/clients/dynatrace"
 	controllermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/controllers"
@@ -38,6 +40,8 @@ import (
 	"github.com/stretchr/testify/require"
 	fakeistio "istio.io/client-go/pkg/clientset/versioned/fake"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -76,7 +80,7 @@ func TestGetDynakubeOrCleanup(t *testing.T) {
 		NamespacedName: types.NamespacedName{Name: "dynakube-test", Namespace: "dynatrace"},
 	}
 
-	t.Run("dynakube doesn't exist => unmap namespace", func(t *testing.T) {
+	t.Run("dynakube doesn't exist => unmap namespace and remove policies", func(t *testing.T) {
 		markedNamespace := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "app-namespace",
@@ -85,7 +89,14 @@ func TestGetDynakubeOrCleanup(t *testing.T) {
 				},
 			},
 		}
-		fakeClient := fake.NewClientWithIndex(markedNamespace)
+		injectionPolicy := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      networkpolicy.BuildName(request.Name, networkpolicy.InjectionTarget),
+				Namespace: markedNamespace.Name,
+				Labels:    labels.NewCoreLabels(request.Name, networkpolicy.PolicyComponentLabel).BuildLabels(),
+			},
+		}
+		fakeClient := fake.NewClientWithIndex(markedNamespace, injectionPolicy)
 		controller := &Controller{
 			client:    fakeClient,
 			apiReader: fakeClient,
@@ -99,6 +110,9 @@ func TestGetDynakubeOrCleanup(t *testing.T) {
 		err = fakeClient.Get(context.Background(), types.NamespacedName{Name: markedNamespace.Name}, unmarkedNamespace)
 		require.NoError(t, err)
 		assert.Empty(t, unmarkedNamespace.Labels)
+
+		err = fakeClient.Get(context.Background(), client.ObjectKeyFromObject(injectionPolicy), &networkingv1.NetworkPolicy{})
+		assert.True(t, k8serrors.IsNotFound(err))
 	})
 
 	t.Run("dynakube exists => return dynakube", func(t *testing.T) {
@@ -373,6 +387,9 @@ func TestReconcileComponents(t *testing.T) {
 		mockKSPMReconciler := controllermock.NewReconciler(t)
 		mockKSPMReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
 
+		mockNetworkPolicyReconciler := controllermock.NewReconciler(t)
+		mockNetworkPolicyReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
+
 		controller := &Controller{
 			client:    fakeClient,
 			apiReader: fakeClient,
@@ -385,6 +402,7 @@ func TestReconcileComponents(t *testing.T) {
 			extensionReconcilerBuilder:     createExtensionReconcilerBuilder(mockExtensionReconciler),
 			otelcReconcilerBuilder:         createOtelcReconcilerBuilder(mockOtelcReconciler),
 			kspmReconcilerBuilder:          createKSPMReconcilerBuilder(mockKSPMReconciler),
+			networkPolicyReconcilerBuilder: createNetworkPolicyReconcilerBuilder(mockNetworkPolicyReconciler),
 		}
 		mockedDtc := dtclientmock.NewClient(t)
 
@@ -392,7 +410,7 @@ func TestReconcileComponents(t *testing.T) {
 
 		require.Error(t, err)
 		// goerrors.Join concats errors with \n
-		assert.Len(t, strings.Split(err.Error(), "\n"), 7) // ActiveGate, Extension, OtelC, OneAgent LogMonitoring, Injection and KSPM reconcilers
+		assert.Len(t, strings.Split(err.Error(), "\n"), 8) // ActiveGate, Extension, OtelC, OneAgent LogMonitoring, Injection, KSPM and NetworkPolicy reconcilers
 	})
 
 	t.Run("exit early in case of no oneagent conncection info", func(t *testing.T) {
@@ -482,6 +500,9 @@ func TestReconcileDynaKube(t *testing.T) {
 	mockKSPMReconciler := controllermock.NewReconciler(t)
 	mockKSPMReconciler.On("Reconcile", mock.Anything).Return(nil)
 
+	mockNetworkPolicyReconciler := controllermock.NewReconciler(t)
+	mockNetworkPolicyReconciler.On("Reconcile", mock.Anything).Return(nil)
+
 	fakeIstio := fakeistio.NewSimpleClientset()
 
 	baseController := &Controller{
@@ -496,6 +517,7 @@ func TestReconcileDynaKube(t *testing.T) {
 		istioReconcilerBuilder:              istio.NewReconciler,
 		kspmReconcilerBuilder:               createKSPMReconcilerBuilder(mockKSPMReconciler),
 		logMonitoringReconcilerBuilder:      createLogMonitoringReconcilerBuilder(mockLogMonitoringReconciler),
+		networkPolicyReconcilerBuilder:      createNetworkPolicyReconcilerBuilder(mockNetworkPolicyReconciler),
 		oneAgentReconcilerBuilder:           createOneAgentReconcilerBuilder(mockOneAgentReconciler),
 		otelcReconcilerBuilder:              createOtelcReconcilerBuilder(mockOtelcReconciler),
 		proxyReconcilerBuilder:              createProxyReconcilerBuilder(mockProxyReconciler),
@@ -587,6 +609,12 @@ func createKSPMReconcilerBuilder(reconciler controllers.Reconciler) kspm.Reconci
 	}
 }
 
+func createNetworkPolicyReconcilerBuilder(reconciler controllers.Reconciler) networkpolicy.ReconcilerBuilder {
+	return func(_ client.Client, _ client.Reader, _ *dynakube.DynaKube) controllers.Reconciler {
+		return reconciler
+	}
+}
+
 func createAPIMonitoringReconcilerBuilder(reconciler controllers.Reconciler) apimonitoring.ReconcilerBuilder {
 	return func(_ dtclient.Client, _ *dynakube.DynaKube, _ string) controllers.Reconciler {
 		return reconciler
@@ -0,0 +1,30 @@
+package dynakube
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// mapDefaultsToDynaKubes enqueues every DynaKube that references the changed DynaKubeDefaults,
+// so changes to the defaults are rolled out without waiting for the next periodic reconcile.
+func (controller *Controller) mapDefaultsToDynaKubes(ctx context.Context, defaults client.Object) []reconcile.Request {
+	var dkList dynakube.DynaKubeList
+	if err := controller.client.List(ctx, &dkList, client.InNamespace(controller.operatorNamespace)); err != nil {
+		log.Info("failed to list DynaKubes for DynaKubeDefaults", "defaults", defaults.GetName(), "error", err.Error())
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, dk := range dkList.Items {
+		if dk.DefaultsName() == defaults.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&dk)})
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,40 @@
+package dynakube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMapDefaultsToDynaKubes(t *testing.T) {
+	dkWithDefaults := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "with-defaults", Namespace: testNamespace},
+		Spec:       dynakube.DynaKubeSpec{DefaultsRef: "fleet"},
+	}
+	dkWithoutDefaults := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "without-defaults", Namespace: testNamespace},
+	}
+	controller := &Controller{
+		client:            fake.NewClient(dkWithDefaults, dkWithoutDefaults),
+		operatorNamespace: testNamespace,
+	}
+
+	t.Run("referenced defaults => dynakube enqueued", func(t *testing.T) {
+		defaults := &dynakube.DynaKubeDefaults{ObjectMeta: metav1.ObjectMeta{Name: "fleet"}}
+
+		requests := controller.mapDefaultsToDynaKubes(context.Background(), defaults)
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "with-defaults", requests[0].Name)
+	})
+	t.Run("unreferenced defaults => nothing enqueued", func(t *testing.T) {
+		defaults := &dynakube.DynaKubeDefaults{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+
+		assert.Empty(t, controller.mapDefaultsToDynaKubes(context.Background(), defaults))
+	})
+}
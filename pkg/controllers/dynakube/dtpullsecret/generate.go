@@ -48,8 +48,11 @@ func (r *Reconciler) generateData() (map[string][]byte, error) {
 		registryToken = r.tokens.PaasToken().Value
 	case r.tokens.APIToken().Value != "":
 		registryToken = r.tokens.APIToken().Value
+	case r.tokens.PlatformToken().Value != "":
+		registryToken = r.tokens.PlatformToken().Value
 	default:
-		return nil, errors.New("token secret does not contain a paas or api token, cannot generate docker config")
+		// the bearer tokens of an OAuth client expire, so they can't be stored as registry password
+		return nil, errors.New("token secret does not contain a paas, api or platform token, cannot generate docker config, use a customPullSecret instead")
 	}
 
 	tenantUUID, err := r.dk.TenantUUID()
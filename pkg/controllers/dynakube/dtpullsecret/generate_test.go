@@ -80,3 +80,48 @@ func TestReconciler_GenerateData(t *testing.T) {
 	assert.NotNil(t, actual)
 	assert.Equal(t, expected, actual)
 }
+
+func TestReconciler_GenerateDataWithPlatformAuth(t *testing.T) {
+	dk := &dynakube.DynaKube{
+		Spec: dynakube.DynaKubeSpec{
+			APIURL: testAPIURL,
+		},
+		Status: dynakube.DynaKubeStatus{
+			OneAgent: oneagent.Status{
+				ConnectionInfoStatus: oneagent.ConnectionInfoStatus{
+					ConnectionInfo: communication.ConnectionInfo{
+						TenantUUID: testTenant,
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("platform token is used as registry password", func(t *testing.T) {
+		r := &Reconciler{
+			dk: dk,
+			tokens: token.Tokens{
+				dtclient.PlatformToken: &token.Token{Value: "platform-token"},
+			},
+		}
+
+		data, err := r.generateData()
+		require.NoError(t, err)
+
+		var actual dockerConfig
+		require.NoError(t, json.Unmarshal(data[DockerConfigJSON], &actual))
+		assert.Equal(t, "platform-token", actual.Auths[testAPIURLHost].Password)
+	})
+	t.Run("oauth client can't be used as registry password", func(t *testing.T) {
+		r := &Reconciler{
+			dk: dk,
+			tokens: token.Tokens{
+				dtclient.OAuthClientID:     &token.Token{Value: "client-id"},
+				dtclient.OAuthClientSecret: &token.Token{Value: "client-secret"},
+			},
+		}
+
+		_, err := r.generateData()
+		require.Error(t, err)
+	})
+}
@@ -68,6 +68,8 @@ func (dynatraceClientBuilder builder) Build(ctx context.Context) (dtclient.Clien
 		return nil, errors.WithStack(err)
 	}
 
+	opts.appendPlatformAuth(dynatraceClientBuilder.getTokens())
+
 	apiToken := dynatraceClientBuilder.getTokens().APIToken().Value
 	paasToken := dynatraceClientBuilder.getTokens().PaasToken().Value
 
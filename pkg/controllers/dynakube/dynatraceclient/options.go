@@ -5,6 +5,8 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/pac"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,12 +36,21 @@ func (opts *options) appendHostGroup(hostGroup string) {
 	}
 }
 
+func (opts *options) appendPlatformAuth(tokens token.Tokens) {
+	switch {
+	case tokens.PlatformToken().Value != "":
+		opts.Opts = append(opts.Opts, dtclient.PlatformTokenAuth(tokens.PlatformToken().Value, tokens.OAuthTokenURL().Value))
+	case tokens.HasOAuthClient():
+		opts.Opts = append(opts.Opts, dtclient.OAuthClientAuth(tokens.OAuthClientID().Value, tokens.OAuthClientSecret().Value, tokens.OAuthTokenURL().Value))
+	}
+}
+
 func (opts *options) appendCertCheck(skipCertCheck bool) {
 	opts.Opts = append(opts.Opts, dtclient.SkipCertificateValidation(skipCertCheck))
 }
 
 func (opts *options) appendProxySettings(apiReader client.Reader, dk *dynakube.DynaKube) error {
-	if dk == nil || !dk.HasProxy() {
+	if dk == nil || !dk.HasComponentProxy(dynakube.OperatorProxyComponent) {
 		return nil
 	}
 
@@ -56,7 +67,11 @@ func (opts *options) appendProxySettings(apiReader client.Reader, dk *dynakube.D
 func (opts *options) createProxyOption(apiReader client.Reader, dk *dynakube.DynaKube) (dtclient.Option, error) {
 	var proxyOption dtclient.Option
 
-	proxyURL, err := dk.Proxy(opts.ctx, apiReader)
+	if dk.ProxyPACConfigMap() != "" {
+		return opts.createPACProxyOption(apiReader, dk)
+	}
+
+	proxyURL, err := dk.ComponentProxy(opts.ctx, apiReader, dynakube.OperatorProxyComponent)
 	if err != nil {
 		return proxyOption, err
 	}
@@ -66,6 +81,25 @@ func (opts *options) createProxyOption(apiReader client.Reader, dk *dynakube.Dyn
 	return proxyOption, nil
 }
 
+func (opts *options) createPACProxyOption(apiReader client.Reader, dk *dynakube.DynaKube) (dtclient.Option, error) {
+	rawScript, err := dk.ProxyPAC(opts.ctx, apiReader)
+	if err != nil {
+		return nil, err
+	}
+
+	script, err := pac.Parse(rawScript)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := dk.ProxyCredentials(opts.ctx, apiReader, dynakube.OperatorProxyComponent)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtclient.PACProxy(script, credentials), nil
+}
+
 func (opts *options) appendTrustedCerts(apiReader client.Reader, trustedCerts string, namespace string) error {
 	if trustedCerts != "" {
 		certs := &corev1.ConfigMap{}
@@ -114,6 +114,42 @@ func TestOptions(t *testing.T) {
 		require.Error(t, err)
 		assert.Empty(t, opts.Opts)
 	})
+	t.Run("AppendProxySettings uses the proxy auto-config of the operator", func(t *testing.T) {
+		dk := createTestDynakubeWithProxy(value.Source{Value: testValue})
+		dk.Spec.Proxies = &dynakube.ProxiesSpec{Operator: &dynakube.ComponentProxySpec{PACConfigMap: testName}}
+
+		opts := newOptions(context.Background())
+		err := opts.appendProxySettings(fake.NewClient(), dk)
+
+		require.Error(t, err)
+		assert.Empty(t, opts.Opts)
+
+		fakeClient := fake.NewClient(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					dynakube.ProxyPACKey: `function FindProxyForURL(url, host) { return "PROXY proxy:3128"; }`,
+				},
+			})
+		opts = newOptions(context.Background())
+		err = opts.appendProxySettings(fakeClient, dk)
+
+		require.NoError(t, err)
+		assert.Len(t, opts.Opts, 1)
+	})
+	t.Run("AppendProxySettings skips disabled operator proxy", func(t *testing.T) {
+		dk := createTestDynakubeWithProxy(value.Source{Value: testValue})
+		dk.Spec.Proxies = &dynakube.ProxiesSpec{Operator: &dynakube.ComponentProxySpec{Disabled: true}}
+
+		opts := newOptions(context.Background())
+		err := opts.appendProxySettings(nil, dk)
+
+		require.NoError(t, err)
+		assert.Empty(t, opts.Opts)
+	})
 	t.Run("Test append trusted certificates", func(t *testing.T) {
 		opts := newOptions(context.Background())
 
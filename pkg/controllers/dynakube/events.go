@@ -0,0 +1,52 @@
+package dynakube
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/version"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// sendStatusChangeEvents compares the status before and after the reconciliation and records an event for every relevant change.
+func (controller *Controller) sendStatusChangeEvents(dk *dynakube.DynaKube, oldStatus dynakube.DynaKubeStatus) {
+	controller.sendVersionChangedEvent(dk, "OneAgent", oldStatus.OneAgent.VersionStatus, dk.Status.OneAgent.VersionStatus)
+	controller.sendVersionChangedEvent(dk, "ActiveGate", oldStatus.ActiveGate.VersionStatus, dk.Status.ActiveGate.VersionStatus)
+	controller.sendVersionChangedEvent(dk, "CodeModules", oldStatus.CodeModules.VersionStatus, dk.Status.CodeModules.VersionStatus)
+
+	for _, condition := range dk.Status.Conditions {
+		oldCondition := meta.FindStatusCondition(oldStatus.Conditions, condition.Type)
+		if oldCondition != nil && oldCondition.Reason == condition.Reason && oldCondition.Message == condition.Message {
+			continue
+		}
+
+		switch condition.Reason {
+		case version.DowngradeReason:
+			controller.eventRecorder.SendDowngradeDetectedEvent(dk, condition.Type, condition.Message)
+		case conditions.OptionalScopeMissingReason:
+			controller.eventRecorder.SendOptionalScopeMissingEvent(dk, condition.Message)
+		case dynakube.ReasonTokenRotationInProgress, dynakube.ReasonTokenRotationCompleted:
+			controller.eventRecorder.SendTokenRotationEvent(dk, condition.Message)
+		}
+	}
+}
+
+func (controller *Controller) sendVersionChangedEvent(dk *dynakube.DynaKube, component string, oldVersion, newVersion status.VersionStatus) {
+	if newVersion.ImageID == "" && newVersion.Version == "" {
+		return
+	}
+
+	if oldVersion.ImageID == newVersion.ImageID && oldVersion.Version == newVersion.Version {
+		return
+	}
+
+	controller.eventRecorder.SendVersionChangedEvent(dk, component, versionForEvent(oldVersion), versionForEvent(newVersion))
+}
+
+func versionForEvent(versionStatus status.VersionStatus) string {
+	if versionStatus.Version == "" || versionStatus.Source == status.CustomImageVersionSource {
+		return versionStatus.ImageID
+	}
+
+	return versionStatus.Version
+}
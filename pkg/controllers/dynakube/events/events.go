@@ -0,0 +1,68 @@
+package events
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	reconcileFailedEvent          = "ReconcileFailed"
+	componentReconcileFailedEvent = "ComponentReconcileFailed"
+	tokenVerificationFailedEvent  = "TokenVerificationFailed"
+	optionalScopeMissingEvent     = "OptionalScopeMissing"
+	versionChangedEvent           = "VersionChanged"
+	downgradeDetectedEvent        = "DowngradeDetected"
+	tokenRotationEvent            = "TokenRotation"
+)
+
+type EventRecorder struct {
+	recorder record.EventRecorder
+}
+
+func NewRecorder(recorder record.EventRecorder) EventRecorder {
+	return EventRecorder{recorder: recorder}
+}
+
+func (er EventRecorder) SendReconcileFailedEvent(dk *dynakube.DynaKube, err error) {
+	er.eventf(dk, corev1.EventTypeWarning, reconcileFailedEvent, "Reconciling the DynaKube failed: %s", err.Error())
+}
+
+func (er EventRecorder) SendComponentReconcileFailedEvent(dk *dynakube.DynaKube, component string, err error) {
+	er.eventf(dk, corev1.EventTypeWarning, componentReconcileFailedEvent, "Reconciling %s failed: %s", component, err.Error())
+}
+
+func (er EventRecorder) SendTokenVerificationFailedEvent(dk *dynakube.DynaKube, err error) {
+	er.eventf(dk, corev1.EventTypeWarning, tokenVerificationFailedEvent, "Verifying the tokens in secret %s failed: %s", dk.Tokens(), err.Error())
+}
+
+func (er EventRecorder) SendOptionalScopeMissingEvent(dk *dynakube.DynaKube, message string) {
+	er.eventf(dk, corev1.EventTypeWarning, optionalScopeMissingEvent, "%s", message)
+}
+
+func (er EventRecorder) SendVersionChangedEvent(dk *dynakube.DynaKube, component, previousVersion, newVersion string) {
+	if previousVersion == "" {
+		er.eventf(dk, corev1.EventTypeNormal, versionChangedEvent, "Version of %s set to %s", component, newVersion)
+
+		return
+	}
+
+	er.eventf(dk, corev1.EventTypeNormal, versionChangedEvent, "Version of %s changed from %s to %s", component, previousVersion, newVersion)
+}
+
+func (er EventRecorder) SendDowngradeDetectedEvent(dk *dynakube.DynaKube, component, message string) {
+	er.eventf(dk, corev1.EventTypeWarning, downgradeDetectedEvent, "%s: %s", component, message)
+}
+
+func (er EventRecorder) SendTokenRotationEvent(dk *dynakube.DynaKube, message string) {
+	er.eventf(dk, corev1.EventTypeNormal, tokenRotationEvent, "%s", message)
+}
+
+// eventf records the event, if no recorder was provided (e.g.: in unit tests) the event is dropped.
+func (er EventRecorder) eventf(dk *dynakube.DynaKube, eventType, reason, messageFmt string, args ...any) {
+	if er.recorder == nil {
+		return
+	}
+
+	er.recorder.Eventf(dk, eventType, reason, messageFmt, args...)
+}
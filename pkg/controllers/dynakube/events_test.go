@@ -0,0 +1,113 @@
+package dynakube
+
+import (
+	"context"
+	goerrors "errors"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/events"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/version"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSendStatusChangeEvents(t *testing.T) {
+	newDynakube := func() *dynakube.DynaKube {
+		return &dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace}}
+	}
+
+	t.Run("version change is recorded", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		controller := &Controller{eventRecorder: events.NewRecorder(recorder)}
+		dk := newDynakube()
+		oldStatus := *dk.Status.DeepCopy()
+		oldStatus.OneAgent.Version = "1.0.0"
+		dk.Status.OneAgent.Version = "1.1.0"
+
+		controller.sendStatusChangeEvents(dk, oldStatus)
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Version of OneAgent changed from 1.0.0 to 1.1.0")
+	})
+	t.Run("custom image change is recorded with image", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		controller := &Controller{eventRecorder: events.NewRecorder(recorder)}
+		dk := newDynakube()
+		dk.Status.ActiveGate.Source = status.CustomImageVersionSource
+		dk.Status.ActiveGate.ImageID = "registry/activegate:custom"
+
+		controller.sendStatusChangeEvents(dk, dynakube.DynaKubeStatus{})
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Version of ActiveGate set to registry/activegate:custom")
+	})
+	t.Run("downgrade is only recorded once", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		controller := &Controller{eventRecorder: events.NewRecorder(recorder)}
+		dk := newDynakube()
+		dk.Status.Conditions = []metav1.Condition{{Type: "OneAgentVersion", Reason: version.DowngradeReason, Message: testMessage}}
+
+		controller.sendStatusChangeEvents(dk, dynakube.DynaKubeStatus{})
+		controller.sendStatusChangeEvents(dk, *dk.Status.DeepCopy())
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "DowngradeDetected")
+	})
+	t.Run("unchanged status records nothing", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		controller := &Controller{eventRecorder: events.NewRecorder(recorder)}
+		dk := newDynakube()
+		dk.Status.OneAgent.Version = "1.0.0"
+
+		controller.sendStatusChangeEvents(dk, *dk.Status.DeepCopy())
+
+		assert.Empty(t, recorder.Events)
+	})
+}
+
+func TestHandleErrorEvents(t *testing.T) {
+	setupController := func(t *testing.T) (*Controller, *dynakube.DynaKube, *record.FakeRecorder) {
+		t.Helper()
+
+		recorder := record.NewFakeRecorder(10)
+		dk := &dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace}}
+		fakeClient := fake.NewClientWithIndex(dk)
+		controller := &Controller{
+			client:        fakeClient,
+			apiReader:     fakeClient,
+			eventRecorder: events.NewRecorder(recorder),
+		}
+
+		return controller, dk, recorder
+	}
+
+	t.Run("general error => ReconcileFailed event", func(t *testing.T) {
+		controller, dk, recorder := setupController(t)
+
+		_, err := controller.handleError(context.Background(), dk, errors.New("BOOM"), dk.Status)
+		require.Error(t, err)
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "ReconcileFailed Reconciling the DynaKube failed: BOOM")
+	})
+	t.Run("component error => only ComponentReconcileFailed event", func(t *testing.T) {
+		controller, dk, recorder := setupController(t)
+
+		err := controller.reconcileComponent(dk, oneAgentComponent, func() error {
+			return errors.New("BOOM")
+		})
+		require.Error(t, err)
+
+		_, err = controller.handleError(context.Background(), dk, goerrors.Join(err), dk.Status)
+		require.EqualError(t, err, "BOOM")
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "ComponentReconcileFailed Reconciling "+oneAgentComponent+" failed: BOOM")
+	})
+}
@@ -13,9 +13,11 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/communication"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/image"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	eecConsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/extension/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/proxy"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/node"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/topology"
@@ -293,6 +295,29 @@ func TestEnvironmentVariables(t *testing.T) {
 		assert.Contains(t, statefulSet.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: envHTTPSPrivKeyPathPem, Value: envEecHTTPSPrivKeyPathPem})
 	})
 
+	t.Run("environment variables with proxy override", func(t *testing.T) {
+		dk := getTestDynakube()
+		dk.Spec.Proxy = &value.Source{Value: "http://proxy.com:8888"}
+
+		statefulSet := getStatefulset(t, dk)
+
+		for _, env := range statefulSet.Spec.Template.Spec.Containers[0].Env {
+			assert.NotEqual(t, envHTTPSProxy, env.Name)
+		}
+
+		dk.Spec.Proxies = &dynakube.ProxiesSpec{ExtensionExecutionController: &dynakube.ComponentProxySpec{URL: &value.Source{Value: "http://eec.proxy.com:8888"}}}
+
+		statefulSet = getStatefulset(t, dk)
+
+		assert.Contains(t, statefulSet.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: envHTTPSProxy, ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: proxy.BuildComponentSecretName(dk.Name, dynakube.EECProxyComponent)},
+				Key:                  dynakube.ProxyKey,
+			},
+		}})
+		assert.Contains(t, statefulSet.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: envNoProxy, Value: buildActiveGateServiceName(dk) + "." + dk.Namespace})
+	})
+
 	t.Run("environment variables with custom EEC config", func(t *testing.T) {
 		dk := getTestDynakube()
 		dk.Spec.Templates.ExtensionExecutionController.CustomConfig = "abc"
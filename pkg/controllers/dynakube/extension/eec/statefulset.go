@@ -9,6 +9,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
 	eecConsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/extension/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/proxy"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
@@ -44,6 +45,8 @@ const (
 	envDSTokenPath                  = "DSTokenPath"
 	envRuntimeConfigMountPath       = "RuntimeConfigMountPath"
 	envCustomCertificateMountPath   = "ExtensionCustomCertificateMountPath"
+	envHTTPSProxy                   = "https_proxy"
+	envNoProxy                      = "no_proxy"
 	// Env variable values
 	envExtensionsModuleExecPath = "/opt/dynatrace/remotepluginmodule/agent/lib64/extensionsmodule"
 	envDsInstallDir             = "/opt/dynatrace/remotepluginmodule/agent/datasources"
@@ -261,6 +264,19 @@ func buildContainerEnvs(dk *dynakube.DynaKube) []corev1.EnvVar {
 		containerEnvs = append(containerEnvs, corev1.EnvVar{Name: envCustomCertificateMountPath, Value: customCertificateMountPath})
 	}
 
+	// the EEC only uses a proxy with an override, as it reaches the Dynatrace cluster through the in-cluster ActiveGate
+	if dk.HasComponentProxy(dynakube.EECProxyComponent) {
+		containerEnvs = append(containerEnvs,
+			corev1.EnvVar{Name: envHTTPSProxy, ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: proxy.BuildComponentSecretName(dk.Name, dynakube.EECProxyComponent)},
+					Key:                  dynakube.ProxyKey,
+				},
+			}},
+			corev1.EnvVar{Name: envNoProxy, Value: buildActiveGateServiceName(dk) + "." + dk.Namespace},
+		)
+	}
+
 	return containerEnvs
 }
 
@@ -96,6 +96,10 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	if err := r.reconcileStorageBudgetCondition(ctx); err != nil {
+		log.Error(err, "failed to reconcile the CSI storage budget condition")
+	}
+
 	namespaces, err := mapper.GetNamespacesForDynakube(ctx, r.apiReader, r.dk.Name)
 	if err != nil {
 		return err
@@ -109,6 +113,10 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		setupErrors = append(setupErrors, err)
 	}
 
+	if err := istio.ReconcileSidecars(ctx, r.client, r.apiReader, r.dk, namespaces); err != nil {
+		setupErrors = append(setupErrors, err)
+	}
+
 	if len(setupErrors) > 0 {
 		return goerrors.Join(setupErrors...)
 	}
@@ -0,0 +1,106 @@
+package injection
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// storageBudgetEventMaxAge is a multiple of the requeue duration of the CSI provisioners, which repeat the event on every reconcile,
+	// so a node, which didn't repeat it in time, fits into its storage budget again.
+	storageBudgetEventMaxAge = 15 * time.Minute
+
+	// maxStorageBudgetNodesInMessage keeps the condition readable on large clusters
+	maxStorageBudgetNodesInMessage = 10
+)
+
+// reconcileStorageBudgetCondition aggregates the StorageBudgetExceeded events, sent by the CSI provisioners of the nodes, into one condition.
+// The provisioners only send events, as each of them updating the status of the DynaKube would race the status update of the operator.
+func (r *Reconciler) reconcileStorageBudgetCondition(ctx context.Context) error {
+	if !r.dk.OneAgent().IsAppInjectionNeeded() || !r.dk.OneAgent().IsCSIAvailable() {
+		meta.RemoveStatusCondition(r.dk.Conditions(), dynakube.CSIStorageBudgetConditionType)
+
+		return nil
+	}
+
+	eventList := &corev1.EventList{}
+
+	err := r.apiReader.List(ctx, eventList, client.InNamespace(r.dk.Namespace))
+	if err != nil {
+		return errors.WithMessage(err, "failed to list the events of the CSI provisioners")
+	}
+
+	nodes := r.getNodesExceedingStorageBudget(eventList.Items)
+	if len(nodes) == 0 {
+		meta.RemoveStatusCondition(r.dk.Conditions(), dynakube.CSIStorageBudgetConditionType)
+
+		return nil
+	}
+
+	setStorageBudgetExceededCondition(r.dk.Conditions(), nodes)
+
+	return nil
+}
+
+func (r *Reconciler) getNodesExceedingStorageBudget(events []corev1.Event) []string {
+	var nodes []string
+
+	for _, event := range events {
+		if event.Reason != dynakube.ReasonStorageBudgetExceeded ||
+			event.InvolvedObject.Kind != "DynaKube" ||
+			event.InvolvedObject.Name != r.dk.Name ||
+			event.InvolvedObject.UID != r.dk.UID {
+			continue
+		}
+
+		if time.Since(lastOccurrence(event)) > storageBudgetEventMaxAge {
+			continue
+		}
+
+		if node := event.Annotations[api.AnnotationNodeName]; node != "" {
+			nodes = append(nodes, node)
+		}
+	}
+
+	slices.Sort(nodes)
+
+	return slices.Compact(nodes)
+}
+
+func lastOccurrence(event corev1.Event) time.Time {
+	if event.Series != nil {
+		return event.Series.LastObservedTime.Time
+	}
+
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+
+	return event.EventTime.Time
+}
+
+func setStorageBudgetExceededCondition(conditions *[]metav1.Condition, nodes []string) {
+	nodeList := strings.Join(nodes, ", ")
+	if len(nodes) > maxStorageBudgetNodesInMessage {
+		nodeList = fmt.Sprintf("%s and %d more", strings.Join(nodes[:maxStorageBudgetNodesInMessage], ", "), len(nodes)-maxStorageBudgetNodesInMessage)
+	}
+
+	condition := metav1.Condition{
+		Type:    dynakube.CSIStorageBudgetConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  dynakube.ReasonStorageBudgetExceeded,
+		Message: fmt.Sprintf("CodeModules not installed on %d node(s), as they would exceed the storage budget of the CSI driver: %s", len(nodes), nodeList),
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
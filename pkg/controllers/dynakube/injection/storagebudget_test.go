@@ -0,0 +1,115 @@
+package injection
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func createStorageBudgetEvent(dynakubeName, node string, age time.Duration) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s.%s", dynakubeName, node),
+			Namespace:   testNamespaceDynatrace,
+			Annotations: map[string]string{api.AnnotationNodeName: node},
+		},
+		InvolvedObject: corev1.ObjectReference{Kind: "DynaKube", Name: dynakubeName, Namespace: testNamespaceDynatrace},
+		Reason:         dynakube.ReasonStorageBudgetExceeded,
+		Type:           corev1.EventTypeWarning,
+		LastTimestamp:  metav1.NewTime(time.Now().Add(-age)),
+	}
+}
+
+func TestReconcileStorageBudgetCondition(t *testing.T) {
+	cloudNativeSpec := oneagent.Spec{CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{}}
+
+	t.Run("recent events of the nodes => aggregated into one condition", func(t *testing.T) {
+		clt := fake.NewClient(
+			createStorageBudgetEvent(testDynakube, "node-b", time.Minute),
+			createStorageBudgetEvent(testDynakube, "node-a", 2*time.Minute),
+			createStorageBudgetEvent(testDynakube, "node-old", storageBudgetEventMaxAge+time.Minute),
+			createStorageBudgetEvent(testDynakube2, "node-other", time.Minute),
+		)
+		rec := createReconciler(clt, testDynakube, testNamespaceDynatrace, cloudNativeSpec)
+
+		err := rec.reconcileStorageBudgetCondition(context.Background())
+		require.NoError(t, err)
+
+		condition := meta.FindStatusCondition(*rec.dk.Conditions(), dynakube.CSIStorageBudgetConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, dynakube.ReasonStorageBudgetExceeded, condition.Reason)
+		assert.Contains(t, condition.Message, "2 node(s)")
+		assert.Contains(t, condition.Message, "node-a, node-b")
+		assert.NotContains(t, condition.Message, "node-old")
+		assert.NotContains(t, condition.Message, "node-other")
+	})
+	t.Run("many nodes => message is truncated", func(t *testing.T) {
+		var events []client.Object
+		for i := range maxStorageBudgetNodesInMessage + 2 {
+			events = append(events, createStorageBudgetEvent(testDynakube, fmt.Sprintf("node-%02d", i), time.Minute))
+		}
+
+		rec := createReconciler(fake.NewClient(events...), testDynakube, testNamespaceDynatrace, cloudNativeSpec)
+
+		err := rec.reconcileStorageBudgetCondition(context.Background())
+		require.NoError(t, err)
+
+		condition := meta.FindStatusCondition(*rec.dk.Conditions(), dynakube.CSIStorageBudgetConditionType)
+		require.NotNil(t, condition)
+		assert.Contains(t, condition.Message, "node-09 and 2 more")
+	})
+	t.Run("no recent events => condition removed", func(t *testing.T) {
+		clt := fake.NewClient(createStorageBudgetEvent(testDynakube, "node-old", storageBudgetEventMaxAge+time.Minute))
+		rec := createReconciler(clt, testDynakube, testNamespaceDynatrace, cloudNativeSpec)
+		setStorageBudgetExceededCondition(rec.dk.Conditions(), []string{"node-old"})
+
+		err := rec.reconcileStorageBudgetCondition(context.Background())
+		require.NoError(t, err)
+
+		assert.Nil(t, meta.FindStatusCondition(*rec.dk.Conditions(), dynakube.CSIStorageBudgetConditionType))
+	})
+	t.Run("no app injection => condition removed, events not listed", func(t *testing.T) {
+		clt := fake.NewClientWithInterceptors(interceptor.Funcs{
+			List: func(_ context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
+				t.Fatal("events must not be listed")
+
+				return nil
+			},
+		})
+		rec := createReconciler(clt, testDynakube, testNamespaceDynatrace, oneagent.Spec{ClassicFullStack: &oneagent.HostInjectSpec{}})
+		setStorageBudgetExceededCondition(rec.dk.Conditions(), []string{"node-a"})
+
+		err := rec.reconcileStorageBudgetCondition(context.Background())
+		require.NoError(t, err)
+
+		assert.Nil(t, meta.FindStatusCondition(*rec.dk.Conditions(), dynakube.CSIStorageBudgetConditionType))
+	})
+	t.Run("listing events fails => condition kept", func(t *testing.T) {
+		clt := fake.NewClientWithInterceptors(interceptor.Funcs{
+			List: func(_ context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
+				return assert.AnError
+			},
+		})
+		rec := createReconciler(clt, testDynakube, testNamespaceDynatrace, cloudNativeSpec)
+		setStorageBudgetExceededCondition(rec.dk.Conditions(), []string{"node-a"})
+
+		err := rec.reconcileStorageBudgetCondition(context.Background())
+		require.Error(t, err)
+
+		assert.NotNil(t, meta.FindStatusCondition(*rec.dk.Conditions(), dynakube.CSIStorageBudgetConditionType))
+	})
+}
@@ -10,6 +10,7 @@ import (
 	istioclientset "istio.io/client-go/pkg/clientset/versioned"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -142,6 +143,20 @@ func (cl *Client) DeleteVirtualService(ctx context.Context, name string) error {
 	return nil
 }
 
+// ListVirtualServices lists the virtual services matching the labels in the namespace of the owner.
+func (cl *Client) ListVirtualServices(ctx context.Context, matchLabels map[string]string) ([]*istiov1beta1.VirtualService, error) {
+	virtualServiceList, err := cl.IstioClientset.NetworkingV1beta1().
+		VirtualServices(cl.Owner.GetNamespace()).
+		List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(matchLabels).String()})
+	if err != nil {
+		log.Info("failed to list virtual services", "error", err.Error())
+
+		return nil, errors.WithStack(err)
+	}
+
+	return virtualServiceList.Items, nil
+}
+
 func (cl *Client) GetServiceEntry(ctx context.Context, name string) (*istiov1beta1.ServiceEntry, error) {
 	serviceEntry, err := cl.IstioClientset.NetworkingV1beta1().ServiceEntries(cl.Owner.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
 	if k8serrors.IsNotFound(err) {
@@ -232,3 +247,167 @@ func (cl *Client) DeleteServiceEntry(ctx context.Context, name string) error {
 
 	return nil
 }
+
+// ListServiceEntries lists the service entries matching the labels in the namespace of the owner.
+func (cl *Client) ListServiceEntries(ctx context.Context, matchLabels map[string]string) ([]*istiov1beta1.ServiceEntry, error) {
+	serviceEntryList, err := cl.IstioClientset.NetworkingV1beta1().
+		ServiceEntries(cl.Owner.GetNamespace()).
+		List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(matchLabels).String()})
+	if err != nil {
+		log.Info("failed to list service entries", "error", err.Error())
+
+		return nil, errors.WithStack(err)
+	}
+
+	return serviceEntryList.Items, nil
+}
+
+func (cl *Client) GetGateway(ctx context.Context, name string) (*istiov1beta1.Gateway, error) {
+	gateway, err := cl.IstioClientset.NetworkingV1beta1().Gateways(cl.Owner.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil //nolint:nilnil
+	} else if err != nil {
+		log.Info("failed to get current gateway", "name", name, "error", err.Error())
+
+		return nil, errors.WithStack(err)
+	}
+
+	return gateway, nil
+}
+
+func (cl *Client) CreateOrUpdateGateway(ctx context.Context, newGateway *istiov1beta1.Gateway) error {
+	if newGateway == nil {
+		return errors.New("can't create gateway based on nil object")
+	}
+
+	// the owner reference is created before the hash annotation is added
+	if err := controllerutil.SetControllerReference(cl.Owner, newGateway, scheme.Scheme); err != nil {
+		return errors.WithStack(err)
+	}
+
+	delete(newGateway.Annotations, hasher.AnnotationHash)
+
+	err := hasher.AddAnnotation(newGateway)
+	if err != nil {
+		return errors.WithMessage(err, "failed to generate and hash annotation for gateway")
+	}
+
+	oldGateway, err := cl.GetGateway(ctx, newGateway.Name)
+	if err != nil {
+		return err
+	}
+
+	if oldGateway == nil {
+		_, err = cl.IstioClientset.NetworkingV1beta1().Gateways(cl.Owner.GetNamespace()).Create(ctx, newGateway, metav1.CreateOptions{})
+		if err != nil {
+			log.Info("failed to create gateway", "name", newGateway.GetName(), "error", err.Error())
+
+			return errors.WithStack(err)
+		}
+
+		return nil
+	}
+
+	if !hasher.IsAnnotationDifferent(oldGateway, newGateway) {
+		return nil
+	}
+
+	newGateway.ResourceVersion = oldGateway.ResourceVersion
+
+	_, err = cl.IstioClientset.NetworkingV1beta1().Gateways(cl.Owner.GetNamespace()).Update(ctx, newGateway, metav1.UpdateOptions{})
+	if err != nil {
+		log.Info("failed to update gateway", "name", newGateway.GetName(), "error", err.Error())
+
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func (cl *Client) DeleteGateway(ctx context.Context, name string) error {
+	err := cl.IstioClientset.NetworkingV1beta1().
+		Gateways(cl.Owner.GetNamespace()).
+		Delete(ctx, name, metav1.DeleteOptions{})
+	if !k8serrors.IsNotFound(err) {
+		log.Info("failed to remove gateway", "name", name)
+
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func (cl *Client) GetDestinationRule(ctx context.Context, name string) (*istiov1beta1.DestinationRule, error) {
+	destinationRule, err := cl.IstioClientset.NetworkingV1beta1().DestinationRules(cl.Owner.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil //nolint:nilnil
+	} else if err != nil {
+		log.Info("failed to get current destination rule", "name", name, "error", err.Error())
+
+		return nil, errors.WithStack(err)
+	}
+
+	return destinationRule, nil
+}
+
+func (cl *Client) CreateOrUpdateDestinationRule(ctx context.Context, newDestinationRule *istiov1beta1.DestinationRule) error {
+	if newDestinationRule == nil {
+		return errors.New("can't create destination rule based on nil object")
+	}
+
+	// the owner reference is created before the hash annotation is added
+	if err := controllerutil.SetControllerReference(cl.Owner, newDestinationRule, scheme.Scheme); err != nil {
+		return errors.WithStack(err)
+	}
+
+	delete(newDestinationRule.Annotations, hasher.AnnotationHash)
+
+	err := hasher.AddAnnotation(newDestinationRule)
+	if err != nil {
+		return errors.WithMessage(err, "failed to generate and hash annotation for destination rule")
+	}
+
+	oldDestinationRule, err := cl.GetDestinationRule(ctx, newDestinationRule.Name)
+	if err != nil {
+		return err
+	}
+
+	if oldDestinationRule == nil {
+		_, err = cl.IstioClientset.NetworkingV1beta1().DestinationRules(cl.Owner.GetNamespace()).Create(ctx, newDestinationRule, metav1.CreateOptions{})
+		if err != nil {
+			log.Info("failed to create destination rule", "name", newDestinationRule.GetName(), "error", err.Error())
+
+			return errors.WithStack(err)
+		}
+
+		return nil
+	}
+
+	if !hasher.IsAnnotationDifferent(oldDestinationRule, newDestinationRule) {
+		return nil
+	}
+
+	newDestinationRule.ResourceVersion = oldDestinationRule.ResourceVersion
+
+	_, err = cl.IstioClientset.NetworkingV1beta1().DestinationRules(cl.Owner.GetNamespace()).Update(ctx, newDestinationRule, metav1.UpdateOptions{})
+	if err != nil {
+		log.Info("failed to update destination rule", "name", newDestinationRule.GetName(), "error", err.Error())
+
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func (cl *Client) DeleteDestinationRule(ctx context.Context, name string) error {
+	err := cl.IstioClientset.NetworkingV1beta1().
+		DestinationRules(cl.Owner.GetNamespace()).
+		Delete(ctx, name, metav1.DeleteOptions{})
+	if !k8serrors.IsNotFound(err) {
+		log.Info("failed to remove destination rule", "name", name)
+
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
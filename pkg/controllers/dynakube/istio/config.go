@@ -15,6 +15,7 @@ const (
 	OneAgentComponent   = "oneagent"
 	CodeModuleComponent = "CodeModule"
 	ActiveGateComponent = "ActiveGate"
+	SidecarComponent    = "istio-sidecar"
 	IstioGVRName        = "networking.istio.io"
 	IstioGVRVersion     = "v1beta1"
 )
@@ -0,0 +1,320 @@
+package istio
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	istio "istio.io/api/networking/v1beta1"
+	istiov1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	meshGateway                 = "mesh"
+	egressGatewaySubset         = "dynatrace"
+	egressGatewayIPSubsetPrefix = "ip-"
+	egressGatewayComponent      = "egress-gateway"
+	gatewayProtocolTLS          = "TLS"
+	gatewayProtocolHTTP         = "HTTP"
+	gatewayPortNameMutualTLS    = "mtls"
+	clusterServiceSuffix        = ".svc.cluster.local"
+)
+
+// BuildNameForEgressGateway returns the name of the Gateway of a component.
+func BuildNameForEgressGateway(ownerName, component string) string {
+	return ownerName + "-" + egressGatewayComponent + "-" + component
+}
+
+// BuildNameForEgressGatewayDestinationRule returns the name of the DestinationRule, which is shared by all components of the owner,
+// as Istio only considers one DestinationRule per host.
+func BuildNameForEgressGatewayDestinationRule(ownerName string) string {
+	return ownerName + "-" + egressGatewayComponent
+}
+
+// BuildNameForEgressGatewayIPHost returns the name of the ServiceEntry and VirtualService, which route an IP host through the egress gateway.
+func BuildNameForEgressGatewayIPHost(ownerName, component, ip string) string {
+	return BuildNameForIPServiceEntry(ownerName, component) + "-" + sanitizeIP(ip)
+}
+
+func buildEgressGatewayComponent(component string) string {
+	return component + "-" + egressGatewayComponent
+}
+
+func buildEgressGatewayHost(egressGateway *dynakube.IstioEgressGatewaySpec) string {
+	return egressGateway.Name + "." + egressGateway.Namespace + clusterServiceSuffix
+}
+
+// buildEgressGatewayIPHost returns a host name for the IP, it is sent as SNI from the sidecars to the egress gateway,
+// so the gateway can tell the IP hosts apart after terminating the mutual TLS.
+func buildEgressGatewayIPHost(ip string) string {
+	return sanitizeIP(ip) + "." + ignoredSubdomain
+}
+
+func buildEgressGatewayIPSubset(ip string) string {
+	return egressGatewayIPSubsetPrefix + sanitizeIP(ip)
+}
+
+// sanitizeIP turns the IP into a DNS label, which can be used in names and hosts.
+func sanitizeIP(ip string) string {
+	label := strings.NewReplacer(".", "-", ":", "-").Replace(ip)
+
+	if strings.HasPrefix(label, "-") {
+		label = "0" + label
+	}
+
+	if strings.HasSuffix(label, "-") {
+		label += "0"
+	}
+
+	return label
+}
+
+// groupIPHostPorts returns the ports of every IP host, sorted by the IP.
+func groupIPHostPorts(ipHosts []dtclient.CommunicationHost) ([]string, map[string][]uint32) {
+	ipPorts := make(map[string][]uint32)
+
+	for _, ipHost := range ipHosts {
+		if !slices.Contains(ipPorts[ipHost.Host], ipHost.Port) {
+			ipPorts[ipHost.Host] = append(ipPorts[ipHost.Host], ipHost.Port)
+		}
+	}
+
+	return slices.Sorted(maps.Keys(ipPorts)), ipPorts
+}
+
+// buildGateway configures the egress gateway to accept the traffic to the communication hosts.
+// The TLS traffic to the FQDN hosts is passed through, so the connection is still terminated by the Dynatrace cluster.
+// The traffic to the IP hosts is wrapped into mutual TLS by the sidecars, as the gateway needs the SNI to route it.
+func buildGateway(meta metav1.ObjectMeta, egressGateway *dynakube.IstioEgressGatewaySpec, fqdnHosts, ipHosts []dtclient.CommunicationHost) *istiov1beta1.Gateway {
+	var servers []*istio.Server
+
+	serverIndex := make(map[string]*istio.Server)
+
+	for _, commHost := range fqdnHosts {
+		var protocol string
+
+		switch commHost.Protocol {
+		case protocolHTTPS:
+			protocol = gatewayProtocolTLS
+		case protocolHTTP:
+			protocol = gatewayProtocolHTTP
+		default:
+			continue
+		}
+
+		portName := commHost.Protocol + "-" + strconv.Itoa(int(commHost.Port))
+		if server, ok := serverIndex[portName]; ok {
+			server.Hosts = append(server.Hosts, commHost.Host)
+
+			continue
+		}
+
+		server := &istio.Server{
+			Port: &istio.Port{
+				Name:     portName,
+				Number:   commHost.Port,
+				Protocol: protocol,
+			},
+			Hosts: []string{commHost.Host},
+		}
+		if protocol == gatewayProtocolTLS {
+			server.Tls = &istio.ServerTLSSettings{Mode: istio.ServerTLSSettings_PASSTHROUGH}
+		}
+
+		serverIndex[portName] = server
+		servers = append(servers, server)
+	}
+
+	ips, ipPorts := groupIPHostPorts(ipHosts)
+
+	for _, ip := range ips {
+		for _, port := range ipPorts[ip] {
+			servers = append(servers, &istio.Server{
+				Port: &istio.Port{
+					Name:     gatewayPortNameMutualTLS + "-" + sanitizeIP(ip) + "-" + strconv.Itoa(int(port)),
+					Number:   port,
+					Protocol: gatewayProtocolTLS,
+				},
+				Hosts: []string{buildEgressGatewayIPHost(ip)},
+				Tls:   &istio.ServerTLSSettings{Mode: istio.ServerTLSSettings_ISTIO_MUTUAL},
+			})
+		}
+	}
+
+	return &istiov1beta1.Gateway{
+		ObjectMeta: meta,
+		Spec: istio.Gateway{
+			Selector: egressGateway.Selector,
+			Servers:  servers,
+		},
+	}
+}
+
+// buildDestinationRule configures the traffic from the sidecars to the egress gateway for all components.
+// The traffic to the FQDN hosts is sent as is, the traffic to every IP host is sent with mutual TLS and the host name of the IP as SNI.
+func buildDestinationRule(meta metav1.ObjectMeta, egressGateway *dynakube.IstioEgressGatewaySpec, ipHosts []dtclient.CommunicationHost) *istiov1beta1.DestinationRule {
+	subsets := []*istio.Subset{{
+		Name: egressGatewaySubset,
+	}}
+
+	ips, ipPorts := groupIPHostPorts(ipHosts)
+
+	for _, ip := range ips {
+		portSettings := make([]*istio.TrafficPolicy_PortTrafficPolicy, 0, len(ipPorts[ip]))
+
+		for _, port := range ipPorts[ip] {
+			portSettings = append(portSettings, &istio.TrafficPolicy_PortTrafficPolicy{
+				Port: &istio.PortSelector{Number: port},
+				Tls: &istio.ClientTLSSettings{
+					Mode: istio.ClientTLSSettings_ISTIO_MUTUAL,
+					Sni:  buildEgressGatewayIPHost(ip),
+				},
+			})
+		}
+
+		subsets = append(subsets, &istio.Subset{
+			Name:          buildEgressGatewayIPSubset(ip),
+			TrafficPolicy: &istio.TrafficPolicy{PortLevelSettings: portSettings},
+		})
+	}
+
+	return &istiov1beta1.DestinationRule{
+		ObjectMeta: meta,
+		Spec: istio.DestinationRule{
+			Host:    buildEgressGatewayHost(egressGateway),
+			Subsets: subsets,
+		},
+	}
+}
+
+// buildEgressGatewayVirtualService routes the traffic of the sidecars to the egress gateway and from the gateway to the communication hosts.
+func buildEgressGatewayVirtualService(meta metav1.ObjectMeta, gatewayName string, egressGateway *dynakube.IstioEgressGatewaySpec, commHosts []dtclient.CommunicationHost) *istiov1beta1.VirtualService {
+	gatewayHost := buildEgressGatewayHost(egressGateway)
+	hosts := make([]string, len(commHosts))
+
+	var (
+		tlses  []*istio.TLSRoute
+		routes []*istio.HTTPRoute
+	)
+
+	for i, commHost := range commHosts {
+		hosts[i] = commHost.Host
+
+		switch commHost.Protocol {
+		case protocolHTTPS:
+			meshRoute := buildVirtualServiceTLSRoute(commHost.Host, commHost.Port)
+			meshRoute.Match[0].Gateways = []string{meshGateway}
+			meshRoute.Route[0].Destination = buildEgressGatewayDestination(gatewayHost, egressGatewaySubset, commHost.Port)
+
+			gatewayRoute := buildVirtualServiceTLSRoute(commHost.Host, commHost.Port)
+			gatewayRoute.Match[0].Gateways = []string{gatewayName}
+
+			tlses = append(tlses, meshRoute, gatewayRoute)
+		case protocolHTTP:
+			meshRoute := buildVirtualServiceHTTPRoute(commHost.Host, commHost.Port)
+			meshRoute.Match[0].Gateways = []string{meshGateway}
+			meshRoute.Route[0].Destination = buildEgressGatewayDestination(gatewayHost, egressGatewaySubset, commHost.Port)
+
+			gatewayRoute := buildVirtualServiceHTTPRoute(commHost.Host, commHost.Port)
+			gatewayRoute.Match[0].Gateways = []string{gatewayName}
+
+			routes = append(routes, meshRoute, gatewayRoute)
+		}
+	}
+
+	return &istiov1beta1.VirtualService{
+		ObjectMeta: meta,
+		Spec: istio.VirtualService{
+			Hosts:    hosts,
+			Gateways: []string{meshGateway, gatewayName},
+			Http:     routes,
+			Tls:      tlses,
+		},
+	}
+}
+
+// buildEgressGatewayIPServiceEntry registers the IP host under its host name, so the gateway can resolve it.
+func buildEgressGatewayIPServiceEntry(meta metav1.ObjectMeta, ip string, ports []uint32) *istiov1beta1.ServiceEntry {
+	servicePorts := make([]*istio.ServicePort, len(ports))
+
+	for i, port := range ports {
+		servicePorts[i] = &istio.ServicePort{
+			Name:     protocolTCP + "-" + strconv.Itoa(int(port)),
+			Number:   port,
+			Protocol: protocolTCP,
+		}
+	}
+
+	return &istiov1beta1.ServiceEntry{
+		ObjectMeta: meta,
+		Spec: istio.ServiceEntry{
+			Hosts:      []string{buildEgressGatewayIPHost(ip)},
+			Addresses:  []string{ip + subnetMask},
+			Ports:      servicePorts,
+			Location:   istio.ServiceEntry_MESH_EXTERNAL,
+			Resolution: istio.ServiceEntry_STATIC,
+			Endpoints:  []*istio.WorkloadEntry{{Address: ip}},
+		},
+	}
+}
+
+// buildEgressGatewayIPVirtualService routes the TCP traffic of the sidecars to the IP host to the egress gateway and from the gateway to the IP host.
+func buildEgressGatewayIPVirtualService(meta metav1.ObjectMeta, gatewayName string, egressGateway *dynakube.IstioEgressGatewaySpec, ip string, ports []uint32) *istiov1beta1.VirtualService {
+	gatewayHost := buildEgressGatewayHost(egressGateway)
+	ipHost := buildEgressGatewayIPHost(ip)
+
+	var routes []*istio.TCPRoute
+
+	for _, port := range ports {
+		meshRoute := &istio.TCPRoute{
+			Match: []*istio.L4MatchAttributes{{
+				Gateways:           []string{meshGateway},
+				DestinationSubnets: []string{ip + subnetMask},
+				Port:               port,
+			}},
+			Route: []*istio.RouteDestination{{
+				Destination: buildEgressGatewayDestination(gatewayHost, buildEgressGatewayIPSubset(ip), port),
+			}},
+		}
+
+		gatewayRoute := &istio.TCPRoute{
+			Match: []*istio.L4MatchAttributes{{
+				Gateways: []string{gatewayName},
+				Port:     port,
+			}},
+			Route: []*istio.RouteDestination{{
+				Destination: &istio.Destination{
+					Host: ipHost,
+					Port: &istio.PortSelector{
+						Number: port,
+					},
+				},
+			}},
+		}
+
+		routes = append(routes, meshRoute, gatewayRoute)
+	}
+
+	return &istiov1beta1.VirtualService{
+		ObjectMeta: meta,
+		Spec: istio.VirtualService{
+			Hosts:    []string{ipHost},
+			Gateways: []string{meshGateway, gatewayName},
+			Tcp:      routes,
+		},
+	}
+}
+
+func buildEgressGatewayDestination(gatewayHost, subset string, port uint32) *istio.Destination {
+	return &istio.Destination{
+		Host:   gatewayHost,
+		Subset: subset,
+		Port: &istio.PortSelector{
+			Number: port,
+		},
+	}
+}
@@ -0,0 +1,332 @@
+package istio
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	istio "istio.io/api/networking/v1beta1"
+	fakeistio "istio.io/client-go/pkg/clientset/versioned/fake"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testEgressGatewayName = "egress-gateway"
+
+func createTestEgressGateway() *dynakube.IstioEgressGatewaySpec {
+	return &dynakube.IstioEgressGatewaySpec{
+		Name:      testEgressGatewayName,
+		Namespace: dynakube.DefaultIstioEgressGatewayNamespace,
+		Selector:  map[string]string{"istio": "egressgateway"},
+	}
+}
+
+func createTestHTTPSCommunicationHost() dtclient.CommunicationHost {
+	return dtclient.CommunicationHost{
+		Protocol: protocolHTTPS,
+		Host:     "tenant.test.io",
+		Port:     443,
+	}
+}
+
+func TestSanitizeIP(t *testing.T) {
+	assert.Equal(t, "42-42-42-42", sanitizeIP("42.42.42.42"))
+	assert.Equal(t, "fd00--1", sanitizeIP("fd00::1"))
+	assert.Equal(t, "fd00--0", sanitizeIP("fd00::"))
+	assert.Equal(t, "0--1", sanitizeIP("::1"))
+}
+
+func TestBuildGateway(t *testing.T) {
+	meta := buildObjectMeta(testName, testNamespace, buildTestLabels())
+
+	t.Run("servers are grouped by port", func(t *testing.T) {
+		otherHTTPSHost := createTestHTTPSCommunicationHost()
+		otherHTTPSHost.Host = "other.test.io"
+
+		gateway := buildGateway(meta, createTestEgressGateway(), []dtclient.CommunicationHost{
+			createTestHTTPSCommunicationHost(),
+			otherHTTPSHost,
+			createTestFQDNCommunicationHost(),
+		}, nil)
+
+		assert.Equal(t, meta, gateway.ObjectMeta)
+		assert.Equal(t, createTestEgressGateway().Selector, gateway.Spec.Selector)
+		require.Len(t, gateway.Spec.Servers, 2)
+
+		tlsServer := gateway.Spec.Servers[0]
+		assert.Equal(t, gatewayProtocolTLS, tlsServer.Port.Protocol)
+		assert.Equal(t, uint32(443), tlsServer.Port.Number)
+		assert.Equal(t, []string{"tenant.test.io", "other.test.io"}, tlsServer.Hosts)
+		assert.Equal(t, istio.ServerTLSSettings_PASSTHROUGH, tlsServer.Tls.Mode)
+
+		httpServer := gateway.Spec.Servers[1]
+		assert.Equal(t, gatewayProtocolHTTP, httpServer.Port.Protocol)
+		assert.Equal(t, []string{createTestFQDNCommunicationHost().Host}, httpServer.Hosts)
+		assert.Nil(t, httpServer.Tls)
+	})
+	t.Run("ip hosts get a mutual tls server each", func(t *testing.T) {
+		ipHost := createTestIPCommunicationHost()
+
+		gateway := buildGateway(meta, createTestEgressGateway(), nil, []dtclient.CommunicationHost{ipHost, ipHost})
+
+		require.Len(t, gateway.Spec.Servers, 1)
+
+		server := gateway.Spec.Servers[0]
+		assert.Equal(t, "mtls-42-42-42-42-620", server.Port.Name)
+		assert.Equal(t, gatewayProtocolTLS, server.Port.Protocol)
+		assert.Equal(t, ipHost.Port, server.Port.Number)
+		assert.Equal(t, []string{"42-42-42-42.ignored.subdomain"}, server.Hosts)
+		assert.Equal(t, istio.ServerTLSSettings_ISTIO_MUTUAL, server.Tls.Mode)
+	})
+}
+
+func TestBuildDestinationRule(t *testing.T) {
+	meta := buildObjectMeta(testName, testNamespace, buildTestLabels())
+	ipHost := createTestIPCommunicationHost()
+	otherPortIPHost := createTestIPCommunicationHost()
+	otherPortIPHost.Port = 443
+
+	destinationRule := buildDestinationRule(meta, createTestEgressGateway(), []dtclient.CommunicationHost{ipHost, otherPortIPHost})
+
+	assert.Equal(t, "egress-gateway.istio-system.svc.cluster.local", destinationRule.Spec.Host)
+	require.Len(t, destinationRule.Spec.Subsets, 2)
+	assert.Equal(t, egressGatewaySubset, destinationRule.Spec.Subsets[0].Name)
+	assert.Nil(t, destinationRule.Spec.Subsets[0].TrafficPolicy)
+
+	ipSubset := destinationRule.Spec.Subsets[1]
+	assert.Equal(t, "ip-42-42-42-42", ipSubset.Name)
+	require.Len(t, ipSubset.TrafficPolicy.PortLevelSettings, 2)
+
+	for i, port := range []uint32{620, 443} {
+		portSettings := ipSubset.TrafficPolicy.PortLevelSettings[i]
+		assert.Equal(t, port, portSettings.Port.Number)
+		assert.Equal(t, istio.ClientTLSSettings_ISTIO_MUTUAL, portSettings.Tls.Mode)
+		assert.Equal(t, "42-42-42-42.ignored.subdomain", portSettings.Tls.Sni)
+	}
+}
+
+func TestBuildEgressGatewayVirtualService(t *testing.T) {
+	const gatewayName = "gateway"
+
+	meta := buildObjectMeta(testName, testNamespace, buildTestLabels())
+	commHosts := []dtclient.CommunicationHost{createTestHTTPSCommunicationHost(), createTestFQDNCommunicationHost()}
+
+	virtualService := buildEgressGatewayVirtualService(meta, gatewayName, createTestEgressGateway(), commHosts)
+
+	assert.Equal(t, []string{meshGateway, gatewayName}, virtualService.Spec.Gateways)
+	assert.Equal(t, []string{"tenant.test.io", createTestFQDNCommunicationHost().Host}, virtualService.Spec.Hosts)
+
+	t.Run("tls traffic of the mesh is sent to the gateway, the gateway sends it to the host", func(t *testing.T) {
+		require.Len(t, virtualService.Spec.Tls, 2)
+
+		meshRoute := virtualService.Spec.Tls[0]
+		assert.Equal(t, []string{meshGateway}, meshRoute.Match[0].Gateways)
+		assert.Equal(t, []string{"tenant.test.io"}, meshRoute.Match[0].SniHosts)
+		assert.Equal(t, buildEgressGatewayHost(createTestEgressGateway()), meshRoute.Route[0].Destination.Host)
+		assert.Equal(t, egressGatewaySubset, meshRoute.Route[0].Destination.Subset)
+
+		gatewayRoute := virtualService.Spec.Tls[1]
+		assert.Equal(t, []string{gatewayName}, gatewayRoute.Match[0].Gateways)
+		assert.Equal(t, "tenant.test.io", gatewayRoute.Route[0].Destination.Host)
+		assert.Equal(t, uint32(443), gatewayRoute.Route[0].Destination.Port.Number)
+	})
+	t.Run("http traffic of the mesh is sent to the gateway, the gateway sends it to the host", func(t *testing.T) {
+		require.Len(t, virtualService.Spec.Http, 2)
+
+		meshRoute := virtualService.Spec.Http[0]
+		assert.Equal(t, []string{meshGateway}, meshRoute.Match[0].Gateways)
+		assert.Equal(t, buildEgressGatewayHost(createTestEgressGateway()), meshRoute.Route[0].Destination.Host)
+
+		gatewayRoute := virtualService.Spec.Http[1]
+		assert.Equal(t, []string{gatewayName}, gatewayRoute.Match[0].Gateways)
+		assert.Equal(t, createTestFQDNCommunicationHost().Host, gatewayRoute.Route[0].Destination.Host)
+	})
+}
+
+func TestBuildEgressGatewayIPHost(t *testing.T) {
+	const gatewayName = "gateway"
+
+	meta := buildObjectMeta(testName, testNamespace, buildTestLabels())
+	ipHost := createTestIPCommunicationHost()
+
+	t.Run("service entry resolves the host name to the ip", func(t *testing.T) {
+		serviceEntry := buildEgressGatewayIPServiceEntry(meta, ipHost.Host, []uint32{ipHost.Port})
+
+		assert.Equal(t, []string{"42-42-42-42.ignored.subdomain"}, serviceEntry.Spec.Hosts)
+		assert.Equal(t, []string{"42.42.42.42/32"}, serviceEntry.Spec.Addresses)
+		assert.Equal(t, istio.ServiceEntry_STATIC, serviceEntry.Spec.Resolution)
+		require.Len(t, serviceEntry.Spec.Endpoints, 1)
+		assert.Equal(t, ipHost.Host, serviceEntry.Spec.Endpoints[0].Address)
+		require.Len(t, serviceEntry.Spec.Ports, 1)
+		assert.Equal(t, protocolTCP, serviceEntry.Spec.Ports[0].Protocol)
+	})
+	t.Run("tcp traffic of the mesh is sent to the gateway, the gateway sends it to the ip", func(t *testing.T) {
+		virtualService := buildEgressGatewayIPVirtualService(meta, gatewayName, createTestEgressGateway(), ipHost.Host, []uint32{ipHost.Port})
+
+		assert.Equal(t, []string{"42-42-42-42.ignored.subdomain"}, virtualService.Spec.Hosts)
+		assert.Equal(t, []string{meshGateway, gatewayName}, virtualService.Spec.Gateways)
+		require.Len(t, virtualService.Spec.Tcp, 2)
+
+		meshRoute := virtualService.Spec.Tcp[0]
+		assert.Equal(t, []string{meshGateway}, meshRoute.Match[0].Gateways)
+		assert.Equal(t, []string{"42.42.42.42/32"}, meshRoute.Match[0].DestinationSubnets)
+		assert.Equal(t, ipHost.Port, meshRoute.Match[0].Port)
+		assert.Equal(t, buildEgressGatewayHost(createTestEgressGateway()), meshRoute.Route[0].Destination.Host)
+		assert.Equal(t, "ip-42-42-42-42", meshRoute.Route[0].Destination.Subset)
+
+		gatewayRoute := virtualService.Spec.Tcp[1]
+		assert.Equal(t, []string{gatewayName}, gatewayRoute.Match[0].Gateways)
+		assert.Equal(t, "42-42-42-42.ignored.subdomain", gatewayRoute.Route[0].Destination.Host)
+		assert.Equal(t, ipHost.Port, gatewayRoute.Route[0].Destination.Port.Number)
+	})
+}
+
+func TestReconcileCommunicationHostsWithEgressGateway(t *testing.T) {
+	ctx := context.Background()
+	component := "best-component"
+	commHosts := []dtclient.CommunicationHost{createTestHTTPSCommunicationHost(), createTestIPCommunicationHost()}
+
+	createEgressGatewayDynaKube := func() *dynakube.DynaKube {
+		dk := createTestDynaKube()
+		dk.Spec.EnableIstio = true
+		dk.Spec.Istio = &dynakube.IstioSpec{EgressGateway: createTestEgressGateway()}
+
+		return dk
+	}
+
+	dk := createEgressGatewayDynaKube()
+	gatewayName := BuildNameForEgressGateway(dk.GetName(), component)
+	fqdnName := BuildNameForFQDNServiceEntry(dk.GetName(), component)
+	ipName := BuildNameForIPServiceEntry(dk.GetName(), component)
+	ipHostName := BuildNameForEgressGatewayIPHost(dk.GetName(), component, createTestIPCommunicationHost().Host)
+	ruleName := BuildNameForEgressGatewayDestinationRule(dk.GetName())
+
+	t.Run("all hosts are routed through the egress gateway", func(t *testing.T) {
+		fakeClient := fakeistio.NewSimpleClientset()
+		reconciler := NewReconciler(newTestingClient(fakeClient, dk.GetNamespace())).(*reconciler)
+
+		err := reconciler.reconcileCommunicationHosts(ctx, dk, commHosts, component)
+		require.NoError(t, err)
+
+		gateway, err := fakeClient.NetworkingV1beta1().Gateways(dk.GetNamespace()).Get(ctx, gatewayName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, gateway.OwnerReferences)
+		assert.Len(t, gateway.Spec.Servers, 2)
+
+		virtualService, err := fakeClient.NetworkingV1beta1().VirtualServices(dk.GetNamespace()).Get(ctx, fqdnName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, virtualService.Spec.Gateways, gatewayName)
+
+		ipVirtualService, err := fakeClient.NetworkingV1beta1().VirtualServices(dk.GetNamespace()).Get(ctx, ipHostName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, ipVirtualService.Spec.Gateways, gatewayName)
+
+		_, err = fakeClient.NetworkingV1beta1().ServiceEntries(dk.GetNamespace()).Get(ctx, ipHostName, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		_, err = fakeClient.NetworkingV1beta1().ServiceEntries(dk.GetNamespace()).Get(ctx, ipName, metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		destinationRule, err := fakeClient.NetworkingV1beta1().DestinationRules(dk.GetNamespace()).Get(ctx, ruleName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, destinationRule.OwnerReferences)
+		assert.Len(t, destinationRule.Spec.Subsets, 2)
+	})
+	t.Run("destination rule is shared by the components", func(t *testing.T) {
+		fakeClient := fakeistio.NewSimpleClientset()
+		reconciler := NewReconciler(newTestingClient(fakeClient, dk.GetNamespace())).(*reconciler)
+
+		require.NoError(t, reconciler.reconcileCommunicationHosts(ctx, dk, commHosts, component))
+		require.NoError(t, reconciler.reconcileCommunicationHosts(ctx, dk, commHosts, "other-component"))
+
+		destinationRules, err := fakeClient.NetworkingV1beta1().DestinationRules(dk.GetNamespace()).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, destinationRules.Items, 1)
+
+		gateways, err := fakeClient.NetworkingV1beta1().Gateways(dk.GetNamespace()).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, gateways.Items, 2)
+	})
+	t.Run("ip host removed => its objects are deleted", func(t *testing.T) {
+		fakeClient := fakeistio.NewSimpleClientset()
+		reconciler := NewReconciler(newTestingClient(fakeClient, dk.GetNamespace())).(*reconciler)
+
+		require.NoError(t, reconciler.reconcileCommunicationHosts(ctx, dk, commHosts, component))
+		require.NoError(t, reconciler.reconcileCommunicationHosts(ctx, dk, commHosts[:1], component))
+
+		_, err := fakeClient.NetworkingV1beta1().ServiceEntries(dk.GetNamespace()).Get(ctx, ipHostName, metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		_, err = fakeClient.NetworkingV1beta1().VirtualServices(dk.GetNamespace()).Get(ctx, ipHostName, metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		_, err = fakeClient.NetworkingV1beta1().ServiceEntries(dk.GetNamespace()).Get(ctx, fqdnName, metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+	t.Run("egress gateway removed => the hosts are reached directly", func(t *testing.T) {
+		fakeClient := fakeistio.NewSimpleClientset()
+		reconciler := NewReconciler(newTestingClient(fakeClient, dk.GetNamespace())).(*reconciler)
+
+		require.NoError(t, reconciler.reconcileCommunicationHosts(ctx, dk, commHosts, component))
+
+		directDk := createEgressGatewayDynaKube()
+		directDk.Spec.Istio = nil
+
+		require.NoError(t, reconciler.reconcileCommunicationHosts(ctx, directDk, commHosts, component))
+
+		gateways, err := fakeClient.NetworkingV1beta1().Gateways(dk.GetNamespace()).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, gateways.Items)
+
+		destinationRules, err := fakeClient.NetworkingV1beta1().DestinationRules(dk.GetNamespace()).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, destinationRules.Items)
+
+		_, err = fakeClient.NetworkingV1beta1().ServiceEntries(dk.GetNamespace()).Get(ctx, ipHostName, metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		_, err = fakeClient.NetworkingV1beta1().ServiceEntries(dk.GetNamespace()).Get(ctx, ipName, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		virtualService, err := fakeClient.NetworkingV1beta1().VirtualServices(dk.GetNamespace()).Get(ctx, fqdnName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, virtualService.Spec.Gateways)
+	})
+	t.Run("component cleaned up => its gateway objects are deleted", func(t *testing.T) {
+		fakeClient := fakeistio.NewSimpleClientset()
+		reconciler := NewReconciler(newTestingClient(fakeClient, dk.GetNamespace())).(*reconciler)
+
+		require.NoError(t, reconciler.reconcileCommunicationHosts(ctx, dk, commHosts, component))
+		require.NoError(t, reconciler.CleanupIstio(ctx, dk, CodeModuleComponent, component))
+
+		gateways, err := fakeClient.NetworkingV1beta1().Gateways(dk.GetNamespace()).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, gateways.Items)
+
+		serviceEntries, err := fakeClient.NetworkingV1beta1().ServiceEntries(dk.GetNamespace()).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, serviceEntries.Items)
+
+		virtualServices, err := fakeClient.NetworkingV1beta1().VirtualServices(dk.GetNamespace()).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, virtualServices.Items)
+	})
+}
+
+func TestIstioEgressGatewayDefaults(t *testing.T) {
+	dk := createTestDynaKube()
+	dk.Spec.EnableIstio = true
+	dk.Spec.Istio = &dynakube.IstioSpec{EgressGateway: &dynakube.IstioEgressGatewaySpec{Name: testEgressGatewayName}}
+
+	egressGateway := dk.IstioEgressGateway()
+
+	require.NotNil(t, egressGateway)
+	assert.Equal(t, createTestEgressGateway(), egressGateway)
+	assert.Empty(t, dk.Spec.Istio.EgressGateway.Namespace)
+
+	dk.Spec.EnableIstio = false
+	assert.Nil(t, dk.IstioEgressGateway())
+}
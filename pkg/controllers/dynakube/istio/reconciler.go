@@ -50,7 +50,7 @@ func (r *reconciler) ReconcileAPIUrl(ctx context.Context, dk *dynakube.DynaKube)
 		return err
 	}
 
-	err = r.reconcileCommunicationHosts(ctx, []dtclient.CommunicationHost{apiHost}, OperatorComponent)
+	err = r.reconcileCommunicationHosts(ctx, dk, []dtclient.CommunicationHost{apiHost}, OperatorComponent)
 	if err != nil {
 		return errors.WithMessage(err, "error reconciling config for Dynatrace API URL")
 	}
@@ -79,7 +79,7 @@ func (r *reconciler) ReconcileCodeModuleCommunicationHosts(ctx context.Context,
 
 	oneAgentCommunicationHosts := oaconnectioninfo.GetCommunicationHosts(dk)
 
-	err := r.reconcileCommunicationHostsForComponent(ctx, oneAgentCommunicationHosts, OneAgentComponent)
+	err := r.reconcileCommunicationHostsForComponent(ctx, dk, oneAgentCommunicationHosts, OneAgentComponent)
 	if err != nil {
 		setServiceEntryFailedConditionForComponent(dk.Conditions(), CodeModuleComponent, err)
 
@@ -122,7 +122,7 @@ func (r *reconciler) ReconcileActiveGateCommunicationHosts(ctx context.Context,
 
 	activeGateEndpoints := activegate.GetEndpointsAsCommunicationHosts(dk)
 
-	err := r.reconcileCommunicationHostsForComponent(ctx, activeGateEndpoints, strings.ToLower(ActiveGateComponent))
+	err := r.reconcileCommunicationHostsForComponent(ctx, dk, activeGateEndpoints, strings.ToLower(ActiveGateComponent))
 	if err != nil {
 		setServiceEntryFailedConditionForComponent(dk.Conditions(), ActiveGateComponent, err)
 
@@ -145,9 +145,11 @@ func (r *reconciler) CleanupIstio(ctx context.Context, dk *dynakube.DynaKube, co
 
 	err1 := r.cleanupIPServiceEntry(ctx, component)
 	err2 := r.cleanupFQDNServiceEntry(ctx, component)
+	err3 := r.cleanupEgressGateway(ctx, component)
+	err4 := r.reconcileDestinationRule(ctx, dk)
 
 	// try to clean up all entries even if one fails
-	return goerrors.Join(err1, err2)
+	return goerrors.Join(err1, err2, err3, err4)
 }
 
 func isIstioConfigured(dk *dynakube.DynaKube, conditionComponent string) bool {
@@ -156,8 +158,8 @@ func isIstioConfigured(dk *dynakube.DynaKube, conditionComponent string) bool {
 	return istioCondition != nil
 }
 
-func (r *reconciler) reconcileCommunicationHostsForComponent(ctx context.Context, comHosts []dtclient.CommunicationHost, componentName string) error {
-	err := r.reconcileCommunicationHosts(ctx, comHosts, componentName)
+func (r *reconciler) reconcileCommunicationHostsForComponent(ctx context.Context, dk *dynakube.DynaKube, comHosts []dtclient.CommunicationHost, componentName string) error {
+	err := r.reconcileCommunicationHosts(ctx, dk, comHosts, componentName)
 	if err != nil {
 		return errors.WithMessage(err, "error reconciling config for Dynatrace communication hosts")
 	}
@@ -167,13 +169,46 @@ func (r *reconciler) reconcileCommunicationHostsForComponent(ctx context.Context
 	return nil
 }
 
-func (r *reconciler) reconcileCommunicationHosts(ctx context.Context, comHosts []dtclient.CommunicationHost, component string) error {
+// reconcileCommunicationHosts creates the Istio objects for the hosts of a component.
+// Without an egress gateway the hosts are reached directly, otherwise the traffic to all hosts is routed through the gateway.
+func (r *reconciler) reconcileCommunicationHosts(ctx context.Context, dk *dynakube.DynaKube, comHosts []dtclient.CommunicationHost, component string) error {
 	ipHosts, fqdnHosts := splitCommunicationHost(comHosts)
+	egressGateway := dk.IstioEgressGateway()
 
-	errIPServiceEntry := r.reconcileIPServiceEntry(ctx, ipHosts, component)
-	errFQDNServiceEntry := r.reconcileFQDNServiceEntry(ctx, fqdnHosts, component)
+	var errIPServiceEntry, errEgressGateway error
 
-	return goerrors.Join(errIPServiceEntry, errFQDNServiceEntry)
+	if egressGateway != nil {
+		// the IP ServiceEntry would let the traffic to the IP hosts bypass the egress gateway
+		errIPServiceEntry = r.cleanupIPServiceEntry(ctx, component)
+		errEgressGateway = r.reconcileEgressGateway(ctx, ipHosts, fqdnHosts, component, egressGateway)
+	} else {
+		errIPServiceEntry = r.reconcileIPServiceEntry(ctx, ipHosts, component)
+		errEgressGateway = r.cleanupEgressGateway(ctx, component)
+	}
+
+	errFQDNServiceEntry := r.reconcileFQDNServiceEntry(ctx, fqdnHosts, component, egressGateway)
+	errDestinationRule := r.reconcileDestinationRule(ctx, dk)
+
+	return goerrors.Join(errIPServiceEntry, errFQDNServiceEntry, errEgressGateway, errDestinationRule)
+}
+
+// collectCommunicationHosts returns the communication hosts of all components, which are configured by the reconciler.
+func collectCommunicationHosts(dk *dynakube.DynaKube) []dtclient.CommunicationHost {
+	var commHosts []dtclient.CommunicationHost
+
+	if apiHost, err := dtclient.ParseEndpoint(dk.Spec.APIURL); err == nil {
+		commHosts = append(commHosts, apiHost)
+	}
+
+	if dk.OneAgent().IsAppInjectionNeeded() {
+		commHosts = append(commHosts, oaconnectioninfo.GetCommunicationHosts(dk)...)
+	}
+
+	if dk.ActiveGate().IsEnabled() {
+		commHosts = append(commHosts, activegate.GetEndpointsAsCommunicationHosts(dk)...)
+	}
+
+	return commHosts
 }
 
 func splitCommunicationHost(comHosts []dtclient.CommunicationHost) (ipHosts, fqdnHosts []dtclient.CommunicationHost) {
@@ -221,38 +256,33 @@ func (r *reconciler) cleanupIPServiceEntry(ctx context.Context, component string
 	return r.client.DeleteServiceEntry(ctx, entryName)
 }
 
-func (r *reconciler) reconcileFQDNServiceEntry(ctx context.Context, fqdnHosts []dtclient.CommunicationHost, component string) error {
+func (r *reconciler) reconcileFQDNServiceEntry(ctx context.Context, fqdnHosts []dtclient.CommunicationHost, component string, egressGateway *dynakube.IstioEgressGatewaySpec) error {
 	owner := r.client.Owner
 	entryName := BuildNameForFQDNServiceEntry(owner.GetName(), component)
 
-	if len(fqdnHosts) != 0 {
-		objectMeta := buildObjectMeta(
-			entryName,
-			owner.GetNamespace(),
-			labels.NewCoreLabels(owner.GetName(), component).BuildLabels(),
-		)
+	if len(fqdnHosts) == 0 {
+		return r.cleanupFQDNServiceEntry(ctx, component)
+	}
 
-		serviceEntry := buildServiceEntryFQDNs(objectMeta, fqdnHosts)
+	objectMeta := buildObjectMeta(
+		entryName,
+		owner.GetNamespace(),
+		labels.NewCoreLabels(owner.GetName(), component).BuildLabels(),
+	)
 
-		err := r.client.CreateOrUpdateServiceEntry(ctx, serviceEntry)
-		if err != nil {
-			return err
-		}
+	serviceEntry := buildServiceEntryFQDNs(objectMeta, fqdnHosts)
 
-		virtualService := buildVirtualService(objectMeta, fqdnHosts)
+	err := r.client.CreateOrUpdateServiceEntry(ctx, serviceEntry)
+	if err != nil {
+		return err
+	}
 
-		err = r.client.CreateOrUpdateVirtualService(ctx, virtualService)
-		if err != nil {
-			return err
-		}
-	} else {
-		err := r.cleanupFQDNServiceEntry(ctx, component)
-		if err != nil {
-			return err
-		}
+	virtualService := buildVirtualService(objectMeta, fqdnHosts)
+	if egressGateway != nil {
+		virtualService = buildEgressGatewayVirtualService(objectMeta, BuildNameForEgressGateway(owner.GetName(), component), egressGateway, fqdnHosts)
 	}
 
-	return nil
+	return r.client.CreateOrUpdateVirtualService(ctx, virtualService)
 }
 
 func (r *reconciler) cleanupFQDNServiceEntry(ctx context.Context, component string) error {
@@ -264,6 +294,111 @@ func (r *reconciler) cleanupFQDNServiceEntry(ctx context.Context, component stri
 	return goerrors.Join(errServiceEntry, errVirtualService)
 }
 
+// reconcileEgressGateway creates the Gateway of the component and routes every IP host through it with its own ServiceEntry and VirtualService.
+func (r *reconciler) reconcileEgressGateway(ctx context.Context, ipHosts, fqdnHosts []dtclient.CommunicationHost, component string, egressGateway *dynakube.IstioEgressGatewaySpec) error {
+	if len(ipHosts) == 0 && len(fqdnHosts) == 0 {
+		return r.cleanupEgressGateway(ctx, component)
+	}
+
+	owner := r.client.Owner
+	gatewayName := BuildNameForEgressGateway(owner.GetName(), component)
+
+	objectMeta := buildObjectMeta(
+		gatewayName,
+		owner.GetNamespace(),
+		labels.NewCoreLabels(owner.GetName(), component).BuildLabels(),
+	)
+
+	err := r.client.CreateOrUpdateGateway(ctx, buildGateway(objectMeta, egressGateway, fqdnHosts, ipHosts))
+	if err != nil {
+		return err
+	}
+
+	ipLabels := labels.NewCoreLabels(owner.GetName(), buildEgressGatewayComponent(component))
+	ips, ipPorts := groupIPHostPorts(ipHosts)
+	ipHostNames := make(map[string]bool, len(ips))
+
+	var errs []error
+
+	for _, ip := range ips {
+		ipMeta := buildObjectMeta(
+			BuildNameForEgressGatewayIPHost(owner.GetName(), component, ip),
+			owner.GetNamespace(),
+			ipLabels.BuildLabels(),
+		)
+		ipHostNames[ipMeta.Name] = true
+
+		errs = append(errs,
+			r.client.CreateOrUpdateServiceEntry(ctx, buildEgressGatewayIPServiceEntry(ipMeta, ip, ipPorts[ip])),
+			r.client.CreateOrUpdateVirtualService(ctx, buildEgressGatewayIPVirtualService(ipMeta, gatewayName, egressGateway, ip, ipPorts[ip])),
+		)
+	}
+
+	errs = append(errs, r.cleanupEgressGatewayIPHosts(ctx, component, ipHostNames))
+
+	return goerrors.Join(errs...)
+}
+
+func (r *reconciler) cleanupEgressGateway(ctx context.Context, component string) error {
+	errGateway := r.client.DeleteGateway(ctx, BuildNameForEgressGateway(r.client.Owner.GetName(), component))
+	errIPHosts := r.cleanupEgressGatewayIPHosts(ctx, component, nil)
+
+	return goerrors.Join(errGateway, errIPHosts)
+}
+
+// cleanupEgressGatewayIPHosts removes the ServiceEntries and VirtualServices of the IP hosts of the component, which are not kept.
+func (r *reconciler) cleanupEgressGatewayIPHosts(ctx context.Context, component string, keep map[string]bool) error {
+	matchLabels := labels.NewCoreLabels(r.client.Owner.GetName(), buildEgressGatewayComponent(component)).BuildMatchLabels()
+
+	serviceEntries, err := r.client.ListServiceEntries(ctx, matchLabels)
+	if err != nil {
+		return err
+	}
+
+	virtualServices, err := r.client.ListVirtualServices(ctx, matchLabels)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, serviceEntry := range serviceEntries {
+		if !keep[serviceEntry.Name] {
+			errs = append(errs, r.client.DeleteServiceEntry(ctx, serviceEntry.Name))
+		}
+	}
+
+	for _, virtualService := range virtualServices {
+		if !keep[virtualService.Name] {
+			errs = append(errs, r.client.DeleteVirtualService(ctx, virtualService.Name))
+		}
+	}
+
+	return goerrors.Join(errs...)
+}
+
+// reconcileDestinationRule keeps the DestinationRule of the egress gateway in sync with the IP hosts of all components,
+// it is removed once the egress gateway is no longer configured.
+func (r *reconciler) reconcileDestinationRule(ctx context.Context, dk *dynakube.DynaKube) error {
+	owner := r.client.Owner
+	ruleName := BuildNameForEgressGatewayDestinationRule(owner.GetName())
+
+	egressGateway := dk.IstioEgressGateway()
+	if egressGateway == nil {
+		return r.client.DeleteDestinationRule(ctx, ruleName)
+	}
+
+	objectMeta := buildObjectMeta(
+		ruleName,
+		owner.GetNamespace(),
+		labels.NewCoreLabels(owner.GetName(), egressGatewayComponent).BuildLabels(),
+	)
+
+	ipHosts, _ := splitCommunicationHost(collectCommunicationHosts(dk))
+
+	return r.client.CreateOrUpdateDestinationRule(ctx, buildDestinationRule(objectMeta, egressGateway, ipHosts))
+}
+
 func buildObjectMeta(name, namespace string, labels map[string]string) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
 		Name:      name,
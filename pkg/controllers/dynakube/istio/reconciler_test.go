@@ -125,7 +125,7 @@ func TestReconcileFQDNServiceEntry(t *testing.T) {
 		istioClient := newTestingClient(fakeClient, owner.GetNamespace())
 		reconciler := NewReconciler(istioClient).(*reconciler)
 
-		err := reconciler.reconcileFQDNServiceEntry(ctx, nil, component)
+		err := reconciler.reconcileFQDNServiceEntry(ctx, nil, component, nil)
 		require.NoError(t, err)
 		_, err = fakeClient.NetworkingV1beta1().ServiceEntries(serviceEntry.Namespace).Get(ctx, serviceEntry.Name, metav1.GetOptions{})
 		require.True(t, k8serrors.IsNotFound(err))
@@ -140,7 +140,7 @@ func TestReconcileFQDNServiceEntry(t *testing.T) {
 			createTestFQDNCommunicationHost(),
 		}
 
-		err := reconciler.reconcileFQDNServiceEntry(ctx, commHosts, component)
+		err := reconciler.reconcileFQDNServiceEntry(ctx, commHosts, component, nil)
 		require.NoError(t, err)
 
 		expectedName := BuildNameForFQDNServiceEntry(owner.GetName(), component)
@@ -162,7 +162,7 @@ func TestReconcileFQDNServiceEntry(t *testing.T) {
 			createTestFQDNCommunicationHost(),
 		}
 
-		err := reconciler.reconcileFQDNServiceEntry(ctx, commHosts, component)
+		err := reconciler.reconcileFQDNServiceEntry(ctx, commHosts, component, nil)
 		require.Error(t, err)
 	})
 }
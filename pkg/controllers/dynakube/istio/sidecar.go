@@ -0,0 +1,138 @@
+package istio
+
+import (
+	"context"
+	goerrors "errors"
+	"slices"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/sidecar"
+	"github.com/pkg/errors"
+	istio "istio.io/api/networking/v1beta1"
+	istiov1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	currentNamespaceHosts = "./*"
+	istioSystemHosts      = dynakube.DefaultIstioEgressGatewayNamespace + "/*"
+)
+
+func BuildNameForSidecar(ownerName string) string {
+	return ownerName + "-sidecar"
+}
+
+// ReconcileSidecars creates a Sidecar in every injected namespace, if enabled, and removes the Sidecars of the namespaces,
+// which are no longer injected. The Sidecars are handled with the kube client instead of the Istio client,
+// so they are also removed after enableIstio was turned off.
+func ReconcileSidecars(ctx context.Context, clt client.Client, apiReader client.Reader, dk *dynakube.DynaKube, namespaces []corev1.Namespace) error {
+	desired := make(map[string]bool)
+
+	var errs []error
+
+	if dk.IsIstioSidecarsEnabled() {
+		for _, namespace := range namespaces {
+			hasForeignSidecar, err := hasForeignDefaultSidecar(ctx, apiReader, dk, namespace.Name)
+			if err != nil {
+				errs = append(errs, err)
+
+				continue
+			} else if hasForeignSidecar {
+				log.Info("skipping the istio sidecar, the namespace already has a default sidecar", "namespace", namespace.Name)
+
+				continue
+			}
+
+			desired[namespace.Name] = true
+
+			_, err = sidecar.Query(clt, apiReader, log).CreateOrUpdate(ctx, buildSidecar(dk, namespace.Name))
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return goerrors.Join(append(errs, cleanupSidecars(ctx, clt, apiReader, dk, desired))...)
+}
+
+// CleanupSidecars removes all Sidecars of a deleted DynaKube, they live in the injected namespaces,
+// so they can't be owned by the DynaKube and aren't removed by the garbage collection.
+func CleanupSidecars(ctx context.Context, clt client.Client, apiReader client.Reader, dk *dynakube.DynaKube) error {
+	return cleanupSidecars(ctx, clt, apiReader, dk, nil)
+}
+
+// cleanupSidecars removes the Sidecars of the DynaKube in all namespaces, which aren't desired anymore.
+// Without Istio there is nothing to clean up.
+func cleanupSidecars(ctx context.Context, clt client.Client, apiReader client.Reader, dk *dynakube.DynaKube, desired map[string]bool) error {
+	sidecarList := &istiov1beta1.SidecarList{}
+
+	err := apiReader.List(ctx, sidecarList, client.MatchingLabels(labels.NewCoreLabels(dk.Name, SidecarComponent).BuildMatchLabels()))
+	if meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var errs []error
+
+	for _, existing := range sidecarList.Items {
+		if desired[existing.Namespace] {
+			continue
+		}
+
+		errs = append(errs, sidecar.Query(clt, apiReader, log).Delete(ctx, existing))
+	}
+
+	return goerrors.Join(errs...)
+}
+
+// hasForeignDefaultSidecar checks for a Sidecar without workload selector, which isn't managed by the DynaKube,
+// as Istio only supports one of them per namespace.
+func hasForeignDefaultSidecar(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube, namespace string) (bool, error) {
+	sidecarList := &istiov1beta1.SidecarList{}
+
+	err := apiReader.List(ctx, sidecarList, client.InNamespace(namespace))
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	for _, existing := range sidecarList.Items {
+		if existing.Name != BuildNameForSidecar(dk.Name) && existing.Spec.GetWorkloadSelector() == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// buildSidecar limits the outbound hosts of the workloads in the namespace to the own namespace, the Istio control plane,
+// the DynaKube namespace with its ServiceEntries, the egress gateway and the configured additional hosts.
+func buildSidecar(dk *dynakube.DynaKube, namespace string) *istiov1beta1.Sidecar {
+	hosts := []string{currentNamespaceHosts, istioSystemHosts, dk.Namespace + "/*"}
+
+	if egressGateway := dk.IstioEgressGateway(); egressGateway != nil {
+		hosts = append(hosts, egressGateway.Namespace+"/*")
+	}
+
+	hosts = append(hosts, dk.Spec.Istio.SidecarEgressHosts...)
+
+	slices.Sort(hosts)
+
+	return &istiov1beta1.Sidecar{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BuildNameForSidecar(dk.Name),
+			Namespace: namespace,
+			Labels:    labels.NewCoreLabels(dk.Name, SidecarComponent).BuildLabels(),
+		},
+		Spec: istio.Sidecar{
+			Egress: []*istio.IstioEgressListener{{
+				Hosts: slices.Compact(hosts),
+			}},
+		},
+	}
+}
@@ -0,0 +1,173 @@
+package istio
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	istio "istio.io/api/networking/v1beta1"
+	istiov1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	testInjectedNamespace = "injected"
+	testRemovedNamespace  = "removed"
+)
+
+func createTestSidecarDynaKube() *dynakube.DynaKube {
+	dk := createTestDynaKube()
+	dk.Spec.EnableIstio = true
+	dk.Spec.Istio = &dynakube.IstioSpec{EnableSidecars: true}
+
+	return dk
+}
+
+func createTestSidecar(dk *dynakube.DynaKube, namespace string) *istiov1beta1.Sidecar {
+	return &istiov1beta1.Sidecar{
+		ObjectMeta: buildObjectMeta(BuildNameForSidecar(dk.Name), namespace, labels.NewCoreLabels(dk.Name, SidecarComponent).BuildLabels()),
+	}
+}
+
+func getTestSidecar(t *testing.T, clt client.Client, name, namespace string) (*istiov1beta1.Sidecar, error) {
+	t.Helper()
+
+	sidecar := &istiov1beta1.Sidecar{}
+	err := clt.Get(t.Context(), client.ObjectKey{Name: name, Namespace: namespace}, sidecar)
+
+	return sidecar, err
+}
+
+func TestBuildSidecar(t *testing.T) {
+	t.Run("outbound hosts are limited, the outbound traffic policy of the mesh is kept", func(t *testing.T) {
+		dk := createTestSidecarDynaKube()
+
+		sidecar := buildSidecar(dk, testInjectedNamespace)
+
+		assert.Nil(t, sidecar.Spec.GetOutboundTrafficPolicy())
+		assert.Nil(t, sidecar.Spec.GetWorkloadSelector())
+		require.Len(t, sidecar.Spec.GetEgress(), 1)
+		assert.Equal(t, []string{"./*", "istio-system/*", "test/*"}, sidecar.Spec.GetEgress()[0].GetHosts())
+	})
+	t.Run("namespace of the egress gateway and additional hosts are added", func(t *testing.T) {
+		dk := createTestSidecarDynaKube()
+		dk.Spec.Istio.EgressGateway = &dynakube.IstioEgressGatewaySpec{Name: testEgressGatewayName, Namespace: "gateways"}
+		dk.Spec.Istio.SidecarEgressHosts = []string{"backend/*", "istio-system/*"}
+
+		sidecar := buildSidecar(dk, testInjectedNamespace)
+
+		assert.Equal(t, []string{"./*", "backend/*", "gateways/*", "istio-system/*", "test/*"}, sidecar.Spec.GetEgress()[0].GetHosts())
+	})
+}
+
+func TestReconcileSidecars(t *testing.T) {
+	namespaces := []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: testInjectedNamespace}}}
+
+	t.Run("sidecars are created in injected namespaces, outdated ones are removed", func(t *testing.T) {
+		dk := createTestSidecarDynaKube()
+		clt := fake.NewClient(createTestSidecar(dk, testRemovedNamespace))
+
+		err := ReconcileSidecars(t.Context(), clt, clt, dk, namespaces)
+		require.NoError(t, err)
+
+		sidecar, err := getTestSidecar(t, clt, BuildNameForSidecar(dk.Name), testInjectedNamespace)
+		require.NoError(t, err)
+		assert.Equal(t, labels.NewCoreLabels(dk.Name, SidecarComponent).BuildLabels(), sidecar.Labels)
+
+		_, err = getTestSidecar(t, clt, BuildNameForSidecar(dk.Name), testRemovedNamespace)
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+	t.Run("namespace with a default sidecar => skipped", func(t *testing.T) {
+		dk := createTestSidecarDynaKube()
+		foreignSidecar := &istiov1beta1.Sidecar{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: testInjectedNamespace}}
+		clt := fake.NewClient(foreignSidecar)
+
+		err := ReconcileSidecars(t.Context(), clt, clt, dk, namespaces)
+		require.NoError(t, err)
+
+		_, err = getTestSidecar(t, clt, BuildNameForSidecar(dk.Name), testInjectedNamespace)
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+	t.Run("namespace with a workload sidecar => created", func(t *testing.T) {
+		dk := createTestSidecarDynaKube()
+		workloadSidecar := &istiov1beta1.Sidecar{
+			ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: testInjectedNamespace},
+			Spec:       istio.Sidecar{WorkloadSelector: &istio.WorkloadSelector{Labels: map[string]string{"app": "test"}}},
+		}
+		clt := fake.NewClient(workloadSidecar)
+
+		err := ReconcileSidecars(t.Context(), clt, clt, dk, namespaces)
+		require.NoError(t, err)
+
+		_, err = getTestSidecar(t, clt, BuildNameForSidecar(dk.Name), testInjectedNamespace)
+		require.NoError(t, err)
+	})
+	t.Run("sidecars disabled => all are removed", func(t *testing.T) {
+		dk := createTestSidecarDynaKube()
+		dk.Spec.EnableIstio = false
+		clt := fake.NewClient(createTestSidecar(dk, testInjectedNamespace))
+
+		err := ReconcileSidecars(t.Context(), clt, clt, dk, namespaces)
+		require.NoError(t, err)
+
+		sidecars := &istiov1beta1.SidecarList{}
+		require.NoError(t, clt.List(t.Context(), sidecars))
+		assert.Empty(t, sidecars.Items)
+	})
+	t.Run("sidecars of other dynakubes are kept", func(t *testing.T) {
+		dk := createTestSidecarDynaKube()
+		otherDk := createTestSidecarDynaKube()
+		otherDk.Name = "other"
+		clt := fake.NewClient(createTestSidecar(otherDk, testRemovedNamespace))
+
+		err := ReconcileSidecars(t.Context(), clt, clt, dk, namespaces)
+		require.NoError(t, err)
+
+		_, err = getTestSidecar(t, clt, BuildNameForSidecar(otherDk.Name), testRemovedNamespace)
+		require.NoError(t, err)
+	})
+	t.Run("istio not installed and sidecars disabled => no error", func(t *testing.T) {
+		dk := createTestDynaKube()
+		clt := fake.NewClientWithInterceptors(interceptor.Funcs{
+			List: func(_ context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
+				return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: IstioGVRName, Kind: "Sidecar"}}
+			},
+		})
+
+		err := ReconcileSidecars(t.Context(), clt, clt, dk, namespaces)
+		require.NoError(t, err)
+	})
+	t.Run("create fails => error", func(t *testing.T) {
+		dk := createTestSidecarDynaKube()
+		clt := fake.NewClientWithInterceptors(interceptor.Funcs{
+			Create: func(_ context.Context, _ client.WithWatch, _ client.Object, _ ...client.CreateOption) error {
+				return k8serrors.NewForbidden(schema.GroupResource{Group: IstioGVRName, Resource: "sidecars"}, "", nil)
+			},
+		})
+
+		err := ReconcileSidecars(t.Context(), clt, clt, dk, namespaces)
+		require.Error(t, err)
+	})
+}
+
+func TestCleanupSidecars(t *testing.T) {
+	dk := createTestSidecarDynaKube()
+	clt := fake.NewClient(createTestSidecar(dk, testInjectedNamespace), createTestSidecar(dk, testRemovedNamespace))
+
+	err := CleanupSidecars(t.Context(), clt, clt, dk)
+	require.NoError(t, err)
+
+	sidecars := &istiov1beta1.SidecarList{}
+	require.NoError(t, clt.List(t.Context(), sidecars))
+	assert.Empty(t, sidecars.Items)
+}
@@ -55,7 +55,7 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		return nil // clean-up shouldn't cause a failure
 	}
 
-	ds, err := r.generateDaemonSet()
+	ds, err := r.BuildDesiredDaemonSet()
 	if err != nil {
 		return err
 	}
@@ -75,7 +75,8 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 	return nil
 }
 
-func (r *Reconciler) generateDaemonSet() (*appsv1.DaemonSet, error) {
+// BuildDesiredDaemonSet builds the DaemonSet as it would be applied by the Reconciler, without touching the cluster.
+func (r *Reconciler) BuildDesiredDaemonSet() (*appsv1.DaemonSet, error) {
 	tenantUUID, err := r.dk.TenantUUID()
 	if err != nil {
 		return nil, err
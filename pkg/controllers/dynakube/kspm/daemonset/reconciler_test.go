@@ -108,7 +108,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 
 		reconciler := NewReconciler(nil,
 			nil, dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -146,7 +146,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 
 		reconciler := NewReconciler(nil,
 			nil, dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -163,7 +163,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 
 		reconciler := NewReconciler(nil,
 			nil, dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -179,7 +179,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 
 		reconciler := NewReconciler(nil,
 			nil, dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -194,7 +194,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 
 		reconciler := NewReconciler(nil,
 			nil, dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -214,7 +214,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.KSPM().Tolerations = customTolerations
 		reconciler := NewReconciler(nil,
 			nil, dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -229,7 +229,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.KSPM().NodeSelector = customNodeSelector
 		reconciler := NewReconciler(nil,
 			nil, dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -247,7 +247,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.KSPM().NodeAffinity = customNodeAffinity
 		reconciler := NewReconciler(nil,
 			nil, dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
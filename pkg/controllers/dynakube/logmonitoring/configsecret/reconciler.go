@@ -147,8 +147,8 @@ func (r *Reconciler) getSecretData(ctx context.Context) (map[string][]byte, erro
 		hostIDSourceKey: "k8s-node-name",
 	}
 
-	if r.dk.HasProxy() {
-		proxyURL, err := r.dk.Proxy(ctx, r.apiReader)
+	if r.dk.HasComponentProxy(dynakube.LogMonitoringProxyComponent) {
+		proxyURL, err := r.dk.ComponentProxy(ctx, r.apiReader, dynakube.LogMonitoringProxyComponent)
 		if err != nil {
 			log.Info("failed get the proxy value")
 
@@ -216,7 +216,7 @@ func AddAnnotations(source map[string]string, dk dynakube.DynaKube) map[string]s
 		annotation[NetworkZoneAnnotationKey] = dk.Spec.NetworkZone
 	}
 
-	if dk.HasProxy() {
+	if dk.HasComponentProxy(dynakube.LogMonitoringProxyComponent) {
 		annotation[ProxyHashAnnotationKey] = dk.Status.ProxyURLHash
 	}
 
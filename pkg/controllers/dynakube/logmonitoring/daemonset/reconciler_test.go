@@ -170,7 +170,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk := createDynakube(true)
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -207,7 +207,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		}
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -227,7 +227,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.Status.OneAgent.ConnectionInfoStatus.TenantTokenHash = testTokenHash
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -244,7 +244,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		}
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -256,7 +256,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.Spec.NetworkZone = "my-networkzone"
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -270,7 +270,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.Status.ProxyURLHash = "proxy-hash"
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -285,7 +285,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		}
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -302,7 +302,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		}
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -316,7 +316,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.Spec.CustomPullSecret = customPullSecret
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -337,7 +337,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 			Tolerations: customTolerations,
 		}
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -354,7 +354,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 			NodeSelector: customNodeSelector,
 		}
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -366,7 +366,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.Status.KubernetesClusterMEID = ""
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.BuildDesiredDaemonSet()
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -0,0 +1,88 @@
+package dynakube
+
+import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	dynakubeMetricLabel  = "dynakube"
+	componentMetricLabel = "component"
+	phaseMetricLabel     = "phase"
+
+	activeGateComponent    = "activegate"
+	extensionComponent     = "extension"
+	otelcComponent         = "otelc"
+	logMonitoringComponent = "logmonitoring"
+	injectionComponent     = "injection"
+	oneAgentComponent      = "oneagent"
+	kspmComponent          = "kspm"
+	networkPolicyComponent = "networkpolicy"
+)
+
+var (
+	componentReconcileDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dynatrace",
+		Subsystem: "dynakube",
+		Name:      "component_reconcile_duration_seconds",
+		Help:      "Duration of the reconciliation of a DynaKube component in seconds",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{dynakubeMetricLabel, componentMetricLabel})
+
+	componentReconcileErrorsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "dynakube",
+		Name:      "component_reconcile_errors_total",
+		Help:      "Number of failed reconciliations of a DynaKube component",
+	}, []string{dynakubeMetricLabel, componentMetricLabel})
+
+	phaseMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dynatrace",
+		Subsystem: "dynakube",
+		Name:      "phase",
+		Help:      "Current phase of a DynaKube, 1 for the active phase and 0 for all others",
+	}, []string{dynakubeMetricLabel, phaseMetricLabel})
+
+	knownPhases = []status.DeploymentPhase{status.Running, status.Deploying, status.Error}
+)
+
+func init() {
+	metrics.Registry.MustRegister(componentReconcileDurationMetric, componentReconcileErrorsMetric, phaseMetric)
+}
+
+// measureComponentReconcile runs the given reconcile function and records its duration and outcome for the component.
+func measureComponentReconcile(dk *dynakube.DynaKube, component string, reconcile func() error) error {
+	start := time.Now()
+	err := reconcile()
+
+	componentReconcileDurationMetric.WithLabelValues(dk.Name, component).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		componentReconcileErrorsMetric.WithLabelValues(dk.Name, component).Inc()
+	}
+
+	return err
+}
+
+func updatePhaseMetric(dk *dynakube.DynaKube) {
+	for _, phase := range knownPhases {
+		value := 0.0
+		if dk.Status.Phase == phase {
+			value = 1
+		}
+
+		phaseMetric.WithLabelValues(dk.Name, string(phase)).Set(value)
+	}
+}
+
+func deleteDynakubeMetrics(dkName string) {
+	labels := prometheus.Labels{dynakubeMetricLabel: dkName}
+
+	componentReconcileDurationMetric.DeletePartialMatch(labels)
+	componentReconcileErrorsMetric.DeletePartialMatch(labels)
+	phaseMetric.DeletePartialMatch(labels)
+}
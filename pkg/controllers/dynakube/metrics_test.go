@@ -0,0 +1,52 @@
+package dynakube
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMeasureComponentReconcile(t *testing.T) {
+	dk := &dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: "metrics-measure"}}
+
+	t.Run("success is observed without counting an error", func(t *testing.T) {
+		err := measureComponentReconcile(dk, oneAgentComponent, func() error { return nil })
+		require.NoError(t, err)
+
+		assert.Positive(t, testutil.CollectAndCount(componentReconcileDurationMetric))
+		assert.InDelta(t, 0, testutil.ToFloat64(componentReconcileErrorsMetric.WithLabelValues(dk.Name, oneAgentComponent)), 0)
+	})
+	t.Run("error is counted and returned", func(t *testing.T) {
+		expectedErr := errors.New("boom")
+
+		err := measureComponentReconcile(dk, kspmComponent, func() error { return expectedErr })
+		require.ErrorIs(t, err, expectedErr)
+
+		assert.InDelta(t, 1, testutil.ToFloat64(componentReconcileErrorsMetric.WithLabelValues(dk.Name, kspmComponent)), 0)
+	})
+}
+
+func TestPhaseMetric(t *testing.T) {
+	dk := &dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: "metrics-phase"}}
+
+	t.Run("only current phase is active", func(t *testing.T) {
+		dk.Status.Phase = status.Deploying
+		updatePhaseMetric(dk)
+
+		assert.InDelta(t, 1, testutil.ToFloat64(phaseMetric.WithLabelValues(dk.Name, string(status.Deploying))), 0)
+		assert.InDelta(t, 0, testutil.ToFloat64(phaseMetric.WithLabelValues(dk.Name, string(status.Running))), 0)
+		assert.InDelta(t, 0, testutil.ToFloat64(phaseMetric.WithLabelValues(dk.Name, string(status.Error))), 0)
+	})
+	t.Run("metrics are removed with the dynakube", func(t *testing.T) {
+		updatePhaseMetric(dk)
+		deleteDynakubeMetrics(dk.Name)
+
+		assert.False(t, phaseMetric.DeleteLabelValues(dk.Name, string(status.Deploying)))
+	})
+}
@@ -0,0 +1,158 @@
+package networkpolicy
+
+import (
+	"strconv"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/networkpolicy"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	ciliumNamespaceLabel        = "k8s:io.kubernetes.pod.namespace"
+	ciliumNamespaceLabelsPrefix = "k8s:io.cilium.k8s.namespace.labels."
+	ciliumAPIServerEntity       = "kube-apiserver"
+	ciliumProtocolTCP           = "TCP"
+	ciliumProtocolAny           = "ANY"
+	ciliumDNSMatchPattern       = "*"
+
+	kubeDNSNamespace = "kube-system"
+	kubeDNSLabelKey  = "k8s:k8s-app"
+	kubeDNSLabel     = "kube-dns"
+)
+
+// only the used part of the Cilium spec is modeled
+type ciliumNetworkPolicySpec struct {
+	EndpointSelector metav1.LabelSelector `json:"endpointSelector"`
+	Egress           []ciliumEgressRule   `json:"egress"`
+}
+
+type ciliumEgressRule struct {
+	ToEndpoints []metav1.LabelSelector `json:"toEndpoints,omitempty"`
+	ToEntities  []string               `json:"toEntities,omitempty"`
+	ToFQDNs     []ciliumFQDNSelector   `json:"toFQDNs,omitempty"`
+	ToCIDR      []string               `json:"toCIDR,omitempty"`
+	ToPorts     []ciliumPortRule       `json:"toPorts,omitempty"`
+}
+
+type ciliumFQDNSelector struct {
+	MatchName string `json:"matchName"`
+}
+
+type ciliumPortRule struct {
+	Rules *ciliumL7Rules `json:"rules,omitempty"`
+	Ports []ciliumPort   `json:"ports"`
+}
+
+type ciliumPort struct {
+	Port     string `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+type ciliumL7Rules struct {
+	DNS []ciliumDNSRule `json:"dns"`
+}
+
+type ciliumDNSRule struct {
+	MatchPattern string `json:"matchPattern"`
+}
+
+// buildCiliumNetworkPolicy allows the egress of the target to the FQDNs and IPs of the communication hosts.
+// The DNS traffic has to pass the DNS proxy of Cilium, otherwise the FQDN rules can't be enforced.
+func buildCiliumNetworkPolicy(dk *dynakube.DynaKube, target egressTarget, policyLabels map[string]string) (*unstructured.Unstructured, error) {
+	ipHosts, fqdnHosts := splitCommunicationHosts(target.hosts)
+
+	egressRules := []ciliumEgressRule{{
+		ToEndpoints: []metav1.LabelSelector{{MatchLabels: map[string]string{
+			ciliumNamespaceLabel: kubeDNSNamespace,
+			kubeDNSLabelKey:      kubeDNSLabel,
+		}}},
+		ToPorts: []ciliumPortRule{{
+			Ports: []ciliumPort{{Port: strconv.Itoa(dnsPort), Protocol: ciliumProtocolAny}},
+			Rules: &ciliumL7Rules{DNS: []ciliumDNSRule{{MatchPattern: ciliumDNSMatchPattern}}},
+		}},
+	}}
+
+	for _, fqdnHost := range fqdnHosts {
+		egressRules = append(egressRules, ciliumEgressRule{
+			ToFQDNs: []ciliumFQDNSelector{{MatchName: fqdnHost.Host}},
+			ToPorts: buildCiliumPorts(fqdnHost.Port),
+		})
+	}
+
+	for _, ipHost := range ipHosts {
+		egressRules = append(egressRules, ciliumEgressRule{
+			ToCIDR:  []string{toCIDR(ipHost.Host)},
+			ToPorts: buildCiliumPorts(ipHost.Port),
+		})
+	}
+
+	if target.allowAPIServer {
+		egressRules = append(egressRules, ciliumEgressRule{
+			ToEntities: []string{ciliumAPIServerEntity},
+		})
+	}
+
+	if target.scrapeNamespaces != nil {
+		egressRules = append(egressRules, ciliumEgressRule{
+			ToEndpoints: []metav1.LabelSelector{buildCiliumNamespaceSelector(*target.scrapeNamespaces)},
+		})
+	}
+
+	if target.allowActiveGate {
+		activeGateLabels := activeGateSelector(dk)
+		activeGateLabels[ciliumNamespaceLabel] = dk.Namespace
+
+		egressRules = append(egressRules, ciliumEgressRule{
+			ToEndpoints: []metav1.LabelSelector{{MatchLabels: activeGateLabels}},
+		})
+	}
+
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&ciliumNetworkPolicySpec{
+		EndpointSelector: metav1.LabelSelector{MatchLabels: target.podSelector},
+		Egress:           egressRules,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	policy := networkpolicy.NewCiliumNetworkPolicy()
+	policy.SetName(target.name)
+	policy.SetNamespace(target.namespace)
+	policy.SetLabels(policyLabels)
+	policy.Object["spec"] = spec
+
+	return policy, nil
+}
+
+func buildCiliumPorts(port uint32) []ciliumPortRule {
+	return []ciliumPortRule{{
+		Ports: []ciliumPort{{Port: strconv.Itoa(int(port)), Protocol: ciliumProtocolTCP}},
+	}}
+}
+
+// buildCiliumNamespaceSelector selects the pods of the namespaces matching the selector, the endpoints of a CiliumNetworkPolicy
+// are restricted to its own namespace, unless the namespace label is part of the selector.
+func buildCiliumNamespaceSelector(namespaceSelector metav1.LabelSelector) metav1.LabelSelector {
+	selector := metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: ciliumNamespaceLabel, Operator: metav1.LabelSelectorOpExists}},
+	}
+
+	if len(namespaceSelector.MatchLabels) > 0 {
+		selector.MatchLabels = make(map[string]string, len(namespaceSelector.MatchLabels))
+
+		for key, value := range namespaceSelector.MatchLabels {
+			selector.MatchLabels[ciliumNamespaceLabelsPrefix+key] = value
+		}
+	}
+
+	for _, expression := range namespaceSelector.MatchExpressions {
+		expression.Key = ciliumNamespaceLabelsPrefix + expression.Key
+		selector.MatchExpressions = append(selector.MatchExpressions, expression)
+	}
+
+	return selector
+}
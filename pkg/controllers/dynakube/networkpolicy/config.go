@@ -0,0 +1,22 @@
+package networkpolicy
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+)
+
+var (
+	log = logd.Get().WithName("dynakube-networkpolicy")
+)
+
+const (
+	conditionType = "NetworkPolicy"
+
+	PolicyComponentLabel = "egress-policy"
+
+	apiServerServiceName = "kubernetes"
+
+	OneAgentTarget      = "oneagent"
+	ActiveGateTarget    = "activegate"
+	OtelCollectorTarget = "otel-collector"
+	InjectionTarget     = "injection"
+)
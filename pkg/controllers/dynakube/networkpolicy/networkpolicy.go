@@ -0,0 +1,126 @@
+package networkpolicy
+
+import (
+	"net"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	dnsPort        = 53
+	ipv4SubnetMask = "/32"
+	ipv6SubnetMask = "/128"
+)
+
+// buildNetworkPolicy allows the egress of the target to the communication hosts.
+// A NetworkPolicy can't select FQDNs, so the traffic to the FQDN hosts is only restricted by port, the IP hosts are selected exactly.
+func buildNetworkPolicy(dk *dynakube.DynaKube, target egressTarget, policyLabels map[string]string) *networkingv1.NetworkPolicy {
+	ipHosts, fqdnHosts := splitCommunicationHosts(target.hosts)
+
+	egressRules := []networkingv1.NetworkPolicyEgressRule{buildDNSEgressRule()}
+
+	if len(fqdnHosts) > 0 {
+		egressRules = append(egressRules, networkingv1.NetworkPolicyEgressRule{
+			Ports: buildPorts(fqdnHosts),
+		})
+	}
+
+	egressRules = append(egressRules, buildIPEgressRules(ipHosts)...)
+
+	if target.allowAPIServer {
+		egressRules = append(egressRules, buildIPEgressRules(target.apiServerHosts)...)
+	}
+
+	if target.scrapeNamespaces != nil {
+		// the scraped pods can expose their metrics on any port
+		egressRules = append(egressRules, networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{{NamespaceSelector: target.scrapeNamespaces}},
+		})
+	}
+
+	if target.allowActiveGate {
+		egressRules = append(egressRules, networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: dk.Namespace}},
+				PodSelector:       &metav1.LabelSelector{MatchLabels: activeGateSelector(dk)},
+			}},
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      target.name,
+			Namespace: target.namespace,
+			Labels:    policyLabels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: target.podSelector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      egressRules,
+		},
+	}
+}
+
+// buildDNSEgressRule allows the name resolution of the communication hosts, any destination is allowed as the DNS setup differs between the clusters.
+func buildDNSEgressRule() networkingv1.NetworkPolicyEgressRule {
+	port := intstr.FromInt32(dnsPort)
+
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: protocolPtr(corev1.ProtocolUDP), Port: &port},
+			{Protocol: protocolPtr(corev1.ProtocolTCP), Port: &port},
+		},
+	}
+}
+
+func buildIPEgressRules(ipHosts []dtclient.CommunicationHost) []networkingv1.NetworkPolicyEgressRule {
+	egressRules := make([]networkingv1.NetworkPolicyEgressRule, 0, len(ipHosts))
+
+	for _, ipHost := range ipHosts {
+		egressRules = append(egressRules, networkingv1.NetworkPolicyEgressRule{
+			To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: toCIDR(ipHost.Host)}}},
+			Ports: buildPorts([]dtclient.CommunicationHost{ipHost}),
+		})
+	}
+
+	return egressRules
+}
+
+func buildPorts(hosts []dtclient.CommunicationHost) []networkingv1.NetworkPolicyPort {
+	var ports []networkingv1.NetworkPolicyPort
+
+	portSet := make(map[uint32]bool)
+
+	for _, host := range hosts {
+		if portSet[host.Port] {
+			continue
+		}
+
+		portSet[host.Port] = true
+		port := intstr.FromInt32(int32(host.Port)) //nolint:gosec
+
+		ports = append(ports, networkingv1.NetworkPolicyPort{
+			Protocol: protocolPtr(corev1.ProtocolTCP),
+			Port:     &port,
+		})
+	}
+
+	return ports
+}
+
+func toCIDR(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		return ip + ipv6SubnetMask
+	}
+
+	return ip + ipv4SubnetMask
+}
+
+func protocolPtr(protocol corev1.Protocol) *corev1.Protocol {
+	return &protocol
+}
@@ -0,0 +1,306 @@
+package networkpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/telemetryingest"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	testName      = "dynakube"
+	testNamespace = "dynatrace"
+	testAPIHost   = "tenant.test.io"
+
+	testInjectedNamespace = "injected"
+)
+
+func createTestDynaKube() *dynakube.DynaKube {
+	return &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: dynakube.DynaKubeSpec{
+			APIURL: "https://" + testAPIHost + "/api",
+			OneAgent: oneagent.Spec{
+				CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{},
+			},
+			ActiveGate: activegate.Spec{
+				Capabilities: []activegate.CapabilityDisplayName{
+					activegate.RoutingCapability.DisplayName,
+				},
+			},
+			NetworkPolicy: &dynakube.NetworkPolicySpec{},
+		},
+		Status: dynakube.DynaKubeStatus{
+			OneAgent: oneagent.Status{
+				ConnectionInfoStatus: oneagent.ConnectionInfoStatus{
+					CommunicationHosts: []oneagent.CommunicationHostStatus{
+						{Protocol: "https", Host: "oneagent.test.io", Port: 8443},
+						{Protocol: "https", Host: "10.0.0.1", Port: 443},
+					},
+				},
+			},
+		},
+	}
+}
+
+func createTestInjectedNamespace(dkName string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   testInjectedNamespace,
+			Labels: map[string]string{dtwebhook.InjectionInstanceLabel: dkName},
+		},
+	}
+}
+
+func createTestAPIServerEndpoints() *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      apiServerServiceName,
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: apiServerServiceName},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"172.16.0.1"}}},
+		Ports:       []discoveryv1.EndpointPort{{Port: ptr.To(int32(6443))}},
+	}
+}
+
+func createTestTarget() egressTarget {
+	return egressTarget{
+		name:        BuildName(testName, OneAgentTarget),
+		namespace:   testNamespace,
+		podSelector: map[string]string{"app": "oneagent"},
+		hosts: []dtclient.CommunicationHost{
+			{Protocol: "https", Host: testAPIHost, Port: 443},
+			{Protocol: "https", Host: "oneagent.test.io", Port: 443},
+			{Protocol: "https", Host: "10.0.0.1", Port: 8443},
+			{Protocol: "https", Host: "fd00::1", Port: 443},
+		},
+		apiServerHosts:   []dtclient.CommunicationHost{{Host: "172.16.0.1", Port: 6443}},
+		scrapeNamespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"scrape": "true"}},
+		allowActiveGate:  true,
+		allowAPIServer:   true,
+	}
+}
+
+func TestGetEgressTargets(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("targets of the enabled components", func(t *testing.T) {
+		dk := createTestDynaKube()
+		dk.Spec.NetworkPolicy.EnableInjectedNamespaces = true
+
+		targets, err := getEgressTargets(ctx, fake.NewClient(createTestInjectedNamespace(dk.Name), createTestAPIServerEndpoints()), dk)
+		require.NoError(t, err)
+		require.Len(t, targets, 3)
+
+		oneAgentTarget := targets[0]
+		assert.Equal(t, BuildName(testName, OneAgentTarget), oneAgentTarget.name)
+		assert.Equal(t, testNamespace, oneAgentTarget.namespace)
+		assert.NotEmpty(t, oneAgentTarget.podSelector)
+		assert.True(t, oneAgentTarget.owned)
+		assert.True(t, oneAgentTarget.allowActiveGate)
+		assert.False(t, oneAgentTarget.allowAPIServer)
+		assert.Len(t, oneAgentTarget.hosts, 3)
+		assert.Equal(t, testAPIHost, oneAgentTarget.hosts[0].Host)
+
+		activeGateTarget := targets[1]
+		assert.Equal(t, BuildName(testName, ActiveGateTarget), activeGateTarget.name)
+		assert.False(t, activeGateTarget.allowActiveGate)
+		assert.True(t, activeGateTarget.allowAPIServer)
+		assert.Equal(t, []dtclient.CommunicationHost{{Host: "172.16.0.1", Port: 6443}}, activeGateTarget.apiServerHosts)
+
+		injectionTarget := targets[2]
+		assert.Equal(t, BuildName(testName, InjectionTarget), injectionTarget.name)
+		assert.Equal(t, testInjectedNamespace, injectionTarget.namespace)
+		assert.Empty(t, injectionTarget.podSelector)
+		assert.False(t, injectionTarget.owned)
+	})
+	t.Run("injected namespaces not enabled => no policies in them", func(t *testing.T) {
+		dk := createTestDynaKube()
+
+		targets, err := getEgressTargets(ctx, fake.NewClient(createTestInjectedNamespace(dk.Name)), dk)
+		require.NoError(t, err)
+		require.Len(t, targets, 2)
+
+		for _, target := range targets {
+			assert.Equal(t, testNamespace, target.namespace)
+		}
+	})
+	t.Run("prometheus protocol => scraped namespaces are allowed", func(t *testing.T) {
+		scrapeSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"scrape": "true"}}
+		dk := createTestDynaKube()
+		dk.Spec.TelemetryIngest = &telemetryingest.Spec{
+			Protocols:  []string{string(otelcgen.PrometheusProtocol)},
+			Prometheus: &telemetryingest.PrometheusSpec{NamespaceSelector: scrapeSelector},
+		}
+
+		targets, err := getEgressTargets(ctx, fake.NewClient(), dk)
+		require.NoError(t, err)
+		require.Len(t, targets, 3)
+
+		otelCollectorTarget := targets[2]
+		assert.Equal(t, BuildName(testName, OtelCollectorTarget), otelCollectorTarget.name)
+		assert.True(t, otelCollectorTarget.allowAPIServer)
+		assert.Equal(t, scrapeSelector, otelCollectorTarget.scrapeNamespaces)
+	})
+	t.Run("proxy => proxy of the component is allowed", func(t *testing.T) {
+		dk := createTestDynaKube()
+		dk.Spec.NetworkPolicy.EnableInjectedNamespaces = true
+		dk.Spec.Proxy = &value.Source{Value: "http://proxy.dynatrace:3128"}
+		dk.Spec.Proxies = &dynakube.ProxiesSpec{
+			ActiveGate: &dynakube.ComponentProxySpec{URL: &value.Source{Value: "https://activegate-proxy.dynatrace"}},
+		}
+
+		targets, err := getEgressTargets(ctx, fake.NewClient(createTestInjectedNamespace(dk.Name)), dk)
+		require.NoError(t, err)
+		require.Len(t, targets, 3)
+
+		oneAgentProxy := dtclient.CommunicationHost{Protocol: "http", Host: "proxy.dynatrace", Port: 3128}
+		assert.Contains(t, targets[0].hosts, oneAgentProxy)
+		assert.Contains(t, targets[1].hosts, dtclient.CommunicationHost{Protocol: "https", Host: "activegate-proxy.dynatrace", Port: 443})
+		assert.NotContains(t, targets[1].hosts, oneAgentProxy)
+		assert.Contains(t, targets[2].hosts, oneAgentProxy)
+	})
+	t.Run("invalid proxy => error", func(t *testing.T) {
+		dk := createTestDynaKube()
+		dk.Spec.Proxy = &value.Source{Value: "proxy.dynatrace:3128"}
+
+		_, err := getEgressTargets(ctx, fake.NewClient(), dk)
+		require.Error(t, err)
+	})
+	t.Run("no components => no targets", func(t *testing.T) {
+		dk := createTestDynaKube()
+		dk.Spec.OneAgent = oneagent.Spec{}
+		dk.Spec.ActiveGate = activegate.Spec{}
+
+		targets, err := getEgressTargets(ctx, fake.NewClient(), dk)
+		require.NoError(t, err)
+		assert.Empty(t, targets)
+	})
+	t.Run("invalid api url => error", func(t *testing.T) {
+		dk := createTestDynaKube()
+		dk.Spec.APIURL = "://invalid"
+
+		_, err := getEgressTargets(ctx, fake.NewClient(), dk)
+		require.Error(t, err)
+	})
+}
+
+func TestBuildNetworkPolicy(t *testing.T) {
+	dk := createTestDynaKube()
+	policyLabels := map[string]string{"test": "label"}
+
+	policy := buildNetworkPolicy(dk, createTestTarget(), policyLabels)
+
+	assert.Equal(t, BuildName(testName, OneAgentTarget), policy.Name)
+	assert.Equal(t, testNamespace, policy.Namespace)
+	assert.Equal(t, policyLabels, policy.Labels)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+	assert.Equal(t, map[string]string{"app": "oneagent"}, policy.Spec.PodSelector.MatchLabels)
+	require.Len(t, policy.Spec.Egress, 7)
+
+	t.Run("dns is allowed", func(t *testing.T) {
+		dnsRule := policy.Spec.Egress[0]
+		assert.Empty(t, dnsRule.To)
+		require.Len(t, dnsRule.Ports, 2)
+		assert.Equal(t, int32(dnsPort), dnsRule.Ports[0].Port.IntVal)
+	})
+	t.Run("fqdn hosts are restricted by port", func(t *testing.T) {
+		fqdnRule := policy.Spec.Egress[1]
+		assert.Empty(t, fqdnRule.To)
+		require.Len(t, fqdnRule.Ports, 1)
+		assert.Equal(t, int32(443), fqdnRule.Ports[0].Port.IntVal)
+	})
+	t.Run("ip hosts are restricted by ip and port", func(t *testing.T) {
+		ipv4Rule := policy.Spec.Egress[2]
+		assert.Equal(t, "10.0.0.1/32", ipv4Rule.To[0].IPBlock.CIDR)
+		assert.Equal(t, int32(8443), ipv4Rule.Ports[0].Port.IntVal)
+
+		ipv6Rule := policy.Spec.Egress[3]
+		assert.Equal(t, "fd00::1/128", ipv6Rule.To[0].IPBlock.CIDR)
+	})
+	t.Run("api server is allowed", func(t *testing.T) {
+		apiServerRule := policy.Spec.Egress[4]
+		assert.Equal(t, "172.16.0.1/32", apiServerRule.To[0].IPBlock.CIDR)
+		assert.Equal(t, int32(6443), apiServerRule.Ports[0].Port.IntVal)
+	})
+	t.Run("scraped namespaces are allowed on any port", func(t *testing.T) {
+		scrapeRule := policy.Spec.Egress[5]
+		assert.Empty(t, scrapeRule.Ports)
+		assert.Equal(t, map[string]string{"scrape": "true"}, scrapeRule.To[0].NamespaceSelector.MatchLabels)
+	})
+	t.Run("activegate is allowed", func(t *testing.T) {
+		activeGateRule := policy.Spec.Egress[6]
+		require.Len(t, activeGateRule.To, 1)
+		assert.Equal(t, testNamespace, activeGateRule.To[0].NamespaceSelector.MatchLabels[corev1.LabelMetadataName])
+		assert.Equal(t, activeGateSelector(dk), activeGateRule.To[0].PodSelector.MatchLabels)
+	})
+}
+
+func TestBuildCiliumNetworkPolicy(t *testing.T) {
+	dk := createTestDynaKube()
+	policyLabels := map[string]string{"test": "label"}
+
+	policy, err := buildCiliumNetworkPolicy(dk, createTestTarget(), policyLabels)
+	require.NoError(t, err)
+
+	assert.Equal(t, "CiliumNetworkPolicy", policy.GetKind())
+	assert.Equal(t, BuildName(testName, OneAgentTarget), policy.GetName())
+	assert.Equal(t, testNamespace, policy.GetNamespace())
+	assert.Equal(t, policyLabels, policy.GetLabels())
+
+	egressRules, found, err := unstructured.NestedSlice(policy.Object, "spec", "egress")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, egressRules, 8)
+
+	t.Run("dns is allowed via the dns proxy", func(t *testing.T) {
+		dnsPattern, _, _ := unstructured.NestedSlice(egressRules[0].(map[string]any), "toPorts")
+		assert.Contains(t, dnsPattern[0], "rules")
+	})
+	t.Run("fqdn hosts are restricted by name", func(t *testing.T) {
+		fqdns, _, _ := unstructured.NestedSlice(egressRules[1].(map[string]any), "toFQDNs")
+		assert.Equal(t, []any{map[string]any{"matchName": testAPIHost}}, fqdns)
+
+		fqdns, _, _ = unstructured.NestedSlice(egressRules[2].(map[string]any), "toFQDNs")
+		assert.Equal(t, []any{map[string]any{"matchName": "oneagent.test.io"}}, fqdns)
+	})
+	t.Run("ip hosts are restricted by cidr", func(t *testing.T) {
+		cidrs, _, _ := unstructured.NestedStringSlice(egressRules[3].(map[string]any), "toCIDR")
+		assert.Equal(t, []string{"10.0.0.1/32"}, cidrs)
+	})
+	t.Run("api server is allowed", func(t *testing.T) {
+		entities, _, _ := unstructured.NestedStringSlice(egressRules[5].(map[string]any), "toEntities")
+		assert.Equal(t, []string{ciliumAPIServerEntity}, entities)
+	})
+	t.Run("scraped namespaces are allowed", func(t *testing.T) {
+		endpoints, _, _ := unstructured.NestedSlice(egressRules[6].(map[string]any), "toEndpoints")
+		matchLabels, _, _ := unstructured.NestedStringMap(endpoints[0].(map[string]any), "matchLabels")
+		assert.Equal(t, map[string]string{ciliumNamespaceLabelsPrefix + "scrape": "true"}, matchLabels)
+	})
+	t.Run("activegate is allowed", func(t *testing.T) {
+		endpoints, _, _ := unstructured.NestedSlice(egressRules[7].(map[string]any), "toEndpoints")
+		matchLabels, _, _ := unstructured.NestedStringMap(endpoints[0].(map[string]any), "matchLabels")
+		assert.Equal(t, testNamespace, matchLabels[ciliumNamespaceLabel])
+	})
+}
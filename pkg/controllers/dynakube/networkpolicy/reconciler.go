@@ -0,0 +1,192 @@
+package networkpolicy
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/networkpolicy"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Reconciler struct {
+	client    client.Client
+	apiReader client.Reader
+	dk        *dynakube.DynaKube
+}
+
+type ReconcilerBuilder func(client client.Client, apiReader client.Reader, dk *dynakube.DynaKube) controllers.Reconciler
+
+func NewReconciler(client client.Client, apiReader client.Reader, dk *dynakube.DynaKube) controllers.Reconciler {
+	return &Reconciler{
+		client:    client,
+		apiReader: apiReader,
+		dk:        dk,
+	}
+}
+
+// Cleanup removes all policies of a deleted DynaKube, the ones in the injected namespaces aren't removed by the garbage collection,
+// as they can't have an owner reference across namespaces.
+func Cleanup(ctx context.Context, client client.Client, apiReader client.Reader, dk *dynakube.DynaKube) error {
+	r := &Reconciler{
+		client:    client,
+		apiReader: apiReader,
+		dk:        dk,
+	}
+
+	return goerrors.Join(r.cleanupNetworkPolicies(ctx, nil), r.cleanupCiliumNetworkPolicies(ctx, nil))
+}
+
+// Reconcile keeps the egress policies of the components in sync with the communication hosts.
+// With Cilium the CiliumNetworkPolicies replace the NetworkPolicies, as Cilium combines both and the port based rules of
+// the NetworkPolicies would allow any destination.
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	if !r.dk.IsNetworkPolicyEnabled() {
+		if meta.FindStatusCondition(*r.dk.Conditions(), conditionType) == nil {
+			return nil
+		}
+		defer meta.RemoveStatusCondition(r.dk.Conditions(), conditionType)
+
+		return goerrors.Join(r.cleanupNetworkPolicies(ctx, nil), r.cleanupCiliumNetworkPolicies(ctx, nil))
+	}
+
+	targets, err := getEgressTargets(ctx, r.apiReader, r.dk)
+	if err != nil {
+		return err
+	}
+
+	if r.dk.IsCiliumNetworkPolicyEnabled() {
+		desired, reconcileErr := r.reconcileCiliumNetworkPolicies(ctx, targets)
+		err = goerrors.Join(reconcileErr, r.cleanupCiliumNetworkPolicies(ctx, desired), r.cleanupNetworkPolicies(ctx, nil))
+	} else {
+		desired, reconcileErr := r.reconcileNetworkPolicies(ctx, targets)
+		err = goerrors.Join(reconcileErr, r.cleanupNetworkPolicies(ctx, desired), r.cleanupCiliumNetworkPolicies(ctx, nil))
+	}
+
+	if err != nil {
+		conditions.SetKubeAPIError(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	conditions.SetStatusUpdated(r.dk.Conditions(), conditionType, fmt.Sprintf("%d egress policies are up to date", len(targets)))
+
+	return nil
+}
+
+func (r *Reconciler) reconcileNetworkPolicies(ctx context.Context, targets []egressTarget) (map[types.NamespacedName]bool, error) {
+	desired := make(map[types.NamespacedName]bool, len(targets))
+
+	var errs []error
+
+	for _, target := range targets {
+		desired[types.NamespacedName{Name: target.name, Namespace: target.namespace}] = true
+
+		policyQuery := networkpolicy.Query(r.client, r.apiReader, log)
+		if target.owned {
+			policyQuery = policyQuery.WithOwner(r.dk)
+		}
+
+		_, err := policyQuery.CreateOrUpdate(ctx, buildNetworkPolicy(r.dk, target, r.policyLabels()))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return desired, goerrors.Join(errs...)
+}
+
+func (r *Reconciler) reconcileCiliumNetworkPolicies(ctx context.Context, targets []egressTarget) (map[types.NamespacedName]bool, error) {
+	desired := make(map[types.NamespacedName]bool, len(targets))
+
+	var errs []error
+
+	for _, target := range targets {
+		desired[types.NamespacedName{Name: target.name, Namespace: target.namespace}] = true
+
+		policy, err := buildCiliumNetworkPolicy(r.dk, target, r.policyLabels())
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		policyQuery := networkpolicy.CiliumQuery(r.client, r.apiReader, log)
+		if target.owned {
+			policyQuery = policyQuery.WithOwner(r.dk)
+		}
+
+		_, err = policyQuery.CreateOrUpdate(ctx, policy)
+		if meta.IsNoMatchError(err) {
+			return desired, errors.WithMessage(err, "CiliumNetworkPolicies are not available, Cilium has to be installed to use them")
+		} else if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return desired, goerrors.Join(errs...)
+}
+
+// cleanupNetworkPolicies removes the NetworkPolicies of the DynaKube in all namespaces, which aren't desired anymore.
+func (r *Reconciler) cleanupNetworkPolicies(ctx context.Context, desired map[types.NamespacedName]bool) error {
+	policyList := &networkingv1.NetworkPolicyList{}
+
+	err := r.apiReader.List(ctx, policyList, client.MatchingLabels(r.policyMatchLabels()))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var errs []error
+
+	for _, policy := range policyList.Items {
+		if desired[types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace}] {
+			continue
+		}
+
+		errs = append(errs, networkpolicy.Query(r.client, r.apiReader, log).Delete(ctx, &policy))
+	}
+
+	return goerrors.Join(errs...)
+}
+
+// cleanupCiliumNetworkPolicies removes the CiliumNetworkPolicies of the DynaKube in all namespaces, which aren't desired anymore.
+// Without Cilium there is nothing to clean up.
+func (r *Reconciler) cleanupCiliumNetworkPolicies(ctx context.Context, desired map[types.NamespacedName]bool) error {
+	policyList := networkpolicy.NewCiliumNetworkPolicyList()
+
+	err := r.apiReader.List(ctx, policyList, client.MatchingLabels(r.policyMatchLabels()))
+	if meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var errs []error
+
+	for _, policy := range policyList.Items {
+		if desired[types.NamespacedName{Name: policy.GetName(), Namespace: policy.GetNamespace()}] {
+			continue
+		}
+
+		errs = append(errs, networkpolicy.CiliumQuery(r.client, r.apiReader, log).Delete(ctx, &policy))
+	}
+
+	return goerrors.Join(errs...)
+}
+
+func (r *Reconciler) policyLabels() map[string]string {
+	return labels.NewCoreLabels(r.dk.Name, PolicyComponentLabel).BuildLabels()
+}
+
+func (r *Reconciler) policyMatchLabels() map[string]string {
+	return labels.NewCoreLabels(r.dk.Name, PolicyComponentLabel).BuildMatchLabels()
+}
@@ -0,0 +1,159 @@
+package networkpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/networkpolicy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func createTestNetworkPolicy(dkName, name, namespace string) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels.NewCoreLabels(dkName, PolicyComponentLabel).BuildLabels(),
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("policies are created for the components and injected namespaces", func(t *testing.T) {
+		dk := createTestDynaKube()
+		dk.Spec.NetworkPolicy.EnableInjectedNamespaces = true
+		clt := fake.NewClient(createTestInjectedNamespace(dk.Name))
+
+		err := NewReconciler(clt, clt, dk).Reconcile(ctx)
+		require.NoError(t, err)
+
+		var oneAgentPolicy networkingv1.NetworkPolicy
+		err = clt.Get(ctx, types.NamespacedName{Name: BuildName(dk.Name, OneAgentTarget), Namespace: dk.Namespace}, &oneAgentPolicy)
+		require.NoError(t, err)
+		assert.NotEmpty(t, oneAgentPolicy.OwnerReferences)
+
+		var injectionPolicy networkingv1.NetworkPolicy
+		err = clt.Get(ctx, types.NamespacedName{Name: BuildName(dk.Name, InjectionTarget), Namespace: testInjectedNamespace}, &injectionPolicy)
+		require.NoError(t, err)
+		assert.Empty(t, injectionPolicy.OwnerReferences)
+
+		condition := meta.FindStatusCondition(*dk.Conditions(), conditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	})
+	t.Run("outdated policies are removed, the ones of other dynakubes are kept", func(t *testing.T) {
+		dk := createTestDynaKube()
+		outdatedPolicy := createTestNetworkPolicy(dk.Name, BuildName(dk.Name, InjectionTarget), "removed")
+		otherPolicy := createTestNetworkPolicy("other", BuildName("other", InjectionTarget), "removed")
+		clt := fake.NewClient(outdatedPolicy, otherPolicy)
+
+		err := NewReconciler(clt, clt, dk).Reconcile(ctx)
+		require.NoError(t, err)
+
+		err = clt.Get(ctx, client.ObjectKeyFromObject(outdatedPolicy), &networkingv1.NetworkPolicy{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		err = clt.Get(ctx, client.ObjectKeyFromObject(otherPolicy), &networkingv1.NetworkPolicy{})
+		require.NoError(t, err)
+	})
+	t.Run("disabled => policies and condition are removed", func(t *testing.T) {
+		dk := createTestDynaKube()
+		clt := fake.NewClient()
+
+		err := NewReconciler(clt, clt, dk).Reconcile(ctx)
+		require.NoError(t, err)
+
+		dk.Spec.NetworkPolicy = nil
+
+		err = NewReconciler(clt, clt, dk).Reconcile(ctx)
+		require.NoError(t, err)
+
+		var policies networkingv1.NetworkPolicyList
+		require.NoError(t, clt.List(ctx, &policies))
+		assert.Empty(t, policies.Items)
+		assert.Nil(t, meta.FindStatusCondition(*dk.Conditions(), conditionType))
+	})
+	t.Run("cilium enabled => cilium policies replace the network policies", func(t *testing.T) {
+		dk := createTestDynaKube()
+		clt := fake.NewClient()
+
+		err := NewReconciler(clt, clt, dk).Reconcile(ctx)
+		require.NoError(t, err)
+
+		dk.Spec.NetworkPolicy.EnableCilium = true
+
+		err = NewReconciler(clt, clt, dk).Reconcile(ctx)
+		require.NoError(t, err)
+
+		var policies networkingv1.NetworkPolicyList
+		require.NoError(t, clt.List(ctx, &policies))
+		assert.Empty(t, policies.Items)
+
+		ciliumPolicy := networkpolicy.NewCiliumNetworkPolicy()
+		err = clt.Get(ctx, types.NamespacedName{Name: BuildName(dk.Name, OneAgentTarget), Namespace: dk.Namespace}, ciliumPolicy)
+		require.NoError(t, err)
+		assert.NotEmpty(t, ciliumPolicy.GetOwnerReferences())
+	})
+	t.Run("cilium policy create fails => error", func(t *testing.T) {
+		dk := createTestDynaKube()
+		dk.Spec.NetworkPolicy.EnableCilium = true
+		clt := fake.NewClientWithInterceptors(interceptor.Funcs{
+			Create: func(_ context.Context, _ client.WithWatch, _ client.Object, _ ...client.CreateOption) error {
+				return &meta.NoKindMatchError{GroupKind: networkpolicy.NewCiliumNetworkPolicy().GroupVersionKind().GroupKind()}
+			},
+		})
+
+		err := NewReconciler(clt, clt, dk).Reconcile(ctx)
+		require.ErrorContains(t, err, "Cilium has to be installed")
+
+		condition := meta.FindStatusCondition(*dk.Conditions(), conditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	})
+	t.Run("unknown k8s client error => error", func(t *testing.T) {
+		dk := createTestDynaKube()
+		clt := fake.NewClientWithInterceptors(interceptor.Funcs{
+			Create: func(_ context.Context, _ client.WithWatch, _ client.Object, _ ...client.CreateOption) error {
+				return errors.New("BOOM")
+			},
+		})
+
+		err := NewReconciler(clt, clt, dk).Reconcile(ctx)
+		require.Error(t, err)
+
+		condition := meta.FindStatusCondition(*dk.Conditions(), conditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	})
+}
+
+func TestCleanup(t *testing.T) {
+	ctx := context.Background()
+
+	dk := createTestDynaKube()
+	injectionPolicy := createTestNetworkPolicy(dk.Name, BuildName(dk.Name, InjectionTarget), testInjectedNamespace)
+	otherPolicy := createTestNetworkPolicy("other", BuildName("other", InjectionTarget), testInjectedNamespace)
+	clt := fake.NewClient(injectionPolicy, otherPolicy)
+
+	err := Cleanup(ctx, clt, clt, dk)
+	require.NoError(t, err)
+
+	err = clt.Get(ctx, client.ObjectKeyFromObject(injectionPolicy), &networkingv1.NetworkPolicy{})
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	err = clt.Get(ctx, client.ObjectKeyFromObject(otherPolicy), &networkingv1.NetworkPolicy{})
+	require.NoError(t, err)
+}
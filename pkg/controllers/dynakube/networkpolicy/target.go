@@ -0,0 +1,227 @@
+package networkpolicy
+
+import (
+	"context"
+	"net"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	agconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/activegate"
+	oaconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/pkg/errors"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// egressTarget describes the pods of a component and the communication hosts they have to reach.
+type egressTarget struct {
+	name        string
+	namespace   string
+	podSelector map[string]string
+	hosts       []dtclient.CommunicationHost
+	// apiServerHosts are the endpoints of the Kubernetes API, which the component watches
+	apiServerHosts []dtclient.CommunicationHost
+	// scrapeNamespaces selects the namespaces of the pods scraped by the prometheus protocol, nil means no scraping
+	scrapeNamespaces *metav1.LabelSelector
+	// allowActiveGate permits the traffic to the in-cluster ActiveGate, which may be used as communication host
+	allowActiveGate bool
+	// allowAPIServer permits the traffic to the Kubernetes API
+	allowAPIServer bool
+	// owned policies are in the namespace of the DynaKube, the policies of the injected namespaces can't have an owner reference
+	owned bool
+}
+
+func BuildName(dkName, target string) string {
+	return dkName + "-" + target + "-egress"
+}
+
+func getEgressTargets(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube) ([]egressTarget, error) {
+	apiHost, err := dtclient.ParseEndpoint(dk.Spec.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	isActiveGateEnabled := dk.ActiveGate().IsEnabled()
+	oneAgentHosts := appendUniqueHosts([]dtclient.CommunicationHost{apiHost}, oaconnectioninfo.GetCommunicationHosts(dk))
+
+	if dk.NeedsOneAgentProxy() {
+		oneAgentHosts, err = appendProxyHost(ctx, apiReader, dk, dynakube.OneAgentProxyComponent, oneAgentHosts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var targets []egressTarget
+
+	if dk.OneAgent().IsDaemonsetRequired() {
+		targets = append(targets, newOwnedEgressTarget(dk, OneAgentTarget, labels.OneAgentComponentLabel, oneAgentHosts, isActiveGateEnabled))
+	}
+
+	isOtelCollectorEnabled := dk.TelemetryIngest().IsEnabled() || dk.Extensions().IsPrometheusEnabled()
+
+	var apiServerHosts []dtclient.CommunicationHost
+	if isActiveGateEnabled || isOtelCollectorEnabled {
+		apiServerHosts, err = getAPIServerHosts(ctx, apiReader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isActiveGateEnabled {
+		activeGateHosts := appendUniqueHosts([]dtclient.CommunicationHost{apiHost}, agconnectioninfo.GetEndpointsAsCommunicationHosts(dk))
+		if dk.NeedsActiveGateProxy() {
+			activeGateHosts, err = appendProxyHost(ctx, apiReader, dk, dynakube.ActiveGateProxyComponent, activeGateHosts)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		activeGateTarget := newOwnedEgressTarget(dk, ActiveGateTarget, labels.ActiveGateComponentLabel, activeGateHosts, false)
+		activeGateTarget.allowAPIServer = true
+		activeGateTarget.apiServerHosts = apiServerHosts
+		targets = append(targets, activeGateTarget)
+	}
+
+	if isOtelCollectorEnabled {
+		otelCollectorHosts := []dtclient.CommunicationHost{apiHost}
+		if dk.HasComponentProxy(dynakube.OtelCollectorProxyComponent) {
+			otelCollectorHosts, err = appendProxyHost(ctx, apiReader, dk, dynakube.OtelCollectorProxyComponent, otelCollectorHosts)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		otelCollectorTarget := newOwnedEgressTarget(dk, OtelCollectorTarget, labels.OtelCComponentLabel, otelCollectorHosts, isActiveGateEnabled)
+		otelCollectorTarget.allowAPIServer = true
+		otelCollectorTarget.apiServerHosts = apiServerHosts
+
+		if dk.TelemetryIngest().IsPrometheusEnabled() {
+			otelCollectorTarget.scrapeNamespaces = &metav1.LabelSelector{}
+			if selector := dk.TelemetryIngest().GetPrometheusNamespaceSelector(); selector != nil {
+				otelCollectorTarget.scrapeNamespaces = selector
+			}
+		}
+
+		targets = append(targets, otelCollectorTarget)
+	}
+
+	if dk.IsInjectedNamespaceNetworkPolicyEnabled() && (dk.OneAgent().IsAppInjectionNeeded() || dk.MetadataEnrichment().IsEnabled() || dk.OTLPExporterConfiguration().IsEnabled()) {
+		namespaces, err := mapper.GetNamespacesForDynakube(ctx, apiReader, dk.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, namespace := range namespaces {
+			// the injected pods aren't labeled, so the policy applies to every pod of the namespace, that's why it has to be enabled explicitly
+			targets = append(targets, egressTarget{
+				name:            BuildName(dk.Name, InjectionTarget),
+				namespace:       namespace.Name,
+				hosts:           oneAgentHosts,
+				allowActiveGate: isActiveGateEnabled,
+			})
+		}
+	}
+
+	return targets, nil
+}
+
+func newOwnedEgressTarget(dk *dynakube.DynaKube, target, componentLabel string, hosts []dtclient.CommunicationHost, allowActiveGate bool) egressTarget {
+	return egressTarget{
+		name:            BuildName(dk.Name, target),
+		namespace:       dk.Namespace,
+		podSelector:     labels.NewAppLabels(componentLabel, dk.Name, "", "").BuildMatchLabels(),
+		hosts:           hosts,
+		allowActiveGate: allowActiveGate,
+		owned:           true,
+	}
+}
+
+// appendProxyHost adds the proxy of the component, as the component connects to it instead of the communication hosts.
+func appendProxyHost(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube, component dynakube.ProxyComponent, hosts []dtclient.CommunicationHost) ([]dtclient.CommunicationHost, error) {
+	proxyURL, err := dk.ComponentProxy(ctx, apiReader, component)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxyURL == "" {
+		return hosts, nil
+	}
+
+	proxyHost, err := dtclient.ParseEndpoint(proxyURL)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse the proxy of the %s", component)
+	}
+
+	return appendUniqueHosts(hosts, []dtclient.CommunicationHost{proxyHost}), nil
+}
+
+// getAPIServerHosts returns the endpoints of the kubernetes service, a NetworkPolicy is applied after the service IP was translated to them.
+func getAPIServerHosts(ctx context.Context, apiReader client.Reader) ([]dtclient.CommunicationHost, error) {
+	endpointSlices := &discoveryv1.EndpointSliceList{}
+
+	err := apiReader.List(ctx, endpointSlices, client.InNamespace(metav1.NamespaceDefault), client.MatchingLabels{discoveryv1.LabelServiceName: apiServerServiceName})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list the endpoints of the Kubernetes API")
+	}
+
+	var hosts []dtclient.CommunicationHost
+
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, port := range endpointSlice.Ports {
+			if port.Port == nil {
+				continue
+			}
+
+			for _, endpoint := range endpointSlice.Endpoints {
+				for _, address := range endpoint.Addresses {
+					hosts = appendUniqueHosts(hosts, []dtclient.CommunicationHost{{Host: address, Port: uint32(*port.Port)}}) //nolint:gosec
+				}
+			}
+		}
+	}
+
+	if len(hosts) == 0 {
+		log.Info("no endpoints of the Kubernetes API found, the egress to it is not allowed")
+	}
+
+	return hosts, nil
+}
+
+func appendUniqueHosts(hosts []dtclient.CommunicationHost, additionalHosts []dtclient.CommunicationHost) []dtclient.CommunicationHost {
+	for _, additionalHost := range additionalHosts {
+		if !containsHost(hosts, additionalHost) {
+			hosts = append(hosts, additionalHost)
+		}
+	}
+
+	return hosts
+}
+
+func containsHost(hosts []dtclient.CommunicationHost, host dtclient.CommunicationHost) bool {
+	for _, existing := range hosts {
+		if existing.Host == host.Host && existing.Port == host.Port {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitCommunicationHosts(hosts []dtclient.CommunicationHost) (ipHosts, fqdnHosts []dtclient.CommunicationHost) {
+	for _, host := range hosts {
+		if net.ParseIP(host.Host) != nil {
+			ipHosts = append(ipHosts, host)
+		} else {
+			fqdnHosts = append(fqdnHosts, host)
+		}
+	}
+
+	return
+}
+
+func activeGateSelector(dk *dynakube.DynaKube) map[string]string {
+	return labels.NewAppLabels(labels.ActiveGateComponentLabel, dk.Name, "", "").BuildMatchLabels()
+}
@@ -0,0 +1,333 @@
+package oneagent
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/version"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	k8sdaemonset "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	webhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const crashLoopBackOffReason = "CrashLoopBackOff"
+
+// reconcileCanary drives the canary phase of a rollout started by the version reconciler.
+// The new version is only used on the canary nodes until the soak time passed without crash-looping OneAgent or injected application pods,
+// afterwards it is promoted to all nodes, otherwise the previous version is restored.
+func (r *Reconciler) reconcileCanary(ctx context.Context) error {
+	oa := r.dk.OneAgent()
+	if !oa.IsCanaryRolloutInProgress() {
+		return nil
+	}
+
+	if !oa.IsRolloutEnabled() {
+		log.Info("rollout configuration was removed, promoting the new version")
+		r.promoteCanary()
+
+		return nil
+	}
+
+	if len(oa.Status.Rollout.CanaryNodes) == 0 {
+		canaryNodes, err := r.selectCanaryNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		oa.Status.Rollout.CanaryNodes = canaryNodes
+		setRolloutCanaryCondition(r.dk.Conditions(), canaryNodes)
+
+		log.Info("selected canary nodes", "nodes", canaryNodes)
+	}
+
+	canaryReady := true
+
+	if oa.IsDaemonsetRequired() {
+		var (
+			crashLooping bool
+			err          error
+		)
+
+		canaryReady, crashLooping, err = r.checkCanaryPods(ctx)
+		if err != nil {
+			return err
+		}
+
+		if crashLooping {
+			r.rollbackCanary()
+
+			return nil
+		}
+	}
+
+	crashLooping, err := r.checkCanaryCodeModules(ctx)
+	if err != nil {
+		return err
+	}
+
+	if crashLooping {
+		r.rollbackCanary()
+
+		return nil
+	}
+
+	now := metav1.Now()
+	if canaryReady && now.Sub(oa.Status.Rollout.StartedAt.Time) >= oa.GetRolloutSoakTime() {
+		r.promoteCanary()
+	}
+
+	return nil
+}
+
+// selectCanaryNodes returns the nodes matching the canary node selector, or if none is set,
+// the configured percentage of the nodes matching the OneAgent node selector.
+func (r *Reconciler) selectCanaryNodes(ctx context.Context) ([]string, error) {
+	oa := r.dk.OneAgent()
+
+	nodeSelector := oa.GetCanaryNodeSelector()
+	if len(nodeSelector) == 0 {
+		nodeSelector = oa.GetNodeSelector(nil)
+	}
+
+	var nodeList corev1.NodeList
+
+	err := r.client.List(ctx, &nodeList, client.MatchingLabelsSelector{Selector: k8slabels.SelectorFromSet(nodeSelector)})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	nodeNames := make([]string, 0, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		nodeNames = append(nodeNames, node.Name)
+	}
+
+	slices.Sort(nodeNames)
+
+	if len(oa.GetCanaryNodeSelector()) > 0 || len(nodeNames) == 0 {
+		return nodeNames, nil
+	}
+
+	count := int(math.Ceil(float64(len(nodeNames)*oa.GetCanaryPercentage()) / 100))
+
+	return nodeNames[:max(1, count)], nil
+}
+
+// checkCanaryPods reports whether the canary DaemonSet runs the new version ready on all of its nodes,
+// and whether one of the OneAgent pods with the new version on the canary nodes is crash-looping.
+func (r *Reconciler) checkCanaryPods(ctx context.Context) (ready bool, crashLooping bool, err error) {
+	oa := r.dk.OneAgent()
+
+	var canaryDaemonSet appsv1.DaemonSet
+
+	err = r.client.Get(ctx, client.ObjectKey{Name: oa.GetCanaryDaemonsetName(), Namespace: r.dk.Namespace}, &canaryDaemonSet)
+	if k8serrors.IsNotFound(err) {
+		// the canary DaemonSet is created after the canary nodes were selected
+		return false, false, nil
+	} else if err != nil {
+		return false, false, errors.WithStack(err)
+	}
+
+	dsStatus := canaryDaemonSet.Status
+	ready = dsStatus.ObservedGeneration >= canaryDaemonSet.Generation &&
+		dsStatus.UpdatedNumberScheduled == dsStatus.DesiredNumberScheduled &&
+		dsStatus.NumberReady == dsStatus.DesiredNumberScheduled
+
+	appLabels := labels.NewAppLabels(labels.OneAgentComponentLabel, r.dk.Name, deploymentmetadata.GetOneAgentDeploymentType(*r.dk), "")
+
+	var podList corev1.PodList
+
+	err = r.client.List(ctx, &podList, client.InNamespace(r.dk.Namespace), client.MatchingLabels(appLabels.BuildMatchLabels()))
+	if err != nil {
+		return false, false, errors.WithStack(err)
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !oa.IsCanaryNode(pod.Spec.NodeName) || !isPodUpdated(pod, oa.GetImage()) {
+			continue
+		}
+
+		if isPodCrashLooping(pod, oa.GetRolloutMaxRestarts()) {
+			log.Info("OneAgent pod on canary node is crash-looping", "pod", pod.Name, "node", pod.Spec.NodeName)
+
+			return false, true, nil
+		}
+	}
+
+	return ready, false, nil
+}
+
+// checkCanaryCodeModules reports whether one of the application pods, that were injected with the new code modules on the canary nodes, is crash-looping.
+// Only the pods started since the rollout are considered, as the older ones still use the previous code modules.
+func (r *Reconciler) checkCanaryCodeModules(ctx context.Context) (crashLooping bool, err error) {
+	oa := r.dk.OneAgent()
+	if oa.Status.Rollout.PreviousCodeModules == nil || !oa.IsAppInjectionNeeded() {
+		return false, nil
+	}
+
+	var namespaceList corev1.NamespaceList
+
+	err = r.client.List(ctx, &namespaceList, client.MatchingLabels{webhook.InjectionInstanceLabel: r.dk.Name})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	for _, namespace := range namespaceList.Items {
+		var podList corev1.PodList
+
+		// the apiReader is used, so no informer is started for the pods of all namespaces
+		err = r.apiReader.List(ctx, &podList, client.InNamespace(namespace.Name))
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			if !oa.IsCanaryNode(pod.Spec.NodeName) || pod.Annotations[webhook.AnnotationDynatraceInjected] != "true" ||
+				pod.CreationTimestamp.Before(oa.Status.Rollout.StartedAt) {
+				continue
+			}
+
+			if isPodCrashLooping(pod, oa.GetRolloutMaxRestarts()) {
+				log.Info("application pod with the new code modules on canary node is crash-looping", "pod", pod.Name, "namespace", pod.Namespace, "node", pod.Spec.NodeName)
+
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// reconcileCanaryDaemonSet creates the DaemonSet that runs the new version on the canary nodes during the canary phase of a rollout.
+func (r *Reconciler) reconcileCanaryDaemonSet(ctx context.Context) error {
+	oa := r.dk.OneAgent()
+	if !oa.IsCanaryRolloutInProgress() || len(oa.Status.Rollout.CanaryNodes) == 0 {
+		return nil
+	}
+
+	dsDesired, err := BuildCanaryDaemonSet(r.dk, r.clusterID)
+	if err != nil {
+		setDaemonSetGenerationFailedCondition(r.dk.Conditions(), err)
+
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(r.dk, dsDesired, scheme.Scheme); err != nil {
+		return err
+	}
+
+	_, err = k8sdaemonset.Query(r.client, r.apiReader, log).WithOwner(r.dk).CreateOrUpdate(ctx, dsDesired)
+	if err != nil {
+		log.Info("failed to roll out canary OneAgent DaemonSet")
+		conditions.SetKubeAPIError(r.dk.Conditions(), oaConditionType, err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Reconciler) removeCanaryDaemonSet(ctx context.Context) error {
+	canaryDaemonSet := appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: r.dk.OneAgent().GetCanaryDaemonsetName(), Namespace: r.dk.Namespace}}
+
+	return errors.WithStack(client.IgnoreNotFound(r.client.Delete(ctx, &canaryDaemonSet)))
+}
+
+func (r *Reconciler) promoteCanary() {
+	rollout := r.dk.OneAgent().Status.Rollout
+
+	log.Info("promoting canary rollout to all nodes", "nodes", rollout.CanaryNodes)
+
+	rollout.Phase = oneagent.RolloutPhasePromoted
+	rollout.CanaryNodes = nil
+	rollout.PreviousVersion = nil
+	rollout.PreviousCodeModules = nil
+
+	setRolloutPromotedCondition(r.dk.Conditions())
+}
+
+func (r *Reconciler) rollbackCanary() {
+	oa := r.dk.OneAgent()
+	rollout := oa.Status.Rollout
+
+	if rollout.PreviousVersion != nil {
+		failedVersion := version.RolloutKey(oa.Status.VersionStatus)
+		rollout.FailedVersions = appendIfMissing(rollout.FailedVersions, failedVersion)
+		oa.Status.VersionStatus = *rollout.PreviousVersion
+	}
+
+	if rollout.PreviousCodeModules != nil {
+		failedVersion := version.RolloutKey(oa.CodeModulesStatus.VersionStatus)
+		rollout.FailedVersions = appendIfMissing(rollout.FailedVersions, failedVersion)
+		oa.CodeModulesStatus.VersionStatus = *rollout.PreviousCodeModules
+	}
+
+	log.Info("rolled back canary rollout", "failedVersions", rollout.FailedVersions)
+
+	rollout.Phase = oneagent.RolloutPhaseRolledBack
+	rollout.CanaryNodes = nil
+	rollout.PreviousVersion = nil
+	rollout.PreviousCodeModules = nil
+
+	setRolloutRolledBackCondition(r.dk.Conditions(), rollout.FailedVersions)
+}
+
+func appendIfMissing(values []string, value string) []string {
+	if value == "" || slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
+
+func isPodUpdated(pod *corev1.Pod, image string) bool {
+	if image == "" {
+		return true
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.Image != image {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func isPodCrashLooping(pod *corev1.Pod, maxRestarts int32) bool {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.RestartCount >= maxRestarts {
+			return true
+		}
+
+		if containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason == crashLoopBackOffReason {
+			return true
+		}
+	}
+
+	return false
+}
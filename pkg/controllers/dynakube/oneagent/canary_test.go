@@ -0,0 +1,330 @@
+package oneagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	webhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	testCanaryNamespace = "dynatrace"
+	testOldImage        = "repo@sha256:old"
+	testNewImage        = "repo@sha256:new"
+)
+
+func TestReconcileCanary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no canary in progress => nothing happens", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now())
+		dk.Status.OneAgent.Rollout = nil
+		r := &Reconciler{client: fake.NewClient(), dk: dk}
+
+		require.NoError(t, r.reconcileCanary(ctx))
+		assert.Nil(t, meta.FindStatusCondition(*dk.Conditions(), rolloutConditionType))
+	})
+
+	t.Run("select percentage of nodes", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now())
+		dk.Spec.OneAgent.Rollout.CanaryPercentage = ptr.To(25)
+		r := &Reconciler{client: fake.NewClient(createNode("node-c"), createNode("node-a"), createNode("node-b"), createNode("node-d"), createNode("node-e")), dk: dk}
+
+		require.NoError(t, r.reconcileCanary(ctx))
+
+		assert.Equal(t, []string{"node-a", "node-b"}, dk.Status.OneAgent.Rollout.CanaryNodes)
+		condition := meta.FindStatusCondition(*dk.Conditions(), rolloutConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, rolloutCanaryReason, condition.Reason)
+	})
+
+	t.Run("select nodes by canary node selector", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now())
+		dk.Spec.OneAgent.Rollout.CanaryNodeSelector = map[string]string{"canary": "true"}
+		canaryNode := createNode("node-b")
+		canaryNode.Labels = map[string]string{"canary": "true"}
+		r := &Reconciler{client: fake.NewClient(createNode("node-a"), canaryNode), dk: dk}
+
+		require.NoError(t, r.reconcileCanary(ctx))
+
+		assert.Equal(t, []string{"node-b"}, dk.Status.OneAgent.Rollout.CanaryNodes)
+	})
+
+	t.Run("canary DaemonSet not created yet => still canary", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now().Add(-2 * time.Hour))
+		dk.Status.OneAgent.Rollout.CanaryNodes = []string{"node-a"}
+		r := &Reconciler{client: fake.NewClient(), dk: dk}
+
+		require.NoError(t, r.reconcileCanary(ctx))
+
+		assert.Equal(t, oneagent.RolloutPhaseCanary, dk.Status.OneAgent.Rollout.Phase)
+	})
+
+	t.Run("canary DaemonSet not ready => still canary", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now().Add(-2 * time.Hour))
+		dk.Status.OneAgent.Rollout.CanaryNodes = []string{"node-a"}
+		canaryDaemonSet := createCanaryDaemonSet(dk)
+		canaryDaemonSet.Status.NumberReady = 0
+		r := &Reconciler{client: fake.NewClient(canaryDaemonSet), dk: dk}
+
+		require.NoError(t, r.reconcileCanary(ctx))
+
+		assert.Equal(t, oneagent.RolloutPhaseCanary, dk.Status.OneAgent.Rollout.Phase)
+	})
+
+	t.Run("canary ready before soak time => still canary", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now())
+		dk.Status.OneAgent.Rollout.CanaryNodes = []string{"node-a"}
+		r := &Reconciler{client: fake.NewClient(createCanaryDaemonSet(dk), createOneAgentPod(dk, "pod-a", "node-a", testNewImage)), dk: dk}
+
+		require.NoError(t, r.reconcileCanary(ctx))
+
+		assert.Equal(t, oneagent.RolloutPhaseCanary, dk.Status.OneAgent.Rollout.Phase)
+	})
+
+	t.Run("canary ready after soak time => promoted", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now().Add(-2 * time.Hour))
+		dk.Status.OneAgent.Rollout.CanaryNodes = []string{"node-a"}
+		r := &Reconciler{client: fake.NewClient(createCanaryDaemonSet(dk), createOneAgentPod(dk, "pod-a", "node-a", testNewImage)), dk: dk}
+
+		require.NoError(t, r.reconcileCanary(ctx))
+
+		assert.Equal(t, oneagent.RolloutPhasePromoted, dk.Status.OneAgent.Rollout.Phase)
+		assert.Nil(t, dk.Status.OneAgent.Rollout.PreviousVersion)
+		assert.Equal(t, testNewImage, dk.Status.OneAgent.ImageID)
+		condition := meta.FindStatusCondition(*dk.Conditions(), rolloutConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	})
+
+	t.Run("crash-looping canary pod => rolled back", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now())
+		dk.Status.OneAgent.Rollout.CanaryNodes = []string{"node-a"}
+		pod := createOneAgentPod(dk, "pod-a", "node-a", testNewImage)
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{RestartCount: oneagent.DefaultRolloutMaxRestarts}}
+		r := &Reconciler{client: fake.NewClient(createCanaryDaemonSet(dk), pod), dk: dk}
+
+		require.NoError(t, r.reconcileCanary(ctx))
+
+		assert.Equal(t, oneagent.RolloutPhaseRolledBack, dk.Status.OneAgent.Rollout.Phase)
+		assert.Equal(t, testOldImage, dk.Status.OneAgent.ImageID)
+		assert.Equal(t, []string{testNewImage}, dk.Status.OneAgent.Rollout.FailedVersions)
+		condition := meta.FindStatusCondition(*dk.Conditions(), rolloutConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, rolloutRolledBackReason, condition.Reason)
+	})
+}
+
+func TestCheckCanaryCodeModules(t *testing.T) {
+	ctx := context.Background()
+
+	createDynaKube := func() *dynakube.DynaKube {
+		dk := createCanaryDynaKube(time.Now().Add(-time.Hour))
+		dk.Status.OneAgent.Rollout.CanaryNodes = []string{"node-a"}
+		dk.Status.OneAgent.Rollout.PreviousCodeModules = &status.VersionStatus{Version: "1.0.0"}
+
+		return dk
+	}
+
+	t.Run("crash-looping injected pod on canary node => crash-looping", func(t *testing.T) {
+		dk := createDynaKube()
+		fakeClient := fake.NewClient(createInjectedNamespace(dk), createInjectedPod("app", "node-a", time.Now(), true))
+		r := &Reconciler{client: fakeClient, apiReader: fakeClient, dk: dk}
+
+		crashLooping, err := r.checkCanaryCodeModules(ctx)
+		require.NoError(t, err)
+		assert.True(t, crashLooping)
+	})
+
+	t.Run("crash-looping pods not using the new code modules => ignored", func(t *testing.T) {
+		dk := createDynaKube()
+		otherNode := createInjectedPod("other-node", "node-b", time.Now(), true)
+		startedBefore := createInjectedPod("started-before", "node-a", time.Now().Add(-2*time.Hour), true)
+		notInjected := createInjectedPod("not-injected", "node-a", time.Now(), true)
+		notInjected.Annotations = nil
+		fakeClient := fake.NewClient(createInjectedNamespace(dk), otherNode, startedBefore, notInjected)
+		r := &Reconciler{client: fakeClient, apiReader: fakeClient, dk: dk}
+
+		crashLooping, err := r.checkCanaryCodeModules(ctx)
+		require.NoError(t, err)
+		assert.False(t, crashLooping)
+	})
+
+	t.Run("crash-looping injected pod => rolled back", func(t *testing.T) {
+		dk := createDynaKube()
+		fakeClient := fake.NewClient(createCanaryDaemonSet(dk), createInjectedNamespace(dk), createInjectedPod("app", "node-a", time.Now(), true))
+		r := &Reconciler{client: fakeClient, apiReader: fakeClient, dk: dk}
+
+		require.NoError(t, r.reconcileCanary(ctx))
+
+		assert.Equal(t, oneagent.RolloutPhaseRolledBack, dk.Status.OneAgent.Rollout.Phase)
+		assert.Equal(t, "1.0.0", dk.Status.CodeModules.Version)
+	})
+}
+
+func TestCanaryDaemonSets(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("canary phase => stable DaemonSet keeps previous version and excludes canary nodes", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now())
+		dk.Status.OneAgent.Rollout.CanaryNodes = []string{"node-a"}
+
+		ds, err := BuildDesiredDaemonSet(dk, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, testOldImage, ds.Spec.Template.Spec.Containers[0].Image)
+		assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+		assertNodeNameRequirement(t, ds, corev1.NodeSelectorOpNotIn)
+		assert.Equal(t, testNewImage, dk.Status.OneAgent.ImageID)
+	})
+
+	t.Run("canary phase => canary DaemonSet runs new version on canary nodes", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now())
+		dk.Status.OneAgent.Rollout.CanaryNodes = []string{"node-a"}
+		fakeClient := fake.NewClient()
+		r := &Reconciler{client: fakeClient, apiReader: fakeClient, dk: dk}
+
+		require.NoError(t, r.reconcileCanaryDaemonSet(ctx))
+
+		var ds appsv1.DaemonSet
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: dk.OneAgent().GetCanaryDaemonsetName(), Namespace: testCanaryNamespace}, &ds))
+		assert.Equal(t, testNewImage, ds.Spec.Template.Spec.Containers[0].Image)
+		assertNodeNameRequirement(t, &ds, corev1.NodeSelectorOpIn)
+
+		stableDs, err := BuildDesiredDaemonSet(dk, "")
+		require.NoError(t, err)
+		assert.NotEqual(t, stableDs.Spec.Selector.MatchLabels, ds.Spec.Selector.MatchLabels)
+	})
+
+	t.Run("promoted => canary DaemonSet removed", func(t *testing.T) {
+		dk := createCanaryDynaKube(time.Now())
+		canaryDaemonSet := createCanaryDaemonSet(dk)
+		dk.Status.OneAgent.Rollout.Phase = oneagent.RolloutPhasePromoted
+		fakeClient := fake.NewClient(canaryDaemonSet)
+		r := &Reconciler{client: fakeClient, apiReader: fakeClient, dk: dk}
+
+		require.NoError(t, r.reconcileCanaryDaemonSet(ctx))
+		require.NoError(t, r.removeCanaryDaemonSet(ctx))
+
+		err := fakeClient.Get(ctx, types.NamespacedName{Name: canaryDaemonSet.Name, Namespace: testCanaryNamespace}, &appsv1.DaemonSet{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		ds, err := BuildDesiredDaemonSet(dk, "")
+		require.NoError(t, err)
+		assert.Equal(t, testNewImage, ds.Spec.Template.Spec.Containers[0].Image)
+		assert.NotEqual(t, appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+	})
+}
+
+func assertNodeNameRequirement(t *testing.T, ds *appsv1.DaemonSet, operator corev1.NodeSelectorOperator) {
+	t.Helper()
+
+	terms := ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.NotEmpty(t, terms)
+
+	for _, term := range terms {
+		assert.Contains(t, term.MatchFields, corev1.NodeSelectorRequirement{Key: "metadata.name", Operator: operator, Values: []string{"node-a"}})
+	}
+}
+
+func createCanaryDynaKube(startedAt time.Time) *dynakube.DynaKube {
+	dk := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: testCanaryNamespace},
+		Spec: dynakube.DynaKubeSpec{
+			OneAgent: oneagent.Spec{
+				CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{},
+				Rollout:              &oneagent.RolloutSpec{},
+			},
+		},
+	}
+	dk.Status.OneAgent.VersionStatus = status.VersionStatus{ImageID: testNewImage, Version: "1.301.0.20240201-000000"}
+	dk.Status.OneAgent.Rollout = &oneagent.RolloutStatus{
+		Phase:           oneagent.RolloutPhaseCanary,
+		StartedAt:       &metav1.Time{Time: startedAt},
+		PreviousVersion: &status.VersionStatus{ImageID: testOldImage, Version: "1.300.0.20240101-000000"},
+	}
+
+	return dk
+}
+
+func createNode(name string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func createOneAgentPod(dk *dynakube.DynaKube, name, nodeName, image string) *corev1.Pod {
+	appLabels := labels.NewAppLabels(labels.OneAgentComponentLabel, dk.Name, deploymentmetadata.GetOneAgentDeploymentType(*dk), "")
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testCanaryNamespace,
+			Labels:    appLabels.BuildLabels(),
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []corev1.Container{{Name: "dynatrace-oneagent", Image: image}},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func createCanaryDaemonSet(dk *dynakube.DynaKube) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dk.OneAgent().GetCanaryDaemonsetName(),
+			Namespace: testCanaryNamespace,
+		},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 1,
+			UpdatedNumberScheduled: 1,
+			NumberReady:            1,
+		},
+	}
+}
+
+func createInjectedNamespace(dk *dynakube.DynaKube) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "app",
+			Labels: map[string]string{webhook.InjectionInstanceLabel: dk.Name},
+		},
+	}
+}
+
+func createInjectedPod(name, nodeName string, createdAt time.Time, crashLooping bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "app",
+			CreationTimestamp: metav1.Time{Time: createdAt},
+			Annotations:       map[string]string{webhook.AnnotationDynatraceInjected: "true"},
+		},
+		Spec: corev1.PodSpec{NodeName: nodeName},
+	}
+
+	if crashLooping {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: crashLoopBackOffReason}},
+		}}
+	}
+
+	return pod
+}
@@ -1,6 +1,8 @@
 package oneagent
 
 import (
+	"strings"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -31,3 +33,41 @@ func setDaemonSetGenerationFailedCondition(conditions *[]metav1.Condition, err e
 	}
 	_ = meta.SetStatusCondition(conditions, condition)
 }
+
+const (
+	rolloutConditionType = "OneAgentRollout"
+
+	rolloutCanaryReason     = "Canary"
+	rolloutPromotedReason   = "Promoted"
+	rolloutRolledBackReason = "RolledBack"
+)
+
+func setRolloutCanaryCondition(conditions *[]metav1.Condition, canaryNodes []string) {
+	condition := metav1.Condition{
+		Type:    rolloutConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  rolloutCanaryReason,
+		Message: "The new version is rolled out to the canary nodes: " + strings.Join(canaryNodes, ", "),
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
+
+func setRolloutPromotedCondition(conditions *[]metav1.Condition) {
+	condition := metav1.Condition{
+		Type:    rolloutConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  rolloutPromotedReason,
+		Message: "The new version was promoted to all nodes.",
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
+
+func setRolloutRolledBackCondition(conditions *[]metav1.Condition, failedVersions []string) {
+	condition := metav1.Condition{
+		Type:    rolloutConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  rolloutRolledBackReason,
+		Message: "The new version was crash-looping on the canary nodes and was rolled back, failed versions: " + strings.Join(failedVersions, ", "),
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
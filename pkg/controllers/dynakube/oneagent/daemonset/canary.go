@@ -0,0 +1,69 @@
+package daemonset
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	labelCanary = api.InternalFlagPrefix + "canary"
+
+	nodeNameField = "metadata.name"
+)
+
+// ExcludeCanaryNodes removes the pods of the DaemonSet from the canary nodes during the canary phase of a rollout.
+// The update strategy is set to OnDelete, so the pods on the other nodes are kept until the new version is promoted.
+func ExcludeCanaryNodes(ds *appsv1.DaemonSet, canaryNodes []string) {
+	ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+		Type: appsv1.OnDeleteDaemonSetStrategyType,
+	}
+
+	if len(canaryNodes) > 0 {
+		addNodeNameRequirement(&ds.Spec.Template.Spec, corev1.NodeSelectorOpNotIn, canaryNodes)
+	}
+}
+
+// RestrictToCanaryNodes turns the DaemonSet into the canary DaemonSet, which runs the new version only on the canary nodes.
+// The canary label keeps its selector apart from the one of the stable DaemonSet.
+func RestrictToCanaryNodes(ds *appsv1.DaemonSet, name string, canaryNodes []string) {
+	ds.Name = name
+	ds.Labels[labelCanary] = "true"
+	ds.Spec.Selector.MatchLabels[labelCanary] = "true"
+	ds.Spec.Template.Labels[labelCanary] = "true"
+
+	addNodeNameRequirement(&ds.Spec.Template.Spec, corev1.NodeSelectorOpIn, canaryNodes)
+}
+
+func addNodeNameRequirement(podSpec *corev1.PodSpec, operator corev1.NodeSelectorOperator, nodeNames []string) {
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+
+	nodeAffinity := podSpec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      nodeNameField,
+		Operator: operator,
+		Values:   nodeNames,
+	}
+
+	terms := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) == 0 {
+		terms = []corev1.NodeSelectorTerm{{}}
+	}
+
+	// the terms are ORed, so the requirement has to be part of every term
+	for i := range terms {
+		terms[i].MatchFields = append(terms[i].MatchFields, requirement)
+	}
+
+	nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = terms
+}
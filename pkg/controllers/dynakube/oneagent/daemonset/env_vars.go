@@ -6,6 +6,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
 	logmonitoring "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/proxy"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/prioritymap"
 	"github.com/Dynatrace/dynatrace-operator/pkg/version"
 	appsv1 "k8s.io/api/apps/v1"
@@ -109,7 +110,14 @@ func (b *builder) addProxyEnv(envVarMap *prioritymap.Map) {
 		return
 	}
 
-	if b.dk.Spec.Proxy.ValueFrom != "" {
+	if proxy.UsesComponentSecret(b.dk, dynakube.OneAgentProxyComponent) {
+		addDefaultValueSource(envVarMap, proxyEnv, &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: proxy.BuildComponentSecretName(b.dk.Name, dynakube.OneAgentProxyComponent)},
+				Key:                  dynakube.ProxyKey,
+			},
+		})
+	} else if b.dk.Spec.Proxy.ValueFrom != "" {
 		addDefaultValueSource(envVarMap, proxyEnv, &corev1.EnvVarSource{
 			SecretKeyRef: &corev1.SecretKeySelector{
 				LocalObjectReference: corev1.LocalObjectReference{Name: b.dk.Spec.Proxy.ValueFrom},
@@ -112,6 +112,11 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 	}
 
 	if !r.dk.OneAgent().IsDaemonsetRequired() {
+		err = r.reconcileCanary(ctx)
+		if err != nil {
+			return err
+		}
+
 		return r.cleanUp(ctx)
 	}
 
@@ -127,11 +132,29 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		return err
 	}
 
+	err = r.reconcileCanary(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !r.dk.OneAgent().IsCanaryRolloutInProgress() {
+		// the canary pods are removed before the DaemonSet is rolled out to the canary nodes again
+		err = r.removeCanaryDaemonSet(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = r.reconcileRollout(ctx)
 	if err != nil {
 		return err
 	}
 
+	err = r.reconcileCanaryDaemonSet(ctx)
+	if err != nil {
+		return err
+	}
+
 	err = r.updateInstancesStatus(ctx)
 	if err != nil {
 		return err
@@ -303,31 +326,73 @@ func (r *Reconciler) getOneagentPods(ctx context.Context, dk *dynakube.DynaKube,
 }
 
 func (r *Reconciler) buildDesiredDaemonSet(dk *dynakube.DynaKube) (*appsv1.DaemonSet, error) {
+	return BuildDesiredDaemonSet(dk, r.clusterID)
+}
+
+// BuildDesiredDaemonSet builds the OneAgent DaemonSet for the configured mode, including the hash annotation used to detect changes.
+// During the canary phase of a rollout it keeps the previous version and excludes the canary nodes, see BuildCanaryDaemonSet.
+func BuildDesiredDaemonSet(dk *dynakube.DynaKube, clusterID string) (*appsv1.DaemonSet, error) {
+	if !dk.OneAgent().IsCanaryRolloutInProgress() {
+		return buildDaemonSet(dk, clusterID)
+	}
+
+	stableDk := dk.DeepCopy()
+	stableDk.OneAgent().UseStableVersion()
+
+	ds, err := buildDaemonSet(stableDk, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	daemonset.ExcludeCanaryNodes(ds, dk.OneAgent().Status.Rollout.CanaryNodes)
+
+	return ds, addHashAnnotation(ds)
+}
+
+// BuildCanaryDaemonSet builds the OneAgent DaemonSet that runs the new version on the canary nodes during the canary phase of a rollout.
+func BuildCanaryDaemonSet(dk *dynakube.DynaKube, clusterID string) (*appsv1.DaemonSet, error) {
+	ds, err := buildDaemonSet(dk, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	daemonset.RestrictToCanaryNodes(ds, dk.OneAgent().GetCanaryDaemonsetName(), dk.OneAgent().Status.Rollout.CanaryNodes)
+
+	return ds, addHashAnnotation(ds)
+}
+
+func buildDaemonSet(dk *dynakube.DynaKube, clusterID string) (*appsv1.DaemonSet, error) {
 	var ds *appsv1.DaemonSet
 
 	var err error
 
 	switch {
 	case dk.OneAgent().IsClassicFullStackMode():
-		ds, err = daemonset.NewClassicFullStack(dk, r.clusterID).BuildDaemonSet()
+		ds, err = daemonset.NewClassicFullStack(dk, clusterID).BuildDaemonSet()
 	case dk.OneAgent().IsHostMonitoringMode():
-		ds, err = daemonset.NewHostMonitoring(dk, r.clusterID).BuildDaemonSet()
+		ds, err = daemonset.NewHostMonitoring(dk, clusterID).BuildDaemonSet()
 	case dk.OneAgent().IsCloudNativeFullstackMode():
-		ds, err = daemonset.NewCloudNativeFullStack(dk, r.clusterID).BuildDaemonSet()
+		ds, err = daemonset.NewCloudNativeFullStack(dk, clusterID).BuildDaemonSet()
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	return ds, addHashAnnotation(ds)
+}
+
+func addHashAnnotation(ds *appsv1.DaemonSet) error {
+	delete(ds.Annotations, hasher.AnnotationHash)
+
 	dsHash, err := hasher.GenerateHash(ds)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	ds.Annotations[hasher.AnnotationHash] = dsHash
 
-	return ds, nil
+	return nil
 }
 
 func (r *Reconciler) reconcileInstanceStatuses(ctx context.Context, dk *dynakube.DynaKube) error {
@@ -353,6 +418,11 @@ func (r *Reconciler) reconcileInstanceStatuses(ctx context.Context, dk *dynakube
 }
 
 func (r *Reconciler) removeOneAgentDaemonSet(ctx context.Context, dk *dynakube.DynaKube) error {
+	err := r.removeCanaryDaemonSet(ctx)
+	if err != nil {
+		return err
+	}
+
 	oneAgentDaemonSet := appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: dk.OneAgent().GetDaemonsetName(), Namespace: dk.Namespace}}
 
 	return client.IgnoreNotFound(r.client.Delete(ctx, &oneAgentDaemonSet))
@@ -0,0 +1,11 @@
+package autoscaling
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+)
+
+const conditionType string = "OtelAutoscaling"
+
+var (
+	log = logd.Get().WithName("otelc-autoscaling")
+)
@@ -0,0 +1,163 @@
+package autoscaling
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/hpa"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/pdb"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxUnavailable of the PodDisruptionBudget, so at most one collector pod is evicted at a time during node drains
+const maxUnavailable = 1
+
+type Reconciler struct {
+	client    client.Client
+	apiReader client.Reader
+	dk        *dynakube.DynaKube
+}
+
+func NewReconciler(clt client.Client, apiReader client.Reader, dk *dynakube.DynaKube) *Reconciler {
+	return &Reconciler{
+		client:    clt,
+		apiReader: apiReader,
+		dk:        dk,
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	isCollectorDeployed := r.dk.Extensions().IsPrometheusEnabled() || r.dk.TelemetryIngest().IsEnabled()
+
+	if !isCollectorDeployed || !r.dk.IsOtelCollectorAutoscalingEnabled() {
+		r.cleanUp(ctx)
+
+		return nil
+	}
+
+	err := r.createOrUpdateHPA(ctx)
+	if err != nil {
+		conditions.SetKubeAPIError(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	err = r.createOrUpdatePDB(ctx)
+	if err != nil {
+		conditions.SetKubeAPIError(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	conditions.SetAutoscalerCreated(r.dk.Conditions(), conditionType, r.dk.OtelCollectorHPAName())
+
+	return nil
+}
+
+func (r *Reconciler) cleanUp(ctx context.Context) {
+	if meta.FindStatusCondition(*r.dk.Conditions(), conditionType) == nil {
+		return // no condition == nothing is there to clean up
+	}
+	defer meta.RemoveStatusCondition(r.dk.Conditions(), conditionType)
+
+	err := hpa.Query(r.client, r.apiReader, log).Delete(ctx, &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: r.dk.OtelCollectorHPAName(), Namespace: r.dk.Namespace}})
+	if err != nil {
+		log.Error(err, "failed to clean up OtelCollector horizontal pod autoscaler")
+	}
+
+	err = pdb.Query(r.client, r.apiReader, log).Delete(ctx, &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: r.dk.OtelCollectorPDBName(), Namespace: r.dk.Namespace}})
+	if err != nil {
+		log.Error(err, "failed to clean up OtelCollector pod disruption budget")
+	}
+}
+
+func (r *Reconciler) createOrUpdateHPA(ctx context.Context) error {
+	desiredHPA, err := r.buildHPA()
+	if err != nil {
+		return err
+	}
+
+	_, err = hpa.Query(r.client, r.apiReader, log).CreateOrUpdate(ctx, desiredHPA)
+
+	return err
+}
+
+func (r *Reconciler) createOrUpdatePDB(ctx context.Context) error {
+	desiredPDB, err := r.buildPDB()
+	if err != nil {
+		return err
+	}
+
+	_, err = pdb.Query(r.client, r.apiReader, log).CreateOrUpdate(ctx, desiredPDB)
+
+	return err
+}
+
+func (r *Reconciler) buildHPA() (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	autoscaling := r.dk.Spec.Templates.OpenTelemetryCollector.Autoscaling
+	coreLabels := labels.NewCoreLabels(r.dk.Name, labels.OtelCComponentLabel)
+
+	scaleTargetRef := autoscalingv2.CrossVersionObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "StatefulSet",
+		Name:       r.dk.OtelCollectorStatefulsetName(),
+	}
+
+	return hpa.Build(r.dk, r.dk.OtelCollectorHPAName(), scaleTargetRef, ptr.To(r.dk.OtelCollectorMinReplicas()), autoscaling.MaxReplicas,
+		hpa.SetLabels(coreLabels.BuildLabels()),
+		hpa.SetMetrics(buildMetrics(autoscaling)),
+		hpa.SetBehavior(autoscaling.Behavior),
+	)
+}
+
+func (r *Reconciler) buildPDB() (*policyv1.PodDisruptionBudget, error) {
+	coreLabels := labels.NewCoreLabels(r.dk.Name, labels.OtelCComponentLabel)
+	appLabels := labels.NewAppLabels(labels.OtelCComponentLabel, r.dk.Name, labels.OtelCComponentLabel, "")
+
+	return pdb.Build(r.dk, r.dk.OtelCollectorPDBName(), appLabels.BuildMatchLabels(),
+		pdb.SetLabels(coreLabels.BuildLabels()),
+		pdb.SetMaxUnavailable(intstr.FromInt32(maxUnavailable)),
+	)
+}
+
+func buildMetrics(autoscaling *dynakube.OpenTelemetryCollectorAutoscalingSpec) []autoscalingv2.MetricSpec {
+	metrics := []autoscalingv2.MetricSpec{}
+
+	if autoscaling.TargetCPUUtilization != nil {
+		metrics = append(metrics, buildResourceMetric(corev1.ResourceCPU, *autoscaling.TargetCPUUtilization))
+	}
+
+	if autoscaling.TargetMemoryUtilization != nil {
+		metrics = append(metrics, buildResourceMetric(corev1.ResourceMemory, *autoscaling.TargetMemoryUtilization))
+	}
+
+	metrics = append(metrics, autoscaling.Metrics...)
+
+	if len(metrics) == 0 {
+		metrics = append(metrics, buildResourceMetric(corev1.ResourceCPU, dynakube.DefaultOtelCollectorTargetCPUUtilization))
+	}
+
+	return metrics
+}
+
+func buildResourceMetric(resourceName corev1.ResourceName, averageUtilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: resourceName,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: ptr.To(averageUtilization),
+			},
+		},
+	}
+}
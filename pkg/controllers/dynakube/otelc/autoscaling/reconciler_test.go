@@ -0,0 +1,148 @@
+package autoscaling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/extensions"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testDynakubeName  = "dynakube"
+	testNamespaceName = "dynatrace"
+)
+
+func getTestDynakube(autoscaling *dynakube.OpenTelemetryCollectorAutoscalingSpec) *dynakube.DynaKube {
+	return &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testDynakubeName,
+			Namespace: testNamespaceName,
+		},
+		Spec: dynakube.DynaKubeSpec{
+			Extensions: &extensions.Spec{Prometheus: &extensions.PrometheusSpec{}},
+			Templates: dynakube.TemplatesSpec{
+				OpenTelemetryCollector: dynakube.OpenTelemetryCollectorSpec{
+					Autoscaling: autoscaling,
+				},
+			},
+		},
+	}
+}
+
+func getHPA(t *testing.T, clt client.Client, dk *dynakube.DynaKube) *autoscalingv2.HorizontalPodAutoscaler {
+	var hpa autoscalingv2.HorizontalPodAutoscaler
+	require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: dk.OtelCollectorHPAName(), Namespace: dk.Namespace}, &hpa))
+
+	return &hpa
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("autoscaling not configured => nothing created", func(t *testing.T) {
+		dk := getTestDynakube(nil)
+		clt := fake.NewClient()
+
+		require.NoError(t, NewReconciler(clt, clt, dk).Reconcile(ctx))
+
+		err := clt.Get(ctx, client.ObjectKey{Name: dk.OtelCollectorHPAName(), Namespace: dk.Namespace}, &autoscalingv2.HorizontalPodAutoscaler{})
+		assert.True(t, k8serrors.IsNotFound(err))
+		assert.Empty(t, dk.Status.Conditions)
+	})
+	t.Run("autoscaling configured => hpa and pdb created", func(t *testing.T) {
+		dk := getTestDynakube(&dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			MinReplicas: ptr.To(int32(2)),
+			MaxReplicas: 10,
+		})
+		clt := fake.NewClient()
+
+		require.NoError(t, NewReconciler(clt, clt, dk).Reconcile(ctx))
+
+		hpa := getHPA(t, clt, dk)
+		assert.Equal(t, dk.OtelCollectorStatefulsetName(), hpa.Spec.ScaleTargetRef.Name)
+		assert.Equal(t, "StatefulSet", hpa.Spec.ScaleTargetRef.Kind)
+		assert.Equal(t, int32(2), *hpa.Spec.MinReplicas)
+		assert.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+		require.Len(t, hpa.OwnerReferences, 1)
+		assert.Equal(t, dk.Name, hpa.OwnerReferences[0].Name)
+
+		var pdb policyv1.PodDisruptionBudget
+		require.NoError(t, clt.Get(ctx, client.ObjectKey{Name: dk.OtelCollectorPDBName(), Namespace: dk.Namespace}, &pdb))
+		assert.Equal(t, int32(maxUnavailable), pdb.Spec.MaxUnavailable.IntVal)
+		assert.NotEmpty(t, pdb.Spec.Selector.MatchLabels)
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, conditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, conditions.AutoscalerCreatedReason, condition.Reason)
+	})
+	t.Run("autoscaling disabled => hpa and pdb removed", func(t *testing.T) {
+		dk := getTestDynakube(&dynakube.OpenTelemetryCollectorAutoscalingSpec{MaxReplicas: 3})
+		clt := fake.NewClient()
+
+		require.NoError(t, NewReconciler(clt, clt, dk).Reconcile(ctx))
+
+		dk.Spec.Templates.OpenTelemetryCollector.Autoscaling = nil
+
+		require.NoError(t, NewReconciler(clt, clt, dk).Reconcile(ctx))
+
+		err := clt.Get(ctx, client.ObjectKey{Name: dk.OtelCollectorHPAName(), Namespace: dk.Namespace}, &autoscalingv2.HorizontalPodAutoscaler{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		err = clt.Get(ctx, client.ObjectKey{Name: dk.OtelCollectorPDBName(), Namespace: dk.Namespace}, &policyv1.PodDisruptionBudget{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		assert.Nil(t, meta.FindStatusCondition(dk.Status.Conditions, conditionType))
+	})
+}
+
+func TestBuildMetrics(t *testing.T) {
+	t.Run("no target => default cpu target", func(t *testing.T) {
+		metrics := buildMetrics(&dynakube.OpenTelemetryCollectorAutoscalingSpec{})
+
+		require.Len(t, metrics, 1)
+		assert.Equal(t, corev1.ResourceCPU, metrics[0].Resource.Name)
+		assert.Equal(t, dynakube.DefaultOtelCollectorTargetCPUUtilization, *metrics[0].Resource.Target.AverageUtilization)
+	})
+	t.Run("cpu, memory and custom metric", func(t *testing.T) {
+		queueSize := autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: "otelcol_exporter_queue_size"},
+			},
+		}
+
+		metrics := buildMetrics(&dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			TargetCPUUtilization:    ptr.To(int32(70)),
+			TargetMemoryUtilization: ptr.To(int32(60)),
+			Metrics:                 []autoscalingv2.MetricSpec{queueSize},
+		})
+
+		require.Len(t, metrics, 3)
+		assert.Equal(t, corev1.ResourceCPU, metrics[0].Resource.Name)
+		assert.Equal(t, int32(70), *metrics[0].Resource.Target.AverageUtilization)
+		assert.Equal(t, corev1.ResourceMemory, metrics[1].Resource.Name)
+		assert.Equal(t, int32(60), *metrics[1].Resource.Target.AverageUtilization)
+		assert.Equal(t, queueSize, metrics[2])
+	})
+	t.Run("only custom metric => no default cpu target", func(t *testing.T) {
+		metrics := buildMetrics(&dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			Metrics: []autoscalingv2.MetricSpec{{Type: autoscalingv2.PodsMetricSourceType}},
+		})
+
+		require.Len(t, metrics, 1)
+		assert.Equal(t, autoscalingv2.PodsMetricSourceType, metrics[0].Type)
+	})
+}
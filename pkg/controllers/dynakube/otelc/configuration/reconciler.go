@@ -3,17 +3,22 @@ package configuration
 import (
 	"context"
 	"path/filepath"
+	"slices"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/telemetryingest"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	otelcconsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	k8sconfigmap "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/configmap"
 	k8slabels "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -57,7 +62,7 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 func (r *Reconciler) reconcileConfigMap(ctx context.Context) error {
 	query := k8sconfigmap.Query(r.client, r.apiReader, log)
 
-	newConfigMap, err := r.prepareConfigMap()
+	newConfigMap, err := r.prepareConfigMap(ctx)
 	if err != nil {
 		return err
 	}
@@ -76,9 +81,11 @@ func (r *Reconciler) reconcileConfigMap(ctx context.Context) error {
 	return nil
 }
 
-func (r *Reconciler) prepareConfigMap() (*corev1.ConfigMap, error) {
-	data, err := r.getData()
+func (r *Reconciler) prepareConfigMap(ctx context.Context) (*corev1.ConfigMap, error) {
+	data, err := r.getData(ctx)
 	if err != nil {
+		conditions.SetConfigMapGenFailed(r.dk.Conditions(), conditionType, err)
+
 		return nil, err
 	}
 
@@ -98,7 +105,7 @@ func (r *Reconciler) prepareConfigMap() (*corev1.ConfigMap, error) {
 	return newSecret, err
 }
 
-func (r *Reconciler) getData() (map[string]string, error) {
+func (r *Reconciler) getData(ctx context.Context) (map[string]string, error) {
 	myPodIP := "${env:MY_POD_IP}"
 
 	options := []otelcgen.Option{
@@ -117,6 +124,27 @@ func (r *Reconciler) getData() (map[string]string, error) {
 		options = append(options, otelcgen.WithTLS(filepath.Join(otelcconsts.CustomTLSCertMountPath, consts.TLSCrtDataName), filepath.Join(otelcconsts.CustomTLSCertMountPath, consts.TLSKeyDataName)))
 	}
 
+	options = append(options, buildExporterOptions(r.dk)...)
+
+	if r.dk.TelemetryIngest().IsPrometheusEnabled() && r.dk.TelemetryIngest().GetPrometheusNamespaceSelector() != nil {
+		namespaces, err := r.getPrometheusNamespaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, otelcgen.WithPrometheusNamespaces(namespaces))
+	}
+
+	if r.dk.TelemetryIngest().HasPipelineExtensions() {
+		extensions, err := GetPipelineExtensions(ctx, r.client, r.apiReader, r.dk)
+		if err != nil {
+			return nil, err
+		}
+
+		// has to be applied before the components and services are built
+		options = append(options, otelcgen.WithPipelineExtensions(*extensions))
+	}
+
 	options = append(options,
 		otelcgen.WithExporters(),
 		otelcgen.WithProcessors(),
@@ -142,6 +170,83 @@ func (r *Reconciler) getData() (map[string]string, error) {
 	return configMap, nil
 }
 
+func buildExporterOptions(dk *dynakube.DynaKube) []otelcgen.Option {
+	var options []otelcgen.Option
+
+	if sendingQueue := dk.TelemetryIngest().GetSendingQueue(); sendingQueue != nil {
+		var storageDirectory string
+		if sendingQueue.Persistent {
+			storageDirectory = otelcconsts.PersistentQueueMountPath
+		}
+
+		options = append(options, otelcgen.WithSendingQueue(ptr.Deref(sendingQueue.QueueSize, 0), storageDirectory))
+	}
+
+	if retry := dk.TelemetryIngest().GetRetry(); retry != nil {
+		options = append(options, otelcgen.WithRetry(formatDuration(retry.InitialInterval), formatDuration(retry.MaxInterval), formatDuration(retry.MaxElapsedTime)))
+	}
+
+	return options
+}
+
+// formatDuration keeps unset durations empty, so the collector defaults are used.
+func formatDuration(duration *metav1.Duration) string {
+	if duration == nil {
+		return ""
+	}
+
+	return duration.Duration.String()
+}
+
+// getPrometheusNamespaces resolves the namespace selector, as the kubernetes service discovery of the prometheus receiver only supports namespace names.
+// New namespaces are picked up with the next reconcile of the DynaKube.
+func (r *Reconciler) getPrometheusNamespaces(ctx context.Context) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(r.dk.TelemetryIngest().GetPrometheusNamespaceSelector())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var namespaceList corev1.NamespaceList
+
+	err = r.apiReader.List(ctx, &namespaceList, &client.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list namespaces for the prometheus receiver")
+	}
+
+	namespaces := make([]string, 0, len(namespaceList.Items))
+	for _, namespace := range namespaceList.Items {
+		namespaces = append(namespaces, namespace.Name)
+	}
+
+	slices.Sort(namespaces)
+
+	return namespaces, nil
+}
+
+// GetPipelineExtensions reads the pipeline extensions from the ConfigMap referenced by the TelemetryIngest spec of the DynaKube.
+func GetPipelineExtensions(ctx context.Context, clt client.Client, apiReader client.Reader, dk *dynakube.DynaKube) (*otelcgen.PipelineExtensions, error) {
+	name := dk.TelemetryIngest().PipelineExtensionsRef
+
+	query := k8sconfigmap.Query(clt, apiReader, log)
+
+	configMap, err := query.Get(ctx, types.NamespacedName{Name: name, Namespace: dk.Namespace})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get pipeline extensions configmap '%s'", name)
+	}
+
+	data, ok := configMap.Data[telemetryingest.PipelineExtensionsConfigMapKey]
+	if !ok {
+		return nil, errors.Errorf("pipeline extensions configmap '%s' has no '%s' key", name, telemetryingest.PipelineExtensionsConfigMapKey)
+	}
+
+	extensions, err := otelcgen.ParsePipelineExtensions([]byte(data))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid pipeline extensions configmap '%s'", name)
+	}
+
+	return extensions, nil
+}
+
 func GetConfigMapName(dkName string) string {
 	return dkName + otelcconsts.TelemetryCollectorConfigmapSuffix
 }
@@ -7,11 +7,13 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/telemetryingest"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -53,4 +55,94 @@ func TestConfigurationConfigMap(t *testing.T) {
 		assert.Equal(t, conditions.ConfigMapCreatedOrUpdatedReason, dk.Status.Conditions[0].Reason)
 		assert.Equal(t, metav1.ConditionTrue, dk.Status.Conditions[0].Status)
 	})
+	t.Run("pipeline extensions are merged into the configuration", func(t *testing.T) {
+		extensions := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "extensions", Namespace: testNamespaceName},
+			Data: map[string]string{
+				telemetryingest.PipelineExtensionsConfigMapKey: `
+processors:
+  attributes/drop-pii:
+    actions:
+      - key: user.email
+        action: delete
+pipelines:
+  traces:
+    processors: [attributes/drop-pii]
+`,
+			},
+		}
+		mockK8sClient := fake.NewFakeClient(extensions)
+		dk := getTestDynakube(&telemetryingest.Spec{PipelineExtensionsRef: extensions.Name})
+		err := NewReconciler(mockK8sClient, mockK8sClient, dk).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		configMap := &corev1.ConfigMap{}
+		err = mockK8sClient.Get(context.Background(), client.ObjectKey{Name: GetConfigMapName(dk.Name), Namespace: dk.Namespace}, configMap)
+		require.NoError(t, err)
+
+		assert.Contains(t, configMap.Data[consts.ConfigFieldName], "attributes/drop-pii")
+	})
+	t.Run("missing pipeline extensions configmap => error", func(t *testing.T) {
+		mockK8sClient := fake.NewFakeClient()
+		dk := getTestDynakube(&telemetryingest.Spec{PipelineExtensionsRef: "extensions"})
+		err := NewReconciler(mockK8sClient, mockK8sClient, dk).Reconcile(context.Background())
+		require.Error(t, err)
+
+		require.Len(t, dk.Status.Conditions, 1)
+		assert.Equal(t, conditions.ConfigMapGenerationFailed, dk.Status.Conditions[0].Reason)
+	})
+	t.Run("invalid pipeline extensions => error", func(t *testing.T) {
+		extensions := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "extensions", Namespace: testNamespaceName},
+			Data: map[string]string{
+				telemetryingest.PipelineExtensionsConfigMapKey: "processors: {batch/custom: {}}",
+			},
+		}
+		mockK8sClient := fake.NewFakeClient(extensions)
+		dk := getTestDynakube(&telemetryingest.Spec{PipelineExtensionsRef: extensions.Name})
+		err := NewReconciler(mockK8sClient, mockK8sClient, dk).Reconcile(context.Background())
+		require.Error(t, err)
+	})
+	t.Run("prometheus receiver is restricted to the selected namespaces", func(t *testing.T) {
+		selected := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "selected", Labels: map[string]string{"scrape": "true"}}}
+		other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+		mockK8sClient := fake.NewFakeClient(selected, other)
+		dk := getTestDynakube(&telemetryingest.Spec{
+			Protocols: []string{string(otelcgen.PrometheusProtocol)},
+			Prometheus: &telemetryingest.PrometheusSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: selected.Labels},
+			},
+		})
+		err := NewReconciler(mockK8sClient, mockK8sClient, dk).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		configMap := &corev1.ConfigMap{}
+		err = mockK8sClient.Get(context.Background(), client.ObjectKey{Name: GetConfigMapName(dk.Name), Namespace: dk.Namespace}, configMap)
+		require.NoError(t, err)
+
+		assert.Contains(t, configMap.Data[consts.ConfigFieldName], "regex: selected\n")
+		assert.NotContains(t, configMap.Data[consts.ConfigFieldName], "other")
+	})
+	t.Run("sending queue and retry of the exporter", func(t *testing.T) {
+		mockK8sClient := fake.NewFakeClient()
+		dk := getTestDynakube(&telemetryingest.Spec{
+			Exporter: &telemetryingest.ExporterSpec{
+				SendingQueue: &telemetryingest.SendingQueueSpec{QueueSize: ptr.To(int64(5000)), Persistent: true},
+				Retry:        &telemetryingest.RetrySpec{MaxElapsedTime: &metav1.Duration{}},
+			},
+		})
+		err := NewReconciler(mockK8sClient, mockK8sClient, dk).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		configMap := &corev1.ConfigMap{}
+		err = mockK8sClient.Get(context.Background(), client.ObjectKey{Name: GetConfigMapName(dk.Name), Namespace: dk.Namespace}, configMap)
+		require.NoError(t, err)
+
+		config := configMap.Data[consts.ConfigFieldName]
+		assert.Contains(t, config, "queue_size: 5000")
+		assert.Contains(t, config, "storage: file_storage/queue")
+		assert.Contains(t, config, "directory: "+consts.PersistentQueueMountPath)
+		assert.Contains(t, config, "max_elapsed_time: 0s")
+		assert.NotContains(t, config, "initial_interval")
+	})
 }
@@ -16,5 +16,7 @@ const (
 	ActiveGateTLSCertCAVolumeMountPath = "/tls/custom/activegate"
 	ActiveGateTLSCertVolumePath        = ActiveGateTLSCertCAVolumeMountPath + "/" + ActiveGateCertFile
 
+	PersistentQueueMountPath = "/var/lib/otelcol/queue"
+
 	EnvDataIngestToken = "DT_DATA_INGEST_TOKEN"
 )
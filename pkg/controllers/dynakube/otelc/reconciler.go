@@ -5,6 +5,7 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/autoscaling"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/configuration"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/endpoint"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/service"
@@ -20,6 +21,7 @@ type Reconciler struct {
 	serviceReconciler       *service.Reconciler
 	endpointReconciler      *endpoint.Reconciler
 	configurationReconciler *configuration.Reconciler
+	autoscalingReconciler   *autoscaling.Reconciler
 }
 
 type ReconcilerBuilder func(client client.Client, apiReader client.Reader, dk *dynakube.DynaKube) controllers.Reconciler
@@ -33,6 +35,7 @@ func NewReconciler(client client.Client, apiReader client.Reader, dk *dynakube.D
 		serviceReconciler:       service.NewReconciler(client, apiReader, dk),
 		endpointReconciler:      endpoint.NewReconciler(client, apiReader, dk),
 		configurationReconciler: configuration.NewReconciler(client, apiReader, dk),
+		autoscalingReconciler:   autoscaling.NewReconciler(client, apiReader, dk),
 	}
 }
 
@@ -59,5 +62,12 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		return err
 	}
 
+	err = r.autoscalingReconciler.Reconcile(ctx)
+	if err != nil {
+		log.Info("failed to reconcile Dynatrace OTELc autoscaling")
+
+		return err
+	}
+
 	return nil
 }
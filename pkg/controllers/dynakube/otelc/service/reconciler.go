@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/configuration"
 	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
@@ -99,7 +100,7 @@ func (r *Reconciler) removeAllServicesExcept(ctx context.Context, actualServiceN
 }
 
 func (r *Reconciler) createOrUpdateService(ctx context.Context) error {
-	newService, err := r.buildService()
+	newService, err := r.buildService(ctx)
 	if err != nil {
 		conditions.SetServiceGenFailed(r.dk.Conditions(), serviceConditionType, err)
 
@@ -119,14 +120,30 @@ func (r *Reconciler) createOrUpdateService(ctx context.Context) error {
 	return nil
 }
 
-func (r *Reconciler) buildService() (*corev1.Service, error) {
+func (r *Reconciler) buildService(ctx context.Context) (*corev1.Service, error) {
 	coreLabels := labels.NewCoreLabels(r.dk.Name, labels.OtelCComponentLabel)
 	appLabels := labels.NewAppLabels(labels.OtelCComponentLabel, r.dk.Name, labels.OtelCComponentLabel, "")
 
+	svcPorts := buildServicePortList(r.dk.TelemetryIngest().GetProtocols())
+
+	if r.dk.TelemetryIngest().HasPipelineExtensions() {
+		extensions, err := configuration.GetPipelineExtensions(ctx, r.client, r.apiReader, r.dk)
+		if err != nil {
+			return nil, err
+		}
+
+		receiverPorts, err := extensions.ReceiverPorts()
+		if err != nil {
+			return nil, err
+		}
+
+		svcPorts = append(svcPorts, buildReceiverServicePorts(receiverPorts)...)
+	}
+
 	return service.Build(r.dk,
 		r.dk.TelemetryIngest().GetServiceName(),
 		appLabels.BuildMatchLabels(),
-		buildServicePortList(r.dk.TelemetryIngest().GetProtocols()),
+		svcPorts,
 		service.SetLabels(coreLabels.BuildLabels()),
 		service.SetType(corev1.ServiceTypeClusterIP),
 	)
@@ -196,6 +213,8 @@ func buildServicePortList(protocols []otelcgen.Protocol) []corev1.ServicePort {
 					Protocol:   corev1.ProtocolUDP,
 					TargetPort: intstr.FromInt32(statsdPort),
 				})
+		case otelcgen.PrometheusProtocol:
+			// the targets are scraped by the collector, so no port has to be exposed
 		default:
 			log.Info("unknown telemetry service protocol ignored", "protocol", protocol)
 		}
@@ -203,3 +222,24 @@ func buildServicePortList(protocols []otelcgen.Protocol) []corev1.ServicePort {
 
 	return svcPorts
 }
+
+// buildReceiverServicePorts exposes the ports of the receivers added by the pipeline extensions.
+func buildReceiverServicePorts(receiverPorts []otelcgen.ReceiverPort) []corev1.ServicePort {
+	svcPorts := make([]corev1.ServicePort, 0, len(receiverPorts))
+
+	for _, receiverPort := range receiverPorts {
+		protocol := corev1.ProtocolTCP
+		if receiverPort.UDP {
+			protocol = corev1.ProtocolUDP
+		}
+
+		svcPorts = append(svcPorts, corev1.ServicePort{
+			Name:       receiverPort.Name,
+			Port:       receiverPort.Port,
+			Protocol:   protocol,
+			TargetPort: intstr.FromInt32(receiverPort.Port),
+		})
+	}
+
+	return svcPorts
+}
@@ -118,6 +118,51 @@ func TestService(t *testing.T) {
 		assert.Equal(t, conditions.ServiceCreatedReason, dk.Status.Conditions[0].Reason)
 		assert.Equal(t, metav1.ConditionTrue, dk.Status.Conditions[0].Status)
 	})
+	t.Run("expose ports of the pipeline extension receivers", func(t *testing.T) {
+		extensionsConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "pipelines", Namespace: testNamespaceName},
+			Data: map[string]string{
+				telemetryingest.PipelineExtensionsConfigMapKey: `
+receivers:
+  statsd/custom:
+    endpoint: 0.0.0.0:18125
+  zipkin/custom:
+    endpoint: 0.0.0.0:19411
+pipelines:
+  traces:
+    receivers: [zipkin/custom]
+  metrics:
+    receivers: [statsd/custom]
+`,
+			},
+		}
+		mockK8sClient := fake.NewFakeClient(extensionsConfigMap)
+		dk := getTestDynakube(&telemetryingest.Spec{
+			Protocols:             []string{string(otelcgen.ZipkinProtocol)},
+			PipelineExtensionsRef: extensionsConfigMap.Name,
+		})
+		err := NewReconciler(mockK8sClient, mockK8sClient, dk).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		service := &corev1.Service{}
+		err = mockK8sClient.Get(context.Background(), client.ObjectKey{Name: dk.TelemetryIngest().GetDefaultServiceName(), Namespace: dk.Namespace}, service)
+		require.NoError(t, err)
+
+		require.Len(t, service.Spec.Ports, 3)
+		assert.Equal(t, zipkinPortName, service.Spec.Ports[0].Name)
+		assert.Equal(t, "ext-18125-udp", service.Spec.Ports[1].Name)
+		assert.Equal(t, int32(18125), service.Spec.Ports[1].Port)
+		assert.Equal(t, corev1.ProtocolUDP, service.Spec.Ports[1].Protocol)
+		assert.Equal(t, "ext-19411", service.Spec.Ports[2].Name)
+		assert.Equal(t, int32(19411), service.Spec.Ports[2].TargetPort.IntVal)
+		assert.Equal(t, corev1.ProtocolTCP, service.Spec.Ports[2].Protocol)
+	})
+	t.Run("missing pipeline extensions => error", func(t *testing.T) {
+		mockK8sClient := fake.NewFakeClient()
+		dk := getTestDynakube(&telemetryingest.Spec{PipelineExtensionsRef: "missing"})
+		err := NewReconciler(mockK8sClient, mockK8sClient, dk).Reconcile(context.Background())
+		require.Error(t, err)
+	})
 	t.Run("default service name, remove service if it is not needed", func(t *testing.T) {
 		dk := getTestDynakube(nil)
 		dk.Status.Conditions = []metav1.Condition{
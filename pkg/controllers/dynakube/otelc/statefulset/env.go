@@ -10,6 +10,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/activegate"
 	otelcConsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/proxy"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -48,6 +49,8 @@ const (
 	customEecTLSCertificateFullPath = customEecTLSCertificatePath + "/" + consts.TLSCrtDataName
 )
 
+// getEnvs sets the number of shards to the configured replicas, the prometheus protocol can't be combined with autoscaling,
+// as each target is scraped only by the replica of its shard.
 func getEnvs(dk *dynakube.DynaKube) []corev1.EnvVar {
 	envs := []corev1.EnvVar{
 		{Name: envShards, Value: strconv.Itoa(int(getReplicas(dk)))},
@@ -71,9 +74,10 @@ func getEnvs(dk *dynakube.DynaKube) []corev1.EnvVar {
 		{Name: envDTentityK8sCluster, Value: dk.Status.KubernetesClusterMEID},
 	}
 
-	if dk.HasProxy() {
-		envs = append(envs, getDynakubeProxyEnvValue(envHTTPSProxy, dk.Spec.Proxy))
-		envs = append(envs, getDynakubeProxyEnvValue(envHTTPProxy, dk.Spec.Proxy))
+	if dk.HasComponentProxy(dynakube.OtelCollectorProxyComponent) {
+		proxySource := getProxySource(dk)
+		envs = append(envs, getDynakubeProxyEnvValue(envHTTPSProxy, proxySource))
+		envs = append(envs, getDynakubeProxyEnvValue(envHTTPProxy, proxySource))
 		envs = append(envs, corev1.EnvVar{Name: envNoProxy, Value: getDynakubeNoProxyEnvValue(dk)})
 	}
 
@@ -120,6 +124,15 @@ func getEnvs(dk *dynakube.DynaKube) []corev1.EnvVar {
 	return envs
 }
 
+// getProxySource returns the internal proxy secret in case of an override, as it contains the credentials too, otherwise spec.proxy.
+func getProxySource(dk *dynakube.DynaKube) *value.Source {
+	if proxy.UsesComponentSecret(dk, dynakube.OtelCollectorProxyComponent) {
+		return &value.Source{ValueFrom: proxy.BuildComponentSecretName(dk.Name, dynakube.OtelCollectorProxyComponent)}
+	}
+
+	return dk.Spec.Proxy
+}
+
 func getDynakubeProxyEnvValue(envVar string, src *value.Source) corev1.EnvVar {
 	if src.ValueFrom != "" {
 		return corev1.EnvVar{
@@ -13,6 +13,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	otelcConsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/proxy"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -338,6 +339,39 @@ func TestProxyEnvsProxyValue(t *testing.T) {
 	}
 }
 
+func TestProxyEnvsProxyOverride(t *testing.T) {
+	t.Run("override uses the internal proxy secret of the collector", func(t *testing.T) {
+		dk := getTestDynakube()
+		dk.Spec.TelemetryIngest = &telemetryingest.Spec{}
+		dk.Spec.Proxy = &value.Source{Value: "http://test.proxy.com:8888"}
+		dk.Spec.Proxies = &dynakube.ProxiesSpec{OtelCollector: &dynakube.ComponentProxySpec{URL: &value.Source{Value: "http://data.proxy.com:8888"}}}
+
+		statefulSet := getWorkload(t, dk)
+
+		assert.Contains(t, statefulSet.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name: envHTTPSProxy,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: proxy.BuildComponentSecretName(dk.Name, dynakube.OtelCollectorProxyComponent)},
+					Key:                  dynakube.ProxyKey,
+				},
+			},
+		})
+	})
+	t.Run("disabled override => no proxy", func(t *testing.T) {
+		dk := getTestDynakube()
+		dk.Spec.TelemetryIngest = &telemetryingest.Spec{}
+		dk.Spec.Proxy = &value.Source{Value: "http://test.proxy.com:8888"}
+		dk.Spec.Proxies = &dynakube.ProxiesSpec{OtelCollector: &dynakube.ComponentProxySpec{Disabled: true}}
+
+		statefulSet := getWorkload(t, dk)
+
+		for _, env := range statefulSet.Spec.Template.Spec.Containers[0].Env {
+			assert.NotEqual(t, envHTTPSProxy, env.Name)
+		}
+	})
+}
+
 func TestCustomNoProxy(t *testing.T) {
 	t.Run("no-proxy ff not used", func(t *testing.T) {
 		dk := &dynakube.DynaKube{
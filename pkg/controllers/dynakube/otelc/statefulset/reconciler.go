@@ -18,8 +18,10 @@ import (
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -27,6 +29,8 @@ const (
 	serviceAccountName                                  = "dynatrace-opentelemetry-collector"
 	annotationTelemetryIngestSecretHash                 = api.InternalFlagPrefix + "telemetry-ingest-secret-hash"
 	annotationTelemetryIngestConfigurationConfigMapHash = api.InternalFlagPrefix + "telemetry-ingest-config-hash"
+
+	persistentQueueGroupID int64 = 1001
 )
 
 type Reconciler struct {
@@ -84,11 +88,41 @@ func (r *Reconciler) createOrUpdateStatefulset(ctx context.Context) error {
 		}
 	}
 
+	sts, err := r.BuildDesiredStatefulSet(ctx)
+	if err != nil {
+		conditions.SetKubeAPIError(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	_, err = statefulset.Query(r.client, r.apiReader, log).WithOwner(r.dk).CreateOrUpdate(ctx, sts)
+	if err != nil {
+		log.Info("failed to create/update " + r.dk.OtelCollectorStatefulsetName() + " statefulset")
+		conditions.SetKubeAPIError(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	conditions.SetStatefulSetCreated(r.dk.Conditions(), conditionType, sts.Name)
+
+	return nil
+}
+
+// BuildDesiredStatefulSet builds the OpenTelemetry collector StatefulSet as it would be applied by the Reconciler, without touching the cluster.
+func (r *Reconciler) BuildDesiredStatefulSet(ctx context.Context) (*appsv1.StatefulSet, error) {
 	appLabels := buildAppLabels(r.dk.Name)
 
 	templateAnnotations, err := r.buildTemplateAnnotations(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	replicas := getReplicas(r.dk)
+	if r.dk.IsOtelCollectorAutoscalingEnabled() {
+		replicas, err = r.getAutoscaledReplicas(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	topologySpreadConstraints := topology.MaxOnePerNode(appLabels)
@@ -97,7 +131,7 @@ func (r *Reconciler) createOrUpdateStatefulset(ctx context.Context) error {
 	}
 
 	sts, err := statefulset.Build(r.dk, r.dk.OtelCollectorStatefulsetName(), getContainer(r.dk),
-		statefulset.SetReplicas(getReplicas(r.dk)),
+		statefulset.SetReplicas(replicas),
 		statefulset.SetPodManagementPolicy(appsv1.ParallelPodManagement),
 		statefulset.SetAllLabels(appLabels.BuildLabels(), appLabels.BuildMatchLabels(), appLabels.BuildLabels(), r.dk.Spec.Templates.OpenTelemetryCollector.Labels),
 		statefulset.SetAllAnnotations(nil, templateAnnotations),
@@ -105,34 +139,21 @@ func (r *Reconciler) createOrUpdateStatefulset(ctx context.Context) error {
 		statefulset.SetServiceAccount(serviceAccountName),
 		statefulset.SetTolerations(r.dk.Spec.Templates.OpenTelemetryCollector.Tolerations),
 		statefulset.SetTopologySpreadConstraints(topologySpreadConstraints),
-		statefulset.SetSecurityContext(buildPodSecurityContext()),
+		statefulset.SetSecurityContext(buildPodSecurityContext(r.dk)),
 		statefulset.SetRollingUpdateStrategyType(),
 		setImagePullSecrets(r.dk.ImagePullSecretReferences()),
 		setVolumes(r.dk),
+		setPersistentVolumeClaim(r.dk),
 	)
 	if err != nil {
-		conditions.SetKubeAPIError(r.dk.Conditions(), conditionType, err)
-
-		return err
+		return nil, err
 	}
 
 	if err := hasher.AddAnnotation(sts); err != nil {
-		conditions.SetKubeAPIError(r.dk.Conditions(), conditionType, err)
-
-		return err
-	}
-
-	_, err = statefulset.Query(r.client, r.apiReader, log).WithOwner(r.dk).CreateOrUpdate(ctx, sts)
-	if err != nil {
-		log.Info("failed to create/update " + r.dk.OtelCollectorStatefulsetName() + " statefulset")
-		conditions.SetKubeAPIError(r.dk.Conditions(), conditionType, err)
-
-		return err
+		return nil, err
 	}
 
-	conditions.SetStatefulSetCreated(r.dk.Conditions(), conditionType, sts.Name)
-
-	return nil
+	return sts, nil
 }
 
 func (r *Reconciler) buildTemplateAnnotations(ctx context.Context) (map[string]string, error) {
@@ -169,6 +190,11 @@ func (r *Reconciler) buildTemplateAnnotations(ctx context.Context) (map[string]s
 		templateAnnotations[annotationTelemetryIngestConfigurationConfigMapHash] = configConfigMapHash
 	}
 
+	// the data ingest token is provided via an env var, so the pods have to be restarted when it is rotated
+	if r.dk.Status.DataIngestTokenRolloutHash != "" {
+		templateAnnotations[api.AnnotationTokensHash] = r.dk.Status.DataIngestTokenRolloutHash
+	}
+
 	return templateAnnotations, nil
 }
 
@@ -221,6 +247,26 @@ func (r *Reconciler) checkDataIngestTokenExists(ctx context.Context) bool {
 	return token.CheckForDataIngestToken(tokens)
 }
 
+// getAutoscaledReplicas keeps the number of replicas set by the HorizontalPodAutoscaler, so updating the statefulset doesn't reset the scaling.
+func (r *Reconciler) getAutoscaledReplicas(ctx context.Context) (int32, error) {
+	minReplicas := r.dk.OtelCollectorMinReplicas()
+
+	var currentSts appsv1.StatefulSet
+
+	err := r.apiReader.Get(ctx, types.NamespacedName{Name: r.dk.OtelCollectorStatefulsetName(), Namespace: r.dk.Namespace}, &currentSts)
+	if k8serrors.IsNotFound(err) {
+		return minReplicas, nil
+	} else if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	if currentSts.Spec.Replicas == nil || *currentSts.Spec.Replicas < minReplicas {
+		return minReplicas, nil
+	}
+
+	return *currentSts.Spec.Replicas, nil
+}
+
 func getReplicas(dk *dynakube.DynaKube) int32 {
 	if dk.Spec.Templates.OpenTelemetryCollector.Replicas != nil {
 		return *dk.Spec.Templates.OpenTelemetryCollector.Replicas
@@ -237,12 +283,19 @@ func buildSecurityContext() *corev1.SecurityContext {
 	}
 }
 
-func buildPodSecurityContext() *corev1.PodSecurityContext {
-	return &corev1.PodSecurityContext{
+func buildPodSecurityContext(dk *dynakube.DynaKube) *corev1.PodSecurityContext {
+	podSecurityContext := &corev1.PodSecurityContext{
 		SeccompProfile: &corev1.SeccompProfile{
 			Type: corev1.SeccompProfileTypeRuntimeDefault,
 		},
 	}
+
+	if dk.TelemetryIngest().IsPersistentQueueEnabled() {
+		// the persistent queue volume has to be writable by the collector
+		podSecurityContext.FSGroup = ptr.To(persistentQueueGroupID)
+	}
+
+	return podSecurityContext
 }
 
 func buildAppLabels(dkName string) *labels.AppLabels {
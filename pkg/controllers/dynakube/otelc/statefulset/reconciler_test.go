@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -151,6 +152,40 @@ func TestStatefulsetBase(t *testing.T) {
 		assert.Equal(t, int32(1), *statefulSet.Spec.Replicas)
 	})
 
+	t.Run("autoscaling => min replicas are used for new statefulset", func(t *testing.T) {
+		dk := getTestDynakubeWithExtensions()
+		dk.Spec.Templates.OpenTelemetryCollector.Replicas = ptr.To(int32(5))
+		dk.Spec.Templates.OpenTelemetryCollector.Autoscaling = &dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			MinReplicas: ptr.To(int32(2)),
+			MaxReplicas: 10,
+		}
+
+		statefulSet := getStatefulset(t, dk)
+
+		assert.Equal(t, int32(2), *statefulSet.Spec.Replicas)
+	})
+
+	t.Run("autoscaling => replicas set by autoscaler are kept", func(t *testing.T) {
+		dk := getTestDynakubeWithExtensions()
+		dk.Spec.Templates.OpenTelemetryCollector.Autoscaling = &dynakube.OpenTelemetryCollectorAutoscalingSpec{
+			MinReplicas: ptr.To(int32(2)),
+			MaxReplicas: 10,
+		}
+
+		scaledSts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: dk.OtelCollectorStatefulsetName(), Namespace: dk.Namespace},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To(int32(7)),
+				Selector: &metav1.LabelSelector{MatchLabels: buildAppLabels(dk.Name).BuildMatchLabels()},
+			},
+		}
+
+		statefulSet := getStatefulset(t, dk, scaledSts)
+
+		assert.Equal(t, int32(7), *statefulSet.Spec.Replicas)
+		assert.Contains(t, statefulSet.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: envShards, Value: "1"})
+	})
+
 	t.Run("pod management policy", func(t *testing.T) {
 		statefulSet := getStatefulset(t, getTestDynakubeWithExtensions())
 
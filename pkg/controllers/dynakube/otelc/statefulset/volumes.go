@@ -7,6 +7,8 @@ import (
 	otelcconsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/consts"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 )
 
@@ -19,6 +21,7 @@ const (
 	extensionsControllerTLSVolumeName  = "extensions-controller-tls"
 	telemetryCollectorConfigVolumeName = "telemetry-collector-config"
 	telemetryCollectorConfigPath       = "/config"
+	persistentQueueVolumeName          = "persistent-queue"
 )
 
 func setVolumes(dk *dynakube.DynaKube) func(o *appsv1.StatefulSet) {
@@ -181,11 +184,60 @@ func buildContainerVolumeMounts(dk *dynakube.DynaKube) []corev1.VolumeMount {
 			MountPath: telemetryCollectorConfigPath,
 			ReadOnly:  true,
 		})
+
+		if dk.TelemetryIngest().IsPersistentQueueEnabled() {
+			vm = append(vm, corev1.VolumeMount{
+				Name:      persistentQueueVolumeName,
+				MountPath: otelcconsts.PersistentQueueMountPath,
+			})
+		}
 	}
 
 	return vm
 }
 
+// setPersistentVolumeClaim provisions a volume per replica for the persistent sending queue of the exporter.
+// The claims are kept when the collector is scaled down, so the data queued by a removed replica is sent once it is scaled up again.
+// They are only deleted together with the StatefulSet.
+func setPersistentVolumeClaim(dk *dynakube.DynaKube) func(o *appsv1.StatefulSet) {
+	return func(o *appsv1.StatefulSet) {
+		if !dk.TelemetryIngest().IsPersistentQueueEnabled() {
+			return
+		}
+
+		pvcSpec := defaultPVCSpec()
+		if dk.TelemetryIngest().GetSendingQueue().VolumeClaimTemplate != nil {
+			pvcSpec = *dk.TelemetryIngest().GetSendingQueue().VolumeClaimTemplate
+		}
+
+		o.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: persistentQueueVolumeName,
+				},
+				Spec: pvcSpec,
+			},
+		}
+		o.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+			WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+			WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+		}
+	}
+}
+
+func defaultPVCSpec() corev1.PersistentVolumeClaimSpec {
+	return corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{
+			corev1.ReadWriteOnce,
+		},
+		Resources: corev1.VolumeResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("1Gi"),
+			},
+		},
+	}
+}
+
 func isTrustedCAsVolumeNeeded(dk *dynakube.DynaKube) bool {
 	return dk.Extensions().IsPrometheusEnabled() && dk.Spec.TrustedCAs != "" || dk.TelemetryIngest().IsEnabled() && dk.IsCACertificateNeeded()
 }
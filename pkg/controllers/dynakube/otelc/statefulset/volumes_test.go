@@ -9,7 +9,10 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/telemetryingest"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	otelcconsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/statefulset"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
@@ -326,6 +329,59 @@ func TestVolumesWithTelemetryIngestAndExtensionsAndInClusterActiveGate(t *testin
 	})
 }
 
+func TestPersistentQueue(t *testing.T) {
+	getStatefulsetWithTelemetryIngest := func(t *testing.T, dk *dynakube.DynaKube) *appsv1.StatefulSet {
+		tokensSecret := getTokens(dk.Name, dk.Namespace)
+		configMap := getConfigConfigMap(dk.Name, dk.Namespace)
+
+		return getStatefulset(t, dk, &tokensSecret, &configMap)
+	}
+	persistentQueueVolumeMount := corev1.VolumeMount{
+		Name:      persistentQueueVolumeName,
+		MountPath: otelcconsts.PersistentQueueMountPath,
+	}
+
+	t.Run("no volume claim without persistent queue", func(t *testing.T) {
+		dk := getTestDynakubeWithTelemetryIngest()
+		dk.Spec.TelemetryIngest.Exporter = &telemetryingest.ExporterSpec{
+			SendingQueue: &telemetryingest.SendingQueueSpec{QueueSize: ptr.To(int64(100))},
+		}
+		statefulSet := getStatefulsetWithTelemetryIngest(t, dk)
+
+		assert.Empty(t, statefulSet.Spec.VolumeClaimTemplates)
+		assert.NotContains(t, statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts, persistentQueueVolumeMount)
+		assert.Nil(t, statefulSet.Spec.Template.Spec.SecurityContext.FSGroup)
+	})
+	t.Run("default volume claim with persistent queue", func(t *testing.T) {
+		dk := getTestDynakubeWithTelemetryIngest()
+		dk.Spec.TelemetryIngest.Exporter = &telemetryingest.ExporterSpec{
+			SendingQueue: &telemetryingest.SendingQueueSpec{Persistent: true},
+		}
+		statefulSet := getStatefulsetWithTelemetryIngest(t, dk)
+
+		require.Len(t, statefulSet.Spec.VolumeClaimTemplates, 1)
+		assert.Equal(t, persistentQueueVolumeName, statefulSet.Spec.VolumeClaimTemplates[0].Name)
+		assert.Equal(t, defaultPVCSpec(), statefulSet.Spec.VolumeClaimTemplates[0].Spec)
+		require.NotNil(t, statefulSet.Spec.PersistentVolumeClaimRetentionPolicy)
+		assert.Equal(t, appsv1.DeletePersistentVolumeClaimRetentionPolicyType, statefulSet.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted)
+		assert.Equal(t, appsv1.RetainPersistentVolumeClaimRetentionPolicyType, statefulSet.Spec.PersistentVolumeClaimRetentionPolicy.WhenScaled)
+		assert.NotEmpty(t, statefulSet.Annotations[statefulset.AnnotationPVCHash])
+		assert.Contains(t, statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts, persistentQueueVolumeMount)
+		assert.Equal(t, ptr.To(persistentQueueGroupID), statefulSet.Spec.Template.Spec.SecurityContext.FSGroup)
+	})
+	t.Run("custom volume claim with persistent queue", func(t *testing.T) {
+		customPVCSpec := &corev1.PersistentVolumeClaimSpec{StorageClassName: ptr.To("fast")}
+		dk := getTestDynakubeWithTelemetryIngest()
+		dk.Spec.TelemetryIngest.Exporter = &telemetryingest.ExporterSpec{
+			SendingQueue: &telemetryingest.SendingQueueSpec{Persistent: true, VolumeClaimTemplate: customPVCSpec},
+		}
+		statefulSet := getStatefulsetWithTelemetryIngest(t, dk)
+
+		require.Len(t, statefulSet.Spec.VolumeClaimTemplates, 1)
+		assert.Equal(t, *customPVCSpec, statefulSet.Spec.VolumeClaimTemplates[0].Spec)
+	})
+}
+
 func trustedCAsVolume(dk *dynakube.DynaKube) corev1.Volume {
 	return corev1.Volume{
 		Name: caCertsVolumeName,
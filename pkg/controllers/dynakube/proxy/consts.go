@@ -1,12 +1,23 @@
 package proxy
 
+import "github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+
 const (
 	hostField     = "host"
 	portField     = "port"
 	usernameField = "username"
 	passwordField = "password"
 	schemeField   = "scheme"
+	urlField      = dynakube.ProxyKey
 
 	SecretMountPath  = "/var/lib/dynatrace/secrets/internal-proxy"
 	SecretVolumeName = "internal-proxy-secret-volume"
 )
+
+// componentsWithSecret read the proxy from an internal proxy secret, the others get it directly from the operator
+var componentsWithSecret = []dynakube.ProxyComponent{
+	dynakube.OneAgentProxyComponent,
+	dynakube.ActiveGateProxyComponent,
+	dynakube.OtelCollectorProxyComponent,
+	dynakube.EECProxyComponent,
+}
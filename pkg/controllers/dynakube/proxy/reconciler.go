@@ -31,11 +31,75 @@ type Reconciler struct {
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context) error {
-	if r.dk.NeedsActiveGateProxy() || r.dk.NeedsOneAgentProxy() {
+	err := r.updateProxyHash(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileSharedSecret(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, component := range componentsWithSecret {
+		secretName := BuildComponentSecretName(r.dk.Name, component)
+
+		if !UsesComponentSecret(r.dk, component) {
+			err = r.ensureDeleted(ctx, secretName)
+		} else {
+			err = r.generateForComponent(ctx, component)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileSharedSecret manages the secret with the proxy of spec.proxy, which is used by all components without an override.
+func (r *Reconciler) reconcileSharedSecret(ctx context.Context) error {
+	if (r.dk.NeedsActiveGateProxy() && !UsesComponentSecret(r.dk, dynakube.ActiveGateProxyComponent)) ||
+		(r.dk.NeedsOneAgentProxy() && !UsesComponentSecret(r.dk, dynakube.OneAgentProxyComponent)) {
 		return r.generateForDynakube(ctx, r.dk)
 	}
 
-	return r.ensureDeleted(ctx, r.dk)
+	return r.ensureDeleted(ctx, BuildSecretName(r.dk.Name))
+}
+
+// updateProxyHash hashes the resolved proxies of all components, including the credentials,
+// so the hash also changes if only the content of a referenced secret changes.
+func (r *Reconciler) updateProxyHash(ctx context.Context) error {
+	proxies := make(map[dynakube.ProxyComponent]string)
+
+	for _, component := range dynakube.ProxyComponents {
+		if !r.dk.HasComponentProxy(component) {
+			continue
+		}
+
+		proxyURL, err := r.dk.ComponentProxy(ctx, r.apiReader, component)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		proxies[component] = proxyURL
+	}
+
+	if len(proxies) == 0 {
+		r.dk.Status.ProxyURLHash = ""
+
+		return nil
+	}
+
+	proxyHash, err := hasher.GenerateSecureHash(proxies)
+	if err != nil {
+		return err
+	}
+
+	r.dk.Status.ProxyURLHash = proxyHash
+
+	return nil
 }
 
 func NewReconciler(client client.Client, apiReader client.Reader, dk *dynakube.DynaKube) controllers.Reconciler {
@@ -52,8 +116,26 @@ func (r *Reconciler) generateForDynakube(ctx context.Context, dk *dynakube.DynaK
 		return errors.WithStack(err)
 	}
 
+	return r.createOrUpdateSecret(ctx, BuildSecretName(dk.Name), data)
+}
+
+func (r *Reconciler) generateForComponent(ctx context.Context, component dynakube.ProxyComponent) error {
+	proxyURL, err := r.dk.ComponentProxy(ctx, r.apiReader, component)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	data, err := buildProxyMap(proxyURL)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return r.createOrUpdateSecret(ctx, BuildComponentSecretName(r.dk.Name, component), data)
+}
+
+func (r *Reconciler) createOrUpdateSecret(ctx context.Context, secretName string, data map[string][]byte) error {
 	secret, err := k8ssecret.Build(r.dk,
-		BuildSecretName(dk.Name),
+		secretName,
 		data,
 		k8ssecret.SetType(corev1.SecretTypeOpaque),
 	)
@@ -68,15 +150,13 @@ func (r *Reconciler) generateForDynakube(ctx context.Context, dk *dynakube.DynaK
 	return errors.WithStack(err)
 }
 
-func (r *Reconciler) ensureDeleted(ctx context.Context, dk *dynakube.DynaKube) error {
-	secretName := BuildSecretName(dk.Name)
-
-	secret := corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: dk.Namespace}}
+func (r *Reconciler) ensureDeleted(ctx context.Context, secretName string) error {
+	secret := corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: r.dk.Namespace}}
 	if err := r.client.Delete(ctx, &secret); err != nil && !k8serrors.IsNotFound(err) {
 		return err
 	} else if err == nil {
 		// If the secret is deleted the error is nil, otherwise err is notFound, then we should log nothing
-		log.Info("removed secret", "namespace", dk.Namespace, "secret", secretName)
+		log.Info("removed secret", "namespace", r.dk.Namespace, "secret", secretName)
 	}
 
 	return nil
@@ -84,8 +164,19 @@ func (r *Reconciler) ensureDeleted(ctx context.Context, dk *dynakube.DynaKube) e
 
 func (r *Reconciler) createProxyMap(ctx context.Context, dk *dynakube.DynaKube) (map[string][]byte, error) {
 	if !dk.HasProxy() {
-		dk.Status.ProxyURLHash = ""
+		return buildProxyMap("")
+	}
 
+	proxyURL, err := dk.Proxy(ctx, r.apiReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildProxyMap(proxyURL)
+}
+
+func buildProxyMap(proxyURL string) (map[string][]byte, error) {
+	if proxyURL == "" {
 		// the parsed-proxy secret is expected to exist and the entrypoint.sh script handles empty values properly
 		return map[string][]byte{
 			hostField:     []byte(""),
@@ -93,19 +184,10 @@ func (r *Reconciler) createProxyMap(ctx context.Context, dk *dynakube.DynaKube)
 			usernameField: []byte(""),
 			passwordField: []byte(""),
 			schemeField:   []byte(""),
+			urlField:      []byte(""),
 		}, nil
 	}
 
-	proxyURL, err := dk.Proxy(ctx, r.apiReader)
-	if err != nil {
-		return nil, err
-	}
-
-	dk.Status.ProxyURLHash, err = hasher.GenerateSecureHash(proxyURL)
-	if err != nil {
-		return nil, err
-	}
-
 	scheme, host, port, username, password, err := parseProxyURL(proxyURL)
 	if err != nil {
 		return nil, err
@@ -117,6 +199,7 @@ func (r *Reconciler) createProxyMap(ctx context.Context, dk *dynakube.DynaKube)
 		usernameField: []byte(username),
 		passwordField: []byte(password),
 		schemeField:   []byte(scheme),
+		urlField:      []byte(proxyURL),
 	}, nil
 }
 
@@ -140,3 +223,23 @@ func parseProxyURL(proxy string) (scheme, host, port, username, password string,
 func BuildSecretName(dynakubeName string) string {
 	return dynakubeName + "-" + consts.ProxySecretSuffix
 }
+
+func BuildComponentSecretName(dynakubeName string, component dynakube.ProxyComponent) string {
+	return BuildSecretName(dynakubeName) + "-" + string(component)
+}
+
+// BuildSecretNameForComponent returns the name of the internal proxy secret, which the component has to use.
+func BuildSecretNameForComponent(dk *dynakube.DynaKube, component dynakube.ProxyComponent) string {
+	if UsesComponentSecret(dk, component) {
+		return BuildComponentSecretName(dk.Name, component)
+	}
+
+	return BuildSecretName(dk.Name)
+}
+
+// UsesComponentSecret is true if the proxy of the component differs from spec.proxy, so it gets its own internal proxy secret.
+func UsesComponentSecret(dk *dynakube.DynaKube, component dynakube.ProxyComponent) bool {
+	override := dk.ProxyOverride(component)
+
+	return dk.HasComponentProxy(component) && override != nil && (dk.HasProxyOverride(component) || override.CredentialsSecret != "")
+}
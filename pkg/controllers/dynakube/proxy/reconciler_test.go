@@ -347,3 +347,129 @@ func buildProxyURL(scheme string, username string, password string, host string,
 
 	return url + host + ":" + port
 }
+
+func TestReconcileProxyOverrides(t *testing.T) {
+	const credentialsSecretName = "proxy-credentials"
+
+	credentialsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsSecretName,
+			Namespace: testNamespace,
+		},
+		Data: map[string][]byte{
+			dynakube.ProxyUsernameKey: []byte(proxyDifferentUsername),
+			dynakube.ProxyPasswordKey: []byte(proxyPassword),
+		},
+	}
+
+	t.Run("component with override gets its own secret, the others use spec.proxy", func(t *testing.T) {
+		testClient := fake.NewClientBuilder().WithObjects(credentialsSecret).Build()
+		dk := createDynaKubeWithProxy(&value.Source{Value: buildProxyURL(proxyHTTPScheme, proxyUsername, proxyPassword, proxyHost, proxyPort)})
+		dk.Spec.Proxies = &dynakube.ProxiesSpec{
+			ActiveGate: &dynakube.ComponentProxySpec{
+				URL:               &value.Source{Value: "https://data.proxy.net:3128"},
+				CredentialsSecret: credentialsSecretName,
+			},
+		}
+
+		r := newTestReconcilerWithCustomDynaKube(testClient, dk)
+		err := r.Reconcile(context.Background())
+		require.NoError(t, err)
+
+		var componentSecret corev1.Secret
+		err = testClient.Get(context.Background(), client.ObjectKey{Name: BuildComponentSecretName(testDynakubeName, dynakube.ActiveGateProxyComponent), Namespace: testNamespace}, &componentSecret)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data.proxy.net"), componentSecret.Data[hostField])
+		assert.Equal(t, []byte(proxyDifferentUsername), componentSecret.Data[usernameField])
+		assert.Equal(t, []byte(proxyHTTPSScheme), componentSecret.Data[schemeField])
+		assert.Equal(t, BuildComponentSecretName(testDynakubeName, dynakube.ActiveGateProxyComponent), BuildSecretNameForComponent(dk, dynakube.ActiveGateProxyComponent))
+
+		var sharedSecret corev1.Secret
+		err = testClient.Get(context.Background(), client.ObjectKey{Name: BuildSecretName(testDynakubeName), Namespace: testNamespace}, &sharedSecret)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(proxyHost), sharedSecret.Data[hostField])
+		assert.Equal(t, BuildSecretName(testDynakubeName), BuildSecretNameForComponent(dk, dynakube.OneAgentProxyComponent))
+	})
+	t.Run("override removed => component secret is deleted", func(t *testing.T) {
+		dk := createDynaKubeWithProxy(nil)
+		dk.Spec.Proxies = &dynakube.ProxiesSpec{OneAgent: &dynakube.ComponentProxySpec{URL: &value.Source{Value: "http://" + proxyHost}}}
+		testClient := fake.NewClientBuilder().Build()
+
+		err := newTestReconcilerWithCustomDynaKube(testClient, dk).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		secretKey := client.ObjectKey{Name: BuildComponentSecretName(testDynakubeName, dynakube.OneAgentProxyComponent), Namespace: testNamespace}
+		require.NoError(t, testClient.Get(context.Background(), secretKey, &corev1.Secret{}))
+
+		err = testClient.Get(context.Background(), client.ObjectKey{Name: BuildSecretName(testDynakubeName), Namespace: testNamespace}, &corev1.Secret{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		dk.Spec.Proxies.OneAgent.Disabled = true
+
+		err = newTestReconcilerWithCustomDynaKube(testClient, dk).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		err = testClient.Get(context.Background(), secretKey, &corev1.Secret{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+}
+
+func TestReconcileProxyHash(t *testing.T) {
+	const credentialsSecretName = "proxy-credentials"
+
+	createCredentialsSecret := func(password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      credentialsSecretName,
+				Namespace: testNamespace,
+			},
+			Data: map[string][]byte{
+				dynakube.ProxyUsernameKey: []byte(proxyUsername),
+				dynakube.ProxyPasswordKey: []byte(password),
+			},
+		}
+	}
+
+	createDynaKubeWithOverride := func() *dynakube.DynaKube {
+		dk := createDynaKubeWithProxy(nil)
+		dk.Spec.Proxies = &dynakube.ProxiesSpec{
+			LogMonitoring: &dynakube.ComponentProxySpec{
+				URL:               &value.Source{Value: "http://" + proxyHost},
+				CredentialsSecret: credentialsSecretName,
+			},
+		}
+
+		return dk
+	}
+
+	t.Run("no proxy => no hash", func(t *testing.T) {
+		dk := createDynaKubeWithProxy(nil)
+		dk.Status.ProxyURLHash = "outdated"
+
+		err := newTestReconcilerWithCustomDynaKube(fake.NewClientBuilder().Build(), dk).Reconcile(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, dk.Status.ProxyURLHash)
+	})
+	t.Run("content of the credentials secret changed => hash changed", func(t *testing.T) {
+		dk := createDynaKubeWithOverride()
+		err := newTestReconcilerWithCustomDynaKube(fake.NewClientBuilder().WithObjects(createCredentialsSecret(proxyPassword)).Build(), dk).Reconcile(context.Background())
+		require.NoError(t, err)
+		require.NotEmpty(t, dk.Status.ProxyURLHash)
+
+		changedDk := createDynaKubeWithOverride()
+		err = newTestReconcilerWithCustomDynaKube(fake.NewClientBuilder().WithObjects(createCredentialsSecret("changed")).Build(), changedDk).Reconcile(context.Background())
+		require.NoError(t, err)
+		assert.NotEqual(t, dk.Status.ProxyURLHash, changedDk.Status.ProxyURLHash)
+	})
+	t.Run("content of the proxy secret changed => hash changed", func(t *testing.T) {
+		dk := createDynaKubeWithProxy(&value.Source{ValueFrom: customProxySecret})
+		err := newTestReconcilerWithCustomDynaKube(fake.NewClientBuilder().WithObjects(createProxySecret("http://"+proxyHost)).Build(), dk).Reconcile(context.Background())
+		require.NoError(t, err)
+		require.NotEmpty(t, dk.Status.ProxyURLHash)
+
+		changedDk := createDynaKubeWithProxy(&value.Source{ValueFrom: customProxySecret})
+		err = newTestReconcilerWithCustomDynaKube(fake.NewClientBuilder().WithObjects(createProxySecret("http://other."+proxyHost)).Build(), changedDk).Reconcile(context.Background())
+		require.NoError(t, err)
+		assert.NotEqual(t, dk.Status.ProxyURLHash, changedDk.Status.ProxyURLHash)
+	})
+}
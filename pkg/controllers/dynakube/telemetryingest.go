@@ -0,0 +1,30 @@
+package dynakube
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// mapPipelineExtensionsToDynaKubes enqueues every DynaKube whose TelemetryIngest references the changed ConfigMap as pipeline extensions,
+// so the OTel collector configuration and service are updated without waiting for the next periodic reconcile.
+func (controller *Controller) mapPipelineExtensionsToDynaKubes(ctx context.Context, configMap client.Object) []reconcile.Request {
+	var dkList dynakube.DynaKubeList
+	if err := controller.client.List(ctx, &dkList, client.InNamespace(configMap.GetNamespace())); err != nil {
+		log.Info("failed to list DynaKubes for pipeline extensions configmap", "configmap", configMap.GetName(), "error", err.Error())
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, dk := range dkList.Items {
+		if dk.TelemetryIngest().HasPipelineExtensions() && dk.TelemetryIngest().PipelineExtensionsRef == configMap.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&dk)})
+		}
+	}
+
+	return requests
+}
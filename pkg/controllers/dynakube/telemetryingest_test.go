@@ -0,0 +1,46 @@
+package dynakube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/telemetryingest"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMapPipelineExtensionsToDynaKubes(t *testing.T) {
+	dkWithExtensions := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "with-extensions", Namespace: testNamespace},
+		Spec: dynakube.DynaKubeSpec{
+			TelemetryIngest: &telemetryingest.Spec{PipelineExtensionsRef: "pipelines"},
+		},
+	}
+	dkWithoutExtensions := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "without-extensions", Namespace: testNamespace},
+		Spec: dynakube.DynaKubeSpec{
+			TelemetryIngest: &telemetryingest.Spec{},
+		},
+	}
+	controller := &Controller{
+		client: fake.NewClient(dkWithExtensions, dkWithoutExtensions),
+	}
+
+	t.Run("referenced configmap => dynakube enqueued", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "pipelines", Namespace: testNamespace}}
+
+		requests := controller.mapPipelineExtensionsToDynaKubes(context.Background(), configMap)
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "with-extensions", requests[0].Name)
+	})
+	t.Run("unreferenced configmap => nothing enqueued", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNamespace}}
+
+		assert.Empty(t, controller.mapPipelineExtensionsToDynaKubes(context.Background(), configMap))
+	})
+}
@@ -59,10 +59,14 @@ func (reader Reader) readTokens(ctx context.Context) (Tokens, error) {
 }
 
 func (reader Reader) verifyAPITokenExists(tokens Tokens) error {
+	if tokens.UsesPlatformAuth() {
+		return nil
+	}
+
 	apiToken, hasAPIToken := tokens[dtclient.APIToken]
 
 	if !hasAPIToken || len(apiToken.Value) == 0 {
-		return errors.New(fmt.Sprintf("the API token is missing from the token secret '%s:%s'", reader.dk.Namespace, reader.dk.Tokens()))
+		return errors.New(fmt.Sprintf("neither the API token nor a platform token or OAuth client is present in the token secret '%s:%s'", reader.dk.Namespace, reader.dk.Tokens()))
 	}
 
 	return nil
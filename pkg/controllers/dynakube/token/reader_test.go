@@ -15,10 +15,13 @@ import (
 )
 
 const (
-	testAPIToken        = "test-api-token"
-	testPaasToken       = "test-paas-token"
-	testDataIngestToken = "test-data-ingest-token"
-	testIrrelevantToken = "test-irrelevant-token"
+	testAPIToken          = "test-api-token"
+	testPaasToken         = "test-paas-token"
+	testDataIngestToken   = "test-data-ingest-token"
+	testIrrelevantToken   = "test-irrelevant-token"
+	testPlatformToken     = "test-platform-token"
+	testOAuthClientID     = "test-oauth-client-id"
+	testOAuthClientSecret = "test-oauth-client-secret"
 
 	testIrrelevantTokenKey = "irrelevant-token"
 
@@ -89,7 +92,7 @@ func testVerifyTokens(t *testing.T) {
 			},
 		})
 
-		require.EqualError(t, err, "the API token is missing from the token secret 'dynatrace:dynakube'")
+		require.EqualError(t, err, "neither the API token nor a platform token or OAuth client is present in the token secret 'dynatrace:dynakube'")
 	})
 	t.Run("no error if api token exists", func(t *testing.T) {
 		reader := NewReader(nil, nil)
@@ -105,4 +108,43 @@ func testVerifyTokens(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+	t.Run("no error if platform token exists", func(t *testing.T) {
+		reader := NewReader(nil, nil)
+
+		err := reader.verifyAPITokenExists(map[string]*Token{
+			dtclient.PlatformToken: {
+				Value: testPlatformToken,
+			},
+		})
+
+		require.NoError(t, err)
+	})
+	t.Run("no error if oauth client exists", func(t *testing.T) {
+		reader := NewReader(nil, nil)
+
+		err := reader.verifyAPITokenExists(map[string]*Token{
+			dtclient.OAuthClientID: {
+				Value: testOAuthClientID,
+			},
+			dtclient.OAuthClientSecret: {
+				Value: testOAuthClientSecret,
+			},
+		})
+
+		require.NoError(t, err)
+	})
+	t.Run("error if oauth client secret is missing", func(t *testing.T) {
+		reader := NewReader(nil, &dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{
+			Name:      dynakubeName,
+			Namespace: dynatraceNamespace,
+		}})
+
+		err := reader.verifyAPITokenExists(map[string]*Token{
+			dtclient.OAuthClientID: {
+				Value: testOAuthClientID,
+			},
+		})
+
+		require.Error(t, err)
+	})
 }
@@ -33,7 +33,7 @@ func (token *Token) verifyScopes(ctx context.Context, dtClient dtclient.Client,
 		return map[string]bool{}, nil
 	}
 
-	scopes, err := dtClient.GetTokenScopes(ctx, token.Value)
+	scopes, err := token.getScopes(ctx, dtClient)
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +57,15 @@ func (token *Token) verifyScopes(ctx context.Context, dtClient dtclient.Client,
 	return optionalScopes, err
 }
 
+func (token *Token) getScopes(ctx context.Context, dtClient dtclient.Client) (dtclient.TokenScopes, error) {
+	switch token.Type {
+	case dtclient.PlatformToken, dtclient.OAuthClientID:
+		return dtClient.GetPlatformTokenScopes(ctx)
+	default:
+		return dtClient.GetTokenScopes(ctx, token.Value)
+	}
+}
+
 func (token *Token) verifyRequiredScopes(scopes dtclient.TokenScopes, dk dynakube.DynaKube) error {
 	collectedErrors := make([]error, 0)
 
@@ -8,6 +8,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceapi"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
 )
 
 type Tokens map[string]*Token
@@ -24,6 +25,45 @@ func (tokens Tokens) DataIngestToken() *Token {
 	return tokens.getToken(dtclient.DataIngestToken)
 }
 
+func (tokens Tokens) PlatformToken() *Token {
+	return tokens.getToken(dtclient.PlatformToken)
+}
+
+func (tokens Tokens) OAuthClientID() *Token {
+	return tokens.getToken(dtclient.OAuthClientID)
+}
+
+func (tokens Tokens) OAuthClientSecret() *Token {
+	return tokens.getToken(dtclient.OAuthClientSecret)
+}
+
+func (tokens Tokens) OAuthTokenURL() *Token {
+	return tokens.getToken(dtclient.OAuthTokenURL)
+}
+
+// UsesPlatformAuth returns true if the tokens contain a platform token or OAuth client credentials.
+// In that case they are used to authenticate all API requests instead of the API and PaaS token.
+func (tokens Tokens) UsesPlatformAuth() bool {
+	return tokens.PlatformToken().Value != "" || tokens.HasOAuthClient()
+}
+
+func (tokens Tokens) HasOAuthClient() bool {
+	return tokens.OAuthClientID().Value != "" && tokens.OAuthClientSecret().Value != ""
+}
+
+// platformAuthToken returns the token that holds the features of the platform authentication.
+func (tokens Tokens) platformAuthToken() *Token {
+	if tokens.PlatformToken().Value != "" {
+		return tokens[dtclient.PlatformToken]
+	}
+
+	if tokens.HasOAuthClient() {
+		return tokens[dtclient.OAuthClientID]
+	}
+
+	return nil
+}
+
 func (tokens Tokens) getToken(tokenName string) *Token {
 	token, hasToken := tokens[tokenName]
 	if !hasToken {
@@ -36,6 +76,19 @@ func (tokens Tokens) getToken(tokenName string) *Token {
 func (tokens Tokens) AddFeatureScopesToTokens() Tokens {
 	_, hasPaasToken := tokens[dtclient.PaasToken]
 
+	// the platform authentication replaces the API and PaaS token for all API requests, so it needs the scopes of both
+	if platformAuthToken := tokens.platformAuthToken(); platformAuthToken != nil {
+		platformAuthToken.addFeatures(getFeaturesForAPIToken(false))
+
+		for _, token := range tokens {
+			if token.Type == dtclient.DataIngestToken {
+				token.addFeatures(getFeaturesForDataIngest())
+			}
+		}
+
+		return tokens
+	}
+
 	for _, token := range tokens {
 		switch token.Type {
 		case dtclient.APIToken:
@@ -109,6 +162,26 @@ func concatErrors(errs []error) error {
 	return errors.New(concatenatedError)
 }
 
+// Hash creates a secure hash of all token values, it changes whenever a token is rotated.
+func (tokens Tokens) Hash() (string, error) {
+	values := make(map[string]string, len(tokens))
+	for tokenType, token := range tokens {
+		values[tokenType] = token.Value
+	}
+
+	return hasher.GenerateSecureHash(values)
+}
+
+// HashOf hashes only the given tokens, so components can be restarted only if the tokens they consume are rotated.
+func (tokens Tokens) HashOf(tokenTypes ...string) (string, error) {
+	values := make(map[string]string, len(tokenTypes))
+	for _, tokenType := range tokenTypes {
+		values[tokenType] = tokens.getToken(tokenType).Value
+	}
+
+	return hasher.GenerateSecureHash(values)
+}
+
 func CheckForDataIngestToken(tokens Tokens) bool {
 	dataIngestToken, hasDataIngestToken := tokens[dtclient.DataIngestToken]
 
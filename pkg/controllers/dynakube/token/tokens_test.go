@@ -238,6 +238,91 @@ func TestTokens(t *testing.T) {
 	})
 }
 
+func TestTokens_PlatformAuth(t *testing.T) {
+	t.Run("platform token gets the features of the api and paas token", func(t *testing.T) {
+		platformToken := newToken(dtclient.PlatformToken, "platform-token")
+		dataIngestToken := newToken(dtclient.DataIngestToken, "data-ingest-token")
+		tokens := Tokens{
+			dtclient.PlatformToken:   &platformToken,
+			dtclient.DataIngestToken: &dataIngestToken,
+		}
+		tokens = tokens.AddFeatureScopesToTokens()
+
+		assert.True(t, tokens.UsesPlatformAuth())
+		assert.Len(t, tokens.PlatformToken().Features, 7)
+		assert.Empty(t, tokens.APIToken().Features)
+		assert.Len(t, tokens.DataIngestToken().Features, 4)
+	})
+	t.Run("verify scopes of platform token", func(t *testing.T) {
+		platformToken := newToken(dtclient.PlatformToken, "platform-token")
+		tokens := Tokens{
+			dtclient.PlatformToken: &platformToken,
+		}
+		tokens = tokens.AddFeatureScopesToTokens()
+
+		fakeClient := dtclientmock.NewClient(t)
+		fakeClient.EXPECT().GetPlatformTokenScopes(mock.Anything).Return(dtclient.TokenScopes{dtclient.TokenScopeDataExport}, nil).Once()
+
+		_, err := tokens.VerifyScopes(t.Context(), fakeClient, dynakube.DynaKube{})
+
+		require.EqualError(t, err, "token 'platformToken' has scope errors: [feature 'Download Installer' is missing scope 'InstallerDownload']")
+	})
+	t.Run("oauth client gets the features of the api and paas token, api token is ignored", func(t *testing.T) {
+		apiToken := newToken(dtclient.APIToken, "api-token")
+		clientID := newToken(dtclient.OAuthClientID, "client-id")
+		clientSecret := newToken(dtclient.OAuthClientSecret, "client-secret")
+		tokens := Tokens{
+			dtclient.APIToken:          &apiToken,
+			dtclient.OAuthClientID:     &clientID,
+			dtclient.OAuthClientSecret: &clientSecret,
+		}
+		tokens = tokens.AddFeatureScopesToTokens()
+
+		assert.True(t, tokens.UsesPlatformAuth())
+		assert.Len(t, tokens.OAuthClientID().Features, 7)
+		assert.Empty(t, tokens.OAuthClientSecret().Features)
+		assert.Empty(t, tokens.APIToken().Features)
+	})
+	t.Run("oauth client without secret is not used", func(t *testing.T) {
+		clientID := newToken(dtclient.OAuthClientID, "client-id")
+		tokens := Tokens{
+			dtclient.OAuthClientID: &clientID,
+		}
+
+		assert.False(t, tokens.UsesPlatformAuth())
+	})
+	t.Run("verify scopes of oauth client", func(t *testing.T) {
+		clientID := newToken(dtclient.OAuthClientID, "client-id")
+		clientSecret := newToken(dtclient.OAuthClientSecret, "client-secret")
+		tokens := Tokens{
+			dtclient.OAuthClientID:     &clientID,
+			dtclient.OAuthClientSecret: &clientSecret,
+		}
+		tokens = tokens.AddFeatureScopesToTokens()
+
+		fakeClient := dtclientmock.NewClient(t)
+		fakeClient.EXPECT().GetPlatformTokenScopes(mock.Anything).Return(dtclient.TokenScopes{dtclient.TokenScopeDataExport}, nil).Once()
+
+		_, err := tokens.VerifyScopes(t.Context(), fakeClient, dynakube.DynaKube{})
+
+		require.EqualError(t, err, "token 'oAuthClientId' has scope errors: [feature 'Download Installer' is missing scope 'InstallerDownload']")
+	})
+	t.Run("oauth token url is not a token with features", func(t *testing.T) {
+		clientID := newToken(dtclient.OAuthClientID, "client-id")
+		clientSecret := newToken(dtclient.OAuthClientSecret, "client-secret")
+		tokenURL := newToken(dtclient.OAuthTokenURL, "https://sso.example.com/token")
+		tokens := Tokens{
+			dtclient.OAuthClientID:     &clientID,
+			dtclient.OAuthClientSecret: &clientSecret,
+			dtclient.OAuthTokenURL:     &tokenURL,
+		}
+		tokens = tokens.AddFeatureScopesToTokens()
+
+		assert.Equal(t, "https://sso.example.com/token", tokens.OAuthTokenURL().Value)
+		assert.Empty(t, tokens.OAuthTokenURL().Features)
+	})
+}
+
 func TestTokens_VerifyScopes(t *testing.T) {
 	type testCase struct {
 		title            string
@@ -0,0 +1,163 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/bootstrapperconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/otlp/exporterconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// detectTokenRotation compares the hash of the current tokens with the one stored in the status.
+// In case the tokens were rotated, the token scopes are verified right away and the rollout of the components is tracked by the TokenRotation condition.
+// The hashes are seeded on the first reconcile without restarting any component.
+func (controller *Controller) detectTokenRotation(dk *dynakube.DynaKube, tokens token.Tokens) error {
+	tokensHash, err := tokens.Hash()
+	if err != nil {
+		return errors.WithMessage(err, "failed to hash tokens")
+	}
+
+	dataIngestTokenHash, err := tokens.HashOf(dtclient.DataIngestToken)
+	if err != nil {
+		return errors.WithMessage(err, "failed to hash data ingest token")
+	}
+
+	// only the pods of the OTel collector consume a token of the secret, the others get their tenant token from the connection info
+	previousDataIngestTokenHash := dk.Status.DataIngestTokenHash
+	dk.Status.DataIngestTokenHash = dataIngestTokenHash
+
+	if previousDataIngestTokenHash != "" && previousDataIngestTokenHash != dataIngestTokenHash {
+		dk.Status.DataIngestTokenRolloutHash = dataIngestTokenHash
+	}
+
+	previousHash := dk.Status.TokensHash
+	dk.Status.TokensHash = tokensHash
+
+	if previousHash == "" || previousHash == tokensHash {
+		return nil
+	}
+
+	log.Info("tokens were rotated, restarting components", "dynakube", dk.Name, "secret", dk.Tokens())
+
+	// the scopes of the new tokens have to be verified, no matter when the last verification happened
+	dk.Status.DynatraceAPI.LastTokenScopeRequest = metav1.Time{}
+
+	controller.setConditionTokenRotationInProgress(dk, fmt.Sprintf("Tokens in secret %s were rotated, restarting components", dk.Tokens()))
+
+	return nil
+}
+
+// updateTokenRotationStatus checks if all components that use the tokens were rolled out with the current tokens.
+// As long as the rollout is ongoing, the DynaKube is requeued more often to report the progress.
+func (controller *Controller) updateTokenRotationStatus(ctx context.Context, dk *dynakube.DynaKube) error {
+	condition := meta.FindStatusCondition(dk.Status.Conditions, dynakube.TokenRotationConditionType)
+	if condition == nil || condition.Reason != dynakube.ReasonTokenRotationInProgress {
+		return nil
+	}
+
+	pending, err := controller.getPendingTokenRolloutWorkloads(ctx, dk)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		controller.setConditionTokenRotationInProgress(dk, "Waiting for rollout of "+strings.Join(pending, ", "))
+		controller.setRequeueAfterIfNewIsShorter(fastUpdateInterval)
+
+		return nil
+	}
+
+	controller.setConditionTokenRotationCompleted(dk)
+
+	return nil
+}
+
+func (controller *Controller) getPendingTokenRolloutWorkloads(ctx context.Context, dk *dynakube.DynaKube) ([]string, error) {
+	listOpts := []client.ListOption{
+		client.InNamespace(dk.Namespace),
+		client.MatchingLabels{labels.AppCreatedByLabel: dk.Name},
+	}
+
+	var pending []string
+
+	var statefulSets appsv1.StatefulSetList
+	if err := controller.client.List(ctx, &statefulSets, listOpts...); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for _, sts := range statefulSets.Items {
+		if usesTokens(sts.Spec.Template) && !isStatefulSetRolledOut(sts, dk.Status.DataIngestTokenRolloutHash) {
+			pending = append(pending, "statefulset/"+sts.Name)
+		}
+	}
+
+	// the config secrets for the injected namespaces contain the tokens too, they are replicated from these source secrets
+	for _, secretName := range []string{bootstrapperconfig.GetSourceConfigSecretName(dk.Name), exporterconfig.GetSourceConfigSecretName(dk.Name)} {
+		var secret corev1.Secret
+
+		err := controller.client.Get(ctx, client.ObjectKey{Name: secretName, Namespace: dk.Namespace}, &secret)
+		if k8serrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if secret.Annotations[api.AnnotationTokensHash] != dk.Status.TokensHash {
+			pending = append(pending, "secret/"+secretName)
+		}
+	}
+
+	return pending, nil
+}
+
+func usesTokens(template corev1.PodTemplateSpec) bool {
+	_, ok := template.Annotations[api.AnnotationTokensHash]
+
+	return ok
+}
+
+func isStatefulSetRolledOut(sts appsv1.StatefulSet, tokensHash string) bool {
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	return sts.Spec.Template.Annotations[api.AnnotationTokensHash] == tokensHash &&
+		sts.Status.ObservedGeneration >= sts.Generation &&
+		sts.Status.UpdatedReplicas == replicas &&
+		sts.Status.ReadyReplicas == replicas
+}
+
+// mapTokenSecretToDynaKubes enqueues all DynaKubes that reference the changed secret as their tokens secret.
+func (controller *Controller) mapTokenSecretToDynaKubes(ctx context.Context, secret client.Object) []reconcile.Request {
+	var dkList dynakube.DynaKubeList
+	if err := controller.client.List(ctx, &dkList, client.InNamespace(secret.GetNamespace())); err != nil {
+		log.Info("failed to list DynaKubes for token secret", "secret", secret.GetName(), "error", err.Error())
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, dk := range dkList.Items {
+		if dk.Tokens() == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&dk)})
+		}
+	}
+
+	return requests
+}
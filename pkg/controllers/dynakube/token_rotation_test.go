@@ -0,0 +1,233 @@
+package dynakube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/bootstrapperconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestDetectTokenRotation(t *testing.T) {
+	newTokens := func(apiToken string) token.Tokens {
+		return token.Tokens{
+			dtclient.APIToken:        &token.Token{Type: dtclient.APIToken, Value: apiToken},
+			dtclient.DataIngestToken: &token.Token{Type: dtclient.DataIngestToken, Value: "data-ingest-token"},
+		}
+	}
+
+	t.Run("first reconcile => hashes are seeded, no rotation and no rollout", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+		controller := &Controller{}
+
+		err := controller.detectTokenRotation(dk, newTokens("token"))
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, dk.Status.TokensHash)
+		assert.NotEmpty(t, dk.Status.DataIngestTokenHash)
+		assert.Empty(t, dk.Status.DataIngestTokenRolloutHash)
+		assert.Nil(t, meta.FindStatusCondition(dk.Status.Conditions, dynakube.TokenRotationConditionType))
+	})
+	t.Run("upgrade with stored tokens hash => data ingest token hash is seeded without rollout", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+		controller := &Controller{}
+
+		require.NoError(t, controller.detectTokenRotation(dk, newTokens("token")))
+
+		dk.Status.DataIngestTokenHash = ""
+
+		require.NoError(t, controller.detectTokenRotation(dk, newTokens("token")))
+
+		assert.NotEmpty(t, dk.Status.DataIngestTokenHash)
+		assert.Empty(t, dk.Status.DataIngestTokenRolloutHash)
+	})
+	t.Run("unchanged tokens => no rotation", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+		dk.Status.DynatraceAPI.LastTokenScopeRequest = metav1.Now()
+		controller := &Controller{}
+
+		require.NoError(t, controller.detectTokenRotation(dk, newTokens("token")))
+		require.NoError(t, controller.detectTokenRotation(dk, newTokens("token")))
+
+		assert.False(t, dk.Status.DynatraceAPI.LastTokenScopeRequest.IsZero())
+		assert.Nil(t, meta.FindStatusCondition(dk.Status.Conditions, dynakube.TokenRotationConditionType))
+	})
+	t.Run("rotated tokens => condition set, scope verification forced", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+		controller := &Controller{}
+
+		require.NoError(t, controller.detectTokenRotation(dk, newTokens("token")))
+
+		previousHash := dk.Status.TokensHash
+		dk.Status.DynatraceAPI.LastTokenScopeRequest = metav1.Now()
+
+		require.NoError(t, controller.detectTokenRotation(dk, newTokens("rotated-token")))
+
+		assert.NotEqual(t, previousHash, dk.Status.TokensHash)
+		assert.True(t, dk.Status.DynatraceAPI.LastTokenScopeRequest.IsZero())
+		assert.Empty(t, dk.Status.DataIngestTokenRolloutHash, "the data ingest token wasn't rotated")
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, dynakube.TokenRotationConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, dynakube.ReasonTokenRotationInProgress, condition.Reason)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	})
+	t.Run("rotated data ingest token => rollout hash set", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+		controller := &Controller{}
+
+		require.NoError(t, controller.detectTokenRotation(dk, newTokens("token")))
+
+		rotatedTokens := newTokens("token")
+		rotatedTokens.DataIngestToken().Value = "rotated-data-ingest-token"
+
+		require.NoError(t, controller.detectTokenRotation(dk, rotatedTokens))
+
+		assert.Equal(t, dk.Status.DataIngestTokenHash, dk.Status.DataIngestTokenRolloutHash)
+		assert.NotEmpty(t, dk.Status.DataIngestTokenRolloutHash)
+	})
+}
+
+func TestUpdateTokenRotationStatus(t *testing.T) {
+	const tokensHash = "new-hash"
+
+	newDynakube := func() *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testName,
+				Namespace: testNamespace,
+			},
+		}
+		dk.Status.TokensHash = tokensHash
+		dk.Status.DataIngestTokenRolloutHash = tokensHash
+		controller := &Controller{}
+		controller.setConditionTokenRotationInProgress(dk, testMessage)
+
+		return dk
+	}
+
+	newStatefulSet := func(name, hash string, rolledOut bool) *appsv1.StatefulSet {
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testName + "-" + name,
+				Namespace: testNamespace,
+				Labels:    map[string]string{labels.AppCreatedByLabel: testName},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To(int32(2)),
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{api.AnnotationTokensHash: hash},
+					},
+				},
+			},
+			Status: appsv1.StatefulSetStatus{
+				UpdatedReplicas: 2,
+				ReadyReplicas:   2,
+			},
+		}
+
+		if !rolledOut {
+			sts.Status.ReadyReplicas = 1
+		}
+
+		return sts
+	}
+
+	t.Run("no rotation in progress => nothing to do", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+		controller := &Controller{client: fake.NewClient()}
+
+		require.NoError(t, controller.updateTokenRotationStatus(context.Background(), dk))
+		assert.Empty(t, dk.Status.Conditions)
+	})
+	t.Run("all workloads rolled out => completed", func(t *testing.T) {
+		dk := newDynakube()
+		controller := &Controller{
+			client:       fake.NewClient(newStatefulSet("otel-collector", tokensHash, true)),
+			requeueAfter: defaultUpdateInterval,
+		}
+
+		require.NoError(t, controller.updateTokenRotationStatus(context.Background(), dk))
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, dynakube.TokenRotationConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, dynakube.ReasonTokenRotationCompleted, condition.Reason)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, defaultUpdateInterval, controller.requeueAfter)
+	})
+	t.Run("workloads still rolling out => in progress, requeue fast", func(t *testing.T) {
+		dk := newDynakube()
+		controller := &Controller{
+			client:       fake.NewClient(newStatefulSet("otel-collector", tokensHash, false), newStatefulSet("other", "old-hash", true)),
+			requeueAfter: defaultUpdateInterval,
+		}
+
+		require.NoError(t, controller.updateTokenRotationStatus(context.Background(), dk))
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, dynakube.TokenRotationConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, dynakube.ReasonTokenRotationInProgress, condition.Reason)
+		assert.Contains(t, condition.Message, "statefulset/"+testName+"-otel-collector")
+		assert.Contains(t, condition.Message, "statefulset/"+testName+"-other")
+		assert.Equal(t, fastUpdateInterval, controller.requeueAfter)
+	})
+	t.Run("bootstrapper config not updated yet => in progress", func(t *testing.T) {
+		dk := newDynakube()
+		configSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        bootstrapperconfig.GetSourceConfigSecretName(testName),
+				Namespace:   testNamespace,
+				Annotations: map[string]string{api.AnnotationTokensHash: "old-hash"},
+			},
+		}
+		controller := &Controller{
+			client:       fake.NewClient(newStatefulSet("otel-collector", tokensHash, true), configSecret),
+			requeueAfter: defaultUpdateInterval,
+		}
+
+		require.NoError(t, controller.updateTokenRotationStatus(context.Background(), dk))
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, dynakube.TokenRotationConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, dynakube.ReasonTokenRotationInProgress, condition.Reason)
+		assert.Contains(t, condition.Message, "secret/"+configSecret.Name)
+	})
+}
+
+func TestMapTokenSecretToDynaKubes(t *testing.T) {
+	dkWithDefaultTokens := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-tokens", Namespace: testNamespace},
+	}
+	dkWithCustomTokens := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-tokens", Namespace: testNamespace},
+		Spec:       dynakube.DynaKubeSpec{Tokens: "custom"},
+	}
+	controller := &Controller{client: fake.NewClient(dkWithDefaultTokens, dkWithCustomTokens)}
+
+	t.Run("secret used as tokens => dynakube enqueued", func(t *testing.T) {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: testNamespace}}
+
+		requests := controller.mapTokenSecretToDynaKubes(context.Background(), secret)
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "custom-tokens", requests[0].Name)
+	})
+	t.Run("unrelated secret => nothing enqueued", func(t *testing.T) {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: testNamespace}}
+
+		assert.Empty(t, controller.mapTokenSecretToDynaKubes(context.Background(), secret))
+	})
+}
@@ -8,7 +8,7 @@ import (
 )
 
 const (
-	downgradeReason           = "Downgrade"
+	DowngradeReason           = "Downgrade"
 	verifiedReason            = "Verified"
 	verificationSkippedReason = "VerificationSkipped"
 	verificationFailedReason  = "VerificationFailed"
@@ -18,7 +18,7 @@ func setDowngradeCondition(conditions *[]metav1.Condition, conditionType, previo
 	condition := metav1.Condition{
 		Type:    conditionType,
 		Status:  metav1.ConditionFalse,
-		Reason:  downgradeReason,
+		Reason:  DowngradeReason,
 		Message: fmt.Sprintf("Downgrade detected from %s to %s, which is not supported for this feature.", previousVersion, newVersion),
 	}
 	_ = meta.SetStatusCondition(conditions, condition)
@@ -0,0 +1,42 @@
+package version
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+)
+
+// deferUpdate keeps the previous version if the updater found a new version outside of the maintenance windows,
+// the new version is stored as pending and applied once a window starts.
+// Changes of the configuration (custom image/version, source) are never deferred, as they are requested by the user.
+func (r *reconciler) deferUpdate(updater StatusUpdater, dk *dynakube.DynaKube, previous status.VersionStatus) bool {
+	target := updater.Target()
+
+	if previous.IsZero() ||
+		previous.Source != target.Source ||
+		target.Source == status.CustomImageVersionSource ||
+		target.Source == status.CustomVersionVersionSource ||
+		RolloutKey(previous) == RolloutKey(*target) {
+		target.Pending = nil
+
+		return false
+	}
+
+	now := r.timeProvider.Now().Time
+	if maintenance.IsOpen(dk.Spec.MaintenanceWindows, now) {
+		target.Pending = nil
+
+		return false
+	}
+
+	pending := maintenance.PendingVersion(dk.Spec.MaintenanceWindows, now, target.ImageID, target.Version)
+
+	log.Info("new version found outside of maintenance windows, keeping it pending", "updater", updater.Name(), "version", RolloutKey(*target), "nextMaintenanceWindow", pending.NextMaintenanceWindow)
+
+	probeTimestamp := target.LastProbeTimestamp
+	*target = previous
+	target.LastProbeTimestamp = probeTimestamp
+	target.Pending = pending
+
+	return true
+}
@@ -0,0 +1,115 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeferUpdate(t *testing.T) {
+	previous := status.VersionStatus{ImageID: "repo@sha256:old", Version: "1.0.0", Source: status.TenantRegistryVersionSource}
+	latest := status.VersionStatus{ImageID: "repo@sha256:new", Version: "2.0.0", Source: status.TenantRegistryVersionSource}
+	nightly := maintenance.Window{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+
+	createDynaKube := func(windows ...maintenance.Window) *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{}
+		dk.Spec.OneAgent.CloudNativeFullStack = &oneagent.CloudNativeFullStackSpec{}
+		dk.Spec.MaintenanceWindows = windows
+		dk.Status.OneAgent.VersionStatus = latest
+
+		return dk
+	}
+
+	createReconciler := func(now time.Time) reconciler {
+		timeProvider := timeprovider.New().Freeze()
+		timeProvider.Set(now)
+
+		return reconciler{timeProvider: timeProvider}
+	}
+
+	t.Run("no maintenance windows => update applied", func(t *testing.T) {
+		dk := createDynaKube()
+		r := createReconciler(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+		assert.False(t, r.deferUpdate(newOneAgentUpdater(dk, nil, nil), dk, previous))
+		assert.Equal(t, latest, dk.Status.OneAgent.VersionStatus)
+	})
+
+	t.Run("inside maintenance window => update applied, pending removed", func(t *testing.T) {
+		dk := createDynaKube(nightly)
+		dk.Status.OneAgent.Pending = &status.PendingVersionStatus{Version: latest.Version}
+		r := createReconciler(time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC))
+
+		assert.False(t, r.deferUpdate(newOneAgentUpdater(dk, nil, nil), dk, previous))
+		assert.Equal(t, latest.ImageID, dk.Status.OneAgent.ImageID)
+		assert.Nil(t, dk.Status.OneAgent.Pending)
+	})
+
+	t.Run("outside maintenance window => previous version kept, new version pending", func(t *testing.T) {
+		dk := createDynaKube(nightly)
+		dk.Status.OneAgent.LastProbeTimestamp = &metav1.Time{Time: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+		r := createReconciler(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+		assert.True(t, r.deferUpdate(newOneAgentUpdater(dk, nil, nil), dk, previous))
+		assert.Equal(t, previous.ImageID, dk.Status.OneAgent.ImageID)
+		assert.Equal(t, previous.Version, dk.Status.OneAgent.Version)
+		assert.NotNil(t, dk.Status.OneAgent.LastProbeTimestamp)
+		require.NotNil(t, dk.Status.OneAgent.Pending)
+		assert.Equal(t, latest.ImageID, dk.Status.OneAgent.Pending.ImageID)
+		assert.Equal(t, latest.Version, dk.Status.OneAgent.Pending.Version)
+		require.NotNil(t, dk.Status.OneAgent.Pending.NextMaintenanceWindow)
+		assert.True(t, time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC).Equal(dk.Status.OneAgent.Pending.NextMaintenanceWindow.Time))
+	})
+
+	t.Run("outside maintenance window, but source changed => update applied", func(t *testing.T) {
+		dk := createDynaKube(nightly)
+		dk.Status.OneAgent.Source = status.PublicRegistryVersionSource
+		r := createReconciler(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+		assert.False(t, r.deferUpdate(newOneAgentUpdater(dk, nil, nil), dk, previous))
+		assert.Equal(t, latest.ImageID, dk.Status.OneAgent.ImageID)
+	})
+
+	t.Run("outside maintenance window, but custom version => update applied", func(t *testing.T) {
+		dk := createDynaKube(nightly)
+		dk.Status.OneAgent.Source = status.CustomVersionVersionSource
+		customPrevious := previous
+		customPrevious.Source = status.CustomVersionVersionSource
+		r := createReconciler(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+		assert.False(t, r.deferUpdate(newOneAgentUpdater(dk, nil, nil), dk, customPrevious))
+		assert.Equal(t, latest.ImageID, dk.Status.OneAgent.ImageID)
+	})
+}
+
+func TestNeedsUpdateWithPendingVersion(t *testing.T) {
+	nightly := maintenance.Window{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+	probe := time.Date(2025, 1, 1, 21, 55, 0, 0, time.UTC)
+
+	dk := &dynakube.DynaKube{}
+	dk.Spec.OneAgent.CloudNativeFullStack = &oneagent.CloudNativeFullStackSpec{}
+	dk.Spec.MaintenanceWindows = []maintenance.Window{nightly}
+	dk.Status.OneAgent.VersionStatus = status.VersionStatus{
+		ImageID:            "repo@sha256:old",
+		Source:             status.TenantRegistryVersionSource,
+		LastProbeTimestamp: &metav1.Time{Time: probe},
+		Pending:            &status.PendingVersionStatus{ImageID: "repo@sha256:new"},
+	}
+
+	timeProvider := timeprovider.New().Freeze()
+	r := reconciler{timeProvider: timeProvider}
+
+	timeProvider.Set(probe.Add(time.Minute))
+	assert.False(t, r.needsUpdate(newOneAgentUpdater(dk, nil, nil), dk))
+
+	timeProvider.Set(probe.Add(6 * time.Minute))
+	assert.True(t, r.needsUpdate(newOneAgentUpdater(dk, nil, nil), dk))
+}
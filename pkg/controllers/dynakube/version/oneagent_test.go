@@ -216,7 +216,7 @@ func TestOneAgentUseDefault(t *testing.T) {
 		assert.Equal(t, previousVersion, dk.Status.OneAgent.Version)
 
 		condition := meta.FindStatusCondition(*dk.Conditions(), oaConditionType)
-		assert.Equal(t, downgradeReason, condition.Reason)
+		assert.Equal(t, DowngradeReason, condition.Reason)
 		assert.Equal(t, metav1.ConditionFalse, condition.Status)
 	})
 
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
@@ -66,6 +67,8 @@ func (r *reconciler) ReconcileActiveGate(ctx context.Context, dk *dynakube.DynaK
 func (r *reconciler) updateVersionStatuses(ctx context.Context, updater StatusUpdater, dk *dynakube.DynaKube) error {
 	log.Info("updating version status", "updater", updater.Name())
 
+	previous := *updater.Target()
+
 	err := r.run(ctx, updater)
 	if err != nil {
 		if updater.Target().ImageID == "" && updater.Target().Version == "" {
@@ -75,6 +78,8 @@ func (r *reconciler) updateVersionStatuses(ctx context.Context, updater StatusUp
 		}
 
 		log.Error(err, "unable to refresh version info, moving on with version from previous run", "component", updater.Name())
+	} else if !r.deferUpdate(updater, dk, previous) {
+		r.startRollout(updater, dk, previous)
 	}
 
 	_, ok := updater.(*oneAgentUpdater)
@@ -107,6 +112,12 @@ func (r *reconciler) needsUpdate(updater StatusUpdater, dk *dynakube.DynaKube) b
 		return true
 	}
 
+	if updater.Target().Pending != nil && maintenance.IsOpen(dk.Spec.MaintenanceWindows, r.timeProvider.Now().Time) {
+		log.Info("maintenance window started, update for pending version is needed", "updater", updater.Name())
+
+		return true
+	}
+
 	if !r.timeProvider.IsOutdated(updater.Target().LastProbeTimestamp, dk.APIRequestThreshold()) {
 		log.Info("status timestamp still valid, skipping version status updater", "updater", updater.Name())
 
@@ -0,0 +1,80 @@
+package version
+
+import (
+	"slices"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+)
+
+// RolloutKey identifies a version in the rollout status, the image is used if present as custom images share the same version.
+func RolloutKey(versionStatus status.VersionStatus) string {
+	if versionStatus.ImageID != "" {
+		return versionStatus.ImageID
+	}
+
+	return versionStatus.Version
+}
+
+// startRollout checks if the updater found a new version and, if a rollout is configured, starts its canary phase.
+// A version that was rolled back before is not rolled out again, the previous version is kept instead.
+// Code modules only have a canary phase with the CSI driver, as only its volumes are provided per node.
+func (r *reconciler) startRollout(updater StatusUpdater, dk *dynakube.DynaKube, previous status.VersionStatus) {
+	_, isOneAgent := updater.(*oneAgentUpdater)
+	_, isCodeModules := updater.(*codeModulesUpdater)
+	target := updater.Target()
+
+	if !(isOneAgent || isCodeModules) ||
+		!dk.OneAgent().IsRolloutEnabled() ||
+		previous.IsZero() ||
+		RolloutKey(previous) == RolloutKey(*target) ||
+		target.Source == status.CustomImageVersionSource ||
+		target.Source == status.CustomVersionVersionSource {
+		return
+	}
+
+	rollout := dk.Status.OneAgent.Rollout
+	if rollout != nil && slices.Contains(rollout.FailedVersions, RolloutKey(*target)) {
+		log.Info("version was rolled back before, keeping the previous version", "updater", updater.Name(), "version", RolloutKey(*target))
+
+		probeTimestamp := target.LastProbeTimestamp
+		*target = previous
+		target.LastProbeTimestamp = probeTimestamp
+
+		return
+	}
+
+	// without the CSI driver the node of an injected pod isn't known during the injection, so every pod gets the stable code modules
+	// and the canary would be promoted without ever being used
+	if isCodeModules && !dk.OneAgent().IsCSIAvailable() {
+		log.Info("CSI driver not available, rolling out the new code modules without canary phase", "version", RolloutKey(*target))
+
+		return
+	}
+
+	if rollout == nil {
+		rollout = &oneagent.RolloutStatus{}
+		dk.Status.OneAgent.Rollout = rollout
+	}
+
+	log.Info("new version found, starting canary rollout", "updater", updater.Name(), "from", RolloutKey(previous), "to", RolloutKey(*target))
+
+	if rollout.Phase != oneagent.RolloutPhaseCanary {
+		rollout.Phase = oneagent.RolloutPhaseCanary
+		rollout.CanaryNodes = nil
+		rollout.PreviousVersion = nil
+		rollout.PreviousCodeModules = nil
+	}
+
+	// a newer version during the canary phase restarts the soak time, but the version to roll back to stays the same
+	rollout.StartedAt = r.timeProvider.Now()
+
+	if isOneAgent && rollout.PreviousVersion == nil {
+		rollout.PreviousVersion = previous.DeepCopy()
+	}
+
+	if isCodeModules && rollout.PreviousCodeModules == nil {
+		rollout.PreviousCodeModules = previous.DeepCopy()
+	}
+}
@@ -0,0 +1,130 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartRollout(t *testing.T) {
+	previous := status.VersionStatus{ImageID: "repo@sha256:old", Version: "1.0.0", Source: status.TenantRegistryVersionSource}
+	latest := status.VersionStatus{ImageID: "repo@sha256:new", Version: "2.0.0", Source: status.TenantRegistryVersionSource}
+
+	createDynaKube := func(rollout *oneagent.RolloutSpec) *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{}
+		dk.Spec.OneAgent.CloudNativeFullStack = &oneagent.CloudNativeFullStackSpec{}
+		dk.Spec.OneAgent.Rollout = rollout
+		dk.Status.OneAgent.VersionStatus = latest
+
+		return dk
+	}
+
+	t.Run("no rollout configured => no canary", func(t *testing.T) {
+		dk := createDynaKube(nil)
+		r := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		r.startRollout(newOneAgentUpdater(dk, nil, nil), dk, previous)
+
+		assert.Nil(t, dk.Status.OneAgent.Rollout)
+		assert.Equal(t, latest, dk.Status.OneAgent.VersionStatus)
+	})
+
+	t.Run("first version => no canary", func(t *testing.T) {
+		dk := createDynaKube(&oneagent.RolloutSpec{})
+		r := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		r.startRollout(newOneAgentUpdater(dk, nil, nil), dk, status.VersionStatus{})
+
+		assert.Nil(t, dk.Status.OneAgent.Rollout)
+	})
+
+	t.Run("new OneAgent version => canary with previous version", func(t *testing.T) {
+		dk := createDynaKube(&oneagent.RolloutSpec{})
+		timeProvider := timeprovider.New().Freeze()
+		r := reconciler{timeProvider: timeProvider}
+
+		r.startRollout(newOneAgentUpdater(dk, nil, nil), dk, previous)
+
+		require.NotNil(t, dk.Status.OneAgent.Rollout)
+		assert.Equal(t, oneagent.RolloutPhaseCanary, dk.Status.OneAgent.Rollout.Phase)
+		assert.Equal(t, timeProvider.Now(), dk.Status.OneAgent.Rollout.StartedAt)
+		assert.Equal(t, &previous, dk.Status.OneAgent.Rollout.PreviousVersion)
+		assert.Nil(t, dk.Status.OneAgent.Rollout.PreviousCodeModules)
+		assert.Equal(t, latest, dk.Status.OneAgent.VersionStatus)
+	})
+
+	t.Run("new code modules version => canary with previous code modules", func(t *testing.T) {
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: true})
+
+		dk := createDynaKube(&oneagent.RolloutSpec{})
+		dk.Status.CodeModules.VersionStatus = latest
+		r := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		r.startRollout(newCodeModulesUpdater(dk, nil), dk, previous)
+
+		require.NotNil(t, dk.Status.OneAgent.Rollout)
+		assert.Equal(t, oneagent.RolloutPhaseCanary, dk.Status.OneAgent.Rollout.Phase)
+		assert.Nil(t, dk.Status.OneAgent.Rollout.PreviousVersion)
+		assert.Equal(t, &previous, dk.Status.OneAgent.Rollout.PreviousCodeModules)
+	})
+
+	t.Run("new code modules version without CSI driver => no canary", func(t *testing.T) {
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: false})
+
+		dk := createDynaKube(&oneagent.RolloutSpec{})
+		dk.Status.CodeModules.VersionStatus = latest
+		r := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		r.startRollout(newCodeModulesUpdater(dk, nil), dk, previous)
+
+		assert.Nil(t, dk.Status.OneAgent.Rollout)
+		assert.Equal(t, latest, dk.Status.CodeModules.VersionStatus)
+	})
+
+	t.Run("newer version during canary => keep version to roll back to", func(t *testing.T) {
+		dk := createDynaKube(&oneagent.RolloutSpec{})
+		dk.Status.OneAgent.Rollout = &oneagent.RolloutStatus{
+			Phase:           oneagent.RolloutPhaseCanary,
+			CanaryNodes:     []string{"node-1"},
+			PreviousVersion: previous.DeepCopy(),
+		}
+		r := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		r.startRollout(newOneAgentUpdater(dk, nil, nil), dk, status.VersionStatus{ImageID: "repo@sha256:between"})
+
+		assert.Equal(t, &previous, dk.Status.OneAgent.Rollout.PreviousVersion)
+		assert.Equal(t, []string{"node-1"}, dk.Status.OneAgent.Rollout.CanaryNodes)
+	})
+
+	t.Run("failed version => previous version is kept", func(t *testing.T) {
+		dk := createDynaKube(&oneagent.RolloutSpec{})
+		dk.Status.OneAgent.LastProbeTimestamp = timeprovider.New().Freeze().Now()
+		dk.Status.OneAgent.Rollout = &oneagent.RolloutStatus{
+			Phase:          oneagent.RolloutPhaseRolledBack,
+			FailedVersions: []string{latest.ImageID},
+		}
+		r := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		r.startRollout(newOneAgentUpdater(dk, nil, nil), dk, previous)
+
+		assert.Equal(t, oneagent.RolloutPhaseRolledBack, dk.Status.OneAgent.Rollout.Phase)
+		assert.Equal(t, previous.ImageID, dk.Status.OneAgent.ImageID)
+		assert.NotNil(t, dk.Status.OneAgent.LastProbeTimestamp)
+	})
+
+	t.Run("custom image => no canary", func(t *testing.T) {
+		dk := createDynaKube(&oneagent.RolloutSpec{})
+		dk.Status.OneAgent.Source = status.CustomImageVersionSource
+		r := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		r.startRollout(newOneAgentUpdater(dk, nil, nil), dk, previous)
+
+		assert.Nil(t, dk.Status.OneAgent.Rollout)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha2/edgeconnect"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
@@ -47,6 +48,10 @@ func (u updater) RequiresReconcile() bool {
 		return true
 	}
 
+	if version.Pending != nil && u.IsAutoUpdateEnabled() && maintenance.IsOpen(u.edgeConnect.Spec.MaintenanceWindows, u.timeProvider.Now().Time) {
+		return true
+	}
+
 	return isRequestOutdated && u.IsAutoUpdateEnabled()
 }
 
@@ -75,10 +80,16 @@ func (u updater) Update(ctx context.Context) error {
 			return err
 		}
 
+		if u.deferUpdate(image) {
+			return nil
+		}
+
 		target.Source = status.PublicRegistryVersionSource
 	} else {
 		log.Debug("EdgeConnect custom image used")
 
+		target.Pending = nil
+
 		target.Source = status.CustomImageVersionSource
 	}
 
@@ -87,6 +98,30 @@ func (u updater) Update(ctx context.Context) error {
 	return nil
 }
 
+// deferUpdate keeps the current image if a new image was found outside of the maintenance windows, the new image is stored as pending.
+func (u updater) deferUpdate(image string) bool {
+	target := u.Target()
+
+	if target.ImageID == "" || target.ImageID == image || target.Source != status.PublicRegistryVersionSource {
+		target.Pending = nil
+
+		return false
+	}
+
+	now := u.timeProvider.Now().Time
+	if maintenance.IsOpen(u.edgeConnect.Spec.MaintenanceWindows, now) {
+		target.Pending = nil
+
+		return false
+	}
+
+	target.Pending = maintenance.PendingVersion(u.edgeConnect.Spec.MaintenanceWindows, now, image, "")
+
+	log.Info("new EdgeConnect image found outside of maintenance windows, keeping it pending", "image", image, "nextMaintenanceWindow", target.Pending.NextMaintenanceWindow)
+
+	return true
+}
+
 func (u updater) combineImageWithDigest(digest digest.Digest) (string, error) {
 	imageRef, err := name.ParseReference(u.edgeConnect.Image())
 	if err != nil {
@@ -8,6 +8,8 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/image"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha2/edgeconnect"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
@@ -81,6 +83,36 @@ func TestUpdate(t *testing.T) {
 		require.Equal(t, customRegistry+":latest", edgeConnect.Status.Version.ImageID)
 		require.NotNil(t, edgeConnect.Status.Version.LastProbeTimestamp)
 	})
+
+	t.Run("new digest outside of maintenance window => kept pending", func(t *testing.T) {
+		oldImageID := "docker.io/dynatrace/edgeconnect:latest@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+		edgeConnect := createBasicEdgeConnect()
+		edgeConnect.Spec.MaintenanceWindows = []maintenance.Window{{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}}}
+		edgeConnect.Status.Version.ImageID = oldImageID
+		edgeConnect.Status.Version.Source = status.PublicRegistryVersionSource
+		fakeRegistryClient := registrymock.NewImageGetter(t)
+		fakeImageVersion := registry.ImageVersion{Digest: fakeDigest}
+		fakeRegistryClient.On("GetImageVersion", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fakeImageVersion, nil)
+
+		timeProvider := timeprovider.New().Freeze()
+		timeProvider.Set(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+		err := newUpdater(fake.NewClient(), timeProvider, fakeRegistryClient, edgeConnect).Update(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, oldImageID, edgeConnect.Status.Version.ImageID)
+		require.NotNil(t, edgeConnect.Status.Version.Pending)
+		assert.Equal(t, "docker.io/dynatrace/edgeconnect:latest@"+fakeDigest, edgeConnect.Status.Version.Pending.ImageID)
+
+		timeProvider.Set(time.Date(2025, 1, 1, 22, 30, 0, 0, time.UTC))
+		assert.True(t, newUpdater(fake.NewClient(), timeProvider, fakeRegistryClient, edgeConnect).RequiresReconcile())
+
+		err = newUpdater(fake.NewClient(), timeProvider, fakeRegistryClient, edgeConnect).Update(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, "docker.io/dynatrace/edgeconnect:latest@"+fakeDigest, edgeConnect.Status.Version.ImageID)
+		assert.Nil(t, edgeConnect.Status.Version.Pending)
+	})
 }
 
 func TestCombineImagesWithDigest(t *testing.T) {
@@ -0,0 +1,105 @@
+package pin
+
+import (
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/version"
+	"github.com/google/go-containerregistry/pkg/name"
+	registryv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Annotation requests a specific code module for the pod, either a version (1.300.0.20240101-000000) or an image digest (sha256:...).
+	Annotation = "oneagent.dynatrace.com/codemodule-version"
+
+	PublicCodeModulesRepository = "public.ecr.aws/dynatrace/dynatrace-codemodules"
+
+	digestPrefix = "sha256:"
+)
+
+// CodeModule describes where a pinned code module comes from.
+// If ImageURI is set the code module is pulled from that image, otherwise Version is downloaded from the tenant.
+type CodeModule struct {
+	Pin      string
+	Version  string
+	ImageURI string
+}
+
+// IsDigest returns true if the pin refers to an image digest instead of a version.
+func IsDigest(pin string) bool {
+	return strings.HasPrefix(pin, digestPrefix)
+}
+
+// Validate checks the format of the pin.
+// The pin ends up as a directory name on the node, so it has to be validated before it is used in any path.
+func Validate(pin string) error {
+	if IsDigest(pin) {
+		if _, err := registryv1.NewHash(pin); err != nil {
+			return errors.Errorf("invalid image digest %q", pin)
+		}
+
+		return nil
+	}
+
+	if _, err := version.ExtractSemanticVersion(pin); err != nil {
+		return errors.Errorf("invalid code module version %q, expected a version like 1.300.0.20240101-000000 or an image digest like sha256:<hex>", pin)
+	}
+
+	return nil
+}
+
+// Resolve validates the pin against the DynaKube and returns where the pinned code module comes from.
+// Digests can only be used if the code modules are pulled from an image, either a custom codeModulesImage or via node image pull.
+func Resolve(dk *dynakube.DynaKube, pin string) (CodeModule, error) {
+	if err := Validate(pin); err != nil {
+		return CodeModule{}, err
+	}
+
+	codeModule := CodeModule{Pin: pin}
+
+	baseImage := dk.OneAgent().GetCustomCodeModulesImage()
+	if baseImage == "" && dk.FF().IsNodeImagePull() {
+		baseImage = PublicCodeModulesRepository
+	}
+
+	if baseImage == "" {
+		if IsDigest(pin) {
+			return CodeModule{}, errors.Errorf("image digest %q can only be used if the DynaKube pulls the code modules from an image", pin)
+		}
+
+		codeModule.Version = pin
+
+		return codeModule, nil
+	}
+
+	imageURI, err := ImageURI(baseImage, pin)
+	if err != nil {
+		return CodeModule{}, err
+	}
+
+	codeModule.ImageURI = imageURI
+
+	if !IsDigest(pin) {
+		codeModule.Version = pin
+	}
+
+	return codeModule, nil
+}
+
+// ImageURI replaces the tag or digest of the base image with the pin.
+func ImageURI(baseImage, pin string) (string, error) {
+	ref, err := name.ParseReference(baseImage)
+	if err != nil {
+		return "", errors.WithMessagef(err, "failed to parse code modules image %s", baseImage)
+	}
+
+	repo := ref.Context()
+
+	if IsDigest(pin) {
+		return repo.Digest(pin).String(), nil
+	}
+
+	return repo.Tag(pin).String(), nil
+}
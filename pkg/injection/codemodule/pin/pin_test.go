@@ -0,0 +1,90 @@
+package pin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testVersion = "1.300.0.20240101-000000"
+	testImage   = "my.registry.com/dynatrace/codemodules:1.299.0"
+)
+
+var testDigest = "sha256:" + strings.Repeat("a", 64)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(testVersion))
+	assert.NoError(t, Validate(testDigest))
+
+	assert.Error(t, Validate(""))
+	assert.Error(t, Validate("latest"))
+	assert.Error(t, Validate("../../etc"))
+	assert.Error(t, Validate("sha256:abc"))
+	assert.Error(t, Validate(testVersion+"/.."))
+}
+
+func TestResolve(t *testing.T) {
+	t.Run("version is downloaded from the tenant", func(t *testing.T) {
+		codeModule, err := Resolve(createDynaKube("", false), testVersion)
+		require.NoError(t, err)
+
+		assert.Equal(t, CodeModule{Pin: testVersion, Version: testVersion}, codeModule)
+	})
+	t.Run("digest without image is rejected", func(t *testing.T) {
+		_, err := Resolve(createDynaKube("", false), testDigest)
+		require.Error(t, err)
+	})
+	t.Run("version replaces the tag of the custom image", func(t *testing.T) {
+		codeModule, err := Resolve(createDynaKube(testImage, false), testVersion)
+		require.NoError(t, err)
+
+		assert.Equal(t, "my.registry.com/dynatrace/codemodules:"+testVersion, codeModule.ImageURI)
+		assert.Equal(t, testVersion, codeModule.Version)
+	})
+	t.Run("digest replaces the tag of the custom image", func(t *testing.T) {
+		codeModule, err := Resolve(createDynaKube(testImage, false), testDigest)
+		require.NoError(t, err)
+
+		assert.Equal(t, "my.registry.com/dynatrace/codemodules@"+testDigest, codeModule.ImageURI)
+		assert.Empty(t, codeModule.Version)
+	})
+	t.Run("node image pull uses the public image", func(t *testing.T) {
+		codeModule, err := Resolve(createDynaKube("", true), testDigest)
+		require.NoError(t, err)
+
+		assert.Equal(t, PublicCodeModulesRepository+"@"+testDigest, codeModule.ImageURI)
+	})
+	t.Run("malformed pin is rejected", func(t *testing.T) {
+		_, err := Resolve(createDynaKube(testImage, false), "latest")
+		require.Error(t, err)
+	})
+}
+
+func createDynaKube(codeModulesImage string, nodeImagePull bool) *dynakube.DynaKube {
+	dk := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "dynakube",
+			Annotations: map[string]string{},
+		},
+		Spec: dynakube.DynaKubeSpec{
+			OneAgent: oneagent.Spec{
+				ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{
+					AppInjectionSpec: oneagent.AppInjectionSpec{CodeModulesImage: codeModulesImage},
+				},
+			},
+		},
+	}
+
+	if nodeImagePull {
+		dk.Annotations[exp.OANodeImagePullKey] = "true"
+	}
+
+	return dk
+}
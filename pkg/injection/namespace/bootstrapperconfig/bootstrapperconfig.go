@@ -10,6 +10,7 @@ import (
 	"github.com/Dynatrace/dynatrace-bootstrapper/pkg/configure/oneagent/curl"
 	"github.com/Dynatrace/dynatrace-bootstrapper/pkg/configure/oneagent/pmc"
 	"github.com/Dynatrace/dynatrace-operator/cmd/bootstrapper/download"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
@@ -95,7 +96,7 @@ func (s *SecretGenerator) createSecretForNSlist( //nolint:revive // argument-lim
 ) error {
 	coreLabels := k8slabels.NewCoreLabels(dk.Name, k8slabels.WebhookComponentLabel)
 
-	secret, err := k8ssecret.BuildForNamespace(secretName, "", data, k8ssecret.SetLabels(coreLabels.BuildLabels()))
+	secret, err := k8ssecret.BuildForNamespace(secretName, "", data, k8ssecret.SetLabels(coreLabels.BuildLabels()), k8ssecret.SetAnnotations(api.TokensHashAnnotation(dk.Status.TokensHash, conditionType == ConfigConditionType)))
 	if err != nil {
 		conditions.SetSecretGenFailed(dk.Conditions(), conditionType, err)
 
@@ -238,23 +239,27 @@ func (s *SecretGenerator) generateCerts(ctx context.Context, dk *dynakube.DynaKu
 
 func (s *SecretGenerator) prepareDownloadConfig(ctx context.Context, dk *dynakube.DynaKube) ([]byte, error) {
 	var tokens corev1.Secret
-	if err := s.client.Get(ctx, client.ObjectKey{Name: dk.Tokens(), Namespace: dk.Namespace}, &tokens); err != nil {
+	if err := s.apiReader.Get(ctx, client.ObjectKey{Name: dk.Tokens(), Namespace: dk.Namespace}, &tokens); err != nil {
 		conditions.SetKubeAPIError(dk.Conditions(), ConfigConditionType, err)
 
 		return nil, errors.WithMessage(err, "failed to query tokens")
 	}
 
 	downloadConfigJSON := download.Config{
-		URL:           dk.Spec.APIURL,
-		APIToken:      string(tokens.Data[dtclient.APIToken]),
-		NoProxy:       dk.FF().GetNoProxy(),
-		NetworkZone:   dk.Spec.NetworkZone,
-		HostGroup:     dk.OneAgent().GetHostGroup(),
-		SkipCertCheck: dk.Spec.SkipCertCheck,
+		URL:               dk.Spec.APIURL,
+		APIToken:          string(tokens.Data[dtclient.APIToken]),
+		PlatformToken:     string(tokens.Data[dtclient.PlatformToken]),
+		OAuthClientID:     string(tokens.Data[dtclient.OAuthClientID]),
+		OAuthClientSecret: string(tokens.Data[dtclient.OAuthClientSecret]),
+		OAuthTokenURL:     string(tokens.Data[dtclient.OAuthTokenURL]),
+		NoProxy:           dk.FF().GetNoProxy(),
+		NetworkZone:       dk.Spec.NetworkZone,
+		HostGroup:         dk.OneAgent().GetHostGroup(),
+		SkipCertCheck:     dk.Spec.SkipCertCheck,
 	}
 
 	if dk.NeedsOneAgentProxy() {
-		proxy, err := dk.Proxy(ctx, s.apiReader)
+		proxy, err := dk.ComponentProxy(ctx, s.apiReader, dynakube.OneAgentProxyComponent)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
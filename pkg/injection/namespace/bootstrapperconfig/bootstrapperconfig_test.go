@@ -2,12 +2,15 @@ package bootstrapperconfig
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/Dynatrace/dynatrace-bootstrapper/pkg/configure/enrichment/endpoint"
 	"github.com/Dynatrace/dynatrace-bootstrapper/pkg/configure/oneagent/ca"
 	"github.com/Dynatrace/dynatrace-bootstrapper/pkg/configure/oneagent/curl"
 	"github.com/Dynatrace/dynatrace-bootstrapper/pkg/configure/oneagent/pmc"
+	"github.com/Dynatrace/dynatrace-operator/cmd/bootstrapper/download"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/activegate"
@@ -118,6 +121,104 @@ func TestGenerateForDynakube(t *testing.T) {
 		require.NotNil(t, c)
 		assert.Equal(t, metav1.ConditionTrue, c.Status)
 	})
+	t.Run("rotated tokens => rolled out to the config secrets of all namespaces", func(t *testing.T) {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testDynakube,
+				Namespace: testNamespaceDynatrace,
+			},
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testAPIurl,
+				OneAgent: oneagent.Spec{
+					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{},
+				},
+			},
+		}
+		dk.Status.TokensHash = "old-hash"
+
+		namespace := clientInjectedNamespace(testNamespace, testDynakube)
+		tokens := clientSecret(testDynakube, testNamespaceDynatrace, map[string][]byte{
+			dtclient.APIToken:  []byte(testAPIToken),
+			dtclient.PaasToken: []byte(testPaasToken),
+		})
+
+		clt := fake.NewClientWithIndex(
+			dk,
+			namespace,
+			tokens,
+			clientSecret(dk.OneAgent().GetTenantSecret(), testNamespaceDynatrace, map[string][]byte{
+				"tenant-token": []byte(testTenantToken),
+			}),
+		)
+
+		mockDTClient := dtclientmock.NewClient(t)
+		mockDTClient.On("GetProcessModuleConfig", mock.AnythingOfType("context.backgroundCtx"), mock.AnythingOfType("uint")).Return(&dtclient.ProcessModuleConfig{}, nil)
+
+		secretGenerator := NewSecretGenerator(clt, clt, mockDTClient)
+		require.NoError(t, secretGenerator.GenerateForDynakube(context.Background(), dk, []corev1.Namespace{*namespace}))
+
+		tokens.Data[dtclient.APIToken] = []byte("rotated-api-token")
+		require.NoError(t, clt.Update(context.Background(), tokens))
+		dk.Status.TokensHash = "new-hash"
+
+		require.NoError(t, secretGenerator.GenerateForDynakube(context.Background(), dk, []corev1.Namespace{*namespace}))
+
+		for _, key := range []client.ObjectKey{
+			{Name: consts.BootstrapperInitSecretName, Namespace: testNamespace},
+			{Name: GetSourceConfigSecretName(dk.Name), Namespace: dk.Namespace},
+		} {
+			var secret corev1.Secret
+			require.NoError(t, clt.Get(context.Background(), key, &secret))
+
+			assert.Contains(t, string(secret.Data[download.InputFileName]), "rotated-api-token", key.Name)
+			assert.Equal(t, "new-hash", secret.Annotations[api.AnnotationTokensHash], key.Name)
+		}
+	})
+	t.Run("oauth client => passed to the download config", func(t *testing.T) {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testDynakube,
+				Namespace: testNamespaceDynatrace,
+			},
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testAPIurl,
+				OneAgent: oneagent.Spec{
+					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{},
+				},
+			},
+		}
+
+		namespace := clientInjectedNamespace(testNamespace, testDynakube)
+
+		clt := fake.NewClientWithIndex(
+			dk,
+			namespace,
+			clientSecret(testDynakube, testNamespaceDynatrace, map[string][]byte{
+				dtclient.OAuthClientID:     []byte("client-id"),
+				dtclient.OAuthClientSecret: []byte("client-secret"),
+				dtclient.OAuthTokenURL:     []byte("https://sso.example.com/token"),
+			}),
+			clientSecret(dk.OneAgent().GetTenantSecret(), testNamespaceDynatrace, map[string][]byte{
+				"tenant-token": []byte(testTenantToken),
+			}),
+		)
+
+		mockDTClient := dtclientmock.NewClient(t)
+		mockDTClient.On("GetProcessModuleConfig", mock.AnythingOfType("context.backgroundCtx"), mock.AnythingOfType("uint")).Return(&dtclient.ProcessModuleConfig{}, nil)
+
+		secretGenerator := NewSecretGenerator(clt, clt, mockDTClient)
+		require.NoError(t, secretGenerator.GenerateForDynakube(context.Background(), dk, []corev1.Namespace{*namespace}))
+
+		var secret corev1.Secret
+		require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: consts.BootstrapperInitSecretName, Namespace: testNamespace}, &secret))
+
+		var config download.Config
+		require.NoError(t, json.Unmarshal(secret.Data[download.InputFileName], &config))
+		assert.Empty(t, config.APIToken)
+		assert.Equal(t, "client-id", config.OAuthClientID)
+		assert.Equal(t, "client-secret", config.OAuthClientSecret)
+		assert.Equal(t, "https://sso.example.com/token", config.OAuthTokenURL)
+	})
 	t.Run("successfully generate secret with fields for dynakube", func(t *testing.T) {
 		dk := &dynakube.DynaKube{
 			ObjectMeta: metav1.ObjectMeta{
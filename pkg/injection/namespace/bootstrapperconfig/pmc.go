@@ -70,7 +70,7 @@ func (s *SecretGenerator) preparePMC(ctx context.Context, dk *dynakube.DynaKube)
 	if dk.NeedsOneAgentProxy() {
 		log.Debug("proxy is needed")
 
-		proxy, err := dk.Proxy(ctx, s.apiReader)
+		proxy, err := dk.ComponentProxy(ctx, s.apiReader, dynakube.OneAgentProxyComponent)
 		if err != nil {
 			conditions.SetKubeAPIError(dk.Conditions(), ConfigConditionType, err)
 
@@ -19,6 +19,7 @@ const (
 var (
 	baseLogger     Logger
 	baseLoggerOnce sync.Once
+	baseLogWriter  = &prettyLogWriter{out: os.Stdout}
 )
 
 // Get returns a new, unnamed logd configured with the basics we need for operator logs which can be used as a blueprint for
@@ -26,12 +27,17 @@ var (
 func Get() Logger {
 	baseLoggerOnce.Do(func() {
 		logLevel := readLogLevelFromEnv()
-		baseLogger = createLogger(NewPrettyLogWriter(), logLevel)
+		baseLogger = createLogger(baseLogWriter, logLevel)
 	})
 
 	return baseLogger
 }
 
+// SetBaseLoggerOutput redirects the base logger and all loggers derived from it, e.g. to stderr to keep stdout free for machine-readable output.
+func SetBaseLoggerOutput(out io.Writer) {
+	baseLogWriter.setOut(out)
+}
+
 func LogBaseLoggerSettings() {
 	logLevel := readLogLevelFromEnv()
 	baseLogger.Info("logging level", "logLevel", logLevel.String())
@@ -2,6 +2,7 @@ package logd
 
 import (
 	"bytes"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,3 +63,14 @@ func TestLogger(t *testing.T) {
 		assert.NotContains(t, logBuffer.String(), "dpanic")
 	})
 }
+
+func TestSetBaseLoggerOutput(t *testing.T) {
+	var logBuffer bytes.Buffer
+
+	SetBaseLoggerOutput(&logBuffer)
+	t.Cleanup(func() { SetBaseLoggerOutput(os.Stdout) })
+
+	Get().WithName("derived").Info("redirected")
+
+	assert.Contains(t, logBuffer.String(), "redirected")
+}
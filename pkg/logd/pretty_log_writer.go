@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 )
@@ -31,9 +32,22 @@ func NewPrettyLogWriter(options ...func(prettifier *prettyLogWriter)) io.Writer
 
 type prettyLogWriter struct {
 	out io.Writer
+
+	// the output of the base logger can be changed while it's in use
+	mutex sync.RWMutex
+}
+
+func (pretty *prettyLogWriter) setOut(out io.Writer) {
+	pretty.mutex.Lock()
+	defer pretty.mutex.Unlock()
+
+	pretty.out = out
 }
 
 func (pretty *prettyLogWriter) Write(payload []byte) (int, error) {
+	pretty.mutex.RLock()
+	defer pretty.mutex.RUnlock()
+
 	if pretty.out == nil {
 		return 0, errors.New("no output set on prettyLogWriter")
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/dockerkeychain"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/pac"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	containerv1 "github.com/google/go-containerregistry/pkg/v1"
@@ -192,6 +193,27 @@ func addProxy(transport *http.Transport, proxy string, noProxy string) (*http.Tr
 	return transport, nil
 }
 
+func addPACProxy(ctx context.Context, apiReader client.Reader, transport *http.Transport, dk *dynakube.DynaKube) (*http.Transport, error) {
+	rawScript, err := dk.ProxyPAC(ctx, apiReader)
+	if err != nil {
+		return nil, err
+	}
+
+	script, err := pac.Parse(rawScript)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := dk.ProxyCredentials(ctx, apiReader, dynakube.OperatorProxyComponent)
+	if err != nil {
+		return nil, err
+	}
+
+	transport.Proxy = script.ProxyFunc(credentials)
+
+	return transport, nil
+}
+
 func proxyWrapper(proxyConfig httpproxy.Config) func(req *http.Request) (*url.URL, error) {
 	return func(req *http.Request) (*url.URL, error) {
 		return proxyConfig.ProxyFunc()(req.URL)
@@ -235,8 +257,13 @@ func PrepareTransportForDynaKube(ctx context.Context, apiReader client.Reader, t
 		err        error
 	)
 
-	if dk.HasProxy() {
-		proxy, err = dk.Proxy(ctx, apiReader)
+	if dk.ProxyPACConfigMap() != "" {
+		transport, err = addPACProxy(ctx, apiReader, transport, dk)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to add proxy auto-config to default transport")
+		}
+	} else if dk.HasComponentProxy(dynakube.OperatorProxyComponent) {
+		proxy, err = dk.ComponentProxy(ctx, apiReader, dynakube.OperatorProxyComponent)
 		if err != nil {
 			return nil, err
 		}
@@ -9,8 +9,35 @@ var (
 	otlphttp = component.MustNewID("otlphttp")
 )
 
+// ExporterConfig is based on "go.opentelemetry.io/collector/exporter/otlphttpexporter.Config"
+// with reduced number of attributes to reduce the number of dependencies.
+type ExporterConfig struct {
+	SendingQueue   *SendingQueueConfig `mapstructure:"sending_queue,omitempty"`
+	RetryOnFailure *RetryConfig        `mapstructure:"retry_on_failure,omitempty"`
+	ServerConfig   `mapstructure:",squash"`
+}
+
+// SendingQueueConfig is based on "go.opentelemetry.io/collector/exporter/exporterhelper.QueueBatchConfig"
+// with reduced number of attributes to reduce the number of dependencies.
+type SendingQueueConfig struct {
+	// StorageID is the ID of the storage extension, which persists the queue. The queue is kept in memory if not set.
+	StorageID *component.ID `mapstructure:"storage,omitempty"`
+	QueueSize int64         `mapstructure:"queue_size,omitempty"`
+	Enabled   bool          `mapstructure:"enabled"`
+}
+
+// RetryConfig is based on "go.opentelemetry.io/collector/config/configretry.BackOffConfig"
+// with reduced number of attributes to reduce the number of dependencies.
+type RetryConfig struct {
+	InitialInterval string `mapstructure:"initial_interval,omitempty"`
+	MaxInterval     string `mapstructure:"max_interval,omitempty"`
+	// MaxElapsedTime is the maximum time spent retrying a batch, 0s retries forever.
+	MaxElapsedTime string `mapstructure:"max_elapsed_time,omitempty"`
+	Enabled        bool   `mapstructure:"enabled"`
+}
+
 func (c *Config) buildExporters() map[component.ID]component.Config {
-	serverConfig := &ServerConfig{
+	serverConfig := ServerConfig{
 		Endpoint: c.buildExportersEndpoint(),
 	}
 
@@ -31,6 +58,10 @@ func (c *Config) buildExporters() map[component.ID]component.Config {
 	}
 
 	return map[component.ID]component.Config{
-		otlphttp: serverConfig,
+		otlphttp: &ExporterConfig{
+			ServerConfig:   serverConfig,
+			SendingQueue:   c.sendingQueue,
+			RetryOnFailure: c.retry,
+		},
 	}
 }
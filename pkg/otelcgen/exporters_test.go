@@ -27,3 +27,24 @@ func TestNewConfigWithExporters(t *testing.T) {
 
 	assert.YAMLEq(t, string(expectedOutput), string(c))
 }
+
+func TestNewConfigWithSendingQueue(t *testing.T) {
+	cfg, err := NewConfig(
+		"",
+		Protocols{OtlpProtocol},
+		WithExportersEndpoint("test"),
+		WithSendingQueue(5000, "/var/lib/otelcol/queue"),
+		WithRetry("5s", "30s", "0s"),
+		WithExporters(),
+		WithExtensions(),
+		WithServices(),
+	)
+	require.NoError(t, err)
+	c, err := cfg.Marshal()
+	require.NoError(t, err)
+
+	expectedOutput, err := os.ReadFile(filepath.Join("testdata", "exporters_sending_queue.yaml"))
+	require.NoError(t, err)
+
+	assert.YAMLEq(t, string(expectedOutput), string(c))
+}
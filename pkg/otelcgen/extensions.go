@@ -3,13 +3,31 @@ package otelcgen
 import "go.opentelemetry.io/collector/component"
 
 var (
-	healthCheck = component.MustNewID("health_check")
+	healthCheck      = component.MustNewID("health_check")
+	fileStorageQueue = component.MustNewIDWithName("file_storage", "queue")
 )
 
+// FileStorageConfig is based on
+// "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage.Config"
+// with reduced number of attributes to reduce the number of dependencies.
+type FileStorageConfig struct {
+	Directory       string `mapstructure:"directory"`
+	CreateDirectory bool   `mapstructure:"create_directory"`
+}
+
 func (c *Config) buildExtensions() map[component.ID]component.Config {
-	return map[component.ID]component.Config{
+	extensions := map[component.ID]component.Config{
 		healthCheck: &ServerConfig{
 			Endpoint: c.buildEndpoint(ExtensionsHealthCheckPort),
 		},
 	}
+
+	if c.fileStorageDirectory != "" {
+		extensions[fileStorageQueue] = &FileStorageConfig{
+			Directory:       c.fileStorageDirectory,
+			CreateDirectory: true,
+		}
+	}
+
+	return extensions
 }
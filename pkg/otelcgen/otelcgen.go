@@ -2,6 +2,7 @@ package otelcgen
 
 import (
 	"fmt"
+	"slices"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -51,6 +52,8 @@ const (
 	ZipkinProtocol Protocol = "zipkin"
 	OtlpProtocol   Protocol = "otlp"
 	StatsdProtocol Protocol = "statsd"
+
+	PrometheusProtocol Protocol = "prometheus"
 )
 
 var (
@@ -59,9 +62,20 @@ var (
 	StatsdID = component.MustNewID(string(StatsdProtocol))
 	ZipkinID = component.MustNewID(string(ZipkinProtocol))
 
+	PrometheusID = component.MustNewID(string(PrometheusProtocol))
+
+	// RegisteredProtocols are the protocols, which are enabled if none are configured explicitly.
 	RegisteredProtocols = Protocols{OtlpProtocol, JaegerProtocol, StatsdProtocol, ZipkinProtocol}
+
+	// OptInProtocols are only enabled if they are configured explicitly, as they actively collect data instead of only receiving it.
+	OptInProtocols = Protocols{PrometheusProtocol}
 )
 
+// IsSupportedProtocol checks if the protocol is one of the RegisteredProtocols or OptInProtocols.
+func IsSupportedProtocol(protocol Protocol) bool {
+	return slices.Contains(RegisteredProtocols, protocol) || slices.Contains(OptInProtocols, protocol)
+}
+
 type Config struct {
 	// Receivers is a map of ComponentID to Receivers.
 	Receivers map[component.ID]component.Config `mapstructure:"receivers"`
@@ -88,6 +102,17 @@ type Config struct {
 	Service   ServiceConfig `mapstructure:"service"`
 	protocols Protocols
 
+	pipelineExtensions pipelineExtensions
+
+	sendingQueue *SendingQueueConfig
+	retry        *RetryConfig
+
+	// fileStorageDirectory is the directory of the file_storage extension, which is only added if it is set
+	fileStorageDirectory string
+
+	// prometheusNamespaces restricts the namespaces of the pods scraped by the prometheus receiver, nil means all namespaces
+	prometheusNamespaces []string
+
 	includeSystemCACertsPool bool
 }
 
@@ -159,6 +184,8 @@ func (c *Config) protocolsToIDs() []component.ID {
 			ids = append(ids, StatsdID)
 		case OtlpProtocol:
 			ids = append(ids, OtlpID)
+		case PrometheusProtocol:
+			ids = append(ids, PrometheusID)
 		}
 	}
 
@@ -258,3 +285,63 @@ func WithExportersEndpoint(endpoint string) Option {
 		return nil
 	}
 }
+
+// WithSendingQueue enables the sending queue of the exporter, a queueSize of 0 keeps the collector default.
+// If storageDirectory is set, the queue is persisted there via the file_storage extension, so queued data survives restarts.
+func WithSendingQueue(queueSize int64, storageDirectory string) Option {
+	return func(c *Config) error {
+		c.sendingQueue = &SendingQueueConfig{
+			Enabled:   true,
+			QueueSize: queueSize,
+		}
+
+		if storageDirectory != "" {
+			c.fileStorageDirectory = storageDirectory
+			c.sendingQueue.StorageID = &fileStorageQueue
+		}
+
+		return nil
+	}
+}
+
+// WithRetry configures the backoff of the exporter for failed exports, empty intervals keep the collector defaults.
+func WithRetry(initialInterval, maxInterval, maxElapsedTime string) Option {
+	return func(c *Config) error {
+		c.retry = &RetryConfig{
+			Enabled:         true,
+			InitialInterval: initialInterval,
+			MaxInterval:     maxInterval,
+			MaxElapsedTime:  maxElapsedTime,
+		}
+
+		return nil
+	}
+}
+
+// WithPrometheusNamespaces restricts the prometheus receiver to the pods of the given namespaces.
+// If not passed, pods of all namespaces are scraped, an empty list disables scraping.
+func WithPrometheusNamespaces(namespaces []string) Option {
+	return func(c *Config) error {
+		c.prometheusNamespaces = namespaces
+		if c.prometheusNamespaces == nil {
+			c.prometheusNamespaces = []string{}
+		}
+
+		return nil
+	}
+}
+
+// WithPipelineExtensions merges the user supplied receivers and processors into the generated configuration.
+// It has to be passed before WithReceivers, WithProcessors and WithServices, as those read the extensions.
+func WithPipelineExtensions(extensions PipelineExtensions) Option {
+	return func(c *Config) error {
+		resolved, err := c.resolvePipelineExtensions(extensions)
+		if err != nil {
+			return err
+		}
+
+		c.pipelineExtensions = resolved
+
+		return nil
+	}
+}
@@ -0,0 +1,364 @@
+package otelcgen
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+	"gopkg.in/yaml.v3"
+)
+
+// allowedExtensionProcessorTypes are the processors which can be added to the generated pipelines.
+// Processors like batch or memory_limiter are left out, as they are already part of the generated pipelines and their position matters.
+var allowedExtensionProcessorTypes = []component.Type{
+	component.MustNewType("attributes"),
+	component.MustNewType("filter"),
+	component.MustNewType("probabilistic_sampler"),
+	component.MustNewType("redaction"),
+	component.MustNewType("resource"),
+	component.MustNewType("tail_sampling"),
+	component.MustNewType("transform"),
+}
+
+// allowedExtensionReceiverTypes are the receivers which can be added to the generated pipelines.
+// Receivers which need access to the host or additional permissions, like filelog, hostmetrics or k8s_cluster, are left out.
+var allowedExtensionReceiverTypes = []component.Type{
+	component.MustNewType("fluentforward"),
+	component.MustNewType("jaeger"),
+	component.MustNewType("otlp"),
+	component.MustNewType("prometheus"),
+	component.MustNewType("statsd"),
+	component.MustNewType("syslog"),
+	component.MustNewType("zipkin"),
+}
+
+// receiverEndpointKeys are the keys of the receiver configurations which contain the address a receiver listens on.
+var receiverEndpointKeys = []string{"endpoint", "listen_address"}
+
+// udpReceiverKeys are the keys of the receiver configurations below which a receiver listens on UDP instead of TCP.
+var udpReceiverKeys = []string{"udp", "thrift_binary", "thrift_compact"}
+
+// ReceiverPort is a port a receiver of the PipelineExtensions listens on, it has to be exposed by the Service of the collector.
+type ReceiverPort struct {
+	// Name is derived from the port and the protocol, so it is unique and a valid Service port name.
+	Name string
+	Port int32
+	UDP  bool
+}
+
+// PipelineExtensions are user supplied receivers and processors, which are merged into the generated configuration.
+// The components are only used by the pipelines that reference them.
+type PipelineExtensions struct {
+	// Receivers is a map of receiver IDs (e.g. prometheus/app) to their configuration.
+	Receivers map[string]any `yaml:"receivers,omitempty"`
+
+	// Processors is a map of processor IDs (e.g. attributes/drop-pii) to their configuration.
+	Processors map[string]any `yaml:"processors,omitempty"`
+
+	// Pipelines is a map of signals (traces, metrics or logs) to the additional components of their pipeline.
+	Pipelines map[string]PipelineExtension `yaml:"pipelines,omitempty"`
+}
+
+// PipelineExtension lists the additional components of a single pipeline, the order of the processors is kept.
+type PipelineExtension struct {
+	Receivers  []string `yaml:"receivers,omitempty"`
+	Processors []string `yaml:"processors,omitempty"`
+}
+
+type pipelineExtensions struct {
+	receivers  map[component.ID]component.Config
+	processors map[component.ID]component.Config
+	pipelines  map[pipeline.ID]pipelineExtension
+}
+
+type pipelineExtension struct {
+	receivers  []component.ID
+	processors []component.ID
+}
+
+// ParsePipelineExtensions parses the YAML representation of PipelineExtensions, unknown fields are rejected.
+func ParsePipelineExtensions(data []byte) (*PipelineExtensions, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var extensions PipelineExtensions
+
+	err := decoder.Decode(&extensions)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse pipeline extensions")
+	}
+
+	return &extensions, nil
+}
+
+func (c *Config) resolvePipelineExtensions(extensions PipelineExtensions) (pipelineExtensions, error) {
+	resolved := pipelineExtensions{
+		receivers:  map[component.ID]component.Config{},
+		processors: map[component.ID]component.Config{},
+		pipelines:  map[pipeline.ID]pipelineExtension{},
+	}
+
+	generatedReceivers := c.protocolsToIDs()
+
+	for name, cfg := range extensions.Receivers {
+		id, err := parseComponentID(name)
+		if err != nil {
+			return resolved, errors.WithMessagef(err, "invalid receiver '%s'", name)
+		}
+
+		if !slices.Contains(allowedExtensionReceiverTypes, id.Type()) {
+			return resolved, errors.Errorf("receiver '%s' is not supported, supported types are %v", name, allowedExtensionReceiverTypes)
+		}
+
+		if slices.Contains(generatedReceivers, id) {
+			return resolved, errors.Errorf("receiver '%s' is already configured by the operator", name)
+		}
+
+		resolved.receivers[id] = componentConfig(cfg)
+	}
+
+	generatedProcessors := c.buildDefaultProcessors()
+
+	for name, cfg := range extensions.Processors {
+		id, err := parseComponentID(name)
+		if err != nil {
+			return resolved, errors.WithMessagef(err, "invalid processor '%s'", name)
+		}
+
+		if !slices.Contains(allowedExtensionProcessorTypes, id.Type()) {
+			return resolved, errors.Errorf("processor '%s' is not supported, supported types are %v", name, allowedExtensionProcessorTypes)
+		}
+
+		if _, ok := generatedProcessors[id]; ok {
+			return resolved, errors.Errorf("processor '%s' is already configured by the operator", name)
+		}
+
+		resolved.processors[id] = componentConfig(cfg)
+	}
+
+	for name, extension := range extensions.Pipelines {
+		var pipelineID pipeline.ID
+
+		err := pipelineID.UnmarshalText([]byte(name))
+		if err != nil || pipelineID.Name() != "" || !slices.Contains([]pipeline.ID{traces, metrics, logs}, pipelineID) {
+			return resolved, errors.Errorf("invalid pipeline '%s', supported pipelines are %s, %s and %s", name, traces, metrics, logs)
+		}
+
+		receivers, err := resolveReferences(extension.Receivers, resolved.receivers)
+		if err != nil {
+			return resolved, errors.WithMessagef(err, "invalid receivers of pipeline '%s'", name)
+		}
+
+		processors, err := resolveReferences(extension.Processors, resolved.processors)
+		if err != nil {
+			return resolved, errors.WithMessagef(err, "invalid processors of pipeline '%s'", name)
+		}
+
+		resolved.pipelines[pipelineID] = pipelineExtension{
+			receivers:  receivers,
+			processors: processors,
+		}
+	}
+
+	receiverPorts, err := extensions.ReceiverPorts()
+	if err != nil {
+		return resolved, err
+	}
+
+	reservedPorts := c.generatedPorts()
+
+	for _, receiverPort := range receiverPorts {
+		if slices.Contains(reservedPorts, receiverPort.Port) {
+			return resolved, errors.Errorf("port %d of the receivers is already used by the operator", receiverPort.Port)
+		}
+	}
+
+	return resolved, nil
+}
+
+// ReceiverPorts returns the ports the receivers used by the pipelines listen on, sorted by port.
+// The ports are taken from the endpoint and listen_address fields of the receiver configurations.
+func (extensions PipelineExtensions) ReceiverPorts() ([]ReceiverPort, error) {
+	var usedReceivers []string
+
+	for _, extension := range extensions.Pipelines {
+		for _, name := range extension.Receivers {
+			if !slices.Contains(usedReceivers, name) {
+				usedReceivers = append(usedReceivers, name)
+			}
+		}
+	}
+
+	slices.Sort(usedReceivers)
+
+	var receiverPorts []ReceiverPort
+
+	for _, name := range usedReceivers {
+		id, err := parseComponentID(name)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid receiver '%s'", name)
+		}
+
+		// the statsd receiver listens on UDP, unless the transport is set to tcp
+		udp := id.Type() == StatsdID.Type()
+
+		err = collectReceiverPorts(extensions.Receivers[name], udp, func(receiverPort ReceiverPort) error {
+			for _, existing := range receiverPorts {
+				if existing.Port == receiverPort.Port && existing.UDP == receiverPort.UDP {
+					return errors.Errorf("port %d is used by more than one receiver", receiverPort.Port)
+				}
+			}
+
+			receiverPorts = append(receiverPorts, receiverPort)
+
+			return nil
+		})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid receiver '%s'", name)
+		}
+	}
+
+	slices.SortFunc(receiverPorts, func(a, b ReceiverPort) int {
+		return cmp.Or(cmp.Compare(a.Port, b.Port), cmp.Compare(a.Name, b.Name))
+	})
+
+	return receiverPorts, nil
+}
+
+func collectReceiverPorts(cfg any, udp bool, collect func(ReceiverPort) error) error {
+	switch typed := cfg.(type) {
+	case map[string]any:
+		if transport, ok := typed["transport"].(string); ok {
+			udp = strings.HasPrefix(transport, "udp")
+		}
+
+		for key, value := range typed {
+			if slices.Contains(receiverEndpointKeys, key) {
+				endpoint, ok := value.(string)
+				if !ok {
+					return errors.Errorf("%s must be a string", key)
+				}
+
+				port, err := parseEndpointPort(endpoint)
+				if err != nil {
+					return err
+				}
+
+				err = collect(newReceiverPort(port, udp))
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			err := collectReceiverPorts(value, udp || slices.Contains(udpReceiverKeys, key), collect)
+			if err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, value := range typed {
+			err := collectReceiverPorts(value, udp, collect)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseEndpointPort(endpoint string) (int32, error) {
+	_, rawPort, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return 0, errors.Errorf("endpoint '%s' has to be in the form host:port", endpoint)
+	}
+
+	port, err := strconv.ParseInt(rawPort, 10, 32)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, errors.Errorf("endpoint '%s' has an invalid port", endpoint)
+	}
+
+	return int32(port), nil
+}
+
+func newReceiverPort(port int32, udp bool) ReceiverPort {
+	name := fmt.Sprintf("ext-%d", port)
+	if udp {
+		name += "-udp"
+	}
+
+	return ReceiverPort{
+		Name: name,
+		Port: port,
+		UDP:  udp,
+	}
+}
+
+// generatedPorts returns the ports the receivers configured by the operator listen on.
+func (c *Config) generatedPorts() []int32 {
+	ports := []int32{ExtensionsHealthCheckPort}
+
+	for _, protocol := range c.protocols {
+		switch protocol {
+		case OtlpProtocol:
+			ports = append(ports, OtlpGrpcPort, OtlpHTTPPort)
+		case JaegerProtocol:
+			ports = append(ports, JaegerGrpcPort, JaegerThriftBinaryPort, JaegerThriftCompactPort, JaegerThriftHTTPPort)
+		case ZipkinProtocol:
+			ports = append(ports, ZipkinPort)
+		case StatsdProtocol:
+			ports = append(ports, StatsdPort)
+		}
+	}
+
+	return ports
+}
+
+func resolveReferences(names []string, defined map[component.ID]component.Config) ([]component.ID, error) {
+	ids := make([]component.ID, 0, len(names))
+
+	for _, name := range names {
+		id, err := parseComponentID(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := defined[id]; !ok {
+			return nil, errors.Errorf("'%s' is not defined", name)
+		}
+
+		if slices.Contains(ids, id) {
+			return nil, errors.Errorf("'%s' is used more than once", name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func parseComponentID(name string) (component.ID, error) {
+	var id component.ID
+
+	err := id.UnmarshalText([]byte(name))
+
+	return id, errors.WithStack(err)
+}
+
+// componentConfig replaces an empty configuration, so the component is still rendered as an empty map instead of null.
+func componentConfig(cfg any) component.Config {
+	if cfg == nil {
+		return map[string]any{}
+	}
+
+	return cfg
+}
@@ -0,0 +1,196 @@
+package otelcgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+)
+
+func readTestPipelineExtensions(t *testing.T) PipelineExtensions {
+	data, err := os.ReadFile(filepath.Join("testdata", "pipeline_extensions.yaml"))
+	require.NoError(t, err)
+
+	extensions, err := ParsePipelineExtensions(data)
+	require.NoError(t, err)
+
+	return *extensions
+}
+
+func TestParsePipelineExtensions(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		extensions := readTestPipelineExtensions(t)
+
+		assert.Len(t, extensions.Receivers, 1)
+		assert.Len(t, extensions.Processors, 3)
+		assert.Equal(t, []string{"attributes/drop-pii", "tail_sampling"}, extensions.Pipelines["traces"].Processors)
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := ParsePipelineExtensions([]byte("exporters: {}"))
+		require.Error(t, err)
+	})
+}
+
+func TestNewConfigWithPipelineExtensions(t *testing.T) {
+	t.Run("merged into receivers and services", func(t *testing.T) {
+		cfg, err := NewConfig(
+			"test",
+			Protocols{OtlpProtocol},
+			WithPipelineExtensions(readTestPipelineExtensions(t)),
+			WithReceivers(),
+			WithServices(),
+		)
+		require.NoError(t, err)
+		c, err := cfg.Marshal()
+		require.NoError(t, err)
+
+		expectedOutput, err := os.ReadFile(filepath.Join("testdata", "services_pipeline_extensions.yaml"))
+		require.NoError(t, err)
+		assert.YAMLEq(t, string(expectedOutput), string(c))
+	})
+	t.Run("merged into processors", func(t *testing.T) {
+		cfg, err := NewConfig(
+			"test",
+			Protocols{OtlpProtocol},
+			WithPipelineExtensions(readTestPipelineExtensions(t)),
+			WithProcessors(),
+		)
+		require.NoError(t, err)
+
+		assert.Contains(t, cfg.Processors, component.MustNewIDWithName("attributes", "drop-pii"))
+		assert.Contains(t, cfg.Processors, component.MustNewID("tail_sampling"))
+		assert.Equal(t, map[string]any{}, cfg.Processors[component.MustNewIDWithName("filter", "unused")])
+		assert.Contains(t, cfg.Processors, batchTraces)
+	})
+	t.Run("extension receivers enable a pipeline", func(t *testing.T) {
+		cfg, err := NewConfig(
+			"test",
+			Protocols{ZipkinProtocol},
+			WithPipelineExtensions(readTestPipelineExtensions(t)),
+			WithServices(),
+		)
+		require.NoError(t, err)
+
+		require.Contains(t, cfg.Service.Pipelines, metrics)
+		assert.Equal(t, []component.ID{component.MustNewIDWithName("prometheus", "app")}, cfg.Service.Pipelines[metrics].Receivers)
+		assert.NotContains(t, cfg.Service.Pipelines, logs)
+	})
+
+	invalid := map[string]PipelineExtensions{
+		"receiver already configured": {
+			Receivers: map[string]any{"otlp": nil},
+		},
+		"invalid receiver ID": {
+			Receivers: map[string]any{"not valid/": nil},
+		},
+		"unsupported processor": {
+			Processors: map[string]any{"batch/custom": nil},
+		},
+		"processor already configured": {
+			Processors: map[string]any{"transform": nil},
+		},
+		"unknown pipeline": {
+			Pipelines: map[string]PipelineExtension{"profiles": {}},
+		},
+		"named pipeline": {
+			Pipelines: map[string]PipelineExtension{"traces/custom": {}},
+		},
+		"undefined processor": {
+			Pipelines: map[string]PipelineExtension{"traces": {Processors: []string{"filter"}}},
+		},
+		"unsupported receiver": {
+			Receivers: map[string]any{"filelog": nil},
+		},
+		"receiver port used by the operator": {
+			Receivers: map[string]any{"otlp/custom": map[string]any{"protocols": map[string]any{"grpc": map[string]any{"endpoint": "0.0.0.0:4317"}}}},
+			Pipelines: map[string]PipelineExtension{"traces": {Receivers: []string{"otlp/custom"}}},
+		},
+		"receiver port used twice": {
+			Receivers: map[string]any{
+				"zipkin/a": map[string]any{"endpoint": "0.0.0.0:9412"},
+				"zipkin/b": map[string]any{"endpoint": "0.0.0.0:9412"},
+			},
+			Pipelines: map[string]PipelineExtension{"traces": {Receivers: []string{"zipkin/a", "zipkin/b"}}},
+		},
+		"invalid receiver endpoint": {
+			Receivers: map[string]any{"zipkin/custom": map[string]any{"endpoint": "0.0.0.0:http"}},
+			Pipelines: map[string]PipelineExtension{"traces": {Receivers: []string{"zipkin/custom"}}},
+		},
+		"undefined receiver": {
+			Pipelines: map[string]PipelineExtension{"traces": {Receivers: []string{"otlp"}}},
+		},
+		"duplicated processor": {
+			Processors: map[string]any{"filter": nil},
+			Pipelines:  map[string]PipelineExtension{"traces": {Processors: []string{"filter", "filter"}}},
+		},
+	}
+
+	for name, extensions := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig("test", Protocols{OtlpProtocol}, WithPipelineExtensions(extensions))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestReceiverPorts(t *testing.T) {
+	t.Run("ports of the used receivers", func(t *testing.T) {
+		extensions, err := ParsePipelineExtensions([]byte(`
+receivers:
+  otlp/custom:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:14317
+      http:
+        endpoint: 0.0.0.0:14318
+  statsd/custom:
+    endpoint: 0.0.0.0:18125
+  syslog/custom:
+    tcp:
+      listen_address: 0.0.0.0:5140
+    udp:
+      listen_address: 0.0.0.0:5140
+  zipkin/unused:
+    endpoint: 0.0.0.0:19411
+pipelines:
+  traces:
+    receivers:
+      - otlp/custom
+  metrics:
+    receivers:
+      - otlp/custom
+      - statsd/custom
+  logs:
+    receivers:
+      - syslog/custom
+`))
+		require.NoError(t, err)
+
+		ports, err := extensions.ReceiverPorts()
+		require.NoError(t, err)
+
+		assert.Equal(t, []ReceiverPort{
+			{Name: "ext-5140", Port: 5140},
+			{Name: "ext-5140-udp", Port: 5140, UDP: true},
+			{Name: "ext-14317", Port: 14317},
+			{Name: "ext-14318", Port: 14318},
+			{Name: "ext-18125-udp", Port: 18125, UDP: true},
+		}, ports)
+
+		_, err = NewConfig("test", Protocols{OtlpProtocol, StatsdProtocol}, WithPipelineExtensions(*extensions))
+		require.NoError(t, err)
+	})
+	t.Run("statsd with tcp transport", func(t *testing.T) {
+		extensions := PipelineExtensions{
+			Receivers: map[string]any{"statsd/custom": map[string]any{"endpoint": "0.0.0.0:18125", "transport": "tcp"}},
+			Pipelines: map[string]PipelineExtension{"metrics": {Receivers: []string{"statsd/custom"}}},
+		}
+
+		ports, err := extensions.ReceiverPorts()
+		require.NoError(t, err)
+		assert.Equal(t, []ReceiverPort{{Name: "ext-18125", Port: 18125}}, ports)
+	})
+}
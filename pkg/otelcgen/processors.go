@@ -52,6 +52,16 @@ var (
 )
 
 func (c *Config) buildProcessors() map[component.ID]component.Config {
+	processors := c.buildDefaultProcessors()
+
+	for id, cfg := range c.pipelineExtensions.processors {
+		processors[id] = cfg
+	}
+
+	return processors
+}
+
+func (c *Config) buildDefaultProcessors() map[component.ID]component.Config {
 	return map[component.ID]component.Config{
 		cumulativeToDelta: map[string]any{},
 		k8sattributes: map[string]any{
@@ -0,0 +1,122 @@
+package otelcgen
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	prometheusPodsJobName        = "kubernetes-pods"
+	prometheusScrapeInterval     = "60s"
+	prometheusShardsEnv          = "${env:SHARDS}"
+	prometheusShardIDEnv         = "${env:SHARD_ID}"
+	prometheusShardHashLabel     = "__tmp_hash"
+	prometheusAnnotationScrape   = "__meta_kubernetes_pod_annotation_prometheus_io_scrape"
+	prometheusAnnotationScheme   = "__meta_kubernetes_pod_annotation_prometheus_io_scheme"
+	prometheusAnnotationPath     = "__meta_kubernetes_pod_annotation_prometheus_io_path"
+	prometheusAnnotationPort     = "__meta_kubernetes_pod_annotation_prometheus_io_port"
+	prometheusMetaNamespace      = "__meta_kubernetes_namespace"
+	prometheusMetaPodName        = "__meta_kubernetes_pod_name"
+	prometheusMetaPodPhase       = "__meta_kubernetes_pod_phase"
+	prometheusLabelNamespaceName = "k8s_namespace_name"
+	prometheusLabelPodName       = "k8s_pod_name"
+)
+
+// buildPrometheusPodsScrapeConfig scrapes the pods annotated with the well-known prometheus.io annotations:
+//   - prometheus.io/scrape: only pods with "true" are scraped
+//   - prometheus.io/scheme: http or https, defaults to http
+//   - prometheus.io/path: defaults to /metrics
+//   - prometheus.io/port: defaults to the ports of the pod's containers
+//
+// The targets are distributed across the collector replicas by hashing their address, so each target is scraped by exactly one replica.
+// The number of shards and the shard of the replica are provided by the statefulset via environment variables.
+func (c *Config) buildPrometheusPodsScrapeConfig() map[string]any {
+	sdConfig := map[string]any{
+		"role": "pod",
+	}
+
+	relabelConfigs := []map[string]any{
+		{
+			"source_labels": []string{prometheusAnnotationScrape},
+			"action":        "keep",
+			"regex":         "true",
+		},
+		{
+			"source_labels": []string{prometheusMetaPodPhase},
+			"action":        "drop",
+			"regex":         "Pending|Succeeded|Failed|Completed",
+		},
+	}
+
+	if c.prometheusNamespaces != nil {
+		if len(c.prometheusNamespaces) > 0 {
+			sdConfig["namespaces"] = map[string]any{"names": c.prometheusNamespaces}
+		}
+
+		// an empty regex doesn't match any namespace, so nothing is scraped if no namespace was selected
+		relabelConfigs = append(relabelConfigs, map[string]any{
+			"source_labels": []string{prometheusMetaNamespace},
+			"action":        "keep",
+			"regex":         namespacesRegex(c.prometheusNamespaces),
+		})
+	}
+
+	relabelConfigs = append(relabelConfigs,
+		map[string]any{
+			"source_labels": []string{prometheusAnnotationScheme},
+			"action":        "replace",
+			"regex":         "(https?)",
+			"target_label":  "__scheme__",
+		},
+		map[string]any{
+			"source_labels": []string{prometheusAnnotationPath},
+			"action":        "replace",
+			"regex":         "(.+)",
+			"target_label":  "__metrics_path__",
+		},
+		map[string]any{
+			"source_labels": []string{"__address__", prometheusAnnotationPort},
+			"action":        "replace",
+			"regex":         `([^:]+)(?::\d+)?;(\d+)`,
+			"replacement":   "$$1:$$2",
+			"target_label":  "__address__",
+		},
+		map[string]any{
+			"source_labels": []string{prometheusMetaNamespace},
+			"action":        "replace",
+			"target_label":  prometheusLabelNamespaceName,
+		},
+		map[string]any{
+			"source_labels": []string{prometheusMetaPodName},
+			"action":        "replace",
+			"target_label":  prometheusLabelPodName,
+		},
+		map[string]any{
+			"source_labels": []string{"__address__"},
+			"action":        "hashmod",
+			"modulus":       prometheusShardsEnv,
+			"target_label":  prometheusShardHashLabel,
+		},
+		map[string]any{
+			"source_labels": []string{prometheusShardHashLabel},
+			"action":        "keep",
+			"regex":         prometheusShardIDEnv,
+		},
+	)
+
+	return map[string]any{
+		"job_name":              prometheusPodsJobName,
+		"scrape_interval":       prometheusScrapeInterval,
+		"kubernetes_sd_configs": []map[string]any{sdConfig},
+		"relabel_configs":       relabelConfigs,
+	}
+}
+
+func namespacesRegex(namespaces []string) string {
+	quoted := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		quoted = append(quoted, regexp.QuoteMeta(namespace))
+	}
+
+	return strings.Join(quoted, "|")
+}
@@ -39,6 +39,12 @@ func (c *Config) buildReceiverComponent(componentID component.ID) component.Conf
 				},
 			},
 		}
+	case PrometheusID:
+		return map[string]any{
+			"config": map[string]any{
+				"scrape_configs": []map[string]any{c.buildPrometheusPodsScrapeConfig()},
+			},
+		}
 	default:
 		return nil
 	}
@@ -57,10 +63,16 @@ func (c *Config) buildReceivers() (map[component.ID]component.Config, error) {
 			receivers[JaegerID] = c.buildReceiverComponent(JaegerID)
 		case OtlpProtocol:
 			receivers[OtlpID] = c.buildReceiverComponent(OtlpID)
+		case PrometheusProtocol:
+			receivers[PrometheusID] = c.buildReceiverComponent(PrometheusID)
 		default:
 			return nil, errors.Errorf("unknown protocol: %s", p)
 		}
 	}
 
+	for id, cfg := range c.pipelineExtensions.receivers {
+		receivers[id] = cfg
+	}
+
 	return receivers, nil
 }
@@ -86,3 +86,50 @@ func TestNewConfig(t *testing.T) {
 		assert.Contains(t, err.Error(), "unknown protocol")
 	})
 }
+
+func TestNewConfigWithPrometheus(t *testing.T) {
+	t.Run("all namespaces", func(t *testing.T) {
+		cfg, err := NewConfig(
+			"test",
+			Protocols{PrometheusProtocol},
+			WithReceivers(),
+			WithServices(),
+		)
+		require.NoError(t, err)
+		c, err := cfg.Marshal()
+		require.NoError(t, err)
+
+		expectedOutput, err := os.ReadFile(filepath.Join("testdata", "receivers_prometheus_only.yaml"))
+		require.NoError(t, err)
+
+		assert.YAMLEq(t, string(expectedOutput), string(c))
+	})
+	t.Run("selected namespaces", func(t *testing.T) {
+		cfg, err := NewConfig(
+			"test",
+			Protocols{PrometheusProtocol},
+			WithPrometheusNamespaces([]string{"app-1", "app-2"}),
+			WithReceivers(),
+		)
+		require.NoError(t, err)
+		c, err := cfg.Marshal()
+		require.NoError(t, err)
+
+		assert.Contains(t, string(c), "- app-1\n")
+		assert.Contains(t, string(c), "regex: app-1|app-2\n")
+	})
+	t.Run("no selected namespace", func(t *testing.T) {
+		cfg, err := NewConfig(
+			"test",
+			Protocols{PrometheusProtocol},
+			WithPrometheusNamespaces(nil),
+			WithReceivers(),
+		)
+		require.NoError(t, err)
+		c, err := cfg.Marshal()
+		require.NoError(t, err)
+
+		assert.NotContains(t, string(c), "namespaces:")
+		assert.Contains(t, string(c), "regex: \"\"\n")
+	})
+}
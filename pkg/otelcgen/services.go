@@ -19,7 +19,7 @@ var (
 
 	// based on
 	// stasd https://github.com/open-telemetry/opentelemetry-collector-contrib/blob/d4372922ec79cb052c7f7e2fcc0fba9f492bd948/receiver/statsdreceiver/factory.go#L33
-	allowedPipelinesMetricsReceiversIDs = []component.ID{OtlpID, StatsdID}
+	allowedPipelinesMetricsReceiversIDs = []component.ID{OtlpID, StatsdID, PrometheusID}
 
 	// based on
 	// zipkin https://github.com/open-telemetry/opentelemetry-collector-contrib/blob/d4372922ec79cb052c7f7e2fcc0fba9f492bd948/receiver/zipkinreceiver/factory.go#L24
@@ -44,45 +44,60 @@ func (c *Config) buildServices() ServiceConfig {
 	pipelinesCfg := pipelines.Config{}
 
 	// traces
-	tracesReceivers := c.buildPipelinesReceivers(allowedPipelinesTracesReceiversIDs)
+	tracesReceivers := c.buildPipelinesReceivers(traces, allowedPipelinesTracesReceiversIDs)
 	if len(tracesReceivers) != 0 {
 		pipelinesCfg[traces] = &pipelines.PipelineConfig{
 			Receivers:  tracesReceivers,
-			Processors: append(buildProcessors(), batchTraces),
+			Processors: c.buildPipelinesProcessors(traces, batchTraces),
 			Exporters:  buildExporters(),
 		}
 	}
 
 	// metrics
-	metricsReceivers := c.buildPipelinesReceivers(allowedPipelinesMetricsReceiversIDs)
+	metricsReceivers := c.buildPipelinesReceivers(metrics, allowedPipelinesMetricsReceiversIDs)
 	if len(metricsReceivers) != 0 {
 		pipelinesCfg[metrics] = &pipelines.PipelineConfig{
 			Receivers:  metricsReceivers,
-			Processors: append(buildProcessors(), cumulativeToDelta, batchMetrics),
+			Processors: c.buildPipelinesProcessors(metrics, cumulativeToDelta, batchMetrics),
 			Exporters:  buildExporters(),
 		}
 	}
 
 	// logs
-	logsReceivers := c.buildPipelinesReceivers(allowedPipelinesLogsReceiversIDs)
+	logsReceivers := c.buildPipelinesReceivers(logs, allowedPipelinesLogsReceiversIDs)
 	if len(logsReceivers) != 0 {
 		pipelinesCfg[logs] = &pipelines.PipelineConfig{
 			Receivers:  logsReceivers,
-			Processors: append(buildProcessors(), batchLogs),
+			Processors: c.buildPipelinesProcessors(logs, batchLogs),
 			Exporters:  buildExporters(),
 		}
 	}
 
+	serviceExtensions := extensions.Config{healthCheck}
+	if c.fileStorageDirectory != "" {
+		serviceExtensions = append(serviceExtensions, fileStorageQueue)
+	}
+
 	return ServiceConfig{
-		Extensions: extensions.Config{healthCheck},
+		Extensions: serviceExtensions,
 		Pipelines:  pipelinesCfg,
 	}
 }
 
-func (c *Config) buildPipelinesReceivers(allowed []component.ID) []component.ID {
-	return filter(c.protocolsToIDs(), func(id component.ID) bool {
+func (c *Config) buildPipelinesReceivers(pipelineID pipeline.ID, allowed []component.ID) []component.ID {
+	receivers := filter(c.protocolsToIDs(), func(id component.ID) bool {
 		return slices.Contains(allowed, id)
 	})
+
+	return append(receivers, c.pipelineExtensions.pipelines[pipelineID].receivers...)
+}
+
+// buildPipelinesProcessors places the user supplied processors after the enrichment with k8s attributes, so they can be used for filtering,
+// but before the batching, which has to stay the last processor of the pipeline.
+func (c *Config) buildPipelinesProcessors(pipelineID pipeline.ID, finalProcessors ...component.ID) []component.ID {
+	processors := append(buildProcessors(), c.pipelineExtensions.pipelines[pipelineID].processors...)
+
+	return append(processors, finalProcessors...)
 }
 
 func buildExporters() []component.ID {
@@ -3,6 +3,7 @@ package exporterconfig
 import (
 	"context"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
@@ -103,7 +104,7 @@ func (s *SecretGenerator) generateConfig(ctx context.Context, dk *dynakube.DynaK
 	}
 
 	var tokens corev1.Secret
-	if err := s.client.Get(ctx, client.ObjectKey{Name: dk.Tokens(), Namespace: dk.Namespace}, &tokens); err != nil {
+	if err := s.apiReader.Get(ctx, client.ObjectKey{Name: dk.Tokens(), Namespace: dk.Namespace}, &tokens); err != nil {
 		conditions.SetKubeAPIError(dk.Conditions(), ConfigConditionType, err)
 
 		return nil, errors.WithMessage(err, "failed to query tokens")
@@ -147,7 +148,7 @@ func (s *SecretGenerator) generateCerts(ctx context.Context, dk *dynakube.DynaKu
 func (s *SecretGenerator) createSecretForNamespaces(ctx context.Context, secretName, conditionType string, nsList []corev1.Namespace, dk *dynakube.DynaKube, data map[string][]byte) error { //nolint:revive
 	coreLabels := k8slabels.NewCoreLabels(dk.Name, k8slabels.WebhookComponentLabel)
 
-	secret, err := k8ssecret.BuildForNamespace(secretName, "", data, k8ssecret.SetLabels(coreLabels.BuildLabels()))
+	secret, err := k8ssecret.BuildForNamespace(secretName, "", data, k8ssecret.SetLabels(coreLabels.BuildLabels()), k8ssecret.SetAnnotations(api.TokensHashAnnotation(dk.Status.TokensHash, conditionType == ConfigConditionType)))
 	if err != nil {
 		conditions.SetSecretGenFailed(dk.Conditions(), conditionType, err)
 
@@ -170,7 +171,7 @@ func (s *SecretGenerator) createSecretForNamespaces(ctx context.Context, secretN
 func (s *SecretGenerator) createSourceForWebhook(ctx context.Context, dk *dynakube.DynaKube, name string, conditionType string, data map[string][]byte) error {
 	coreLabels := k8slabels.NewCoreLabels(dk.Name, k8slabels.WebhookComponentLabel)
 
-	secret, err := k8ssecret.BuildForNamespace(name, dk.Namespace, data, k8ssecret.SetLabels(coreLabels.BuildLabels()))
+	secret, err := k8ssecret.BuildForNamespace(name, dk.Namespace, data, k8ssecret.SetLabels(coreLabels.BuildLabels()), k8ssecret.SetAnnotations(api.TokensHashAnnotation(dk.Status.TokensHash, conditionType == ConfigConditionType)))
 	if err != nil {
 		conditions.SetSecretGenFailed(dk.Conditions(), conditionType, err)
 
@@ -0,0 +1,53 @@
+package hpa
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/internal/builder"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	// Mandatory fields, provided in constructor as named params
+	setName      = builder.SetName[*autoscalingv2.HorizontalPodAutoscaler]
+	setNamespace = builder.SetNamespace[*autoscalingv2.HorizontalPodAutoscaler]
+
+	// Optional fields, provided in constructor as list of options
+	SetLabels = builder.SetLabels[*autoscalingv2.HorizontalPodAutoscaler]
+)
+
+func Build(owner metav1.Object, name string, scaleTargetRef autoscalingv2.CrossVersionObjectReference, minReplicas *int32, maxReplicas int32, options ...builder.Option[*autoscalingv2.HorizontalPodAutoscaler]) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	neededOpts := []builder.Option[*autoscalingv2.HorizontalPodAutoscaler]{
+		setName(name),
+		setNamespace(owner.GetNamespace()),
+		setScaleTargetRef(scaleTargetRef),
+		setReplicas(minReplicas, maxReplicas),
+	}
+	neededOpts = append(neededOpts, options...)
+
+	return builder.Build(owner, &autoscalingv2.HorizontalPodAutoscaler{}, neededOpts...)
+}
+
+func setScaleTargetRef(scaleTargetRef autoscalingv2.CrossVersionObjectReference) builder.Option[*autoscalingv2.HorizontalPodAutoscaler] {
+	return func(h *autoscalingv2.HorizontalPodAutoscaler) {
+		h.Spec.ScaleTargetRef = scaleTargetRef
+	}
+}
+
+func setReplicas(minReplicas *int32, maxReplicas int32) builder.Option[*autoscalingv2.HorizontalPodAutoscaler] {
+	return func(h *autoscalingv2.HorizontalPodAutoscaler) {
+		h.Spec.MinReplicas = minReplicas
+		h.Spec.MaxReplicas = maxReplicas
+	}
+}
+
+func SetMetrics(metrics []autoscalingv2.MetricSpec) builder.Option[*autoscalingv2.HorizontalPodAutoscaler] {
+	return func(h *autoscalingv2.HorizontalPodAutoscaler) {
+		h.Spec.Metrics = metrics
+	}
+}
+
+func SetBehavior(behavior *autoscalingv2.HorizontalPodAutoscalerBehavior) builder.Option[*autoscalingv2.HorizontalPodAutoscaler] {
+	return func(h *autoscalingv2.HorizontalPodAutoscaler) {
+		h.Spec.Behavior = behavior
+	}
+}
@@ -0,0 +1,34 @@
+package hpa
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/internal/query"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Query(kubeClient client.Client, kubeReader client.Reader, log logd.Logger) query.Generic[*autoscalingv2.HorizontalPodAutoscaler, *autoscalingv2.HorizontalPodAutoscalerList] {
+	return query.Generic[*autoscalingv2.HorizontalPodAutoscaler, *autoscalingv2.HorizontalPodAutoscalerList]{
+		Target:     &autoscalingv2.HorizontalPodAutoscaler{},
+		ListTarget: &autoscalingv2.HorizontalPodAutoscalerList{},
+		ToList: func(hl *autoscalingv2.HorizontalPodAutoscalerList) []*autoscalingv2.HorizontalPodAutoscaler {
+			out := []*autoscalingv2.HorizontalPodAutoscaler{}
+			for _, h := range hl.Items {
+				out = append(out, &h)
+			}
+
+			return out
+		},
+		IsEqual:      isEqual,
+		MustRecreate: func(_, _ *autoscalingv2.HorizontalPodAutoscaler) bool { return false },
+
+		KubeClient: kubeClient,
+		KubeReader: kubeReader,
+		Log:        log,
+	}
+}
+
+func isEqual(current, desired *autoscalingv2.HorizontalPodAutoscaler) bool {
+	return !hasher.IsAnnotationDifferent(current, desired)
+}
@@ -44,3 +44,10 @@ func SetLabels[T client.Object](labels map[string]string) func(T) {
 		o.SetResourceVersion("")
 	}
 }
+
+func SetAnnotations[T client.Object](annotations map[string]string) func(T) {
+	return func(o T) {
+		o.SetAnnotations(annotations)
+		o.SetResourceVersion("")
+	}
+}
@@ -0,0 +1,50 @@
+package networkpolicy
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/internal/query"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CiliumNetworkPolicyGVK identifies the CiliumNetworkPolicies, the Cilium API isn't a dependency of the operator, so they are handled as unstructured objects.
+var CiliumNetworkPolicyGVK = schema.GroupVersionKind{Group: "cilium.io", Version: "v2", Kind: "CiliumNetworkPolicy"}
+
+func NewCiliumNetworkPolicy() *unstructured.Unstructured {
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(CiliumNetworkPolicyGVK)
+
+	return policy
+}
+
+func NewCiliumNetworkPolicyList() *unstructured.UnstructuredList {
+	policyList := &unstructured.UnstructuredList{}
+	policyList.SetGroupVersionKind(CiliumNetworkPolicyGVK.GroupVersion().WithKind(CiliumNetworkPolicyGVK.Kind + "List"))
+
+	return policyList
+}
+
+func CiliumQuery(kubeClient client.Client, kubeReader client.Reader, log logd.Logger) query.Generic[*unstructured.Unstructured, *unstructured.UnstructuredList] {
+	return query.Generic[*unstructured.Unstructured, *unstructured.UnstructuredList]{
+		Target:     NewCiliumNetworkPolicy(),
+		ListTarget: NewCiliumNetworkPolicyList(),
+		ToList: func(policyList *unstructured.UnstructuredList) []*unstructured.Unstructured {
+			out := []*unstructured.Unstructured{}
+			for _, policy := range policyList.Items {
+				out = append(out, &policy)
+			}
+
+			return out
+		},
+		IsEqual: func(current, desired *unstructured.Unstructured) bool {
+			return !hasher.IsAnnotationDifferent(current, desired)
+		},
+		MustRecreate: func(_, _ *unstructured.Unstructured) bool { return false },
+
+		KubeClient: kubeClient,
+		KubeReader: kubeReader,
+		Log:        log,
+	}
+}
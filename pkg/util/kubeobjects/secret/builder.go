@@ -12,7 +12,8 @@ var (
 	setNamespace = builder.SetNamespace[*corev1.Secret]
 
 	// Optional fields, provided in constructor as list of options
-	SetLabels = builder.SetLabels[*corev1.Secret]
+	SetLabels      = builder.SetLabels[*corev1.Secret]
+	SetAnnotations = builder.SetAnnotations[*corev1.Secret]
 )
 
 func Build(owner metav1.Object, name string, data map[string][]byte, options ...builder.Option[*corev1.Secret]) (*corev1.Secret, error) {
@@ -3,6 +3,7 @@ package secret
 import (
 	"reflect"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api"
 	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/internal/query"
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +37,8 @@ func Query(kubeClient client.Client, kubeReader client.Reader, log logd.Logger)
 	}
 }
 
+// isEqual ignores the annotations, except the tokens hash, so a rotation is also rolled out to secrets whose data doesn't depend on the rotated token.
 func isEqual(secret *corev1.Secret, other *corev1.Secret) bool {
-	return reflect.DeepEqual(secret.Data, other.Data) && reflect.DeepEqual(secret.Labels, other.Labels) && reflect.DeepEqual(secret.OwnerReferences, other.OwnerReferences)
+	return reflect.DeepEqual(secret.Data, other.Data) && reflect.DeepEqual(secret.Labels, other.Labels) && reflect.DeepEqual(secret.OwnerReferences, other.OwnerReferences) &&
+		secret.Annotations[api.AnnotationTokensHash] == other.Annotations[api.AnnotationTokensHash]
 }
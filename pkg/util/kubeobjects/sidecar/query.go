@@ -0,0 +1,27 @@
+package sidecar
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/internal/query"
+	istiov1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Query(kubeClient client.Client, kubeReader client.Reader, log logd.Logger) query.Generic[*istiov1beta1.Sidecar, *istiov1beta1.SidecarList] {
+	return query.Generic[*istiov1beta1.Sidecar, *istiov1beta1.SidecarList]{
+		Target:     &istiov1beta1.Sidecar{},
+		ListTarget: &istiov1beta1.SidecarList{},
+		ToList: func(sidecarList *istiov1beta1.SidecarList) []*istiov1beta1.Sidecar {
+			return sidecarList.Items
+		},
+		IsEqual: func(current, desired *istiov1beta1.Sidecar) bool {
+			return !hasher.IsAnnotationDifferent(current, desired)
+		},
+		MustRecreate: func(_, _ *istiov1beta1.Sidecar) bool { return false },
+
+		KubeClient: kubeClient,
+		KubeReader: kubeReader,
+		Log:        log,
+	}
+}
@@ -0,0 +1,160 @@
+package pac
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+const (
+	dnsLookupTimeout = 2 * time.Second
+	localhostIP      = "127.0.0.1"
+)
+
+// lookupIP is replaced in the tests
+var lookupIP = func(ctx context.Context, host string) ([]net.IP, error) {
+	return net.DefaultResolver.LookupIP(ctx, "ip", host)
+}
+
+// registerFunctions provides the functions of the PAC standard to the script.
+func registerFunctions(runtime *goja.Runtime) error {
+	functions := map[string]any{
+		"isPlainHostName":     isPlainHostName,
+		"dnsDomainIs":         dnsDomainIs,
+		"localHostOrDomainIs": localHostOrDomainIs,
+		"isResolvable":        isResolvable,
+		"isInNet":             isInNet,
+		"dnsResolve":          func(host string) goja.Value { return optionalString(runtime, dnsResolve(host)) },
+		"convert_addr":        convertAddr,
+		"myIpAddress":         myIPAddress,
+		"dnsDomainLevels":     dnsDomainLevels,
+		"shExpMatch":          shExpMatch,
+	}
+
+	for name, function := range functions {
+		err := runtime.Set(name, function)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func optionalString(runtime *goja.Runtime, value string) goja.Value {
+	if value == "" {
+		return goja.Null()
+	}
+
+	return runtime.ToValue(value)
+}
+
+func isPlainHostName(host string) bool {
+	return !strings.Contains(host, ".")
+}
+
+func dnsDomainIs(host, domain string) bool {
+	return strings.HasSuffix(host, domain)
+}
+
+func localHostOrDomainIs(host, hostDomain string) bool {
+	return host == hostDomain || (isPlainHostName(host) && strings.HasPrefix(hostDomain, host+"."))
+}
+
+func isResolvable(host string) bool {
+	return dnsResolve(host) != ""
+}
+
+// isInNet checks if the IP of the host is in the network of the pattern and mask, e.g. isInNet(host, "10.0.0.0", "255.0.0.0").
+func isInNet(host, pattern, mask string) bool {
+	hostIP := net.ParseIP(dnsResolve(host)).To4()
+	patternIP := net.ParseIP(pattern).To4()
+	maskIP := net.ParseIP(mask).To4()
+
+	if hostIP == nil || patternIP == nil || maskIP == nil {
+		return false
+	}
+
+	ipMask := net.IPMask(maskIP)
+
+	return hostIP.Mask(ipMask).Equal(patternIP.Mask(ipMask))
+}
+
+// dnsResolve returns the first IPv4 address of the host, empty if it can't be resolved.
+func dnsResolve(host string) string {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dnsLookupTimeout)
+	defer cancel()
+
+	ips, err := lookupIP(ctx, host)
+	if err != nil {
+		return ""
+	}
+
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip.String()
+		}
+	}
+
+	return ""
+}
+
+func convertAddr(ip string) uint32 {
+	ipv4 := net.ParseIP(ip).To4()
+	if ipv4 == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint32(ipv4)
+}
+
+// myIPAddress returns the first IPv4 address of the operator, which isn't a loopback address.
+func myIPAddress() string {
+	addresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return localhostIP
+	}
+
+	for _, address := range addresses {
+		if ipNet, ok := address.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+
+	return localhostIP
+}
+
+func dnsDomainLevels(host string) int {
+	return strings.Count(host, ".")
+}
+
+// shExpMatch matches the shell expression, where * matches any characters and ? a single one.
+func shExpMatch(str, shExp string) bool {
+	var pattern strings.Builder
+
+	pattern.WriteString("^")
+
+	for _, char := range shExp {
+		switch char {
+		case '*':
+			pattern.WriteString(".*")
+		case '?':
+			pattern.WriteString(".")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+
+	pattern.WriteString("$")
+
+	return regexp.MustCompile(pattern.String()).MatchString(str)
+}
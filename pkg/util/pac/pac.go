@@ -0,0 +1,134 @@
+package pac
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/pkg/errors"
+)
+
+const (
+	findProxyFunction = "FindProxyForURL"
+	directResult      = "DIRECT"
+
+	schemeHTTP   = "http"
+	schemeHTTPS  = "https"
+	schemeSOCKS5 = "socks5"
+
+	// evaluationTimeout stops scripts, which don't terminate, the DNS lookups have their own timeout
+	evaluationTimeout = 10 * time.Second
+)
+
+// resultSchemes maps the proxy types of the PAC results to the schemes of the proxy URLs
+var resultSchemes = map[string]string{
+	"PROXY":  schemeHTTP,
+	"HTTP":   schemeHTTP,
+	"HTTPS":  schemeHTTPS,
+	"SOCKS":  schemeSOCKS5,
+	"SOCKS5": schemeSOCKS5,
+}
+
+// Script is a proxy auto-config file, which is evaluated with the goja JavaScript runtime.
+// The functions of the PAC standard are provided, except for the time based ones (weekdayRange, dateRange and timeRange).
+type Script struct {
+	runtime   *goja.Runtime
+	findProxy goja.Callable
+
+	// the runtime isn't goroutine safe
+	mutex sync.Mutex
+}
+
+func Parse(script string) (*Script, error) {
+	runtime := goja.New()
+
+	err := registerFunctions(runtime)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	_, err = runtime.RunString(script)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse proxy auto-config")
+	}
+
+	findProxy, ok := goja.AssertFunction(runtime.Get(findProxyFunction))
+	if !ok {
+		return nil, errors.Errorf("proxy auto-config doesn't define the function %s", findProxyFunction)
+	}
+
+	return &Script{
+		runtime:   runtime,
+		findProxy: findProxy,
+	}, nil
+}
+
+// FindProxy evaluates the FindProxyForURL function for the target, nil is returned if the target should be reached directly.
+// Only the first entry of the result is used, the fallbacks aren't considered.
+func (s *Script) FindProxy(target *url.URL) (*url.URL, error) {
+	result, err := s.evaluate(target.String(), target.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	return parseResult(result)
+}
+
+// ProxyFunc can be used as the Proxy of an http.Transport, the credentials are added to the selected proxy.
+func (s *Script) ProxyFunc(credentials *url.Userinfo) func(req *http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := s.FindProxy(req.URL)
+		if err != nil || proxyURL == nil {
+			return nil, err
+		}
+
+		proxyURL.User = credentials
+
+		return proxyURL, nil
+	}
+}
+
+func (s *Script) evaluate(targetURL, host string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	timer := time.AfterFunc(evaluationTimeout, func() {
+		s.runtime.Interrupt("proxy auto-config evaluation timed out")
+	})
+	defer timer.Stop()
+	defer s.runtime.ClearInterrupt()
+
+	result, err := s.findProxy(goja.Undefined(), s.runtime.ToValue(targetURL), s.runtime.ToValue(host))
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to evaluate proxy auto-config")
+	}
+
+	if goja.IsUndefined(result) || goja.IsNull(result) {
+		return directResult, nil
+	}
+
+	return result.String(), nil
+}
+
+func parseResult(result string) (*url.URL, error) {
+	first, _, _ := strings.Cut(result, ";")
+
+	fields := strings.Fields(first)
+	if len(fields) == 0 || (len(fields) == 1 && strings.EqualFold(fields[0], directResult)) {
+		return nil, nil
+	}
+
+	if len(fields) != 2 {
+		return nil, errors.Errorf("invalid proxy auto-config result %q", result)
+	}
+
+	scheme, ok := resultSchemes[strings.ToUpper(fields[0])]
+	if !ok {
+		return nil, errors.Errorf("unsupported proxy type %q in proxy auto-config result", fields[0])
+	}
+
+	return &url.URL{Scheme: scheme, Host: fields[1]}, nil
+}
@@ -0,0 +1,140 @@
+package pac
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScript = `
+// management traffic and data traffic use different proxies
+function FindProxyForURL(url, host) {
+	/* cluster internal */
+	if (isPlainHostName(host) || dnsDomainIs(host, ".svc.cluster.local") || isInNet(host, "10.0.0.0", "255.0.0.0")) {
+		return "DIRECT";
+	}
+
+	var downloadProxy = "PROXY download-proxy:3128; DIRECT";
+
+	if (shExpMatch(url, "*/api/v1/deployment/*") && !localHostOrDomainIs(host, "tenant.live.dynatrace.com"))
+		return downloadProxy;
+	else if (shExpMatch(host, "*.live.dynatrace.com"))
+		return 'HTTPS data-proxy:443';
+
+	return "PROXY management-proxy:8080";
+}
+`
+
+func mockLookupIP(t *testing.T, ips map[string]string) {
+	t.Helper()
+
+	original := lookupIP
+	lookupIP = func(_ context.Context, host string) ([]net.IP, error) {
+		ip, ok := ips[host]
+		if !ok {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+
+		return []net.IP{net.ParseIP(ip)}, nil
+	}
+
+	t.Cleanup(func() { lookupIP = original })
+}
+
+func TestFindProxy(t *testing.T) {
+	mockLookupIP(t, map[string]string{"registry.internal.example.com": "10.1.2.3"})
+
+	script, err := Parse(testScript)
+	require.NoError(t, err)
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{target: "http://dynakube-activegate", expected: ""},
+		{target: "https://dynakube-activegate.dynatrace.svc.cluster.local/api", expected: ""},
+		{target: "https://registry.internal.example.com/v2", expected: ""},
+		{target: "https://other.live.dynatrace.com/api/v1/deployment/installer", expected: "http://download-proxy:3128"},
+		{target: "https://tenant.live.dynatrace.com/api/v1/deployment/installer", expected: "https://data-proxy:443"},
+		{target: "https://tenant.live.dynatrace.com/api/v2/settings", expected: "https://data-proxy:443"},
+		{target: "https://registry.example.com/v2", expected: "http://management-proxy:8080"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.target, func(t *testing.T) {
+			target, err := url.Parse(test.target)
+			require.NoError(t, err)
+
+			proxyURL, err := script.FindProxy(target)
+			require.NoError(t, err)
+
+			if test.expected == "" {
+				assert.Nil(t, proxyURL)
+			} else {
+				require.NotNil(t, proxyURL)
+				assert.Equal(t, test.expected, proxyURL.String())
+			}
+		})
+	}
+
+	t.Run("no return => direct", func(t *testing.T) {
+		script, err := Parse(`function FindProxyForURL(u, h) { if (isPlainHostName(h)) return "PROXY proxy:3128"; }`)
+		require.NoError(t, err)
+
+		proxyURL, err := script.FindProxy(&url.URL{Scheme: "https", Host: "tenant.live.dynatrace.com"})
+		require.NoError(t, err)
+		assert.Nil(t, proxyURL)
+	})
+	t.Run("invalid result => error", func(t *testing.T) {
+		script, err := Parse(`function FindProxyForURL(u, h) { return "PROXY"; }`)
+		require.NoError(t, err)
+
+		_, err = script.FindProxy(&url.URL{Scheme: "https", Host: "tenant.live.dynatrace.com"})
+		require.Error(t, err)
+	})
+	t.Run("unsupported function => error", func(t *testing.T) {
+		script, err := Parse(`function FindProxyForURL(u, h) { return weekdayRange("MON", "FRI") ? "DIRECT" : "PROXY proxy:3128"; }`)
+		require.NoError(t, err)
+
+		_, err = script.FindProxy(&url.URL{Scheme: "https", Host: "tenant.live.dynatrace.com"})
+		require.Error(t, err)
+	})
+	t.Run("unresolvable host => null", func(t *testing.T) {
+		script, err := Parse(`function FindProxyForURL(u, h) { return dnsResolve(h) === null ? "PROXY proxy:3128" : "DIRECT"; }`)
+		require.NoError(t, err)
+
+		proxyURL, err := script.FindProxy(&url.URL{Scheme: "https", Host: "unknown.example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, "http://proxy:3128", proxyURL.String())
+	})
+}
+
+func TestParse(t *testing.T) {
+	invalidScripts := map[string]string{
+		"other function":      `function FindProxy(url, host) { return "DIRECT"; }`,
+		"missing brace":       `function FindProxyForURL(url, host) { return "DIRECT";`,
+		"unterminated string": `function FindProxyForURL(url, host) { return "DIRECT; }`,
+		"not a function":      `var FindProxyForURL = "DIRECT";`,
+	}
+
+	for name, invalidScript := range invalidScripts {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(invalidScript)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	assert.Equal(t, uint32(0x0a000001), convertAddr("10.0.0.1"))
+	assert.Equal(t, 2, dnsDomainLevels("tenant.live.dynatrace"))
+	assert.True(t, localHostOrDomainIs("tenant", "tenant.live.dynatrace.com"))
+	assert.False(t, localHostOrDomainIs("other", "tenant.live.dynatrace.com"))
+	assert.True(t, shExpMatch("tenant.live.dynatrace.com", "*.dynatrace.???"))
+	assert.False(t, shExpMatch("tenant.live.dynatrace.com", "*.dynatrace.de"))
+	assert.NotEmpty(t, myIPAddress())
+}
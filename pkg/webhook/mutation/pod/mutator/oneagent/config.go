@@ -2,6 +2,7 @@ package oneagent
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/pin"
 	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/volumes"
 )
@@ -17,7 +18,8 @@ const (
 	AnnotationInjected = AnnotationPrefix + ".dynatrace.com/injected"
 	AnnotationReason   = AnnotationPrefix + ".dynatrace.com/reason"
 
-	MissingTenantUUIDReason = "MissingTenantUUID"
+	MissingTenantUUIDReason        = "MissingTenantUUID"
+	InvalidCodeModuleVersionReason = "InvalidCodeModuleVersion"
 
 	// AnnotationTechnologies can be set on a Pod to configure which code module technologies to download. It's set to
 	// "all" if not set.
@@ -26,6 +28,9 @@ const (
 	// AnnotationFlavor can be set on a Pod to configure which code modules flavor to download.
 	AnnotationFlavor = "oneagent.dynatrace.com/flavor"
 
+	// AnnotationCodeModuleVersion can be set on a Pod to pin the code module to a version or image digest instead of the latest of the DynaKube.
+	AnnotationCodeModuleVersion = pin.Annotation
+
 	// AnnotationInstallPath can be set on a Pod to configure on which directory the OneAgent will be available from,
 	// defaults to DefaultInstallPath if not set.
 	AnnotationInstallPath = AnnotationPrefix + ".dynatrace.com/install-path"
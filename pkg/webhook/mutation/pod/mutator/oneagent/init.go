@@ -7,19 +7,30 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/cmd/bootstrapper"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/pin"
 	maputils "github.com/Dynatrace/dynatrace-operator/pkg/util/map"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/arg"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/mutator"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 )
 
 func mutateInitContainer(mutationRequest *dtwebhook.MutationRequest, installPath string) error {
+	pinned, err := getPinnedCodeModule(mutationRequest.BaseRequest)
+	if err != nil {
+		return dtwebhook.MutatorError{
+			Err:      err,
+			Annotate: setNotInjectedAnnotationFunc(InvalidCodeModuleVersionReason),
+		}
+	}
+
 	if isCSIVolume(mutationRequest.BaseRequest) {
 		log.Info("configuring init-container with CSI bin volume", "name", mutationRequest.PodName())
 		addCSIBinVolume(
 			mutationRequest.Pod,
 			mutationRequest.DynaKube.Name,
-			mutationRequest.DynaKube.FF().GetCSIMaxRetryTimeout().String())
+			mutationRequest.DynaKube.FF().GetCSIMaxRetryTimeout().String(),
+			pinned.Pin)
 		// in case of CSI, the CSI volume itself is already always readonly, so the mount should always be readonly, the init-container should just read from it
 		addInitBinMount(mutationRequest.InstallContainer, true)
 
@@ -29,6 +40,10 @@ func mutateInitContainer(mutationRequest *dtwebhook.MutationRequest, installPath
 		}
 	} else {
 		log.Info("configuring init-container with emptyDir bin volume", "name", mutationRequest.PodName())
+		// the node of the pod is not known yet, so during a canary rollout the stable code modules are used,
+		// the new code modules are only tested with the pods using the CSI volume on the canary nodes
+		mutationRequest.DynaKube.OneAgent().UseStableCodeModules("")
+
 		addEmptyDirBinVolume(mutationRequest.Pod)
 		// in case of no CSI, the the emptyDir can't be readonly for the init-container, as it first has to download/move the agent into it
 		addInitBinMount(mutationRequest.InstallContainer, false)
@@ -40,10 +55,20 @@ func mutateInitContainer(mutationRequest *dtwebhook.MutationRequest, installPath
 			// The first element would be the "bootstrap" subcommand, which is not needed in case of self-extracting image
 			mutationRequest.InstallContainer.Args = mutationRequest.InstallContainer.Args[1:]
 			mutationRequest.InstallContainer.Image = mutationRequest.DynaKube.OneAgent().GetCodeModulesImage()
+
+			if pinned.ImageURI != "" {
+				mutationRequest.InstallContainer.Image = pinned.ImageURI
+			}
 		} else {
 			log.Info("configuring init-container for ZIP download", "name", mutationRequest.PodName())
+
+			targetVersion := mutationRequest.DynaKube.OneAgent().GetCodeModulesVersion()
+			if pinned.Version != "" {
+				targetVersion = pinned.Version
+			}
+
 			downloadArgs := []arg.Arg{
-				{Name: bootstrapper.TargetVersionFlag, Value: mutationRequest.DynaKube.OneAgent().GetCodeModulesVersion()},
+				{Name: bootstrapper.TargetVersionFlag, Value: targetVersion},
 			}
 
 			if flavor := maputils.GetField(mutationRequest.Pod.Annotations, AnnotationFlavor, ""); flavor != "" {
@@ -58,6 +83,28 @@ func mutateInitContainer(mutationRequest *dtwebhook.MutationRequest, installPath
 	return addInitArgs(mutationRequest.Pod, mutationRequest.InstallContainer, mutationRequest.DynaKube, installPath)
 }
 
+// getPinnedCodeModule resolves the AnnotationCodeModuleVersion of the pod, an empty pin.CodeModule is returned if the pod doesn't request one.
+func getPinnedCodeModule(request *dtwebhook.BaseRequest) (pin.CodeModule, error) {
+	requested := maputils.GetField(request.Pod.Annotations, AnnotationCodeModuleVersion, "")
+	if requested == "" {
+		return pin.CodeModule{}, nil
+	}
+
+	pinned, err := pin.Resolve(&request.DynaKube, requested)
+	if err != nil {
+		return pin.CodeModule{}, err
+	}
+
+	// the ZIP download can only download versions, digests need an image
+	if pinned.Version == "" && !isCSIVolume(request) && !request.DynaKube.FF().IsNodeImagePull() {
+		return pin.CodeModule{}, errors.Errorf("image digest %s can only be used with the CSI driver or node image pull", requested)
+	}
+
+	log.Info("pod requests pinned CodeModule", "name", request.PodName(), "pin", requested)
+
+	return pinned, nil
+}
+
 func initContainerResources(dk dynakube.DynaKube) corev1.ResourceRequirements {
 	customInitResources := dk.OneAgent().GetInitResources()
 	if customInitResources != nil {
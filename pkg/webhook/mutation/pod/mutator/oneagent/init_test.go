@@ -2,6 +2,7 @@ package oneagent
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/Dynatrace/dynatrace-bootstrapper/cmd"
@@ -12,6 +13,8 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	csivolumes "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/driver/volumes"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/pin"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/mounts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/resources"
@@ -248,6 +251,121 @@ func TestMutateInitContainer(t *testing.T) {
 
 		assert.Equal(t, *dk.Spec.OneAgent.ApplicationMonitoring.InitResources, request.InstallContainer.Resources) // respects custom resources
 	})
+
+	t.Run("csi-scenario -> pinned code module version", func(t *testing.T) {
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: true})
+
+		pinned := "1.300.0.20240101-000000"
+		dk := dynakube.DynaKube{}
+		dk.Name = "csi-scenario"
+		dk.Spec.OneAgent.ApplicationMonitoring = &oneagent.ApplicationMonitoringSpec{}
+		pod := &corev1.Pod{}
+		pod.Annotations = map[string]string{
+			AnnotationCodeModuleVersion: pinned,
+		}
+
+		request := &webhook.MutationRequest{
+			BaseRequest: &webhook.BaseRequest{
+				Pod:      pod,
+				DynaKube: dk,
+			},
+			InstallContainer: initContainerBase.DeepCopy(),
+		}
+
+		err := mutateInitContainer(request, installPath)
+		require.NoError(t, err)
+
+		csiVolume, err := volumes.GetByName(request.Pod.Spec.Volumes, BinVolumeName)
+		require.NoError(t, err)
+		require.NotNil(t, csiVolume.CSI)
+		assert.Equal(t, pinned, csiVolume.CSI.VolumeAttributes[csivolumes.CSIVolumeAttributeCodeModuleVersion])
+	})
+
+	t.Run("zip-scenario -> pinned code module version", func(t *testing.T) {
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: false})
+
+		pinned := "1.300.0.20240101-000000"
+		dk := dynakube.DynaKube{}
+		dk.Name = "zip-scenario"
+		dk.Spec.OneAgent.ApplicationMonitoring = &oneagent.ApplicationMonitoringSpec{}
+		dk.Status.CodeModules.Version = "1.2.3"
+		pod := &corev1.Pod{}
+		pod.Annotations = map[string]string{
+			AnnotationCodeModuleVersion: pinned,
+		}
+
+		request := &webhook.MutationRequest{
+			BaseRequest: &webhook.BaseRequest{
+				Pod:      pod,
+				DynaKube: dk,
+			},
+			InstallContainer: initContainerBase.DeepCopy(),
+		}
+
+		err := mutateInitContainer(request, installPath)
+		require.NoError(t, err)
+
+		assert.Contains(t, request.InstallContainer.Args, fmt.Sprintf("--%s=%s", bootstrapper.TargetVersionFlag, pinned))
+	})
+
+	t.Run("node-image-pull-scenario -> pinned image digest", func(t *testing.T) {
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: false})
+
+		digest := "sha256:" + strings.Repeat("a", 64)
+		dk := dynakube.DynaKube{}
+		dk.Name = "node-image-pull-scenario"
+		dk.Annotations = map[string]string{
+			exp.OANodeImagePullKey: "true",
+		}
+		dk.Spec.OneAgent.ApplicationMonitoring = &oneagent.ApplicationMonitoringSpec{}
+		dk.Status.CodeModules.ImageID = "myimage.io:latest"
+		pod := &corev1.Pod{}
+		pod.Annotations = map[string]string{
+			AnnotationCodeModuleVersion: digest,
+		}
+
+		request := &webhook.MutationRequest{
+			BaseRequest: &webhook.BaseRequest{
+				Pod:      pod,
+				DynaKube: dk,
+			},
+			InstallContainer: initContainerBase.DeepCopy(),
+		}
+
+		err := mutateInitContainer(request, installPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, pin.PublicCodeModulesRepository+"@"+digest, request.InstallContainer.Image)
+	})
+
+	t.Run("invalid pinned code module version -> error", func(t *testing.T) {
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: true})
+
+		dk := dynakube.DynaKube{}
+		dk.Name = "csi-scenario"
+		dk.Spec.OneAgent.ApplicationMonitoring = &oneagent.ApplicationMonitoringSpec{}
+		pod := &corev1.Pod{}
+		pod.Annotations = map[string]string{
+			AnnotationCodeModuleVersion: "latest",
+		}
+
+		request := &webhook.MutationRequest{
+			BaseRequest: &webhook.BaseRequest{
+				Pod:      pod,
+				DynaKube: dk,
+			},
+			InstallContainer: initContainerBase.DeepCopy(),
+		}
+
+		err := mutateInitContainer(request, installPath)
+
+		var mutatorErr webhook.MutatorError
+		require.ErrorAs(t, err, &mutatorErr)
+
+		mutatorErr.Annotate(pod)
+		assert.Equal(t, InvalidCodeModuleVersionReason, pod.Annotations[AnnotationReason])
+		assert.Empty(t, pod.Spec.Volumes)
+	})
 }
 
 func TestAddInitArgs(t *testing.T) {
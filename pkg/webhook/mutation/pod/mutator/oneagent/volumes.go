@@ -74,7 +74,7 @@ func addEmptyDirBinVolume(pod *corev1.Pod) {
 	)
 }
 
-func addCSIBinVolume(pod *corev1.Pod, dkName string, maxTimeout string) {
+func addCSIBinVolume(pod *corev1.Pod, dkName, maxTimeout, codeModuleVersion string) {
 	if volumeutils.IsIn(pod.Spec.Volumes, BinVolumeName) {
 		return
 	}
@@ -91,6 +91,10 @@ func addCSIBinVolume(pod *corev1.Pod, dkName string, maxTimeout string) {
 		},
 	}
 
+	if codeModuleVersion != "" {
+		volumeSource.CSI.VolumeAttributes[csivolumes.CSIVolumeAttributeCodeModuleVersion] = codeModuleVersion
+	}
+
 	pod.Spec.Volumes = append(pod.Spec.Volumes,
 		corev1.Volume{
 			Name:         BinVolumeName,
@@ -21,6 +21,14 @@ const (
 	OTLPMetricsCertificateEnv  = "OTEL_EXPORTER_OTLP_METRICS_CERTIFICATE"
 	OTLPExporterCertificateEnv = "OTLP_EXPORTER_OTLP_CERTIFICATE"
 
+	OTLPTraceCompressionEnv   = "OTEL_EXPORTER_OTLP_TRACES_COMPRESSION"
+	OTLPLogsCompressionEnv    = "OTEL_EXPORTER_OTLP_LOGS_COMPRESSION"
+	OTLPMetricsCompressionEnv = "OTEL_EXPORTER_OTLP_METRICS_COMPRESSION"
+
+	OTLPTraceTimeoutEnv   = "OTEL_EXPORTER_OTLP_TRACES_TIMEOUT"
+	OTLPLogsTimeoutEnv    = "OTEL_EXPORTER_OTLP_LOGS_TIMEOUT"
+	OTLPMetricsTimeoutEnv = "OTEL_EXPORTER_OTLP_METRICS_TIMEOUT"
+
 	OTLPAuthorizationHeader = "authorization=Api-Token $(DT_API_TOKEN)"
 
 	DynatraceAPITokenEnv = "DT_API_TOKEN"
@@ -2,6 +2,10 @@ package exporter
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/otlp"
 	"github.com/Dynatrace/dynatrace-operator/pkg/otlp/exporterconfig"
@@ -14,6 +18,43 @@ type injector interface {
 	Inject(c *corev1.Container, apiURL string, addCertificate bool) bool
 }
 
+// signalEnvs are the names of the env vars used to configure the exporter of a single signal.
+type signalEnvs struct {
+	endpoint    string
+	protocol    string
+	headers     string
+	certificate string
+	compression string
+	timeout     string
+}
+
+var (
+	traceEnvs = signalEnvs{
+		endpoint:    OTLPTraceEndpointEnv,
+		protocol:    OTLPTraceProtocolEnv,
+		headers:     OTLPTraceHeadersEnv,
+		certificate: OTLPTraceCertificateEnv,
+		compression: OTLPTraceCompressionEnv,
+		timeout:     OTLPTraceTimeoutEnv,
+	}
+	metricsEnvs = signalEnvs{
+		endpoint:    OTLPMetricsEndpointEnv,
+		protocol:    OTLPMetricsProtocolEnv,
+		headers:     OTLPMetricsHeadersEnv,
+		certificate: OTLPMetricsCertificateEnv,
+		compression: OTLPMetricsCompressionEnv,
+		timeout:     OTLPMetricsTimeoutEnv,
+	}
+	logsEnvs = signalEnvs{
+		endpoint:    OTLPLogsEndpointEnv,
+		protocol:    OTLPLogsProtocolEnv,
+		headers:     OTLPLogsHeadersEnv,
+		certificate: OTLPLogsCertificateEnv,
+		compression: OTLPLogsCompressionEnv,
+		timeout:     OTLPLogsTimeoutEnv,
+	}
+)
+
 // traceInjector handles traces signal env var injection.
 type traceInjector struct {
 	cfg *otlp.ExporterConfiguration
@@ -34,13 +75,7 @@ func (ti *traceInjector) Inject(c *corev1.Container, apiURL string, addCertifica
 		return false
 	}
 
-	addEnvVarLiteralValue(c, OTLPTraceEndpointEnv, apiURL+"/v1/traces")
-	addEnvVarLiteralValue(c, OTLPTraceProtocolEnv, "http/protobuf")
-	addEnvVarLiteralValue(c, OTLPTraceHeadersEnv, OTLPAuthorizationHeader)
-
-	if addCertificate {
-		addEnvVarLiteralValue(c, OTLPTraceCertificateEnv, getCertificatePath())
-	}
+	injectSignal(c, traceEnvs, ti.cfg.Spec.Signals.Traces.SignalExporterSpec, apiURL+"/v1/traces", addCertificate)
 
 	return true
 }
@@ -63,13 +98,7 @@ func (mi *metricsInjector) Inject(c *corev1.Container, apiURL string, addCertifi
 		return false
 	}
 
-	addEnvVarLiteralValue(c, OTLPMetricsEndpointEnv, apiURL+"/v1/metrics")
-	addEnvVarLiteralValue(c, OTLPMetricsProtocolEnv, "http/protobuf")
-	addEnvVarLiteralValue(c, OTLPMetricsHeadersEnv, OTLPAuthorizationHeader)
-
-	if addCertificate {
-		addEnvVarLiteralValue(c, OTLPMetricsCertificateEnv, getCertificatePath())
-	}
+	injectSignal(c, metricsEnvs, mi.cfg.Spec.Signals.Metrics.SignalExporterSpec, apiURL+"/v1/metrics", addCertificate)
 
 	return true
 }
@@ -92,15 +121,56 @@ func (li *logsInjector) Inject(c *corev1.Container, apiURL string, addCertificat
 		return false
 	}
 
-	addEnvVarLiteralValue(c, OTLPLogsEndpointEnv, apiURL+"/v1/logs")
-	addEnvVarLiteralValue(c, OTLPLogsProtocolEnv, "http/protobuf")
-	addEnvVarLiteralValue(c, OTLPLogsHeadersEnv, OTLPAuthorizationHeader)
+	injectSignal(c, logsEnvs, li.cfg.Spec.Signals.Logs.SignalExporterSpec, apiURL+"/v1/logs", addCertificate)
+
+	return true
+}
+
+// injectSignal sets the env vars of a single signal.
+// The Dynatrace authorization header and the ActiveGate certificate are only added if the signal is sent to the Dynatrace environment,
+// so the API token is never leaked to a custom endpoint.
+func injectSignal(c *corev1.Container, envs signalEnvs, spec otlp.SignalExporterSpec, defaultEndpoint string, addCertificate bool) {
+	headers := formatHeaders(spec.Headers)
+
+	if spec.HasCustomEndpoint() {
+		addEnvVarLiteralValue(c, envs.endpoint, spec.Endpoint)
+	} else {
+		addEnvVarLiteralValue(c, envs.endpoint, defaultEndpoint)
+
+		headers = joinHeaders(OTLPAuthorizationHeader, headers)
+
+		if addCertificate {
+			addEnvVarLiteralValue(c, envs.certificate, getCertificatePath())
+		}
+	}
+
+	addEnvVarLiteralValue(c, envs.protocol, string(spec.GetProtocol()))
 
-	if addCertificate {
-		addEnvVarLiteralValue(c, OTLPLogsCertificateEnv, getCertificatePath())
+	if headers != "" {
+		addEnvVarLiteralValue(c, envs.headers, headers)
 	}
 
-	return true
+	if spec.Compression != "" {
+		addEnvVarLiteralValue(c, envs.compression, spec.Compression)
+	}
+
+	if spec.Timeout != nil {
+		addEnvVarLiteralValue(c, envs.timeout, strconv.FormatInt(spec.Timeout.Milliseconds(), 10))
+	}
+}
+
+// formatHeaders formats the headers as sorted, comma separated key=value pairs as defined by the OTLP exporter specification.
+func formatHeaders(headers map[string]string) string {
+	pairs := make([]string, 0, len(headers))
+	for _, key := range slices.Sorted(maps.Keys(headers)) {
+		pairs = append(pairs, key+"="+headers[key])
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func joinHeaders(headers ...string) string {
+	return strings.Join(slices.DeleteFunc(headers, func(h string) bool { return h == "" }), ",")
 }
 
 func addEnvVarLiteralValue(c *corev1.Container, name string, value string) {
@@ -2,11 +2,14 @@ package exporter
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/latest/dynakube/otlp"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestTraceInjectorIsEnabledAndInject(t *testing.T) {
@@ -191,3 +194,65 @@ func TestLogsInjectorIsEnabledAndInject(t *testing.T) {
 		})
 	}
 }
+
+func TestInjectSignal(t *testing.T) {
+	defaultEndpoint := "http://example/api/v2/otlp/v1/traces"
+
+	getValue := func(t *testing.T, c *corev1.Container, name string) string {
+		envVar := env.FindEnvVar(c.Env, name)
+		require.NotNil(t, envVar, "expected env var %s to be injected", name)
+
+		return envVar.Value
+	}
+
+	t.Run("defaults => dynatrace endpoint with authorization", func(t *testing.T) {
+		c := &corev1.Container{}
+
+		injectSignal(c, traceEnvs, otlp.SignalExporterSpec{}, defaultEndpoint, true)
+
+		assert.Len(t, c.Env, 4)
+		assert.Equal(t, defaultEndpoint, getValue(t, c, OTLPTraceEndpointEnv))
+		assert.Equal(t, "http/protobuf", getValue(t, c, OTLPTraceProtocolEnv))
+		assert.Equal(t, OTLPAuthorizationHeader, getValue(t, c, OTLPTraceHeadersEnv))
+		assert.Equal(t, getCertificatePath(), getValue(t, c, OTLPTraceCertificateEnv))
+	})
+	t.Run("additional headers, compression and timeout => appended to defaults", func(t *testing.T) {
+		c := &corev1.Container{}
+		spec := otlp.SignalExporterSpec{
+			Headers:     map[string]string{"x-b": "2", "x-a": "1"},
+			Compression: "gzip",
+			Timeout:     &metav1.Duration{Duration: 5 * time.Second},
+		}
+
+		injectSignal(c, metricsEnvs, spec, defaultEndpoint, false)
+
+		assert.Equal(t, OTLPAuthorizationHeader+",x-a=1,x-b=2", getValue(t, c, OTLPMetricsHeadersEnv))
+		assert.Equal(t, "gzip", getValue(t, c, OTLPMetricsCompressionEnv))
+		assert.Equal(t, "5000", getValue(t, c, OTLPMetricsTimeoutEnv))
+		assert.False(t, env.IsIn(c.Env, OTLPMetricsCertificateEnv))
+	})
+	t.Run("custom endpoint => no authorization and certificate", func(t *testing.T) {
+		c := &corev1.Container{}
+		spec := otlp.SignalExporterSpec{
+			Endpoint: "http://collector.observability:4317",
+			Protocol: otlp.ProtocolGRPC,
+		}
+
+		injectSignal(c, logsEnvs, spec, defaultEndpoint, true)
+
+		assert.Len(t, c.Env, 2)
+		assert.Equal(t, spec.Endpoint, getValue(t, c, OTLPLogsEndpointEnv))
+		assert.Equal(t, "grpc", getValue(t, c, OTLPLogsProtocolEnv))
+	})
+	t.Run("custom endpoint with headers => only custom headers", func(t *testing.T) {
+		c := &corev1.Container{}
+		spec := otlp.SignalExporterSpec{
+			Endpoint: "http://collector.observability:4318/v1/logs",
+			Headers:  map[string]string{"authorization": "Bearer $(TOKEN)"},
+		}
+
+		injectSignal(c, logsEnvs, spec, defaultEndpoint, false)
+
+		assert.Equal(t, "authorization=Bearer $(TOKEN)", getValue(t, c, OTLPLogsHeadersEnv))
+	})
+}
@@ -199,16 +199,22 @@ func shouldSkipContainer(request dtwebhook.BaseRequest, c corev1.Container, over
 		OTLPTraceHeadersEnv,
 		OTLPTraceCertificateEnv,
 		OTLPTraceProtocolEnv,
+		OTLPTraceCompressionEnv,
+		OTLPTraceTimeoutEnv,
 		// metrics exporter env var
 		OTLPMetricsEndpointEnv,
 		OTLPMetricsHeadersEnv,
 		OTLPMetricsCertificateEnv,
 		OTLPMetricsProtocolEnv,
+		OTLPMetricsCompressionEnv,
+		OTLPMetricsTimeoutEnv,
 		// logs exporter env var
 		OTLPLogsEndpointEnv,
 		OTLPLogsHeadersEnv,
 		OTLPLogsCertificateEnv,
 		OTLPLogsProtocolEnv,
+		OTLPLogsCompressionEnv,
+		OTLPLogsTimeoutEnv,
 	}
 
 	for _, envVar := range envVarsToCheck {
@@ -90,5 +90,10 @@ func (wh *webhook) getDynakube(ctx context.Context, dynakubeName string) (*dynak
 		return nil, err
 	}
 
+	err = dk.MergeDefaults(ctx, wh.apiReader)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dk, nil
 }
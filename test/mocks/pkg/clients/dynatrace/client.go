@@ -1294,6 +1294,68 @@ func (_c *Client_GetOneAgentConnectionInfo_Call) RunAndReturn(run func(ctx conte
 	return _c
 }
 
+// GetPlatformTokenScopes provides a mock function for the type Client
+func (_mock *Client) GetPlatformTokenScopes(ctx context.Context) (dynatrace.TokenScopes, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPlatformTokenScopes")
+	}
+
+	var r0 dynatrace.TokenScopes
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (dynatrace.TokenScopes, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) dynatrace.TokenScopes); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dynatrace.TokenScopes)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Client_GetPlatformTokenScopes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPlatformTokenScopes'
+type Client_GetPlatformTokenScopes_Call struct {
+	*mock.Call
+}
+
+// GetPlatformTokenScopes is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Client_Expecter) GetPlatformTokenScopes(ctx interface{}) *Client_GetPlatformTokenScopes_Call {
+	return &Client_GetPlatformTokenScopes_Call{Call: _e.mock.On("GetPlatformTokenScopes", ctx)}
+}
+
+func (_c *Client_GetPlatformTokenScopes_Call) Run(run func(ctx context.Context)) *Client_GetPlatformTokenScopes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *Client_GetPlatformTokenScopes_Call) Return(tokenScopes dynatrace.TokenScopes, err error) *Client_GetPlatformTokenScopes_Call {
+	_c.Call.Return(tokenScopes, err)
+	return _c
+}
+
+func (_c *Client_GetPlatformTokenScopes_Call) RunAndReturn(run func(ctx context.Context) (dynatrace.TokenScopes, error)) *Client_GetPlatformTokenScopes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetProcessModuleConfig provides a mock function for the type Client
 func (_mock *Client) GetProcessModuleConfig(ctx context.Context, prevRevision uint) (*dynatrace.ProcessModuleConfig, error) {
 	ret := _mock.Called(ctx, prevRevision)